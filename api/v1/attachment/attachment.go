@@ -57,7 +57,7 @@ func (a *Api) MultipleUpload(ctx *gin.Context) {
 		response.FailWithCode(ctx, response.AuthErr)
 		return
 	}
-	result, err := a.service.SaveFiles(ctx, files, claims.User.ID)
+	result, err := a.service.SaveFiles(ctx, files, claims.Operator().ID)
 	if err != nil {
 		response.FailWithError(ctx, err)
 		return
@@ -80,7 +80,7 @@ func (a *Api) Upload(ctx *gin.Context) {
 		response.FailWithCode(ctx, response.AuthErr)
 		return
 	}
-	result, err := a.service.SaveFile(ctx, file, claims.User.ID)
+	result, err := a.service.SaveFile(ctx, file, claims.Operator().ID)
 	if err != nil {
 		response.FailWithError(ctx, err)
 		return
@@ -3,13 +3,14 @@ package captcha
 import (
 	v1 "github.com/supuwoerc/weaver/api/v1"
 	"github.com/supuwoerc/weaver/pkg/constant"
+	"github.com/supuwoerc/weaver/pkg/request"
 	"github.com/supuwoerc/weaver/pkg/response"
 
 	"github.com/gin-gonic/gin"
 )
 
 type Service interface {
-	Generate(t constant.CaptchaType) (*response.GetCaptchaResponse, error)
+	Generate(t constant.CaptchaType, audio bool) (*response.GetCaptchaResponse, error)
 }
 
 type Api struct {
@@ -25,12 +26,19 @@ func NewCaptchaApi(basic *v1.BasicApi, service Service) *Api {
 	captchaGroup := basic.Route.Group("public/captcha")
 	{
 		captchaGroup.GET("signup", captchaApi.GenerateSignUpCaptcha)
+		captchaGroup.GET("reset-password", captchaApi.GenerateResetPasswordCaptcha)
+		captchaGroup.GET("login", captchaApi.GenerateLoginCaptcha)
 	}
 	return captchaApi
 }
 
 func (c *Api) commonGenerate(ctx *gin.Context, t constant.CaptchaType) {
-	res, err := c.service.Generate(t)
+	var params request.GetCaptchaRequest
+	if err := ctx.ShouldBindQuery(&params); err != nil {
+		response.ParamsValidateFail(ctx, err)
+		return
+	}
+	res, err := c.service.Generate(t, params.Audio)
 	if err != nil {
 		response.FailWithError(ctx, err)
 		return
@@ -41,13 +49,47 @@ func (c *Api) commonGenerate(ctx *gin.Context, t constant.CaptchaType) {
 // GenerateSignUpCaptcha 注册验证码
 //
 //	@Summary		生成注册验证码
-//	@Description	生成用户注册时使用的验证码
+//	@Description	生成用户注册时使用的验证码,type为audio时base64为语音
 //	@Tags			验证码管理
 //	@Accept			json
 //	@Produce		json
+//	@Param			audio	query		bool												false	"是否获取语音验证码"
 //	@Success		10000	{object}	response.BasicResponse[response.GetCaptchaResponse]	"生成成功，code=10000"
+//	@Failure		10002	{object}	response.BasicResponse[any]							"参数验证失败，code=10002"
 //	@Failure		10001	{object}	response.BasicResponse[any]							"生成失败，code=10001"
 //	@Router			/public/captcha/signup [get]
 func (c *Api) GenerateSignUpCaptcha(ctx *gin.Context) {
 	c.commonGenerate(ctx, constant.SignUp)
 }
+
+// GenerateResetPasswordCaptcha 重置密码验证码
+//
+//	@Summary		生成重置密码验证码
+//	@Description	生成用户找回密码时使用的验证码,type为audio时base64为语音
+//	@Tags			验证码管理
+//	@Accept			json
+//	@Produce		json
+//	@Param			audio	query		bool												false	"是否获取语音验证码"
+//	@Success		10000	{object}	response.BasicResponse[response.GetCaptchaResponse]	"生成成功，code=10000"
+//	@Failure		10002	{object}	response.BasicResponse[any]							"参数验证失败，code=10002"
+//	@Failure		10001	{object}	response.BasicResponse[any]							"生成失败，code=10001"
+//	@Router			/public/captcha/reset-password [get]
+func (c *Api) GenerateResetPasswordCaptcha(ctx *gin.Context) {
+	c.commonGenerate(ctx, constant.ResetPassword)
+}
+
+// GenerateLoginCaptcha 登录验证码
+//
+//	@Summary		生成登录验证码
+//	@Description	生成用户登录失败次数较多时使用的验证码,type为audio时base64为语音
+//	@Tags			验证码管理
+//	@Accept			json
+//	@Produce		json
+//	@Param			audio	query		bool												false	"是否获取语音验证码"
+//	@Success		10000	{object}	response.BasicResponse[response.GetCaptchaResponse]	"生成成功，code=10000"
+//	@Failure		10002	{object}	response.BasicResponse[any]							"参数验证失败，code=10002"
+//	@Failure		10001	{object}	response.BasicResponse[any]							"生成失败，code=10001"
+//	@Router			/public/captcha/login [get]
+func (c *Api) GenerateLoginCaptcha(ctx *gin.Context) {
+	c.commonGenerate(ctx, constant.Login)
+}
@@ -28,9 +28,9 @@ func NewDepartmentApi(basic *v1.BasicApi, service Service) *Api {
 		service:  service,
 	}
 	// 挂载路由
-	departmentAccessGroup := basic.Route.Group("department").Use(basic.Auth.LoginRequired())
+	departmentAccessGroup := basic.Route.Group("department", basic.Auth.LoginRequired())
 	{
-		departmentAccessGroup.POST("create", basic.Auth.PermissionRequired(), departmentApi.CreateDepartment)
+		basic.Auth.PermissionGroup(departmentAccessGroup).POST("create", departmentApi.CreateDepartment)
 		departmentAccessGroup.GET("tree", departmentApi.GetDepartmentTree)
 	}
 	return departmentApi
@@ -61,7 +61,7 @@ func (r *Api) CreateDepartment(ctx *gin.Context) {
 		response.FailWithCode(ctx, response.AuthErr)
 		return
 	}
-	err = r.service.CreateDepartment(ctx, claims.User.ID, &params)
+	err = r.service.CreateDepartment(ctx, claims.Operator().ID, &params)
 	if err != nil {
 		response.FailWithError(ctx, err)
 		return
@@ -32,11 +32,11 @@ func NewPermissionApi(basic *v1.BasicApi, service Service) *Api {
 		service:  service,
 	}
 	// 挂载路由
-	permissionGroup := basic.Route.Group("permission").Use(basic.Auth.LoginRequired())
+	permissionGroup := basic.Route.Group("permission", basic.Auth.LoginRequired())
 	{
 		permissionGroup.GET("user-route-menu-permissions", permissionApi.GetUserViewRouteAndMenuPermissions)
 	}
-	permissionAccessGroup := permissionGroup.Use(basic.Auth.PermissionRequired())
+	permissionAccessGroup := basic.Auth.PermissionGroup(permissionGroup)
 	{
 		permissionAccessGroup.POST("create", permissionApi.CreatePermission)
 		permissionAccessGroup.GET("list", permissionApi.GetPermissionList)
@@ -73,7 +73,7 @@ func (r *Api) CreatePermission(ctx *gin.Context) {
 		response.FailWithCode(ctx, response.AuthErr)
 		return
 	}
-	err = r.service.CreatePermission(ctx, claims.User.ID, &params)
+	err = r.service.CreatePermission(ctx, claims.Operator().ID, &params)
 	if err != nil {
 		response.FailWithError(ctx, err)
 		return
@@ -192,7 +192,7 @@ func (r *Api) UpdatePermission(ctx *gin.Context) {
 		response.FailWithCode(ctx, response.AuthErr)
 		return
 	}
-	err = r.service.UpdatePermission(ctx, claims.User.ID, &params)
+	err = r.service.UpdatePermission(ctx, claims.Operator().ID, &params)
 	if err != nil {
 		response.FailWithError(ctx, err)
 		return
@@ -225,7 +225,7 @@ func (r *Api) DeletePermission(ctx *gin.Context) {
 		response.FailWithCode(ctx, response.AuthErr)
 		return
 	}
-	err = r.service.DeletePermission(ctx, params.ID, claims.User.ID)
+	err = r.service.DeletePermission(ctx, params.ID, claims.Operator().ID)
 	if err != nil {
 		response.FailWithError(ctx, err)
 		return
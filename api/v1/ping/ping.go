@@ -33,7 +33,7 @@ func NewPingApi(basic *v1.BasicApi, service Service) *Api {
 	group := basic.Route.Group("check")
 	{
 		group.GET("exception", pinApi.Exception)
-		group.GET("check-permission", basic.Auth.LoginRequired(), basic.Auth.PermissionRequired(), pinApi.CheckPermission)
+		basic.Auth.PermissionGroup(group).GET("check-permission", basic.Auth.LoginRequired(), pinApi.CheckPermission)
 		group.GET("slow", pinApi.SlowResponse)
 		group.GET("check-lock", pinApi.LockResponse)
 		group.GET("logger-trace", pinApi.LoggerTrace)
@@ -29,10 +29,7 @@ func NewRoleApi(basic *v1.BasicApi, service Service) *Api {
 		BasicApi: basic,
 		service:  service,
 	}
-	roleAccessGroup := basic.Route.Group("role").Use(
-		basic.Auth.LoginRequired(),
-		basic.Auth.PermissionRequired(),
-	)
+	roleAccessGroup := basic.Auth.PermissionGroup(basic.Route.Group("role", basic.Auth.LoginRequired()))
 	{
 		roleAccessGroup.POST("create", roleApi.CreateRole)
 		roleAccessGroup.GET("list", roleApi.GetRoleList)
@@ -68,7 +65,7 @@ func (r *Api) CreateRole(ctx *gin.Context) {
 		response.FailWithCode(ctx, response.AuthErr)
 		return
 	}
-	err = r.service.CreateRole(ctx, claims.User.ID, &params)
+	err = r.service.CreateRole(ctx, claims.Operator().ID, &params)
 	if err != nil {
 		response.FailWithError(ctx, err)
 		return
@@ -157,7 +154,7 @@ func (r *Api) UpdateRole(ctx *gin.Context) {
 		response.FailWithCode(ctx, response.AuthErr)
 		return
 	}
-	err = r.service.UpdateRole(ctx, claims.User.ID, &params)
+	err = r.service.UpdateRole(ctx, claims.Operator().ID, &params)
 	if err != nil {
 		response.FailWithError(ctx, err)
 		return
@@ -190,7 +187,7 @@ func (r *Api) DeleteRole(ctx *gin.Context) {
 		response.FailWithCode(ctx, response.AuthErr)
 		return
 	}
-	err = r.service.DeleteRole(ctx, params.ID, claims.User.ID)
+	err = r.service.DeleteRole(ctx, params.ID, claims.Operator().ID)
 	if err != nil {
 		response.FailWithError(ctx, err)
 		return
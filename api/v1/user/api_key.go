@@ -0,0 +1,99 @@
+package user
+
+import (
+	"github.com/supuwoerc/weaver/pkg/request"
+	"github.com/supuwoerc/weaver/pkg/response"
+	"github.com/supuwoerc/weaver/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetAPIKeys
+//
+//	@Summary		获取API密钥
+//	@Description	获取当前用户的API密钥列表,不包含密钥明文
+//	@Tags			用户管理
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		10000	{object}	response.BasicResponse[[]response.APIKeyResponse]	"获取成功，code=10000"
+//	@Failure		10001	{object}	response.BasicResponse[any]							"服务器内部错误，code=10001"
+//	@Router			/user/api-keys [get]
+func (r *Api) GetAPIKeys(ctx *gin.Context) {
+	claims, err := utils.GetContextClaims(ctx)
+	if err != nil || claims == nil {
+		response.FailWithCode(ctx, response.UserNotExist)
+		return
+	}
+	keys, err := r.service.GetAPIKeys(ctx, claims.User.ID)
+	if err != nil {
+		response.FailWithError(ctx, err)
+		return
+	}
+	response.SuccessWithData(ctx, keys)
+}
+
+// CreateAPIKey
+//
+//	@Summary		创建API密钥
+//	@Description	创建供脚本等机器客户端使用的API密钥,请求时通过 X-API-Key 头携带,权限范围只能是当前用户已拥有的接口权限,密钥明文仅返回一次
+//	@Tags			用户管理
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		request.CreateAPIKeyRequest								true	"创建API密钥请求参数"
+//	@Success		10000	{object}	response.BasicResponse[response.CreateAPIKeyResponse]	"创建成功，code=10000"
+//	@Failure		10002	{object}	response.BasicResponse[any]								"参数验证失败，code=10002"
+//	@Failure		20022	{object}	response.BasicResponse[any]								"API密钥数量已达上限，code=20022"
+//	@Failure		20023	{object}	response.BasicResponse[any]								"权限范围超出用户权限，code=20023"
+//	@Failure		50001	{object}	response.BasicResponse[any]								"权限不存在，code=50001"
+//	@Router			/user/api-keys/create [post]
+func (r *Api) CreateAPIKey(ctx *gin.Context) {
+	var params request.CreateAPIKeyRequest
+	if err := ctx.ShouldBindJSON(&params); err != nil {
+		response.ParamsValidateFail(ctx, err)
+		return
+	}
+	claims, err := utils.GetContextClaims(ctx)
+	if err != nil || claims == nil {
+		response.FailWithCode(ctx, response.UserNotExist)
+		return
+	}
+	res, err := r.service.CreateAPIKey(ctx, claims.User.ID, claims.User.Email, &params)
+	if err != nil {
+		response.FailWithError(ctx, err)
+		return
+	}
+	response.SuccessWithData(ctx, res)
+}
+
+// DeleteAPIKey
+//
+//	@Summary		删除API密钥
+//	@Description	删除当前用户的API密钥,删除后立即失效
+//	@Tags			用户管理
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		request.DeleteAPIKeyRequest	true	"删除API密钥请求参数"
+//	@Success		10000	{object}	response.BasicResponse[any]	"删除成功，code=10000"
+//	@Failure		10002	{object}	response.BasicResponse[any]	"参数验证失败，code=10002"
+//	@Failure		20021	{object}	response.BasicResponse[any]	"API密钥不存在，code=20021"
+//	@Router			/user/api-keys/delete [post]
+func (r *Api) DeleteAPIKey(ctx *gin.Context) {
+	var params request.DeleteAPIKeyRequest
+	if err := ctx.ShouldBindJSON(&params); err != nil {
+		response.ParamsValidateFail(ctx, err)
+		return
+	}
+	claims, err := utils.GetContextClaims(ctx)
+	if err != nil || claims == nil {
+		response.FailWithCode(ctx, response.UserNotExist)
+		return
+	}
+	if err = r.service.DeleteAPIKey(ctx, claims.User.ID, params.ID); err != nil {
+		response.FailWithError(ctx, err)
+		return
+	}
+	response.Success(ctx)
+}
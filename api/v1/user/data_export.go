@@ -0,0 +1,70 @@
+package user
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/supuwoerc/weaver/pkg/constant"
+	"github.com/supuwoerc/weaver/pkg/request"
+	"github.com/supuwoerc/weaver/pkg/response"
+	"github.com/supuwoerc/weaver/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestDataExport
+//
+//	@Summary		导出个人数据
+//	@Description	异步打包当前用户的个人资料、角色、部门、附件信息和登录记录,完成后向用户邮箱发送下载链接,
+//	@Description	打包结果为zip文件,每类数据为一个json文件;两次申请之间需要间隔一段时间
+//	@Tags			用户管理
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		10000	{object}	response.BasicResponse[any]	"申请成功，code=10000"
+//	@Failure		20046	{object}	response.BasicResponse[any]	"申请过于频繁，code=20046"
+//	@Failure		20029	{object}	response.BasicResponse[any]	"模拟登录期间不允许该操作，code=20029"
+//	@Failure		10001	{object}	response.BasicResponse[any]	"服务器内部错误，code=10001"
+//	@Router			/user/data-export [post]
+func (r *Api) RequestDataExport(ctx *gin.Context) {
+	claims, err := utils.GetContextClaims(ctx)
+	if err != nil || claims == nil {
+		response.FailWithCode(ctx, response.UserNotExist)
+		return
+	}
+	if err = r.service.RequestDataExport(ctx, claims.User.ID); err != nil {
+		response.FailWithError(ctx, err)
+		return
+	}
+	response.Success(ctx)
+}
+
+// DownloadDataExport
+//
+//	@Summary		下载个人数据
+//	@Description	通过邮件中的下载链接下载打包好的个人数据,有效期内可以重复下载
+//	@Tags			用户管理
+//	@Accept			json
+//	@Produce		application/zip
+//	@Param			code	query		string						true	"下载码"
+//	@Success		200		{file}		file						"个人数据压缩包"
+//	@Failure		10002	{object}	response.BasicResponse[any]	"参数验证失败，code=10002"
+//	@Failure		20047	{object}	response.BasicResponse[any]	"下载链接无效或已过期，code=20047"
+//	@Failure		10001	{object}	response.BasicResponse[any]	"服务器内部错误，code=10001"
+//	@Router			/public/user/data-export [get]
+func (r *Api) DownloadDataExport(ctx *gin.Context) {
+	var params request.DownloadDataExportRequest
+	if err := ctx.ShouldBindQuery(&params); err != nil {
+		response.ParamsValidateFail(ctx, err)
+		return
+	}
+	data, err := r.service.DownloadDataExport(ctx, params.Code)
+	if err != nil {
+		response.FailWithError(ctx, err)
+		return
+	}
+	filename := fmt.Sprintf("data-export-%s.zip", time.Now().Format("20060102150405"))
+	ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	ctx.Data(http.StatusOK, string(constant.ApplicationZIP), data)
+}
@@ -0,0 +1,102 @@
+package user
+
+import (
+	"github.com/supuwoerc/weaver/pkg/request"
+	"github.com/supuwoerc/weaver/pkg/response"
+	"github.com/supuwoerc/weaver/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ChangeEmail
+//
+//	@Summary		修改邮箱
+//	@Description	校验当前密码后向新邮箱发送确认链接,通过 /public/user/email/confirm 确认后邮箱才会变更
+//	@Tags			用户管理
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		request.ChangeEmailRequest	true	"修改邮箱请求参数"
+//	@Success		10000	{object}	response.BasicResponse[any]	"确认邮件已发送，code=10000"
+//	@Failure		10002	{object}	response.BasicResponse[any]	"参数验证失败，code=10002"
+//	@Failure		20003	{object}	response.BasicResponse[any]	"邮箱格式错误，code=20003"
+//	@Failure		20000	{object}	response.BasicResponse[any]	"邮箱已被注册，code=20000"
+//	@Failure		20012	{object}	response.BasicResponse[any]	"密码错误，code=20012"
+//	@Failure		10001	{object}	response.BasicResponse[any]	"服务器内部错误，code=10001"
+//	@Router			/user/email/change [post]
+func (r *Api) ChangeEmail(ctx *gin.Context) {
+	var params request.ChangeEmailRequest
+	if err := ctx.ShouldBindJSON(&params); err != nil {
+		response.ParamsValidateFail(ctx, err)
+		return
+	}
+	emailValid, err := r.emailRegexExp.MatchString(params.Email)
+	if err != nil || !emailValid {
+		response.HttpResponse[any](ctx, response.EmailValidErr, nil, nil, nil)
+		return
+	}
+	claims, err := utils.GetContextClaims(ctx)
+	if err != nil || claims == nil {
+		response.FailWithCode(ctx, response.UserNotExist)
+		return
+	}
+	if err = r.service.ChangeEmail(ctx, claims.User.ID, params.Password, params.Email); err != nil {
+		response.FailWithError(ctx, err)
+		return
+	}
+	response.Success(ctx)
+}
+
+// ConfirmEmailChange
+//
+//	@Summary		确认修改邮箱
+//	@Description	使用新邮箱收到的确认链接中的code完成修改,原邮箱会收到附带撤销链接的通知,用户需要刷新token以获取新的邮箱
+//	@Tags			用户管理
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		request.EmailChangeCodeRequest	true	"确认修改邮箱请求参数"
+//	@Success		10000	{object}	response.BasicResponse[any]		"修改成功，code=10000"
+//	@Failure		10002	{object}	response.BasicResponse[any]		"参数验证失败，code=10002"
+//	@Failure		20000	{object}	response.BasicResponse[any]		"邮箱已被注册，code=20000"
+//	@Failure		20032	{object}	response.BasicResponse[any]		"链接无效或已过期，code=20032"
+//	@Failure		10001	{object}	response.BasicResponse[any]		"服务器内部错误，code=10001"
+//	@Router			/public/user/email/confirm [post]
+func (r *Api) ConfirmEmailChange(ctx *gin.Context) {
+	var params request.EmailChangeCodeRequest
+	if err := ctx.ShouldBindJSON(&params); err != nil {
+		response.ParamsValidateFail(ctx, err)
+		return
+	}
+	if err := r.service.ConfirmEmailChange(ctx, params.Code); err != nil {
+		response.FailWithError(ctx, err)
+		return
+	}
+	response.Success(ctx)
+}
+
+// RevertEmailChange
+//
+//	@Summary		撤销修改邮箱
+//	@Description	使用原邮箱收到的通知中的撤销链接恢复原邮箱,恢复后用户的全部登录会话失效
+//	@Tags			用户管理
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		request.EmailChangeCodeRequest	true	"撤销修改邮箱请求参数"
+//	@Success		10000	{object}	response.BasicResponse[any]		"撤销成功，code=10000"
+//	@Failure		10002	{object}	response.BasicResponse[any]		"参数验证失败，code=10002"
+//	@Failure		20000	{object}	response.BasicResponse[any]		"原邮箱已被注册，code=20000"
+//	@Failure		20032	{object}	response.BasicResponse[any]		"链接无效或已过期，code=20032"
+//	@Failure		10001	{object}	response.BasicResponse[any]		"服务器内部错误，code=10001"
+//	@Router			/public/user/email/revert [post]
+func (r *Api) RevertEmailChange(ctx *gin.Context) {
+	var params request.EmailChangeCodeRequest
+	if err := ctx.ShouldBindJSON(&params); err != nil {
+		response.ParamsValidateFail(ctx, err)
+		return
+	}
+	if err := r.service.RevertEmailChange(ctx, params.Code); err != nil {
+		response.FailWithError(ctx, err)
+		return
+	}
+	response.Success(ctx)
+}
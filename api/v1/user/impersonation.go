@@ -0,0 +1,107 @@
+package user
+
+import (
+	"github.com/supuwoerc/weaver/pkg/request"
+	"github.com/supuwoerc/weaver/pkg/response"
+	"github.com/supuwoerc/weaver/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Impersonate
+//
+//	@Summary		模拟登录
+//	@Description	管理员以指定用户的身份获取短token,用于复现用户看到的菜单和权限;token有效期较短且不签发refresh token,
+//	@Description	沿用管理员当前的登录会话,期间的请求按被模拟用户的权限鉴权,写操作记录为管理员本人
+//	@Tags			用户管理
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		request.ImpersonateRequest								true	"模拟登录请求参数"
+//	@Success		10000	{object}	response.BasicResponse[response.ImpersonateResponse]	"模拟登录成功，code=10000"
+//	@Failure		10002	{object}	response.BasicResponse[any]								"参数验证失败，code=10002"
+//	@Failure		20004	{object}	response.BasicResponse[any]								"用户不存在，code=20004"
+//	@Failure		20005	{object}	response.BasicResponse[any]								"用户未激活，code=20005"
+//	@Failure		20006	{object}	response.BasicResponse[any]								"用户已禁用，code=20006"
+//	@Failure		20026	{object}	response.BasicResponse[any]								"不能模拟自己、系统管理员或权限超过自己的用户，code=20026"
+//	@Failure		20029	{object}	response.BasicResponse[any]								"模拟登录期间不允许该操作，code=20029"
+//	@Failure		10001	{object}	response.BasicResponse[any]								"服务器内部错误，code=10001"
+//	@Router			/user/impersonate [post]
+func (r *Api) Impersonate(ctx *gin.Context) {
+	var params request.ImpersonateRequest
+	if err := ctx.ShouldBindJSON(&params); err != nil {
+		response.ParamsValidateFail(ctx, err)
+		return
+	}
+	claims, err := utils.GetContextClaims(ctx)
+	if err != nil || claims == nil {
+		response.FailWithCode(ctx, response.AuthErr)
+		return
+	}
+	// api key没有登录会话,不能用于模拟登录
+	if claims.APIKey != 0 {
+		response.FailWithCode(ctx, response.AuthErr)
+		return
+	}
+	res, err := r.service.Impersonate(ctx, claims.User, claims.Session, ctx.ClientIP(), &params)
+	if err != nil {
+		response.FailWithError(ctx, err)
+		return
+	}
+	response.SuccessWithData(ctx, res)
+}
+
+// StopImpersonation
+//
+//	@Summary		结束模拟登录
+//	@Description	使用模拟登录的token调用,吊销该token并记录结束时间,管理员本人的登录会话不受影响
+//	@Tags			用户管理
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		10000	{object}	response.BasicResponse[any]	"结束成功，code=10000"
+//	@Failure		10003	{object}	response.BasicResponse[any]	"不是模拟登录的token，code=10003"
+//	@Failure		10001	{object}	response.BasicResponse[any]	"服务器内部错误，code=10001"
+//	@Router			/user/impersonate/stop [post]
+func (r *Api) StopImpersonation(ctx *gin.Context) {
+	claims, err := utils.GetContextClaims(ctx)
+	if err != nil || claims == nil {
+		response.FailWithCode(ctx, response.AuthErr)
+		return
+	}
+	if err = r.service.StopImpersonation(ctx, claims); err != nil {
+		response.FailWithError(ctx, err)
+		return
+	}
+	response.Success(ctx)
+}
+
+// GetImpersonations
+//
+//	@Summary		获取模拟登录记录
+//	@Description	分页获取模拟登录的审计记录,支持按操作人和被模拟用户过滤
+//	@Tags			用户管理
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			actor_id	query		int																	false	"操作人ID"
+//	@Param			user_id		query		int																	false	"被模拟的用户ID"
+//	@Param			limit		query		int																	true	"每页数量"
+//	@Param			offset		query		int																	false	"偏移量"	default(0)
+//	@Success		10000		{object}	response.BasicResponse[response.DataList[models.UserImpersonation]]	"获取成功，code=10000"
+//	@Failure		10002		{object}	response.BasicResponse[any]											"参数验证失败，code=10002"
+//	@Failure		10001		{object}	response.BasicResponse[any]											"服务器内部错误，code=10001"
+//	@Router			/user/impersonations [get]
+func (r *Api) GetImpersonations(ctx *gin.Context) {
+	var params request.GetImpersonationsRequest
+	if err := ctx.ShouldBindQuery(&params); err != nil {
+		response.ParamsValidateFail(ctx, err)
+		return
+	}
+	list, total, err := r.service.GetImpersonations(ctx, &params)
+	if err != nil {
+		response.FailWithError(ctx, err)
+		return
+	}
+	response.SuccessWithPageData(ctx, total, list)
+}
@@ -0,0 +1,155 @@
+package user
+
+import (
+	"github.com/supuwoerc/weaver/pkg/request"
+	"github.com/supuwoerc/weaver/pkg/response"
+	"github.com/supuwoerc/weaver/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateInvitation
+//
+//	@Summary		创建注册邀请
+//	@Description	向指定邮箱发送注册邀请,可以预设角色和部门,被邀请人接受邀请后直接创建已激活的用户;关闭注册时不能创建邀请
+//	@Tags			用户管理
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		request.CreateInvitationRequest	true	"创建注册邀请请求参数"
+//	@Success		10000	{object}	response.BasicResponse[any]		"创建成功，code=10000"
+//	@Failure		10002	{object}	response.BasicResponse[any]		"参数验证失败，code=10002"
+//	@Failure		20000	{object}	response.BasicResponse[any]		"邮箱已被注册，code=20000"
+//	@Failure		20030	{object}	response.BasicResponse[any]		"注册已关闭，code=20030"
+//	@Failure		10001	{object}	response.BasicResponse[any]		"服务器内部错误，code=10001"
+//	@Router			/user/invitations/create [post]
+func (r *Api) CreateInvitation(ctx *gin.Context) {
+	var params request.CreateInvitationRequest
+	if err := ctx.ShouldBindJSON(&params); err != nil {
+		response.ParamsValidateFail(ctx, err)
+		return
+	}
+	claims, err := utils.GetContextClaims(ctx)
+	if err != nil || claims == nil {
+		response.FailWithCode(ctx, response.AuthErr)
+		return
+	}
+	if err = r.service.CreateInvitation(ctx, claims.Operator(), &params); err != nil {
+		response.FailWithError(ctx, err)
+		return
+	}
+	response.Success(ctx)
+}
+
+// GetInvitation
+//
+//	@Summary		查询注册邀请
+//	@Description	根据邀请邮件中的邀请码查询邀请的邮箱、邀请人和过期时间,用于接受邀请页面的预填
+//	@Tags			用户管理
+//	@Accept			json
+//	@Produce		json
+//	@Param			code	query		string												true	"邀请码"
+//	@Success		10000	{object}	response.BasicResponse[response.InvitationResponse]	"获取成功，code=10000"
+//	@Failure		10002	{object}	response.BasicResponse[any]							"参数验证失败，code=10002"
+//	@Failure		20030	{object}	response.BasicResponse[any]							"注册已关闭，code=20030"
+//	@Failure		20031	{object}	response.BasicResponse[any]							"邀请不存在、已过期或已被接受，code=20031"
+//	@Failure		10001	{object}	response.BasicResponse[any]							"服务器内部错误，code=10001"
+//	@Router			/public/user/invitation [get]
+func (r *Api) GetInvitation(ctx *gin.Context) {
+	var params request.GetInvitationRequest
+	if err := ctx.ShouldBindQuery(&params); err != nil {
+		response.ParamsValidateFail(ctx, err)
+		return
+	}
+	res, err := r.service.GetInvitation(ctx, params.Code)
+	if err != nil {
+		response.FailWithError(ctx, err)
+		return
+	}
+	response.SuccessWithData(ctx, res)
+}
+
+// AcceptInvitation
+//
+//	@Summary		接受注册邀请
+//	@Description	使用邀请码设置密码完成注册,创建的用户无需激活并关联邀请中预设的角色和部门
+//	@Tags			用户管理
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		request.AcceptInvitationRequest	true	"接受注册邀请请求参数"
+//	@Success		10000	{object}	response.BasicResponse[any]		"注册成功，code=10000"
+//	@Failure		10002	{object}	response.BasicResponse[any]		"参数验证失败，code=10002"
+//	@Failure		20033	{object}	response.BasicResponse[any]		"密码不符合密码策略，code=20033~20039"
+//	@Failure		20000	{object}	response.BasicResponse[any]		"邮箱已被注册，code=20000"
+//	@Failure		20030	{object}	response.BasicResponse[any]		"注册已关闭，code=20030"
+//	@Failure		20031	{object}	response.BasicResponse[any]		"邀请不存在、已过期或已被接受，code=20031"
+//	@Failure		10001	{object}	response.BasicResponse[any]		"服务器内部错误，code=10001"
+//	@Router			/public/user/invitation/accept [post]
+func (r *Api) AcceptInvitation(ctx *gin.Context) {
+	var params request.AcceptInvitationRequest
+	if err := ctx.ShouldBindJSON(&params); err != nil {
+		response.ParamsValidateFail(ctx, err)
+		return
+	}
+	if err := r.service.AcceptInvitation(ctx, &params); err != nil {
+		response.FailWithError(ctx, err)
+		return
+	}
+	response.Success(ctx)
+}
+
+// GetInvitations
+//
+//	@Summary		获取注册邀请列表
+//	@Description	分页获取注册邀请,包含邀请人、预设的角色和部门以及接受状态
+//	@Tags			用户管理
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			keyword	query		string																false	"邮箱关键字"
+//	@Param			limit	query		int																	true	"每页数量"
+//	@Param			offset	query		int																	false	"偏移量"	default(0)
+//	@Success		10000	{object}	response.BasicResponse[response.DataList[models.UserInvitation]]	"获取成功，code=10000"
+//	@Failure		10002	{object}	response.BasicResponse[any]											"参数验证失败，code=10002"
+//	@Failure		10001	{object}	response.BasicResponse[any]											"服务器内部错误，code=10001"
+//	@Router			/user/invitations [get]
+func (r *Api) GetInvitations(ctx *gin.Context) {
+	var params request.GetInvitationsRequest
+	if err := ctx.ShouldBindQuery(&params); err != nil {
+		response.ParamsValidateFail(ctx, err)
+		return
+	}
+	list, total, err := r.service.GetInvitations(ctx, &params)
+	if err != nil {
+		response.FailWithError(ctx, err)
+		return
+	}
+	response.SuccessWithPageData(ctx, total, list)
+}
+
+// RevokeInvitation
+//
+//	@Summary		撤销注册邀请
+//	@Description	撤销未被接受的注册邀请,撤销后邀请邮件中的链接失效
+//	@Tags			用户管理
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		request.RevokeInvitationRequest	true	"撤销注册邀请请求参数"
+//	@Success		10000	{object}	response.BasicResponse[any]		"撤销成功，code=10000"
+//	@Failure		10002	{object}	response.BasicResponse[any]		"参数验证失败，code=10002"
+//	@Failure		20031	{object}	response.BasicResponse[any]		"邀请不存在或已被接受，code=20031"
+//	@Failure		10001	{object}	response.BasicResponse[any]		"服务器内部错误，code=10001"
+//	@Router			/user/invitations/revoke [post]
+func (r *Api) RevokeInvitation(ctx *gin.Context) {
+	var params request.RevokeInvitationRequest
+	if err := ctx.ShouldBindJSON(&params); err != nil {
+		response.ParamsValidateFail(ctx, err)
+		return
+	}
+	if err := r.service.RevokeInvitation(ctx, params.ID); err != nil {
+		response.FailWithError(ctx, err)
+		return
+	}
+	response.Success(ctx)
+}
@@ -0,0 +1,77 @@
+package user
+
+import (
+	"github.com/supuwoerc/weaver/pkg/request"
+	"github.com/supuwoerc/weaver/pkg/response"
+	"github.com/supuwoerc/weaver/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetLoginHistory
+//
+//	@Summary		获取登录记录
+//	@Description	分页获取当前用户的登录记录,包括失败的登录尝试和token刷新
+//	@Tags			用户管理
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			limit	query		int																	true	"每页数量"
+//	@Param			offset	query		int																	false	"偏移量"	default(0)
+//	@Success		10000	{object}	response.BasicResponse[response.DataList[models.UserLoginHistory]]	"获取成功，code=10000"
+//	@Failure		10002	{object}	response.BasicResponse[any]											"参数验证失败，code=10002"
+//	@Failure		10001	{object}	response.BasicResponse[any]											"服务器内部错误，code=10001"
+//	@Router			/user/login-history [get]
+func (r *Api) GetLoginHistory(ctx *gin.Context) {
+	var params request.GetLoginHistoryRequest
+	if err := ctx.ShouldBindQuery(&params); err != nil {
+		response.ParamsValidateFail(ctx, err)
+		return
+	}
+	claims, err := utils.GetContextClaims(ctx)
+	if err != nil || claims == nil {
+		response.FailWithCode(ctx, response.UserNotExist)
+		return
+	}
+	list, total, err := r.service.GetLoginHistory(ctx, claims.User.ID, &params)
+	if err != nil {
+		response.FailWithError(ctx, err)
+		return
+	}
+	response.SuccessWithPageData(ctx, total, list)
+}
+
+// GetLoginHistories
+//
+//	@Summary		查询登录记录
+//	@Description	分页查询全部用户的登录记录,支持按用户、邮箱、登录方式、结果、IP和时间范围过滤
+//	@Tags			用户管理
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			user_id	query		int																	false	"用户ID"
+//	@Param			email	query		string																false	"登录邮箱(模糊匹配)"
+//	@Param			type	query		string																false	"登录方式"	Enums(password, mfa, sso, sms, refresh)
+//	@Param			success	query		bool																false	"是否成功"
+//	@Param			ip		query		string																false	"IP"
+//	@Param			start	query		int																	false	"开始时间(秒级时间戳)"
+//	@Param			end		query		int																	false	"结束时间(秒级时间戳)"
+//	@Param			limit	query		int																	true	"每页数量"
+//	@Param			offset	query		int																	false	"偏移量"	default(0)
+//	@Success		10000	{object}	response.BasicResponse[response.DataList[models.UserLoginHistory]]	"获取成功，code=10000"
+//	@Failure		10002	{object}	response.BasicResponse[any]											"参数验证失败，code=10002"
+//	@Failure		10001	{object}	response.BasicResponse[any]											"服务器内部错误，code=10001"
+//	@Router			/user/login-histories [get]
+func (r *Api) GetLoginHistories(ctx *gin.Context) {
+	var params request.GetLoginHistoriesRequest
+	if err := ctx.ShouldBindQuery(&params); err != nil {
+		response.ParamsValidateFail(ctx, err)
+		return
+	}
+	list, total, err := r.service.GetLoginHistories(ctx, &params)
+	if err != nil {
+		response.FailWithError(ctx, err)
+		return
+	}
+	response.SuccessWithPageData(ctx, total, list)
+}
@@ -0,0 +1,199 @@
+package user
+
+import (
+	"github.com/supuwoerc/weaver/pkg/request"
+	"github.com/supuwoerc/weaver/pkg/response"
+	"github.com/supuwoerc/weaver/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateUser
+//
+//	@Summary		创建用户
+//	@Description	管理员创建用户并设置角色和部门,发送激活邮件时用户需激活后才能登录,否则直接启用
+//	@Tags			用户管理
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		request.CreateUserRequest	true	"创建用户请求参数"
+//	@Success		10000	{object}	response.BasicResponse[any]	"创建成功，code=10000"
+//	@Failure		10002	{object}	response.BasicResponse[any]	"参数验证失败，code=10002"
+//	@Failure		20000	{object}	response.BasicResponse[any]	"邮箱已被注册，code=20000"
+//	@Failure		20033	{object}	response.BasicResponse[any]	"密码不符合密码策略，code=20033~20039"
+//	@Failure		10001	{object}	response.BasicResponse[any]	"服务器内部错误，code=10001"
+//	@Router			/user/create [post]
+func (r *Api) CreateUser(ctx *gin.Context) {
+	var params request.CreateUserRequest
+	if err := ctx.ShouldBindJSON(&params); err != nil {
+		response.ParamsValidateFail(ctx, err)
+		return
+	}
+	emailValid, err := r.emailRegexExp.MatchString(params.Email)
+	if err != nil || !emailValid {
+		response.HttpResponse[any](ctx, response.EmailValidErr, nil, nil, nil)
+		return
+	}
+	if err = r.service.CreateUser(ctx, &params); err != nil {
+		response.FailWithError(ctx, err)
+		return
+	}
+	response.Success(ctx)
+}
+
+// UpdateUserStatus
+//
+//	@Summary		启用/禁用用户
+//	@Description	管理员启用或禁用用户,禁用后用户全部的登录会话被注销,已签发的token立即失效
+//	@Tags			用户管理
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		request.UpdateUserStatusRequest	true	"更新用户状态请求参数"
+//	@Success		10000	{object}	response.BasicResponse[any]		"更新成功，code=10000"
+//	@Failure		10002	{object}	response.BasicResponse[any]		"参数验证失败，code=10002"
+//	@Failure		20004	{object}	response.BasicResponse[any]		"用户不存在，code=20004"
+//	@Failure		20026	{object}	response.BasicResponse[any]		"不能管理自己或系统管理员，code=20026"
+//	@Router			/user/status [post]
+func (r *Api) UpdateUserStatus(ctx *gin.Context) {
+	var params request.UpdateUserStatusRequest
+	if err := ctx.ShouldBindJSON(&params); err != nil {
+		response.ParamsValidateFail(ctx, err)
+		return
+	}
+	claims, err := utils.GetContextClaims(ctx)
+	if err != nil || claims == nil {
+		response.FailWithCode(ctx, response.AuthErr)
+		return
+	}
+	if err = r.service.UpdateUserStatus(ctx, claims.Operator().ID, params.ID, params.Status); err != nil {
+		response.FailWithError(ctx, err)
+		return
+	}
+	response.Success(ctx)
+}
+
+// DeleteUser
+//
+//	@Summary		删除用户
+//	@Description	管理员删除用户(软删除),删除后用户全部的登录会话被注销,已签发的token立即失效
+//	@Tags			用户管理
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		request.DeleteUserRequest	true	"删除用户请求参数"
+//	@Success		10000	{object}	response.BasicResponse[any]	"删除成功，code=10000"
+//	@Failure		10002	{object}	response.BasicResponse[any]	"参数验证失败，code=10002"
+//	@Failure		20004	{object}	response.BasicResponse[any]	"用户不存在，code=20004"
+//	@Failure		20026	{object}	response.BasicResponse[any]	"不能管理自己或系统管理员，code=20026"
+//	@Router			/user/delete [post]
+func (r *Api) DeleteUser(ctx *gin.Context) {
+	var params request.DeleteUserRequest
+	if err := ctx.ShouldBindJSON(&params); err != nil {
+		response.ParamsValidateFail(ctx, err)
+		return
+	}
+	claims, err := utils.GetContextClaims(ctx)
+	if err != nil || claims == nil {
+		response.FailWithCode(ctx, response.AuthErr)
+		return
+	}
+	if err = r.service.DeleteUser(ctx, claims.Operator().ID, params.ID); err != nil {
+		response.FailWithError(ctx, err)
+		return
+	}
+	response.Success(ctx)
+}
+
+// RestoreUser
+//
+//	@Summary		恢复用户
+//	@Description	管理员恢复已删除的用户,角色和部门随之恢复
+//	@Tags			用户管理
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		request.RestoreUserRequest	true	"恢复用户请求参数"
+//	@Success		10000	{object}	response.BasicResponse[any]	"恢复成功，code=10000"
+//	@Failure		10002	{object}	response.BasicResponse[any]	"参数验证失败，code=10002"
+//	@Failure		20004	{object}	response.BasicResponse[any]	"已删除的用户不存在，code=20004"
+//	@Failure		20048	{object}	response.BasicResponse[any]	"用户已注销，code=20048"
+//	@Router			/user/restore [post]
+func (r *Api) RestoreUser(ctx *gin.Context) {
+	var params request.RestoreUserRequest
+	if err := ctx.ShouldBindJSON(&params); err != nil {
+		response.ParamsValidateFail(ctx, err)
+		return
+	}
+	if err := r.service.RestoreUser(ctx, params.ID); err != nil {
+		response.FailWithError(ctx, err)
+		return
+	}
+	response.Success(ctx)
+}
+
+// EraseUser
+//
+//	@Summary		注销用户
+//	@Description	管理员注销用户(已删除的用户同样可以注销),清除用户的个人信息、角色和部门关联、两步验证、绑定的外部账户、API密钥和登录记录,
+//	@Description	用户记录保留为已注销的匿名账户,其创建和更新的角色、权限、部门等数据不受影响;注销后不能恢复
+//	@Tags			用户管理
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		request.EraseUserRequest	true	"注销用户请求参数"
+//	@Success		10000	{object}	response.BasicResponse[any]	"注销成功，code=10000"
+//	@Failure		10002	{object}	response.BasicResponse[any]	"参数验证失败，code=10002"
+//	@Failure		20004	{object}	response.BasicResponse[any]	"用户不存在，code=20004"
+//	@Failure		20026	{object}	response.BasicResponse[any]	"不能管理自己或系统管理员，code=20026"
+//	@Failure		20048	{object}	response.BasicResponse[any]	"用户已注销，code=20048"
+//	@Router			/user/erase [post]
+func (r *Api) EraseUser(ctx *gin.Context) {
+	var params request.EraseUserRequest
+	if err := ctx.ShouldBindJSON(&params); err != nil {
+		response.ParamsValidateFail(ctx, err)
+		return
+	}
+	claims, err := utils.GetContextClaims(ctx)
+	if err != nil || claims == nil {
+		response.FailWithCode(ctx, response.AuthErr)
+		return
+	}
+	if err = r.service.EraseUser(ctx, claims.Operator().ID, params.ID); err != nil {
+		response.FailWithError(ctx, err)
+		return
+	}
+	response.Success(ctx)
+}
+
+// AssignUser
+//
+//	@Summary		分配角色和部门
+//	@Description	管理员替换用户的角色和部门,被移除角色时用户已签发的token立即失效
+//	@Tags			用户管理
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		request.AssignUserRequest	true	"分配角色和部门请求参数"
+//	@Success		10000	{object}	response.BasicResponse[any]	"分配成功，code=10000"
+//	@Failure		10002	{object}	response.BasicResponse[any]	"参数验证失败，code=10002"
+//	@Failure		20004	{object}	response.BasicResponse[any]	"用户不存在，code=20004"
+//	@Failure		20026	{object}	response.BasicResponse[any]	"不能管理自己或系统管理员，code=20026"
+//	@Router			/user/assign [post]
+func (r *Api) AssignUser(ctx *gin.Context) {
+	var params request.AssignUserRequest
+	if err := ctx.ShouldBindJSON(&params); err != nil {
+		response.ParamsValidateFail(ctx, err)
+		return
+	}
+	claims, err := utils.GetContextClaims(ctx)
+	if err != nil || claims == nil {
+		response.FailWithCode(ctx, response.AuthErr)
+		return
+	}
+	if err = r.service.AssignUser(ctx, claims.Operator().ID, &params); err != nil {
+		response.FailWithError(ctx, err)
+		return
+	}
+	response.Success(ctx)
+}
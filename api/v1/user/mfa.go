@@ -0,0 +1,157 @@
+package user
+
+import (
+	"github.com/supuwoerc/weaver/pkg/request"
+	"github.com/supuwoerc/weaver/pkg/response"
+	"github.com/supuwoerc/weaver/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LoginMFA
+//
+//	@Summary		两步验证登录
+//	@Description	使用登录接口返回的 mfa_ticket 和 TOTP 验证码(或恢复码)换取 token,凭证只能使用一次,验证码错误时需要重新登录
+//	@Tags			用户管理
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		request.LoginMFARequest							true	"两步验证登录请求参数"
+//	@Success		10000	{object}	response.BasicResponse[response.LoginResponse]	"登录成功，code=10000"
+//	@Failure		10002	{object}	response.BasicResponse[any]						"参数验证失败，code=10002"
+//	@Failure		20015	{object}	response.BasicResponse[any]						"验证码错误，code=20015"
+//	@Failure		20016	{object}	response.BasicResponse[any]						"登录凭证无效，code=20016"
+//	@Router			/public/user/login/mfa [post]
+func (r *Api) LoginMFA(ctx *gin.Context) {
+	var params request.LoginMFARequest
+	if err := ctx.ShouldBindJSON(&params); err != nil {
+		response.ParamsValidateFail(ctx, err)
+		return
+	}
+	res, err := r.service.LoginMFA(ctx, params.Ticket, params.Code)
+	if err != nil {
+		response.FailWithError(ctx, err)
+		return
+	}
+	response.SuccessWithData(ctx, res)
+}
+
+// EnrollMFA
+//
+//	@Summary		绑定两步验证
+//	@Description	生成 TOTP 密钥及二维码,使用认证器App扫码后调用 /user/mfa/verify 完成绑定
+//	@Tags			用户管理
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		10000	{object}	response.BasicResponse[response.MFAEnrollResponse]	"生成成功，code=10000"
+//	@Failure		20014	{object}	response.BasicResponse[any]							"已开启两步验证，code=20014"
+//	@Router			/user/mfa/enroll [post]
+func (r *Api) EnrollMFA(ctx *gin.Context) {
+	claims, err := utils.GetContextClaims(ctx)
+	if err != nil || claims == nil {
+		response.FailWithCode(ctx, response.UserNotExist)
+		return
+	}
+	res, err := r.service.EnrollMFA(ctx, claims.User.ID, claims.User.Email)
+	if err != nil {
+		response.FailWithError(ctx, err)
+		return
+	}
+	response.SuccessWithData(ctx, res)
+}
+
+// VerifyMFA
+//
+//	@Summary		开启两步验证
+//	@Description	校验认证器App生成的验证码,校验通过后开启两步验证并返回恢复码(仅返回一次)
+//	@Tags			用户管理
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		request.MFACodeRequest										true	"验证码"
+//	@Success		10000	{object}	response.BasicResponse[response.MFARecoveryCodesResponse]	"开启成功，code=10000"
+//	@Failure		20013	{object}	response.BasicResponse[any]									"未绑定两步验证，code=20013"
+//	@Failure		20015	{object}	response.BasicResponse[any]									"验证码错误，code=20015"
+//	@Router			/user/mfa/verify [post]
+func (r *Api) VerifyMFA(ctx *gin.Context) {
+	var params request.MFACodeRequest
+	if err := ctx.ShouldBindJSON(&params); err != nil {
+		response.ParamsValidateFail(ctx, err)
+		return
+	}
+	claims, err := utils.GetContextClaims(ctx)
+	if err != nil || claims == nil {
+		response.FailWithCode(ctx, response.UserNotExist)
+		return
+	}
+	res, err := r.service.VerifyMFA(ctx, claims.User.ID, params.Code)
+	if err != nil {
+		response.FailWithError(ctx, err)
+		return
+	}
+	response.SuccessWithData(ctx, res)
+}
+
+// RegenerateRecoveryCodes
+//
+//	@Summary		重新生成恢复码
+//	@Description	校验 TOTP 验证码后重新生成恢复码,旧的恢复码全部失效
+//	@Tags			用户管理
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		request.MFACodeRequest										true	"验证码"
+//	@Success		10000	{object}	response.BasicResponse[response.MFARecoveryCodesResponse]	"生成成功，code=10000"
+//	@Failure		20013	{object}	response.BasicResponse[any]									"未开启两步验证，code=20013"
+//	@Failure		20015	{object}	response.BasicResponse[any]									"验证码错误，code=20015"
+//	@Router			/user/mfa/recovery-codes [post]
+func (r *Api) RegenerateRecoveryCodes(ctx *gin.Context) {
+	var params request.MFACodeRequest
+	if err := ctx.ShouldBindJSON(&params); err != nil {
+		response.ParamsValidateFail(ctx, err)
+		return
+	}
+	claims, err := utils.GetContextClaims(ctx)
+	if err != nil || claims == nil {
+		response.FailWithCode(ctx, response.UserNotExist)
+		return
+	}
+	res, err := r.service.RegenerateRecoveryCodes(ctx, claims.User.ID, params.Code)
+	if err != nil {
+		response.FailWithError(ctx, err)
+		return
+	}
+	response.SuccessWithData(ctx, res)
+}
+
+// DisableMFA
+//
+//	@Summary		关闭两步验证
+//	@Description	校验 TOTP 验证码或恢复码后关闭两步验证
+//	@Tags			用户管理
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		request.MFACodeRequest		true	"验证码或恢复码"
+//	@Success		10000	{object}	response.BasicResponse[any]	"关闭成功，code=10000"
+//	@Failure		20013	{object}	response.BasicResponse[any]	"未开启两步验证，code=20013"
+//	@Failure		20015	{object}	response.BasicResponse[any]	"验证码错误，code=20015"
+//	@Router			/user/mfa/disable [post]
+func (r *Api) DisableMFA(ctx *gin.Context) {
+	var params request.MFACodeRequest
+	if err := ctx.ShouldBindJSON(&params); err != nil {
+		response.ParamsValidateFail(ctx, err)
+		return
+	}
+	claims, err := utils.GetContextClaims(ctx)
+	if err != nil || claims == nil {
+		response.FailWithCode(ctx, response.UserNotExist)
+		return
+	}
+	err = r.service.DisableMFA(ctx, claims.User.ID, params.Code)
+	if err != nil {
+		response.FailWithError(ctx, err)
+		return
+	}
+	response.Success(ctx)
+}
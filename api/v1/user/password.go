@@ -0,0 +1,36 @@
+package user
+
+import (
+	"github.com/supuwoerc/weaver/pkg/request"
+	"github.com/supuwoerc/weaver/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ChangeExpiredPassword
+//
+//	@Summary		修改过期密码
+//	@Description	使用登录接口返回的 password_ticket 修改超过有效期的密码,修改成功后其他登录会话全部失效并继续完成登录,
+//	@Description	开启两步验证的用户返回 mfa_ticket
+//	@Tags			用户管理
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		request.ChangeExpiredPasswordRequest			true	"修改过期密码请求参数"
+//	@Success		10000	{object}	response.BasicResponse[response.LoginResponse]	"修改成功，code=10000"
+//	@Failure		10002	{object}	response.BasicResponse[any]						"参数验证失败，code=10002"
+//	@Failure		20033	{object}	response.BasicResponse[any]						"密码不符合密码策略，code=20033~20040"
+//	@Failure		20041	{object}	response.BasicResponse[any]						"修改密码凭证无效，code=20041"
+//	@Router			/public/user/password/expired [post]
+func (r *Api) ChangeExpiredPassword(ctx *gin.Context) {
+	var params request.ChangeExpiredPasswordRequest
+	if err := ctx.ShouldBindJSON(&params); err != nil {
+		response.ParamsValidateFail(ctx, err)
+		return
+	}
+	res, err := r.service.ChangeExpiredPassword(ctx, params.Ticket, params.Password)
+	if err != nil {
+		response.FailWithError(ctx, err)
+		return
+	}
+	response.SuccessWithData(ctx, res)
+}
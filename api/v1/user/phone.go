@@ -0,0 +1,159 @@
+package user
+
+import (
+	"github.com/supuwoerc/weaver/pkg/request"
+	"github.com/supuwoerc/weaver/pkg/response"
+	"github.com/supuwoerc/weaver/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// validPhone 校验手机号格式,格式错误时直接响应
+func (r *Api) validPhone(ctx *gin.Context, phone string) bool {
+	valid, err := r.phoneRegexExp.MatchString(phone)
+	if err != nil || !valid {
+		response.HttpResponse[any](ctx, response.PhoneValidErr, nil, nil, nil)
+		return false
+	}
+	return true
+}
+
+// SendBindPhoneCode
+//
+//	@Summary		发送绑定手机号验证码
+//	@Description	向待绑定的手机号发送短信验证码,同一手机号受重新发送间隔和每日发送次数限制
+//	@Tags			用户管理
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		request.SendBindPhoneCodeRequest	true	"发送绑定手机号验证码请求参数"
+//	@Success		10000	{object}	response.BasicResponse[any]			"发送成功，code=10000"
+//	@Failure		10002	{object}	response.BasicResponse[any]			"参数验证失败，code=10002"
+//	@Failure		20042	{object}	response.BasicResponse[any]			"手机号格式错误，code=20042"
+//	@Failure		20044	{object}	response.BasicResponse[any]			"发送过于频繁，code=20044"
+//	@Failure		20045	{object}	response.BasicResponse[any]			"手机号已被其他账户绑定，code=20045"
+//	@Failure		20029	{object}	response.BasicResponse[any]			"模拟登录期间不允许该操作，code=20029"
+//	@Failure		10001	{object}	response.BasicResponse[any]			"服务器内部错误，code=10001"
+//	@Router			/user/phone/code [post]
+func (r *Api) SendBindPhoneCode(ctx *gin.Context) {
+	var params request.SendBindPhoneCodeRequest
+	if err := ctx.ShouldBindJSON(&params); err != nil {
+		response.ParamsValidateFail(ctx, err)
+		return
+	}
+	if !r.validPhone(ctx, params.Phone) {
+		return
+	}
+	claims, err := utils.GetContextClaims(ctx)
+	if err != nil || claims == nil {
+		response.FailWithCode(ctx, response.UserNotExist)
+		return
+	}
+	if err = r.service.SendBindPhoneCode(ctx, claims.User.ID, params.Phone); err != nil {
+		response.FailWithError(ctx, err)
+		return
+	}
+	response.Success(ctx)
+}
+
+// BindPhone
+//
+//	@Summary		绑定手机号
+//	@Description	校验短信验证码后绑定手机号,已绑定的手机号会被替换,绑定后可使用手机号验证码登录
+//	@Tags			用户管理
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		request.BindPhoneRequest	true	"绑定手机号请求参数"
+//	@Success		10000	{object}	response.BasicResponse[any]	"绑定成功，code=10000"
+//	@Failure		10002	{object}	response.BasicResponse[any]	"参数验证失败，code=10002"
+//	@Failure		20042	{object}	response.BasicResponse[any]	"手机号格式错误，code=20042"
+//	@Failure		20043	{object}	response.BasicResponse[any]	"验证码错误或已过期，code=20043"
+//	@Failure		20045	{object}	response.BasicResponse[any]	"手机号已被其他账户绑定，code=20045"
+//	@Failure		20029	{object}	response.BasicResponse[any]	"模拟登录期间不允许该操作，code=20029"
+//	@Failure		10001	{object}	response.BasicResponse[any]	"服务器内部错误，code=10001"
+//	@Router			/user/phone/bind [post]
+func (r *Api) BindPhone(ctx *gin.Context) {
+	var params request.BindPhoneRequest
+	if err := ctx.ShouldBindJSON(&params); err != nil {
+		response.ParamsValidateFail(ctx, err)
+		return
+	}
+	if !r.validPhone(ctx, params.Phone) {
+		return
+	}
+	claims, err := utils.GetContextClaims(ctx)
+	if err != nil || claims == nil {
+		response.FailWithCode(ctx, response.UserNotExist)
+		return
+	}
+	if err = r.service.BindPhone(ctx, claims.User.ID, params.Phone, params.Code); err != nil {
+		response.FailWithError(ctx, err)
+		return
+	}
+	response.Success(ctx)
+}
+
+// SendLoginSMSCode
+//
+//	@Summary		发送登录验证码
+//	@Description	校验图形验证码(登录验证码)后向手机号发送登录短信验证码,手机号未绑定时同样返回成功
+//	@Tags			用户管理
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		request.SendLoginSMSCodeRequest	true	"发送登录验证码请求参数"
+//	@Success		10000	{object}	response.BasicResponse[any]		"发送成功，code=10000"
+//	@Failure		10002	{object}	response.BasicResponse[any]		"参数验证失败，code=10002"
+//	@Failure		20042	{object}	response.BasicResponse[any]		"手机号格式错误，code=20042"
+//	@Failure		20044	{object}	response.BasicResponse[any]		"发送过于频繁，code=20044"
+//	@Failure		30000	{object}	response.BasicResponse[any]		"图形验证码错误，code=30000"
+//	@Failure		10001	{object}	response.BasicResponse[any]		"服务器内部错误，code=10001"
+//	@Router			/public/user/sms/login-code [post]
+func (r *Api) SendLoginSMSCode(ctx *gin.Context) {
+	var params request.SendLoginSMSCodeRequest
+	if err := ctx.ShouldBindJSON(&params); err != nil {
+		response.ParamsValidateFail(ctx, err)
+		return
+	}
+	if !r.validPhone(ctx, params.Phone) {
+		return
+	}
+	if err := r.service.SendLoginSMSCode(ctx, params.ID, params.Code, params.Phone); err != nil {
+		response.FailWithError(ctx, err)
+		return
+	}
+	response.Success(ctx)
+}
+
+// LoginSMS
+//
+//	@Summary		手机号验证码登录
+//	@Description	使用绑定的手机号和短信验证码登录,开启两步验证的用户返回 mfa_ticket,需要调用 /public/user/login/mfa 完成登录
+//	@Tags			用户管理
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		request.LoginSMSRequest							true	"手机号验证码登录请求参数"
+//	@Success		10000	{object}	response.BasicResponse[response.LoginResponse]	"登录成功，code=10000"
+//	@Failure		10002	{object}	response.BasicResponse[any]						"参数验证失败，code=10002"
+//	@Failure		20042	{object}	response.BasicResponse[any]						"手机号格式错误，code=20042"
+//	@Failure		20043	{object}	response.BasicResponse[any]						"验证码错误或已过期，code=20043"
+//	@Failure		20005	{object}	response.BasicResponse[any]						"用户未激活，code=20005"
+//	@Failure		20006	{object}	response.BasicResponse[any]						"用户已禁用，code=20006"
+//	@Failure		10001	{object}	response.BasicResponse[any]						"服务器内部错误，code=10001"
+//	@Router			/public/user/login/sms [post]
+func (r *Api) LoginSMS(ctx *gin.Context) {
+	var params request.LoginSMSRequest
+	if err := ctx.ShouldBindJSON(&params); err != nil {
+		response.ParamsValidateFail(ctx, err)
+		return
+	}
+	if !r.validPhone(ctx, params.Phone) {
+		return
+	}
+	res, err := r.service.LoginSMS(ctx, params.Phone, params.Code, r.loginClient(ctx, params.Device))
+	if err != nil {
+		response.FailWithError(ctx, err)
+		return
+	}
+	response.SuccessWithData(ctx, res)
+}
@@ -0,0 +1,92 @@
+package user
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"path"
+
+	"github.com/supuwoerc/weaver/pkg/constant"
+	"github.com/supuwoerc/weaver/pkg/request"
+	"github.com/supuwoerc/weaver/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetSSOProviders
+//
+//	@Summary		获取单点登录身份提供方
+//	@Description	获取已配置的单点登录身份提供方标识列表
+//	@Tags			用户管理
+//	@Accept			json
+//	@Produce		json
+//	@Success		10000	{object}	response.BasicResponse[[]string]	"获取成功，code=10000"
+//	@Router			/public/user/sso/providers [get]
+func (r *Api) GetSSOProviders(ctx *gin.Context) {
+	response.SuccessWithData(ctx, r.service.GetSSOProviders(ctx))
+}
+
+// SSOAuthorize
+//
+//	@Summary		获取单点登录授权地址
+//	@Description	生成身份提供方的授权地址(授权码模式 + PKCE),前端跳转至该地址完成认证,state同时写入HttpOnly的cookie,登录时校验
+//	@Tags			用户管理
+//	@Accept			json
+//	@Produce		json
+//	@Param			provider	path		string													true	"身份提供方标识"
+//	@Success		10000		{object}	response.BasicResponse[response.SSOAuthorizeResponse]	"获取成功，code=10000"
+//	@Failure		20017		{object}	response.BasicResponse[any]								"身份提供方不存在，code=20017"
+//	@Router			/public/user/sso/{provider}/authorize [get]
+func (r *Api) SSOAuthorize(ctx *gin.Context) {
+	res, state, err := r.service.SSOAuthorize(ctx, ctx.Param("provider"))
+	if err != nil {
+		response.FailWithError(ctx, err)
+		return
+	}
+	r.setSSOStateCookie(ctx, state, int(r.Conf.SSO.StateExpiration))
+	response.SuccessWithData(ctx, res)
+}
+
+// setSSOStateCookie 写入单点登录的state cookie,作用于同一身份提供方的授权及登录接口,maxAge小于0时删除
+func (r *Api) setSSOStateCookie(ctx *gin.Context, state string, maxAge int) {
+	ctx.SetSameSite(http.SameSiteLaxMode)
+	ctx.SetCookie(constant.SSOStateCookie, state, maxAge, path.Dir(ctx.Request.URL.Path), "",
+		r.Conf.System.Scheme == "https", true)
+}
+
+// LoginSSO
+//
+//	@Summary		单点登录
+//	@Description	使用身份提供方回调的 code 和 state 登录,state需要与发起授权时写入的cookie一致,开启两步验证的用户返回 mfa_ticket,需要调用 /public/user/login/mfa 完成登录
+//	@Tags			用户管理
+//	@Accept			json
+//	@Produce		json
+//	@Param			provider	path		string											true	"身份提供方标识"
+//	@Param			request		body		request.SSOLoginRequest							true	"单点登录请求参数"
+//	@Success		10000		{object}	response.BasicResponse[response.LoginResponse]	"登录成功，code=10000"
+//	@Failure		10002		{object}	response.BasicResponse[any]						"参数验证失败，code=10002"
+//	@Failure		20017		{object}	response.BasicResponse[any]						"身份提供方不存在，code=20017"
+//	@Failure		20018		{object}	response.BasicResponse[any]						"state无效或已过期，code=20018"
+//	@Failure		20019		{object}	response.BasicResponse[any]						"单点登录失败，code=20019"
+//	@Failure		20030		{object}	response.BasicResponse[any]						"注册已关闭，未关联的外部账户不能自动创建用户，code=20030"
+//	@Router			/public/user/sso/{provider}/login [post]
+func (r *Api) LoginSSO(ctx *gin.Context) {
+	var params request.SSOLoginRequest
+	if err := ctx.ShouldBindJSON(&params); err != nil {
+		response.ParamsValidateFail(ctx, err)
+		return
+	}
+	// state需要由发起授权的浏览器提交,避免登录CSRF
+	cookie, err := ctx.Cookie(constant.SSOStateCookie)
+	r.setSSOStateCookie(ctx, "", -1)
+	if err != nil || subtle.ConstantTimeCompare([]byte(cookie), []byte(params.State)) != 1 {
+		response.FailWithError(ctx, response.InvalidSSOState)
+		return
+	}
+	client := r.loginClient(ctx, params.Device)
+	res, err := r.service.LoginSSO(ctx, ctx.Param("provider"), params.Code, params.State, client)
+	if err != nil {
+		response.FailWithError(ctx, err)
+		return
+	}
+	response.SuccessWithData(ctx, res)
+}
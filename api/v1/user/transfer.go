@@ -0,0 +1,119 @@
+package user
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/supuwoerc/weaver/models"
+	"github.com/supuwoerc/weaver/pkg/request"
+	"github.com/supuwoerc/weaver/pkg/response"
+	"github.com/supuwoerc/weaver/pkg/spreadsheet"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ImportUsers
+//
+//	@Summary		批量导入用户
+//	@Description	上传csv或xlsx文件批量导入用户,首行为表头,包含email、nickname、departments(部门路径,如"总部/研发部",多个用逗号分隔)和roles(角色名称,多个用逗号分隔)列;
+//	@Description	全部行校验通过后才会按批次创建用户,存在校验错误时返回每一行的错误且不创建任何用户
+//	@Tags			用户管理
+//	@Accept			multipart/form-data
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			file				formData	file													true	"csv或xlsx文件"
+//	@Param			dry_run				formData	bool													false	"是否仅校验"
+//	@Param			send_active_email	formData	bool													false	"是否发送激活邮件"
+//	@Success		10000				{object}	response.BasicResponse[response.ImportUsersResponse]	"导入完成，code=10000"
+//	@Failure		10002				{object}	response.BasicResponse[any]								"参数验证失败，code=10002"
+//	@Failure		20027				{object}	response.BasicResponse[any]								"导入文件无效，code=20027"
+//	@Failure		20028				{object}	response.BasicResponse[any]								"导入文件行数超过限制，code=20028"
+//	@Failure		10001				{object}	response.BasicResponse[any]								"服务器内部错误，code=10001"
+//	@Router			/user/import [post]
+func (r *Api) ImportUsers(ctx *gin.Context) {
+	var params request.ImportUsersRequest
+	if err := ctx.ShouldBind(&params); err != nil {
+		response.ParamsValidateFail(ctx, err)
+		return
+	}
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		response.FailWithCode(ctx, response.InvalidParams)
+		return
+	}
+	format, err := spreadsheet.FormatOf(fileHeader.Filename)
+	if err != nil {
+		response.FailWithCode(ctx, response.InvalidImportFile)
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		response.FailWithError(ctx, err)
+		return
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	result, err := r.service.ImportUsers(ctx, file, format, params.DryRun, params.SendActiveEmail)
+	if err != nil {
+		response.FailWithError(ctx, err)
+		return
+	}
+	for _, item := range result.Errors {
+		item.Message = response.Localize(ctx, item.Code)
+	}
+	response.SuccessWithData(ctx, result)
+}
+
+// ExportUsers
+//
+//	@Summary		导出用户
+//	@Description	按条件导出用户为csv或xlsx文件,导出的文件可以直接用于批量导入
+//	@Tags			用户管理
+//	@Accept			json
+//	@Produce		octet-stream
+//	@Security		BearerAuth
+//	@Param			keyword			query		string						false	"搜索关键词"
+//	@Param			status			query		int							false	"账户状态"
+//	@Param			role_id			query		int							false	"角色ID"
+//	@Param			department_id	query		int							false	"部门ID"
+//	@Param			format			query		string						false	"文件格式"	Enums(csv, xlsx)	default(csv)
+//	@Success		200				{file}		file						"导出的文件"
+//	@Failure		10002			{object}	response.BasicResponse[any]	"参数验证失败，code=10002"
+//	@Failure		10001			{object}	response.BasicResponse[any]	"服务器内部错误，code=10001"
+//	@Router			/user/export [get]
+func (r *Api) ExportUsers(ctx *gin.Context) {
+	var params request.ExportUsersRequest
+	if err := ctx.ShouldBindQuery(&params); err != nil {
+		response.ParamsValidateFail(ctx, err)
+		return
+	}
+	format := spreadsheet.CSV
+	if params.Format != "" {
+		format = spreadsheet.Format(params.Format)
+	}
+	rows, err := r.service.ExportUsers(ctx, toUserFilter(&params.UserFilterRequest))
+	if err != nil {
+		response.FailWithError(ctx, err)
+		return
+	}
+	var buf bytes.Buffer
+	if err = spreadsheet.Write(&buf, format, rows); err != nil {
+		response.FailWithError(ctx, err)
+		return
+	}
+	filename := fmt.Sprintf("users-%s.%s", time.Now().Format("20060102150405"), format)
+	ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	ctx.Data(http.StatusOK, format.ContentType(), buf.Bytes())
+}
+
+func toUserFilter(params *request.UserFilterRequest) *models.UserFilter {
+	return &models.UserFilter{
+		Keyword:      params.Keyword,
+		Status:       params.Status,
+		RoleID:       params.RoleID,
+		DepartmentID: params.DepartmentID,
+	}
+}
@@ -2,13 +2,18 @@ package user
 
 import (
 	"context"
+	"io"
 	"net/http"
+	"strings"
+	"time"
 
 	v1 "github.com/supuwoerc/weaver/api/v1"
 	"github.com/supuwoerc/weaver/models"
 	"github.com/supuwoerc/weaver/pkg/constant"
+	"github.com/supuwoerc/weaver/pkg/jwt"
 	"github.com/supuwoerc/weaver/pkg/request"
 	"github.com/supuwoerc/weaver/pkg/response"
+	"github.com/supuwoerc/weaver/pkg/spreadsheet"
 	"github.com/supuwoerc/weaver/pkg/utils"
 
 	regexp "github.com/dlclark/regexp2"
@@ -17,44 +22,150 @@ import (
 
 type Service interface {
 	SignUp(ctx context.Context, id string, code string, user *models.User) error
-	Login(ctx context.Context, email string, password string) (*response.LoginResponse, error)
-	Logout(ctx context.Context, email string) error
+	Login(
+		ctx context.Context, email string, password string, id string, code string, client *models.LoginClient,
+	) (*response.LoginResponse, error)
+	UnlockLogin(ctx context.Context, email string, ip string) error
+	RefreshToken(ctx context.Context, refreshToken string, client *models.LoginClient) (*response.RefreshTokenResponse, error)
+	Logout(ctx context.Context, claims *jwt.TokenClaims) error
+	GetSessions(ctx context.Context, uid uint, current string) ([]*response.UserSessionResponse, error)
+	RevokeSessions(ctx context.Context, uid uint, sids []string) error
+	RevokeUserSessions(ctx context.Context, uid uint) error
 	Profile(ctx context.Context, uid uint) (*response.ProfileResponse, error)
-	GetUserList(ctx context.Context, keyword string, limit, offset int) ([]*response.UserListRowResponse, int64, error)
+	GetUserList(ctx context.Context, filter *models.UserFilter, limit, offset int) ([]*response.UserListRowResponse, int64, error)
 	ActiveAccount(ctx context.Context, uid uint, activeCode string) error
+	ForgotPassword(ctx context.Context, id string, code string, email string) error
+	ResetPassword(ctx context.Context, email string, code string, password string) error
+	UpdateProfile(ctx context.Context, uid uint, profile *models.User) error
+	ChangePassword(ctx context.Context, uid uint, sid string, oldPassword, newPassword string) error
+	ChangeEmail(ctx context.Context, uid uint, password string, email string) error
+	ConfirmEmailChange(ctx context.Context, code string) error
+	RevertEmailChange(ctx context.Context, code string) error
+	EnrollMFA(ctx context.Context, uid uint, email string) (*response.MFAEnrollResponse, error)
+	VerifyMFA(ctx context.Context, uid uint, code string) (*response.MFARecoveryCodesResponse, error)
+	RegenerateRecoveryCodes(ctx context.Context, uid uint, code string) (*response.MFARecoveryCodesResponse, error)
+	DisableMFA(ctx context.Context, uid uint, code string) error
+	LoginMFA(ctx context.Context, ticket string, code string) (*response.LoginResponse, error)
+	ChangeExpiredPassword(ctx context.Context, ticket string, password string) (*response.LoginResponse, error)
+	GetSSOProviders(ctx context.Context) []string
+	SSOAuthorize(ctx context.Context, name string) (*response.SSOAuthorizeResponse, string, error)
+	LoginSSO(ctx context.Context, name string, code string, state string, client *models.LoginClient) (*response.LoginResponse, error)
+	GetAPIKeys(ctx context.Context, uid uint) ([]*response.APIKeyResponse, error)
+	CreateAPIKey(ctx context.Context, uid uint, email string, params *request.CreateAPIKeyRequest) (*response.CreateAPIKeyResponse, error)
+	DeleteAPIKey(ctx context.Context, uid uint, id uint) error
+	CreateUser(ctx context.Context, params *request.CreateUserRequest) error
+	UpdateUserStatus(ctx context.Context, operator uint, id uint, status constant.UserStatus) error
+	DeleteUser(ctx context.Context, operator uint, id uint) error
+	RestoreUser(ctx context.Context, id uint) error
+	EraseUser(ctx context.Context, operator uint, id uint) error
+	AssignUser(ctx context.Context, operator uint, params *request.AssignUserRequest) error
+	ImportUsers(
+		ctx context.Context, reader io.Reader, format spreadsheet.Format, dryRun, sendActiveEmail bool,
+	) (*response.ImportUsersResponse, error)
+	ExportUsers(ctx context.Context, filter *models.UserFilter) ([][]string, error)
+	Impersonate(
+		ctx context.Context, actor *jwt.TokenClaimsBasic, session string, ip string, params *request.ImpersonateRequest,
+	) (*response.ImpersonateResponse, error)
+	StopImpersonation(ctx context.Context, claims *jwt.TokenClaims) error
+	GetImpersonations(ctx context.Context, params *request.GetImpersonationsRequest) ([]*models.UserImpersonation, int64, error)
+	CreateInvitation(ctx context.Context, inviter *jwt.TokenClaimsBasic, params *request.CreateInvitationRequest) error
+	GetInvitation(ctx context.Context, code string) (*response.InvitationResponse, error)
+	AcceptInvitation(ctx context.Context, params *request.AcceptInvitationRequest) error
+	GetInvitations(ctx context.Context, params *request.GetInvitationsRequest) ([]*models.UserInvitation, int64, error)
+	RevokeInvitation(ctx context.Context, id uint) error
+	GetLoginHistory(
+		ctx context.Context, uid uint, params *request.GetLoginHistoryRequest,
+	) ([]*models.UserLoginHistory, int64, error)
+	GetLoginHistories(ctx context.Context, params *request.GetLoginHistoriesRequest) ([]*models.UserLoginHistory, int64, error)
+	SendBindPhoneCode(ctx context.Context, uid uint, phone string) error
+	BindPhone(ctx context.Context, uid uint, phone string, code string) error
+	SendLoginSMSCode(ctx context.Context, id string, code string, phone string) error
+	LoginSMS(ctx context.Context, phone string, code string, client *models.LoginClient) (*response.LoginResponse, error)
+	RequestDataExport(ctx context.Context, uid uint) error
+	DownloadDataExport(ctx context.Context, code string) ([]byte, error)
 }
 
 type Api struct {
 	*v1.BasicApi
-	emailRegexExp    *regexp.Regexp
-	passwordRegexExp *regexp.Regexp
-	phoneRegexExp    *regexp.Regexp
-	service          Service
+	emailRegexExp *regexp.Regexp
+	phoneRegexExp *regexp.Regexp
+	service       Service
 }
 
 func NewUserApi(basic *v1.BasicApi, service Service) *Api {
 	userApi := &Api{
-		BasicApi:         basic,
-		emailRegexExp:    regexp.MustCompile(constant.EmailRegexPattern, regexp.None),
-		passwordRegexExp: regexp.MustCompile(constant.PasswdRegexPattern, regexp.None),
-		phoneRegexExp:    regexp.MustCompile(constant.PhoneRegexPattern, regexp.None),
-		service:          service,
+		BasicApi:      basic,
+		emailRegexExp: regexp.MustCompile(constant.EmailRegexPattern, regexp.None),
+		phoneRegexExp: regexp.MustCompile(constant.PhoneRegexPattern, regexp.None),
+		service:       service,
 	}
 	// 挂载路由
 	userPublicGroup := basic.Route.Group("public/user")
 	{
 		userPublicGroup.POST("signup", userApi.SignUp)
 		userPublicGroup.POST("login", userApi.Login)
+		userPublicGroup.POST("login/mfa", userApi.LoginMFA)
+		userPublicGroup.POST("login/sms", userApi.LoginSMS)
+		userPublicGroup.POST("sms/login-code", userApi.SendLoginSMSCode)
+		userPublicGroup.POST("password/expired", userApi.ChangeExpiredPassword)
 		userPublicGroup.GET("active", userApi.Active)
 		userPublicGroup.GET("active-success", userApi.ActiveSuccess)
 		userPublicGroup.GET("active-failure", userApi.ActiveFailure)
+		userPublicGroup.POST("forgot-password", userApi.ForgotPassword)
+		userPublicGroup.POST("reset-password", userApi.ResetPassword)
+		userPublicGroup.GET("sso/providers", userApi.GetSSOProviders)
+		userPublicGroup.GET("sso/:provider/authorize", userApi.SSOAuthorize)
+		userPublicGroup.POST("sso/:provider/login", userApi.LoginSSO)
+		userPublicGroup.POST("email/confirm", userApi.ConfirmEmailChange)
+		userPublicGroup.POST("email/revert", userApi.RevertEmailChange)
+		userPublicGroup.GET("invitation", userApi.GetInvitation)
+		userPublicGroup.POST("invitation/accept", userApi.AcceptInvitation)
+		userPublicGroup.GET("data-export", userApi.DownloadDataExport)
 	}
-	userAccessGroup := basic.Route.Group("user").Use(basic.Auth.LoginRequired())
+	// 刷新 token 时短 token 已过期,仅通过 refresh token 鉴权
+	userRefreshGroup := basic.Route.Group("user")
+	{
+		userRefreshGroup.POST("refresh-token", userApi.RefreshToken)
+	}
+	userAccessGroup := basic.Route.Group("user", basic.Auth.LoginRequired())
+	userPermissionGroup := basic.Auth.PermissionGroup(userAccessGroup)
 	{
-		userAccessGroup.POST("refresh-token")
 		userAccessGroup.GET("profile", userApi.Profile)
+		userAccessGroup.POST("profile/update", basic.Auth.NotImpersonated(), userApi.UpdateProfile)
+		userAccessGroup.POST("password/change", basic.Auth.NotImpersonated(), userApi.ChangePassword)
+		userAccessGroup.POST("email/change", basic.Auth.NotImpersonated(), userApi.ChangeEmail)
+		userAccessGroup.POST("mfa/enroll", basic.Auth.NotImpersonated(), userApi.EnrollMFA)
+		userAccessGroup.POST("mfa/verify", basic.Auth.NotImpersonated(), userApi.VerifyMFA)
+		userAccessGroup.POST("mfa/recovery-codes", basic.Auth.NotImpersonated(), userApi.RegenerateRecoveryCodes)
+		userAccessGroup.POST("mfa/disable", basic.Auth.NotImpersonated(), userApi.DisableMFA)
 		userAccessGroup.POST("logout", userApi.Logout)
-		userAccessGroup.GET("list", basic.Auth.PermissionRequired(), userApi.GetUserList)
+		userPermissionGroup.GET("list", userApi.GetUserList)
+		userPermissionGroup.POST("create", userApi.CreateUser)
+		userPermissionGroup.POST("status", userApi.UpdateUserStatus)
+		userPermissionGroup.POST("delete", userApi.DeleteUser)
+		userPermissionGroup.POST("restore", userApi.RestoreUser)
+		userPermissionGroup.POST("erase", userApi.EraseUser)
+		userPermissionGroup.POST("assign", userApi.AssignUser)
+		userPermissionGroup.POST("import", userApi.ImportUsers)
+		userPermissionGroup.GET("export", userApi.ExportUsers)
+		userPermissionGroup.POST("impersonate", basic.Auth.NotImpersonated(), userApi.Impersonate)
+		userAccessGroup.POST("impersonate/stop", userApi.StopImpersonation)
+		userPermissionGroup.GET("impersonations", userApi.GetImpersonations)
+		userPermissionGroup.GET("invitations", userApi.GetInvitations)
+		userPermissionGroup.POST("invitations/create", userApi.CreateInvitation)
+		userPermissionGroup.POST("invitations/revoke", userApi.RevokeInvitation)
+		userAccessGroup.GET("sessions", userApi.GetSessions)
+		userAccessGroup.POST("sessions/revoke", basic.Auth.NotImpersonated(), userApi.RevokeSessions)
+		userPermissionGroup.POST("sessions/revoke-all", userApi.RevokeUserSessions)
+		userAccessGroup.GET("login-history", userApi.GetLoginHistory)
+		userAccessGroup.POST("data-export", basic.Auth.NotImpersonated(), userApi.RequestDataExport)
+		userAccessGroup.POST("phone/code", basic.Auth.NotImpersonated(), userApi.SendBindPhoneCode)
+		userAccessGroup.POST("phone/bind", basic.Auth.NotImpersonated(), userApi.BindPhone)
+		userPermissionGroup.GET("login-histories", userApi.GetLoginHistories)
+		userPermissionGroup.POST("login/unlock", userApi.UnlockLogin)
+		userAccessGroup.GET("api-keys", userApi.GetAPIKeys)
+		userAccessGroup.POST("api-keys/create", basic.Auth.NotImpersonated(), userApi.CreateAPIKey)
+		userAccessGroup.POST("api-keys/delete", basic.Auth.NotImpersonated(), userApi.DeleteAPIKey)
 	}
 	return userApi
 }
@@ -70,7 +181,8 @@ func NewUserApi(basic *v1.BasicApi, service Service) *Api {
 //	@Success		10000	{object}	response.BasicResponse[any]	"注册成功，code=10000"
 //	@Failure		10002	{object}	response.BasicResponse[any]	"参数验证失败，code=10002"
 //	@Failure		20004	{object}	response.BasicResponse[any]	"邮箱格式错误，code=20004"
-//	@Failure		20003	{object}	response.BasicResponse[any]	"密码格式错误，code=20003"
+//	@Failure		20033	{object}	response.BasicResponse[any]	"密码不符合密码策略，code=20033~20039"
+//	@Failure		20030	{object}	response.BasicResponse[any]	"注册已关闭，code=20030"
 //	@Failure		10001	{object}	response.BasicResponse[any]	"业务逻辑失败，code=10001"
 //	@Router			/public/user/signup [post]
 func (r *Api) SignUp(ctx *gin.Context) {
@@ -84,11 +196,6 @@ func (r *Api) SignUp(ctx *gin.Context) {
 		response.HttpResponse[any](ctx, response.EmailValidErr, nil, nil, nil)
 		return
 	}
-	passwordValid, err := r.passwordRegexExp.MatchString(params.Password)
-	if err != nil || !passwordValid {
-		response.HttpResponse[any](ctx, response.PasswordValidErr, nil, nil, nil)
-		return
-	}
 	err = r.service.SignUp(ctx, params.ID, params.Code, &models.User{
 		Email:    params.Email,
 		Password: params.Password,
@@ -103,14 +210,20 @@ func (r *Api) SignUp(ctx *gin.Context) {
 // Login
 //
 //	@Summary		用户登录
-//	@Description	用户通过邮箱和密码进行登录
+//	@Description	用户通过邮箱和密码进行登录,开启两步验证的用户返回 mfa_ticket,需要调用 /public/user/login/mfa 完成登录;
+//	@Description	密码超过有效期的用户返回 password_ticket,需要调用 /public/user/password/expired 修改密码后完成登录;
+//	@Description	登录失败后需要等待一段时间才能重试,失败次数较多时需要携带 /public/captcha/login 的验证码,达到阈值后临时锁定
 //	@Tags			用户管理
 //	@Accept			json
 //	@Produce		json
 //	@Param			request	body		request.LoginRequest							true	"登录请求参数"
 //	@Success		10000	{object}	response.BasicResponse[response.LoginResponse]	"登录成功，code=10000"
 //	@Failure		10002	{object}	response.BasicResponse[any]						"参数验证失败，code=10002"
+//	@Failure		10010	{object}	response.BasicResponse[any]						"登录失败后的等待期内，code=10010"
 //	@Failure		20001	{object}	response.BasicResponse[any]						"登录失败，code=20001"
+//	@Failure		20024	{object}	response.BasicResponse[any]						"账户已被临时锁定，code=20024"
+//	@Failure		20025	{object}	response.BasicResponse[any]						"需要验证码，code=20025"
+//	@Failure		30000	{object}	response.BasicResponse[any]						"验证码错误，code=30000"
 //	@Router			/public/user/login [post]
 func (r *Api) Login(ctx *gin.Context) {
 	var params request.LoginRequest
@@ -118,7 +231,89 @@ func (r *Api) Login(ctx *gin.Context) {
 		response.ParamsValidateFail(ctx, err)
 		return
 	}
-	res, err := r.service.Login(ctx, params.Email, params.Password)
+	client := r.loginClient(ctx, params.Device)
+	res, err := r.service.Login(ctx, params.Email, params.Password, params.ID, params.Code, client)
+	if err != nil {
+		response.FailWithError(ctx, err)
+		return
+	}
+	response.SuccessWithData(ctx, res)
+}
+
+// ForgotPassword
+//
+//	@Summary		找回密码
+//	@Description	向邮箱发送重置密码的验证码,邮箱未注册时同样返回成功
+//	@Tags			用户管理
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		request.ForgotPasswordRequest	true	"找回密码请求参数"
+//	@Success		10000	{object}	response.BasicResponse[any]		"发送成功，code=10000"
+//	@Failure		10002	{object}	response.BasicResponse[any]		"参数验证失败，code=10002"
+//	@Failure		30000	{object}	response.BasicResponse[any]		"验证码错误，code=30000"
+//	@Failure		20011	{object}	response.BasicResponse[any]		"请求过于频繁，code=20011"
+//	@Router			/public/user/forgot-password [post]
+func (r *Api) ForgotPassword(ctx *gin.Context) {
+	var params request.ForgotPasswordRequest
+	if err := ctx.ShouldBindJSON(&params); err != nil {
+		response.ParamsValidateFail(ctx, err)
+		return
+	}
+	err := r.service.ForgotPassword(ctx, params.ID, params.Code, params.Email)
+	if err != nil {
+		response.FailWithError(ctx, err)
+		return
+	}
+	response.Success(ctx)
+}
+
+// ResetPassword
+//
+//	@Summary		重置密码
+//	@Description	使用邮件中的验证码重置密码,重置成功后用户在全部设备上的登录会话失效
+//	@Tags			用户管理
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		request.ResetPasswordRequest	true	"重置密码请求参数"
+//	@Success		10000	{object}	response.BasicResponse[any]		"重置成功，code=10000"
+//	@Failure		10002	{object}	response.BasicResponse[any]		"参数验证失败，code=10002"
+//	@Failure		20033	{object}	response.BasicResponse[any]		"密码不符合密码策略，code=20033~20040"
+//	@Failure		20010	{object}	response.BasicResponse[any]		"验证码错误或已过期，code=20010"
+//	@Failure		20011	{object}	response.BasicResponse[any]		"请求过于频繁，code=20011"
+//	@Router			/public/user/reset-password [post]
+func (r *Api) ResetPassword(ctx *gin.Context) {
+	var params request.ResetPasswordRequest
+	if err := ctx.ShouldBindJSON(&params); err != nil {
+		response.ParamsValidateFail(ctx, err)
+		return
+	}
+	err := r.service.ResetPassword(ctx, params.Email, params.Code, params.Password)
+	if err != nil {
+		response.FailWithError(ctx, err)
+		return
+	}
+	response.Success(ctx)
+}
+
+// RefreshToken
+//
+//	@Summary		刷新token
+//	@Description	使用 refresh token 换取新的 token 和 refresh token,旧的 refresh token 随即失效,重复使用会使该次登录签发的全部 refresh token 失效
+//	@Tags			用户管理
+//	@Accept			json
+//	@Produce		json
+//	@Param			Refresh-Token	header		string													true	"refresh token"
+//	@Success		10000			{object}	response.BasicResponse[response.RefreshTokenResponse]	"刷新成功，code=10000"
+//	@Failure		10006			{object}	response.BasicResponse[any]								"refresh token 无效，code=10006"
+//	@Failure		10011			{object}	response.BasicResponse[any]								"refresh token 被重复使用，code=10011"
+//	@Router			/user/refresh-token [post]
+func (r *Api) RefreshToken(ctx *gin.Context) {
+	refreshToken := strings.TrimSpace(ctx.GetHeader(r.Conf.JWT.RefreshTokenKey))
+	if refreshToken == "" {
+		response.FailWithCode(ctx, response.InvalidRefreshToken)
+		return
+	}
+	res, err := r.service.RefreshToken(ctx, refreshToken, r.loginClient(ctx, ""))
 	if err != nil {
 		response.FailWithError(ctx, err)
 		return
@@ -129,7 +324,7 @@ func (r *Api) Login(ctx *gin.Context) {
 // Logout
 //
 //	@Summary		退出登录
-//	@Description	用户登出(退出登录)
+//	@Description	用户登出(退出登录),仅注销当前会话
 //	@Tags			用户管理
 //	@Accept			json
 //	@Produce		json
@@ -142,7 +337,63 @@ func (r *Api) Logout(ctx *gin.Context) {
 		response.FailWithCode(ctx, response.UserNotExist)
 		return
 	}
-	err = r.service.Logout(ctx, claims.User.Email)
+	if err = r.service.Logout(ctx, claims); err != nil {
+		response.FailWithError(ctx, err)
+		return
+	}
+	response.Success(ctx)
+}
+
+// GetSessions
+//
+//	@Summary		获取登录会话
+//	@Description	获取当前用户在各个设备上的登录会话
+//	@Tags			用户管理
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		10000	{object}	response.BasicResponse[[]response.UserSessionResponse]	"获取成功，code=10000"
+//	@Failure		10001	{object}	response.BasicResponse[any]								"服务器内部错误，code=10001"
+//	@Router			/user/sessions [get]
+func (r *Api) GetSessions(ctx *gin.Context) {
+	claims, err := utils.GetContextClaims(ctx)
+	if err != nil || claims == nil {
+		response.FailWithCode(ctx, response.UserNotExist)
+		return
+	}
+	sessions, err := r.service.GetSessions(ctx, claims.User.ID, claims.Session)
+	if err != nil {
+		response.FailWithError(ctx, err)
+		return
+	}
+	response.SuccessWithData(ctx, sessions)
+}
+
+// RevokeSessions
+//
+//	@Summary		注销登录会话
+//	@Description	注销当前用户指定的登录会话,被注销会话的 token 和 refresh token 随即失效
+//	@Tags			用户管理
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		request.RevokeSessionsRequest	true	"注销会话请求参数"
+//	@Success		10000	{object}	response.BasicResponse[any]		"注销成功，code=10000"
+//	@Failure		10002	{object}	response.BasicResponse[any]		"参数验证失败，code=10002"
+//	@Failure		10001	{object}	response.BasicResponse[any]		"服务器内部错误，code=10001"
+//	@Router			/user/sessions/revoke [post]
+func (r *Api) RevokeSessions(ctx *gin.Context) {
+	var params request.RevokeSessionsRequest
+	if err := ctx.ShouldBindJSON(&params); err != nil {
+		response.ParamsValidateFail(ctx, err)
+		return
+	}
+	claims, err := utils.GetContextClaims(ctx)
+	if err != nil || claims == nil {
+		response.FailWithCode(ctx, response.UserNotExist)
+		return
+	}
+	err = r.service.RevokeSessions(ctx, claims.User.ID, params.IDs)
 	if err != nil {
 		response.FailWithError(ctx, err)
 		return
@@ -150,6 +401,69 @@ func (r *Api) Logout(ctx *gin.Context) {
 	response.Success(ctx)
 }
 
+// RevokeUserSessions
+//
+//	@Summary		注销用户全部登录会话
+//	@Description	管理员注销指定用户在全部设备上的登录会话
+//	@Tags			用户管理
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		request.RevokeUserSessionsRequest	true	"注销用户会话请求参数"
+//	@Success		10000	{object}	response.BasicResponse[any]			"注销成功，code=10000"
+//	@Failure		10002	{object}	response.BasicResponse[any]			"参数验证失败，code=10002"
+//	@Failure		20005	{object}	response.BasicResponse[any]			"用户不存在，code=20005"
+//	@Failure		10001	{object}	response.BasicResponse[any]			"服务器内部错误，code=10001"
+//	@Router			/user/sessions/revoke-all [post]
+func (r *Api) RevokeUserSessions(ctx *gin.Context) {
+	var params request.RevokeUserSessionsRequest
+	if err := ctx.ShouldBindJSON(&params); err != nil {
+		response.ParamsValidateFail(ctx, err)
+		return
+	}
+	err := r.service.RevokeUserSessions(ctx, params.UID)
+	if err != nil {
+		response.FailWithError(ctx, err)
+		return
+	}
+	response.Success(ctx)
+}
+
+// UnlockLogin
+//
+//	@Summary		解除登录锁定
+//	@Description	管理员解除因登录失败次数过多导致的邮箱(及ip)锁定,并清空失败次数
+//	@Tags			用户管理
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		request.UnlockLoginRequest	true	"解除登录锁定请求参数"
+//	@Success		10000	{object}	response.BasicResponse[any]	"解除成功，code=10000"
+//	@Failure		10002	{object}	response.BasicResponse[any]	"参数验证失败，code=10002"
+//	@Failure		10001	{object}	response.BasicResponse[any]	"服务器内部错误，code=10001"
+//	@Router			/user/login/unlock [post]
+func (r *Api) UnlockLogin(ctx *gin.Context) {
+	var params request.UnlockLoginRequest
+	if err := ctx.ShouldBindJSON(&params); err != nil {
+		response.ParamsValidateFail(ctx, err)
+		return
+	}
+	if err := r.service.UnlockLogin(ctx, params.Email, params.IP); err != nil {
+		response.FailWithError(ctx, err)
+		return
+	}
+	response.Success(ctx)
+}
+
+// loginClient 获取发起请求的客户端信息
+func (r *Api) loginClient(ctx *gin.Context, device string) *models.LoginClient {
+	return &models.LoginClient{
+		Device:    device,
+		IP:        ctx.ClientIP(),
+		UserAgent: ctx.Request.UserAgent(),
+	}
+}
+
 // Profile
 //
 //	@Summary		获取用户资料
@@ -176,20 +490,102 @@ func (r *Api) Profile(ctx *gin.Context) {
 	response.SuccessWithData(ctx, detail)
 }
 
+// UpdateProfile
+//
+//	@Summary		更新个人资料
+//	@Description	更新当前登录用户的昵称、头像、性别、简介和生日,未传递的字段会被清空
+//	@Tags			用户管理
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		request.UpdateProfileRequest	true	"个人资料"
+//	@Success		10000	{object}	response.BasicResponse[any]		"更新成功，code=10000"
+//	@Failure		10002	{object}	response.BasicResponse[any]		"参数验证失败，code=10002"
+//	@Failure		10001	{object}	response.BasicResponse[any]		"服务器内部错误，code=10001"
+//	@Router			/user/profile/update [post]
+func (r *Api) UpdateProfile(ctx *gin.Context) {
+	var params request.UpdateProfileRequest
+	if err := ctx.ShouldBindJSON(&params); err != nil {
+		response.ParamsValidateFail(ctx, err)
+		return
+	}
+	claims, err := utils.GetContextClaims(ctx)
+	if err != nil || claims == nil {
+		response.FailWithCode(ctx, response.UserNotExist)
+		return
+	}
+	profile := &models.User{
+		Nickname: params.Nickname,
+		Avatar:   params.Avatar,
+		Gender:   params.Gender,
+		About:    params.About,
+	}
+	if params.Birthday != nil {
+		birthday, parseErr := time.ParseInLocation(time.DateOnly, *params.Birthday, time.Local)
+		if parseErr != nil {
+			response.ParamsValidateFail(ctx, parseErr)
+			return
+		}
+		profile.Birthday = &birthday
+	}
+	err = r.service.UpdateProfile(ctx, claims.User.ID, profile)
+	if err != nil {
+		response.FailWithError(ctx, err)
+		return
+	}
+	response.Success(ctx)
+}
+
+// ChangePassword
+//
+//	@Summary		修改密码
+//	@Description	校验原密码后修改密码,修改成功后除当前会话外的其他登录会话全部失效
+//	@Tags			用户管理
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		request.ChangePasswordRequest	true	"修改密码请求参数"
+//	@Success		10000	{object}	response.BasicResponse[any]		"修改成功，code=10000"
+//	@Failure		10002	{object}	response.BasicResponse[any]		"参数验证失败，code=10002"
+//	@Failure		20033	{object}	response.BasicResponse[any]		"密码不符合密码策略，code=20033~20040"
+//	@Failure		20012	{object}	response.BasicResponse[any]		"原密码错误，code=20012"
+//	@Router			/user/password/change [post]
+func (r *Api) ChangePassword(ctx *gin.Context) {
+	var params request.ChangePasswordRequest
+	if err := ctx.ShouldBindJSON(&params); err != nil {
+		response.ParamsValidateFail(ctx, err)
+		return
+	}
+	claims, err := utils.GetContextClaims(ctx)
+	if err != nil || claims == nil {
+		response.FailWithCode(ctx, response.UserNotExist)
+		return
+	}
+	err = r.service.ChangePassword(ctx, claims.User.ID, claims.Session, params.OldPassword, params.NewPassword)
+	if err != nil {
+		response.FailWithError(ctx, err)
+		return
+	}
+	response.Success(ctx)
+}
+
 // GetUserList
 //
 //	@Summary		获取用户列表
-//	@Description	分页获取用户列表，支持关键词搜索
+//	@Description	分页获取用户列表，支持按关键词、账户状态、角色和部门过滤
 //	@Tags			用户管理
 //	@Accept			json
 //	@Produce		json
 //	@Security		BearerAuth
-//	@Param			keyword	query		string																	false	"搜索关键词"
-//	@Param			limit	query		int																		false	"每页数量"	default(10)
-//	@Param			offset	query		int																		false	"偏移量"	default(0)
-//	@Success		10000	{object}	response.BasicResponse[response.DataList[response.UserListRowResponse]]	"获取成功，code=10000"
-//	@Failure		10002	{object}	response.BasicResponse[any]												"参数验证失败，code=10002"
-//	@Failure		10001	{object}	response.BasicResponse[any]												"服务器内部错误，code=10001"
+//	@Param			keyword			query		string																	false	"搜索关键词"
+//	@Param			status			query		int																		false	"账户状态"
+//	@Param			role_id			query		int																		false	"角色ID"
+//	@Param			department_id	query		int																		false	"部门ID"
+//	@Param			limit			query		int																		false	"每页数量"	default(10)
+//	@Param			offset			query		int																		false	"偏移量"	default(0)
+//	@Success		10000			{object}	response.BasicResponse[response.DataList[response.UserListRowResponse]]	"获取成功，code=10000"
+//	@Failure		10002			{object}	response.BasicResponse[any]												"参数验证失败，code=10002"
+//	@Failure		10001			{object}	response.BasicResponse[any]												"服务器内部错误，code=10001"
 //	@Router			/user/list [get]
 func (r *Api) GetUserList(ctx *gin.Context) {
 	var params request.GetUserListRequest
@@ -197,7 +593,7 @@ func (r *Api) GetUserList(ctx *gin.Context) {
 		response.ParamsValidateFail(ctx, err)
 		return
 	}
-	list, total, err := r.service.GetUserList(ctx, params.Keyword, params.Limit, params.Offset)
+	list, total, err := r.service.GetUserList(ctx, toUserFilter(&params.UserFilterRequest), params.Limit, params.Offset)
 	if err != nil {
 		response.FailWithError(ctx, err)
 		return
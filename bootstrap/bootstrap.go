@@ -15,12 +15,15 @@ import (
 	"github.com/supuwoerc/weaver/api/v1/user"
 	"github.com/supuwoerc/weaver/conf"
 	"github.com/supuwoerc/weaver/initialize"
+	"github.com/supuwoerc/weaver/middleware"
 	"github.com/supuwoerc/weaver/pkg/cache"
 	"github.com/supuwoerc/weaver/pkg/constant"
 	"github.com/supuwoerc/weaver/pkg/consul"
 	"github.com/supuwoerc/weaver/pkg/job"
 	"github.com/supuwoerc/weaver/pkg/logger"
 	"github.com/supuwoerc/weaver/pkg/utils"
+	permissionService "github.com/supuwoerc/weaver/service/permission"
+	userService "github.com/supuwoerc/weaver/service/user"
 	tracesdk "go.opentelemetry.io/otel/sdk/trace"
 )
 
@@ -39,6 +42,8 @@ type App struct {
 	httpServer          *initialize.HttpServer
 	traceSpanExporter   tracesdk.SpanExporter
 	tracerProvider      *tracesdk.TracerProvider
+	authMiddleware      *middleware.AuthMiddleware
+	permissionService   *permissionService.Service
 	attachmentApi       *attachment.Api
 	captchaApi          *captcha.Api
 	departmentApi       *department.Api
@@ -57,8 +62,13 @@ func (a *App) Run() {
 	if len(a.conf.System.Hooks.Launch) > 0 {
 		for _, item := range a.conf.System.Hooks.Launch {
 			switch item {
-			case constant.AutoManageDeptCache:
-				if err := a.cacheManager.Refresh(context.Background(), constant.AutoManageDeptCache); err != nil {
+			case constant.AutoManageDeptCache, constant.AutoManagePermissionCache:
+				if err := a.cacheManager.Refresh(context.Background(), item); err != nil {
+					panic(err)
+				}
+			case constant.AutoSyncRoutePermissions:
+				if _, err := syncRoutePermissions(context.Background(), a.authMiddleware, a.permissionService,
+					a.logger, false); err != nil {
 					panic(err)
 				}
 			}
@@ -92,8 +102,8 @@ func (a *App) Close() {
 	if len(a.conf.System.Hooks.Close) > 0 {
 		for _, item := range a.conf.System.Hooks.Close {
 			switch item {
-			case constant.AutoManageDeptCache:
-				if err := a.cacheManager.Clean(context.Background(), constant.AutoManageDeptCache); err != nil {
+			case constant.AutoManageDeptCache, constant.AutoManagePermissionCache:
+				if err := a.cacheManager.Clean(context.Background(), item); err != nil {
 					panic(err)
 				}
 			}
@@ -127,6 +137,7 @@ func (a *App) registerService() error {
 }
 
 type Cli struct {
-	Logger *logger.Logger
-	Conf   *conf.Config
+	Logger      *logger.Logger
+	Conf        *conf.Config
+	UserService *userService.Service
 }
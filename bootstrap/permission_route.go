@@ -0,0 +1,63 @@
+package bootstrap
+
+import (
+	"context"
+
+	"github.com/supuwoerc/weaver/api/v1/attachment"
+	"github.com/supuwoerc/weaver/api/v1/captcha"
+	"github.com/supuwoerc/weaver/api/v1/department"
+	"github.com/supuwoerc/weaver/api/v1/permission"
+	"github.com/supuwoerc/weaver/api/v1/ping"
+	"github.com/supuwoerc/weaver/api/v1/role"
+	"github.com/supuwoerc/weaver/api/v1/user"
+	"github.com/supuwoerc/weaver/middleware"
+	"github.com/supuwoerc/weaver/pkg/logger"
+	"github.com/supuwoerc/weaver/pkg/response"
+	"github.com/supuwoerc/weaver/router"
+	permissionService "github.com/supuwoerc/weaver/service/permission"
+)
+
+// syncRoutePermissions 将通过PermissionGroup注册的路由同步为API权限,需要在全部路由注册完成后调用
+func syncRoutePermissions(
+	ctx context.Context,
+	auth *middleware.AuthMiddleware,
+	service *permissionService.Service,
+	logger *logger.Logger,
+	dryRun bool,
+) (*response.SyncRoutePermissionsResponse, error) {
+	routes := auth.PermissionRoutes()
+	router.AttachSwaggerSummaries(routes)
+	result, err := service.SyncRoutePermissions(ctx, routes, dryRun)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range result.Created {
+		logger.Infow("route permission created", "name", item.Name, "method", item.Method,
+			"resource", item.Resource, "dry_run", dryRun)
+	}
+	for _, item := range result.Orphans {
+		logger.Warnw("orphaned route permission", "id", item.ID, "name", item.Name, "method", item.Method,
+			"resource", item.Resource)
+	}
+	return result, nil
+}
+
+// RouteCli 需要完整注册路由的命令行脚本依赖
+type RouteCli struct {
+	Logger            *logger.Logger
+	auth              *middleware.AuthMiddleware
+	permissionService *permissionService.Service
+	// 各模块在构造时注册路由
+	attachmentApi *attachment.Api
+	captchaApi    *captcha.Api
+	departmentApi *department.Api
+	permissionApi *permission.Api
+	pingApi       *ping.Api
+	roleApi       *role.Api
+	userApi       *user.Api
+}
+
+// SyncRoutePermissions 同步路由的API权限,dryRun为true时只对比差异
+func (c *RouteCli) SyncRoutePermissions(ctx context.Context, dryRun bool) (*response.SyncRoutePermissionsResponse, error) {
+	return syncRoutePermissions(ctx, c.auth, c.permissionService, c.Logger, dryRun)
+}
@@ -104,9 +104,78 @@ func WireCli() *Cli {
 		initialize.NewWriterSyncer,
 
 		initialize.NewZapLogger,
+
+		wire.Bind(new(utils.LocksmithLogger), new(*logger.Logger)),
+		wire.Bind(new(initialize.ClientLogger), new(*logger.Logger)),
 		logger.NewLogger,
 
+		initialize.NewDialer,
+
+		wire.Bind(new(gormLogger.Interface), new(*initialize.GormLogger)),
+		initialize.NewGormLogger,
+		initialize.NewGORM,
+
+		utils.NewRedisLocksmith,
+
+		wire.Bind(new(goredislib.Hook), new(*initialize.RedisLogger)),
+		initialize.NewRedisLogger,
+
+		initialize.NewRedisClient,
+
+		initialize.NewEmailClient,
+
+		providers.CommonProvider,
+		providers.CliProvider,
+
 		wire.Struct(new(Cli), "*"),
 	)
 	return nil
 }
+
+func WireRouteCli() *RouteCli {
+	wire.Build(
+		initialize.NewViper,
+
+		initialize.LoadConsulConfig,
+
+		initialize.NewConsulClient,
+
+		initialize.LoadConfig,
+
+		initialize.NewWriterSyncer,
+
+		initialize.NewZapLogger,
+
+		wire.Bind(new(utils.LocksmithLogger), new(*logger.Logger)),
+		wire.Bind(new(initialize.ClientLogger), new(*logger.Logger)),
+		logger.NewLogger,
+
+		initialize.NewDialer,
+
+		wire.Bind(new(gormLogger.Interface), new(*initialize.GormLogger)),
+		initialize.NewGormLogger,
+		initialize.NewGORM,
+
+		utils.NewRedisLocksmith,
+
+		wire.Bind(new(goredislib.Hook), new(*initialize.RedisLogger)),
+		initialize.NewRedisLogger,
+
+		initialize.NewRedisClient,
+
+		initialize.NewEmailClient,
+
+		wire.Bind(new(initialize.OSSClient), new(*s3.Client)),
+		initialize.NewS3Client,
+
+		initialize.NewEngine,
+		router.NewRouter,
+
+		providers.CommonProvider,
+		providers.MiddlewareProvider,
+		providers.ApiProvider,
+
+		wire.Struct(new(RouteCli), "*"),
+	)
+	return nil
+}
@@ -20,6 +20,7 @@ import (
 	cache2 "github.com/supuwoerc/weaver/pkg/cache"
 	"github.com/supuwoerc/weaver/pkg/captcha"
 	"github.com/supuwoerc/weaver/pkg/consul"
+	"github.com/supuwoerc/weaver/pkg/idp"
 	"github.com/supuwoerc/weaver/pkg/job"
 	"github.com/supuwoerc/weaver/pkg/jwt"
 	"github.com/supuwoerc/weaver/pkg/logger"
@@ -52,52 +53,62 @@ func WireApp() *App {
 	emailClient := initialize.NewEmailClient(loggerLogger, dialer, config)
 	cronLogger := initialize.NewCronLogger(loggerLogger, emailClient)
 	cron := initialize.NewCronClient(cronLogger)
-	v := providers.SystemJobs(loggerLogger)
-	systemJobManager := job.NewSystemJobManager(cronLogger, cron, loggerLogger, v...)
 	gormLogger := initialize.NewGormLogger(loggerLogger, config)
 	db := initialize.NewGORM(config, gormLogger)
 	redisLogger := initialize.NewRedisLogger(loggerLogger, config)
 	commonRedisClient := initialize.NewRedisClient(redisLogger, config)
+	tokenBuilder := jwt.NewJwtBuilder(db, commonRedisClient, config)
 	redisLocksmith := utils.NewRedisLocksmith(loggerLogger, commonRedisClient)
 	basicService := service.NewBasicService(loggerLogger, db, redisLocksmith, config, emailClient)
+	redisStore := captcha.NewRedisStore(commonRedisClient, config)
+	captchaService := captcha2.NewCaptchaService(redisStore, config)
 	basicDAO := dao.NewBasicDao(db)
-	departmentDAO := dao.NewDepartmentDAO(basicDAO)
-	departmentCache := cache.NewDepartmentCache(commonRedisClient)
 	userDAO := dao.NewUserDAO(basicDAO)
-	departmentService := department.NewDepartmentService(basicService, departmentDAO, departmentCache, userDAO)
+	userMFADAO := dao.NewUserMFADAO(basicDAO)
+	userIdentityDAO := dao.NewUserIdentityDAO(basicDAO)
+	userAPIKeyDAO := dao.NewUserAPIKeyDAO(basicDAO)
+	userImpersonationDAO := dao.NewUserImpersonationDAO(basicDAO)
+	userInvitationDAO := dao.NewUserInvitationDAO(basicDAO)
+	userPasswordHistoryDAO := dao.NewUserPasswordHistoryDAO(basicDAO)
+	userLoginHistoryDAO := dao.NewUserLoginHistoryDAO(basicDAO)
+	attachmentDAO := dao.NewAttachmentDAO(basicDAO)
 	permissionDAO := dao.NewPermissionDAO(basicDAO)
-	rolePermissionDAO := dao.NewRolePermissionDAO(basicDAO)
 	roleDAO := dao.NewRoleDAO(basicDAO)
-	permissionService := permission.NewPermissionService(basicService, permissionDAO, rolePermissionDAO, roleDAO)
+	departmentDAO := dao.NewDepartmentDAO(basicDAO)
+	userCache := cache.NewUserCache(commonRedisClient)
+	departmentCache := cache.NewDepartmentCache(commonRedisClient)
+	permissionCache := cache.NewPermissionCache(commonRedisClient, config)
+	rolePermissionDAO := dao.NewRolePermissionDAO(basicDAO)
+	permissionService := permission.NewPermissionService(basicService, permissionDAO, rolePermissionDAO, roleDAO, userDAO, permissionCache)
+	manager := idp.NewManager(config)
+	smsClient := initialize.NewSMSClient(loggerLogger, config)
+	userService := user.NewUserService(basicService, captchaService, userDAO, userMFADAO, userIdentityDAO, userAPIKeyDAO, userImpersonationDAO, userInvitationDAO, userPasswordHistoryDAO, userLoginHistoryDAO, attachmentDAO, permissionDAO, roleDAO, departmentDAO, userCache, departmentCache, permissionCache, permissionService, tokenBuilder, manager, smsClient)
+	v := providers.SystemJobs(loggerLogger, tokenBuilder, userService)
+	systemJobManager := job.NewSystemJobManager(cronLogger, cron, loggerLogger, v...)
+	departmentService := department.NewDepartmentService(basicService, departmentDAO, departmentCache, userDAO)
 	v2 := providers.SystemCaches(departmentService, permissionService)
 	systemCacheManager := cache2.NewSystemCacheManager(v2...)
 	elasticsearchLogger := initialize.NewElasticsearchLogger(loggerLogger, config)
 	typedClient := initialize.NewElasticsearchClient(config, elasticsearchLogger)
 	serviceRegister := consul.NewServiceRegistry(client, emailClient, loggerLogger)
-	engine := initialize.NewEngine(emailClient, commonRedisClient, loggerLogger, config)
+	engine := initialize.NewEngine(emailClient, commonRedisClient, tokenBuilder, loggerLogger, config)
 	httpServer := initialize.NewHttpServer(config, engine, loggerLogger)
 	exporter := initialize.NewOTLPExporter(config)
 	tracerProvider := initialize.NewTracerProvider(config, exporter)
+	roleService := role.NewRoleService(basicService, roleDAO, userDAO, permissionDAO, departmentDAO, userCache, permissionCache)
+	authMiddleware := middleware.NewAuthMiddleware(config, userCache, tokenBuilder, permissionService, userAPIKeyDAO, roleService)
 	routerGroup := router.NewRouter(engine)
-	userCache := cache.NewUserCache(commonRedisClient)
-	tokenBuilder := jwt.NewJwtBuilder(db, commonRedisClient, config, userCache)
-	authMiddleware := middleware.NewAuthMiddleware(config, userCache, tokenBuilder, permissionDAO)
 	basicApi := v1.NewBasicApi(routerGroup, loggerLogger, config, authMiddleware)
-	attachmentDAO := dao.NewAttachmentDAO(basicDAO)
 	s3Client := initialize.NewS3Client(config)
 	s3CompatibleStorage := initialize.NewS3CompatibleStorage(config, s3Client)
 	attachmentService := attachment.NewAttachmentService(basicService, attachmentDAO, s3CompatibleStorage)
 	api := attachment2.NewAttachmentApi(basicApi, attachmentService)
-	redisStore := captcha.NewRedisStore(commonRedisClient, config)
-	captchaService := captcha2.NewCaptchaService(redisStore)
 	captchaApi := captcha3.NewCaptchaApi(basicApi, captchaService)
 	departmentApi := department2.NewDepartmentApi(basicApi, departmentService)
 	permissionApi := permission2.NewPermissionApi(basicApi, permissionService)
 	pingService := ping.NewPingService(basicService)
 	pingApi := ping2.NewPingApi(basicApi, pingService)
-	roleService := role.NewRoleService(basicService, roleDAO, userDAO, permissionDAO)
 	roleApi := role2.NewRoleApi(basicApi, roleService)
-	userService := user.NewUserService(basicService, captchaService, userDAO, userCache, tokenBuilder)
 	userApi := user2.NewUserApi(basicApi, userService)
 	app := &App{
 		logger:              loggerLogger,
@@ -110,6 +121,8 @@ func WireApp() *App {
 		httpServer:          httpServer,
 		traceSpanExporter:   exporter,
 		tracerProvider:      tracerProvider,
+		authMiddleware:      authMiddleware,
+		permissionService:   permissionService,
 		attachmentApi:       api,
 		captchaApi:          captchaApi,
 		departmentApi:       departmentApi,
@@ -129,9 +142,114 @@ func WireCli() *Cli {
 	writeSyncer := initialize.NewWriterSyncer(config)
 	sugaredLogger := initialize.NewZapLogger(config, writeSyncer)
 	loggerLogger := logger.NewLogger(sugaredLogger)
+	gormLogger := initialize.NewGormLogger(loggerLogger, config)
+	db := initialize.NewGORM(config, gormLogger)
+	redisLogger := initialize.NewRedisLogger(loggerLogger, config)
+	commonRedisClient := initialize.NewRedisClient(redisLogger, config)
+	redisLocksmith := utils.NewRedisLocksmith(loggerLogger, commonRedisClient)
+	dialer := initialize.NewDialer(config)
+	emailClient := initialize.NewEmailClient(loggerLogger, dialer, config)
+	basicService := service.NewBasicService(loggerLogger, db, redisLocksmith, config, emailClient)
+	redisStore := captcha.NewRedisStore(commonRedisClient, config)
+	captchaService := captcha2.NewCaptchaService(redisStore, config)
+	basicDAO := dao.NewBasicDao(db)
+	userDAO := dao.NewUserDAO(basicDAO)
+	userMFADAO := dao.NewUserMFADAO(basicDAO)
+	userIdentityDAO := dao.NewUserIdentityDAO(basicDAO)
+	userAPIKeyDAO := dao.NewUserAPIKeyDAO(basicDAO)
+	userImpersonationDAO := dao.NewUserImpersonationDAO(basicDAO)
+	userInvitationDAO := dao.NewUserInvitationDAO(basicDAO)
+	userPasswordHistoryDAO := dao.NewUserPasswordHistoryDAO(basicDAO)
+	userLoginHistoryDAO := dao.NewUserLoginHistoryDAO(basicDAO)
+	attachmentDAO := dao.NewAttachmentDAO(basicDAO)
+	permissionDAO := dao.NewPermissionDAO(basicDAO)
+	roleDAO := dao.NewRoleDAO(basicDAO)
+	departmentDAO := dao.NewDepartmentDAO(basicDAO)
+	userCache := cache.NewUserCache(commonRedisClient)
+	departmentCache := cache.NewDepartmentCache(commonRedisClient)
+	permissionCache := cache.NewPermissionCache(commonRedisClient, config)
+	rolePermissionDAO := dao.NewRolePermissionDAO(basicDAO)
+	permissionService := permission.NewPermissionService(basicService, permissionDAO, rolePermissionDAO, roleDAO, userDAO, permissionCache)
+	tokenBuilder := jwt.NewJwtBuilder(db, commonRedisClient, config)
+	manager := idp.NewManager(config)
+	smsClient := initialize.NewSMSClient(loggerLogger, config)
+	userService := user.NewUserService(basicService, captchaService, userDAO, userMFADAO, userIdentityDAO, userAPIKeyDAO, userImpersonationDAO, userInvitationDAO, userPasswordHistoryDAO, userLoginHistoryDAO, attachmentDAO, permissionDAO, roleDAO, departmentDAO, userCache, departmentCache, permissionCache, permissionService, tokenBuilder, manager, smsClient)
 	cli := &Cli{
-		Logger: loggerLogger,
-		Conf:   config,
+		Logger:      loggerLogger,
+		Conf:        config,
+		UserService: userService,
 	}
 	return cli
 }
+
+func WireRouteCli() *RouteCli {
+	viper := initialize.NewViper()
+	consulConfig := initialize.LoadConsulConfig(viper)
+	client := initialize.NewConsulClient(consulConfig)
+	config := initialize.LoadConfig(viper, client)
+	writeSyncer := initialize.NewWriterSyncer(config)
+	sugaredLogger := initialize.NewZapLogger(config, writeSyncer)
+	loggerLogger := logger.NewLogger(sugaredLogger)
+	redisLogger := initialize.NewRedisLogger(loggerLogger, config)
+	commonRedisClient := initialize.NewRedisClient(redisLogger, config)
+	userCache := cache.NewUserCache(commonRedisClient)
+	gormLogger := initialize.NewGormLogger(loggerLogger, config)
+	db := initialize.NewGORM(config, gormLogger)
+	tokenBuilder := jwt.NewJwtBuilder(db, commonRedisClient, config)
+	redisLocksmith := utils.NewRedisLocksmith(loggerLogger, commonRedisClient)
+	dialer := initialize.NewDialer(config)
+	emailClient := initialize.NewEmailClient(loggerLogger, dialer, config)
+	basicService := service.NewBasicService(loggerLogger, db, redisLocksmith, config, emailClient)
+	basicDAO := dao.NewBasicDao(db)
+	permissionDAO := dao.NewPermissionDAO(basicDAO)
+	rolePermissionDAO := dao.NewRolePermissionDAO(basicDAO)
+	roleDAO := dao.NewRoleDAO(basicDAO)
+	userDAO := dao.NewUserDAO(basicDAO)
+	permissionCache := cache.NewPermissionCache(commonRedisClient, config)
+	permissionService := permission.NewPermissionService(basicService, permissionDAO, rolePermissionDAO, roleDAO, userDAO, permissionCache)
+	userAPIKeyDAO := dao.NewUserAPIKeyDAO(basicDAO)
+	departmentDAO := dao.NewDepartmentDAO(basicDAO)
+	roleService := role.NewRoleService(basicService, roleDAO, userDAO, permissionDAO, departmentDAO, userCache, permissionCache)
+	authMiddleware := middleware.NewAuthMiddleware(config, userCache, tokenBuilder, permissionService, userAPIKeyDAO, roleService)
+	engine := initialize.NewEngine(emailClient, commonRedisClient, tokenBuilder, loggerLogger, config)
+	routerGroup := router.NewRouter(engine)
+	basicApi := v1.NewBasicApi(routerGroup, loggerLogger, config, authMiddleware)
+	attachmentDAO := dao.NewAttachmentDAO(basicDAO)
+	s3Client := initialize.NewS3Client(config)
+	s3CompatibleStorage := initialize.NewS3CompatibleStorage(config, s3Client)
+	attachmentService := attachment.NewAttachmentService(basicService, attachmentDAO, s3CompatibleStorage)
+	api := attachment2.NewAttachmentApi(basicApi, attachmentService)
+	redisStore := captcha.NewRedisStore(commonRedisClient, config)
+	captchaService := captcha2.NewCaptchaService(redisStore, config)
+	captchaApi := captcha3.NewCaptchaApi(basicApi, captchaService)
+	departmentCache := cache.NewDepartmentCache(commonRedisClient)
+	departmentService := department.NewDepartmentService(basicService, departmentDAO, departmentCache, userDAO)
+	departmentApi := department2.NewDepartmentApi(basicApi, departmentService)
+	permissionApi := permission2.NewPermissionApi(basicApi, permissionService)
+	pingService := ping.NewPingService(basicService)
+	pingApi := ping2.NewPingApi(basicApi, pingService)
+	roleApi := role2.NewRoleApi(basicApi, roleService)
+	userMFADAO := dao.NewUserMFADAO(basicDAO)
+	userIdentityDAO := dao.NewUserIdentityDAO(basicDAO)
+	userImpersonationDAO := dao.NewUserImpersonationDAO(basicDAO)
+	userInvitationDAO := dao.NewUserInvitationDAO(basicDAO)
+	userPasswordHistoryDAO := dao.NewUserPasswordHistoryDAO(basicDAO)
+	userLoginHistoryDAO := dao.NewUserLoginHistoryDAO(basicDAO)
+	manager := idp.NewManager(config)
+	smsClient := initialize.NewSMSClient(loggerLogger, config)
+	userService := user.NewUserService(basicService, captchaService, userDAO, userMFADAO, userIdentityDAO, userAPIKeyDAO, userImpersonationDAO, userInvitationDAO, userPasswordHistoryDAO, userLoginHistoryDAO, attachmentDAO, permissionDAO, roleDAO, departmentDAO, userCache, departmentCache, permissionCache, permissionService, tokenBuilder, manager, smsClient)
+	userApi := user2.NewUserApi(basicApi, userService)
+	routeCli := &RouteCli{
+		Logger:            loggerLogger,
+		auth:              authMiddleware,
+		permissionService: permissionService,
+		attachmentApi:     api,
+		captchaApi:        captchaApi,
+		departmentApi:     departmentApi,
+		permissionApi:     permissionApi,
+		pingApi:           pingApi,
+		roleApi:           roleApi,
+		userApi:           userApi,
+	}
+	return routeCli
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"github.com/supuwoerc/weaver/bootstrap"
+	"github.com/supuwoerc/weaver/pkg/spreadsheet"
+)
+
+var importUsersCmd = &cobra.Command{
+	Use:   "import-users",
+	Short: "import users from csv or xlsx file",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, _ := cmd.Flags().GetString("file")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		sendActiveEmail, _ := cmd.Flags().GetBool("send-active-email")
+		format, err := spreadsheet.FormatOf(path)
+		if err != nil {
+			return err
+		}
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = file.Close()
+		}()
+		cli := bootstrap.WireCli()
+		cli.Logger.Infow("import users is running...", "file", path, "dry_run", dryRun)
+		ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+		defer stop()
+		result, err := cli.UserService.ImportUsers(ctx, file, format, dryRun, sendActiveEmail)
+		if result != nil {
+			for _, item := range result.Errors {
+				cmd.Printf("第%d行 %s=%q: %s(%d)\n", item.Row, item.Column, item.Value, item.Code.String(), int(item.Code))
+			}
+			cmd.Printf("共%d行, 校验失败%d项, 创建用户%d个\n", result.Total, len(result.Errors), result.Created)
+		}
+		if err != nil {
+			return fmt.Errorf("import users: %w", err)
+		}
+		return nil
+	},
+}
+
+func init() {
+	importUsersCmd.Flags().StringP("file", "f", "", "csv或xlsx文件路径,首行为表头")
+	importUsersCmd.Flags().Bool("dry-run", false, "仅校验,不创建用户")
+	importUsersCmd.Flags().Bool("send-active-email", false, "发送激活邮件,为否时用户直接启用")
+	_ = importUsersCmd.MarkFlagRequired("file")
+}
@@ -18,4 +18,6 @@ func Execute() {
 
 func init() {
 	rootCmd.AddCommand(welcomeCmd)
+	rootCmd.AddCommand(importUsersCmd)
+	rootCmd.AddCommand(syncRoutePermissionsCmd)
 }
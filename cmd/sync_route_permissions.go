@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"github.com/supuwoerc/weaver/bootstrap"
+	_ "github.com/supuwoerc/weaver/docs"
+)
+
+var syncRoutePermissionsCmd = &cobra.Command{
+	Use:   "sync-route-permissions",
+	Short: "sync api permissions from routes registered through PermissionGroup",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		cli := bootstrap.WireRouteCli()
+		cli.Logger.Infow("sync route permissions is running...", "dry_run", dryRun)
+		ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+		defer stop()
+		result, err := cli.SyncRoutePermissions(ctx, dryRun)
+		if err != nil {
+			return fmt.Errorf("sync route permissions: %w", err)
+		}
+		for _, item := range result.Created {
+			cmd.Printf("+ %s %s %s\n", item.Method, item.Resource, item.Name)
+		}
+		for _, item := range result.Orphans {
+			cmd.Printf("- %s %s %s(id=%d)\n", item.Method, item.Resource, item.Name, item.ID)
+		}
+		action := "新建"
+		if dryRun {
+			action = "待新建"
+		}
+		cmd.Printf("共%d个路由, %s权限%d个, 失效权限%d个(需人工确认后删除)\n",
+			result.Total, action, len(result.Created), len(result.Orphans))
+		return nil
+	},
+}
+
+func init() {
+	syncRoutePermissionsCmd.Flags().Bool("dry-run", false, "仅对比差异,不创建权限")
+}
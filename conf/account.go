@@ -1,7 +1,34 @@
 package conf
 
-import "time"
+import (
+	"time"
+
+	"github.com/supuwoerc/weaver/pkg/constant"
+)
 
 type AccountConfig struct {
-	Expiration time.Duration `mapstructure:"expiration"` // 过期时长(秒)
+	Expiration              time.Duration       `mapstructure:"expiration"`                // 过期时长(秒)
+	ResetPasswordExpiration time.Duration       `mapstructure:"reset_password_expiration"` // 重置密码验证码过期时长(秒)
+	ResetPasswordLimit      int64               `mapstructure:"reset_password_limit"`      // 限流窗口内单个邮箱允许的找回/重置密码请求次数
+	ResetPasswordWindow     time.Duration       `mapstructure:"reset_password_window"`     // 找回/重置密码的限流窗口(秒)
+	MFATicketExpiration     time.Duration       `mapstructure:"mfa_ticket_expiration"`     // 两步验证登录凭证过期时长(秒)
+	LoginFailWindow         time.Duration       `mapstructure:"login_fail_window"`         // 统计登录失败次数的窗口(秒)
+	LoginCaptchaThreshold   int64               `mapstructure:"login_captcha_threshold"`   // 单个邮箱登录失败达到该次数后需要验证码
+	LoginLockThreshold      int64               `mapstructure:"login_lock_threshold"`      // 单个邮箱登录失败达到该次数后临时锁定
+	LoginIPLockThreshold    int64               `mapstructure:"login_ip_lock_threshold"`   // 单个ip登录失败达到该次数后临时锁定
+	LoginLockDuration       time.Duration       `mapstructure:"login_lock_duration"`       // 临时锁定时长(秒)
+	LoginDelayBase          time.Duration       `mapstructure:"login_delay_base"`          // 登录失败后的等待时长(秒),每次失败翻倍
+	LoginDelayMax           time.Duration       `mapstructure:"login_delay_max"`           // 登录失败后的最大等待时长(秒)
+	ImportBatchSize         int                 `mapstructure:"import_batch_size"`         // 批量导入用户时每个事务创建的用户数
+	ImportMaxRows           int                 `mapstructure:"import_max_rows"`           // 批量导入用户允许的最大行数
+	SignupMode              constant.SignupMode `mapstructure:"signup_mode"`               // 注册方式: open(开放注册) invite(仅邀请) disabled(关闭注册)
+	InvitationURL           string              `mapstructure:"invitation_url"`            // 前端接受邀请的页面地址,邀请邮件中的链接为该地址拼接code参数
+	ChangeEmailExpiration   time.Duration       `mapstructure:"change_email_expiration"`   // 修改邮箱确认链接的有效时长(秒)
+	ChangeEmailURL          string              `mapstructure:"change_email_url"`          // 前端确认修改邮箱的页面地址,确认邮件中的链接为该地址拼接code参数
+	RevertEmailExpiration   time.Duration       `mapstructure:"revert_email_expiration"`   // 撤销修改邮箱链接的有效时长(秒)
+	RevertEmailURL          string              `mapstructure:"revert_email_url"`          // 前端撤销修改邮箱的页面地址,通知邮件中的链接为该地址拼接code参数
+	LoginAlert              bool                `mapstructure:"login_alert"`               // 从新的ip或设备登录成功时是否发送提醒邮件
+	DataExportInterval      time.Duration       `mapstructure:"data_export_interval"`      // 两次导出个人数据的最小间隔(秒)
+	DataExportExpiration    time.Duration       `mapstructure:"data_export_expiration"`    // 个人数据下载链接的有效时长(秒)
+	DataExportURL           string              `mapstructure:"data_export_url"`           // 个人数据的下载地址,邮件中的链接为该地址拼接code参数
 }
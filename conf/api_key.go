@@ -0,0 +1,10 @@
+package conf
+
+import "time"
+
+type APIKeyConfig struct {
+	Header        string        `mapstructure:"header"`         // 客户端api key对应的header-key
+	Prefix        string        `mapstructure:"prefix"`         // api key前缀,便于识别及密钥泄露扫描
+	MaxCount      int64         `mapstructure:"max_count"`      // 单个用户允许创建的api key数量上限
+	TouchInterval time.Duration `mapstructure:"touch_interval"` // 最后使用时间的更新间隔(秒)
+}
@@ -1,7 +1,31 @@
 package conf
 
-import "time"
+import (
+	"time"
+
+	"github.com/supuwoerc/weaver/pkg/constant"
+)
 
 type CaptchaConfig struct {
-	Expiration time.Duration `mapstructure:"expiration"` // 过期时长(秒)
+	Expiration    time.Duration       `mapstructure:"expiration"`     // 过期时长(秒)
+	Default       CaptchaDriverConfig `mapstructure:"default"`        // 默认验证码
+	SignUp        CaptchaDriverConfig `mapstructure:"signup"`         // 注册验证码,未配置时使用默认验证码
+	ResetPassword CaptchaDriverConfig `mapstructure:"reset_password"` // 找回/重置密码验证码,未配置时使用默认验证码
+	Login         CaptchaDriverConfig `mapstructure:"login"`          // 登录验证码,未配置时使用默认验证码
+	Audio         CaptchaDriverConfig `mapstructure:"audio"`          // 语音验证码,各业务均可请求语音验证码代替图片验证码
+}
+
+// CaptchaDriverConfig 验证码生成参数,未配置的参数使用内置默认值
+type CaptchaDriverConfig struct {
+	Driver          constant.CaptchaDriver `mapstructure:"driver"`            // 生成方式: digit string math audio chinese
+	Height          int                    `mapstructure:"height"`            // 图片高度
+	Width           int                    `mapstructure:"width"`             // 图片宽度
+	Length          int                    `mapstructure:"length"`            // 字符数量,math无效
+	MaxSkew         float64                `mapstructure:"max_skew"`          // 数字最大倾斜程度,仅digit有效
+	DotCount        int                    `mapstructure:"dot_count"`         // 干扰点数量,仅digit有效
+	NoiseCount      int                    `mapstructure:"noise_count"`       // 干扰字符数量,string/math/chinese有效
+	ShowLineOptions int                    `mapstructure:"show_line_options"` // 干扰线(2空心线 4曲线 8正弦线,可相加组合),string/math/chinese有效
+	Source          string                 `mapstructure:"source"`            // 候选字符,string/chinese有效
+	Fonts           []string               `mapstructure:"fonts"`             // 字体文件名,string/math/chinese有效
+	Language        string                 `mapstructure:"language"`          // 语音语言,仅audio有效
 }
@@ -13,6 +13,11 @@ type Config struct {
 	Cors          CorsConfig          `mapstructure:"cors"`           // cors相关配置
 	Captcha       CaptchaConfig       `mapstructure:"captcha"`        // 验证码相关配置
 	Account       AccountConfig       `mapstructure:"account"`        // 账户相关配置
+	Password      PasswordConfig      `mapstructure:"password"`       // 密码策略配置
+	Permission    PermissionConfig    `mapstructure:"permission"`     // 权限相关配置
+	SSO           SSOConfig           `mapstructure:"sso"`            // 单点登录配置
+	SMS           SMSConfig           `mapstructure:"sms"`            // 短信配置
+	APIKey        APIKeyConfig        `mapstructure:"api_key"`        // api key相关配置
 	Consul        ConsulConfig        `mapstructure:"consul"`         // consul配置
 	Redis         RedisConfig         `mapstructure:"redis"`          // redis配置
 	GORM          GORMConfig          `mapstructure:"gorm"`           // gorm配置
@@ -3,11 +3,14 @@ package conf
 import "time"
 
 type JWTConfig struct {
-	Expires             time.Duration `mapstructure:"expires"`               // token过期时长(分钟)
-	RefreshTokenExpires time.Duration `mapstructure:"refresh_token_expires"` // refresh_token的过期时长(分钟)
-	Secret              string        `mapstructure:"secret"`                // 密钥
-	Issuer              string        `mapstructure:"issuer"`                // issuer
-	TokenKey            string        `mapstructure:"token_key"`             // 客户端token对应的header-key
-	RefreshTokenKey     string        `mapstructure:"refresh_token_key"`     // 客户端token对应的header-key
-	TokenPrefix         string        `mapstructure:"token_prefix"`          // token前缀
+	Expires              time.Duration `mapstructure:"expires"`               // token过期时长(分钟)
+	RefreshTokenExpires  time.Duration `mapstructure:"refresh_token_expires"` // refresh_token的过期时长(分钟)
+	Secret               string        `mapstructure:"secret"`                // 密钥,非对称算法时用于加密redis中保存的私钥
+	Issuer               string        `mapstructure:"issuer"`                // issuer
+	TokenKey             string        `mapstructure:"token_key"`             // 客户端token对应的header-key
+	RefreshTokenKey      string        `mapstructure:"refresh_token_key"`     // 客户端token对应的header-key
+	TokenPrefix          string        `mapstructure:"token_prefix"`          // token前缀
+	Algorithm            string        `mapstructure:"algorithm"`             // 签名算法: HS256(默认,使用secret) RS256 ES256
+	KeyRotation          time.Duration `mapstructure:"key_rotation"`          // 非对称签名密钥的轮换周期(分钟)
+	ImpersonationExpires time.Duration `mapstructure:"impersonation_expires"` // 模拟登录token的过期时长(分钟)
 }
@@ -0,0 +1,13 @@
+package conf
+
+type PasswordConfig struct {
+	MinLength     int  `mapstructure:"min_length"`     // 最小长度
+	MaxLength     int  `mapstructure:"max_length"`     // 最大字符数,同时不能超过bcrypt限制的72个字节
+	RequireUpper  bool `mapstructure:"require_upper"`  // 是否必须包含大写字母
+	RequireLower  bool `mapstructure:"require_lower"`  // 是否必须包含小写字母
+	RequireDigit  bool `mapstructure:"require_digit"`  // 是否必须包含数字
+	RequireSymbol bool `mapstructure:"require_symbol"` // 是否必须包含特殊字符
+	CheckBreached bool `mapstructure:"check_breached"` // 是否禁止使用内置泄露密码库中的密码
+	HistorySize   int  `mapstructure:"history_size"`   // 禁止重复使用最近N次的密码,0表示不限制
+	MaxAge        int  `mapstructure:"max_age"`        // 密码最长使用天数,超过后登录时必须修改密码,0表示不限制
+}
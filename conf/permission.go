@@ -0,0 +1,22 @@
+package conf
+
+import (
+	"time"
+
+	"github.com/supuwoerc/weaver/pkg/constant"
+)
+
+type PermissionConfig struct {
+	RoleInheritance      constant.RoleInheritance `mapstructure:"role_inheritance"`       // 角色继承方向: descendants ancestors none
+	CacheExpiration      time.Duration            `mapstructure:"cache_expiration"`       // 用户权限集合在redis中的缓存时长(秒)
+	LocalCacheSize       int                      `mapstructure:"local_cache_size"`       // 进程内缓存的用户权限集合数量,0表示不启用
+	LocalCacheExpiration time.Duration            `mapstructure:"local_cache_expiration"` // 进程内缓存的用户权限集合的有效时长(秒)
+}
+
+// Inheritance 角色继承方向,未配置时用户同时拥有后代角色的权限
+func (p *PermissionConfig) Inheritance() constant.RoleInheritance {
+	if p.RoleInheritance == "" {
+		return constant.InheritDescendants
+	}
+	return p.RoleInheritance
+}
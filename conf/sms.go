@@ -0,0 +1,17 @@
+package conf
+
+import "time"
+
+type SMSConfig struct {
+	Provider       string            `mapstructure:"provider"`        // 短信服务: log(只打印日志,用于开发环境) http(调用短信服务的http接口)
+	URL            string            `mapstructure:"url"`             // http短信服务的接口地址
+	Token          string            `mapstructure:"token"`           // http短信服务的鉴权token,以Bearer方式放在Authorization头中
+	Timeout        time.Duration     `mapstructure:"timeout"`         // http请求超时时长(秒)
+	Sign           string            `mapstructure:"sign"`            // 短信签名
+	Templates      map[string]string `mapstructure:"templates"`       // 各场景使用的短信模板,key为场景(bind、login)
+	CodeLength     int               `mapstructure:"code_length"`     // 验证码长度
+	CodeExpiration time.Duration     `mapstructure:"code_expiration"` // 验证码有效时长(秒)
+	ResendInterval time.Duration     `mapstructure:"resend_interval"` // 同一手机号再次发送验证码的间隔(秒)
+	DailyLimit     int64             `mapstructure:"daily_limit"`     // 单个手机号每天允许发送验证码的次数
+	MaxAttempts    int64             `mapstructure:"max_attempts"`    // 单个验证码允许的错误次数,达到后验证码失效
+}
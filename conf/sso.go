@@ -0,0 +1,20 @@
+package conf
+
+import "time"
+
+type SSOConfig struct {
+	StateExpiration time.Duration            `mapstructure:"state_expiration"` // 授权state过期时长(秒)
+	Providers       []IdentityProviderConfig `mapstructure:"providers"`        // 身份提供方
+}
+
+type IdentityProviderConfig struct {
+	Name            string   `mapstructure:"name"`             // 身份提供方标识,用于路由及身份关联
+	Type            string   `mapstructure:"type"`             // 身份提供方类型,目前支持: oidc
+	Issuer          string   `mapstructure:"issuer"`           // OIDC issuer,用于服务发现及id_token校验
+	ClientID        string   `mapstructure:"client_id"`        // 客户端ID
+	ClientSecret    string   `mapstructure:"client_secret"`    // 客户端密钥
+	RedirectURL     string   `mapstructure:"redirect_url"`     // 授权回调地址
+	Scopes          []string `mapstructure:"scopes"`           // 额外申请的scope,openid默认包含
+	AutoProvision   bool     `mapstructure:"auto_provision"`   // 外部账户未关联用户时是否自动创建用户,仅在开放注册时生效
+	ProvisionActive bool     `mapstructure:"provision_active"` // 自动创建的用户是否直接激活,为false时需要管理员激活后才能登录
+}
@@ -714,9 +714,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/public/captcha/signup": {
+        "/public/captcha/login": {
             "get": {
-                "description": "生成用户注册时使用的验证码",
+                "description": "生成用户登录失败次数较多时使用的验证码,type为audio时base64为语音",
                 "consumes": [
                     "application/json"
                 ],
@@ -726,7 +726,15 @@ const docTemplate = `{
                 "tags": [
                     "验证码管理"
                 ],
-                "summary": "生成注册验证码",
+                "summary": "生成登录验证码",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "是否获取语音验证码",
+                        "name": "audio",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "10000": {
                         "description": "生成成功，code=10000",
@@ -739,13 +747,19 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/response.BasicResponse-any"
                         }
+                    },
+                    "10002": {
+                        "description": "参数验证失败，code=10002",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
                     }
                 }
             }
         },
-        "/public/user/login": {
-            "post": {
-                "description": "用户通过邮箱和密码进行登录",
+        "/public/captcha/reset-password": {
+            "get": {
+                "description": "生成用户找回密码时使用的验证码,type为audio时base64为语音",
                 "consumes": [
                     "application/json"
                 ],
@@ -753,35 +767,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "用户管理"
+                    "验证码管理"
                 ],
-                "summary": "用户登录",
+                "summary": "生成重置密码验证码",
                 "parameters": [
                     {
-                        "description": "登录请求参数",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/request.LoginRequest"
-                        }
+                        "type": "boolean",
+                        "description": "是否获取语音验证码",
+                        "name": "audio",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "10000": {
-                        "description": "登录成功，code=10000",
+                        "description": "生成成功，code=10000",
                         "schema": {
-                            "$ref": "#/definitions/response.BasicResponse-response_LoginResponse"
+                            "$ref": "#/definitions/response.BasicResponse-response_GetCaptchaResponse"
                         }
                     },
-                    "10002": {
-                        "description": "参数验证失败，code=10002",
+                    "10001": {
+                        "description": "生成失败，code=10001",
                         "schema": {
                             "$ref": "#/definitions/response.BasicResponse-any"
                         }
                     },
-                    "20001": {
-                        "description": "登录失败，code=20001",
+                    "10002": {
+                        "description": "参数验证失败，code=10002",
                         "schema": {
                             "$ref": "#/definitions/response.BasicResponse-any"
                         }
@@ -789,9 +800,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/public/user/signup": {
-            "post": {
-                "description": "用户通过邮箱和密码进行注册",
+        "/public/captcha/signup": {
+            "get": {
+                "description": "生成用户注册时使用的验证码,type为audio时base64为语音",
                 "consumes": [
                     "application/json"
                 ],
@@ -799,29 +810,26 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "用户管理"
+                    "验证码管理"
                 ],
-                "summary": "用户注册",
+                "summary": "生成注册验证码",
                 "parameters": [
                     {
-                        "description": "注册请求参数",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/request.SignUpRequest"
-                        }
+                        "type": "boolean",
+                        "description": "是否获取语音验证码",
+                        "name": "audio",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "10000": {
-                        "description": "注册成功，code=10000",
+                        "description": "生成成功，code=10000",
                         "schema": {
-                            "$ref": "#/definitions/response.BasicResponse-any"
+                            "$ref": "#/definitions/response.BasicResponse-response_GetCaptchaResponse"
                         }
                     },
                     "10001": {
-                        "description": "业务逻辑失败，code=10001",
+                        "description": "生成失败，code=10001",
                         "schema": {
                             "$ref": "#/definitions/response.BasicResponse-any"
                         }
@@ -831,15 +839,53 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/response.BasicResponse-any"
                         }
+                    }
+                }
+            }
+        },
+        "/public/user/data-export": {
+            "get": {
+                "description": "通过邮件中的下载链接下载打包好的个人数据,有效期内可以重复下载",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/zip"
+                ],
+                "tags": [
+                    "用户管理"
+                ],
+                "summary": "下载个人数据",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "下载码",
+                        "name": "code",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "10001": {
+                        "description": "服务器内部错误，code=10001",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
                     },
-                    "20003": {
-                        "description": "密码格式错误，code=20003",
+                    "10002": {
+                        "description": "参数验证失败，code=10002",
                         "schema": {
                             "$ref": "#/definitions/response.BasicResponse-any"
                         }
                     },
-                    "20004": {
-                        "description": "邮箱格式错误，code=20004",
+                    "200": {
+                        "description": "个人数据压缩包",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "20047": {
+                        "description": "下载链接无效或已过期，code=20047",
                         "schema": {
                             "$ref": "#/definitions/response.BasicResponse-any"
                         }
@@ -847,14 +893,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/role/create": {
+        "/public/user/email/confirm": {
             "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "创建新的角色",
+                "description": "使用新邮箱收到的确认链接中的code完成修改,原邮箱会收到附带撤销链接的通知,用户需要刷新token以获取新的邮箱",
                 "consumes": [
                     "application/json"
                 ],
@@ -862,29 +903,29 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "角色管理"
+                    "用户管理"
                 ],
-                "summary": "创建角色",
+                "summary": "确认修改邮箱",
                 "parameters": [
                     {
-                        "description": "创建角色请求参数",
+                        "description": "确认修改邮箱请求参数",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/request.CreateRoleRequest"
+                            "$ref": "#/definitions/request.EmailChangeCodeRequest"
                         }
                     }
                 ],
                 "responses": {
                     "10000": {
-                        "description": "创建成功，code=10000",
+                        "description": "修改成功，code=10000",
                         "schema": {
                             "$ref": "#/definitions/response.BasicResponse-any"
                         }
                     },
                     "10001": {
-                        "description": "业务逻辑失败，code=10001",
+                        "description": "服务器内部错误，code=10001",
                         "schema": {
                             "$ref": "#/definitions/response.BasicResponse-any"
                         }
@@ -895,8 +936,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.BasicResponse-any"
                         }
                     },
-                    "10008": {
-                        "description": "认证失败，code=10008",
+                    "20000": {
+                        "description": "邮箱已被注册，code=20000",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20032": {
+                        "description": "链接无效或已过期，code=20032",
                         "schema": {
                             "$ref": "#/definitions/response.BasicResponse-any"
                         }
@@ -904,14 +951,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/role/delete": {
+        "/public/user/email/revert": {
             "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "根据角色ID删除角色",
+                "description": "使用原邮箱收到的通知中的撤销链接恢复原邮箱,恢复后用户的全部登录会话失效",
                 "consumes": [
                     "application/json"
                 ],
@@ -919,29 +961,29 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "角色管理"
+                    "用户管理"
                 ],
-                "summary": "删除角色",
+                "summary": "撤销修改邮箱",
                 "parameters": [
                     {
-                        "description": "删除角色请求参数",
+                        "description": "撤销修改邮箱请求参数",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/request.DeleteRoleRequest"
+                            "$ref": "#/definitions/request.EmailChangeCodeRequest"
                         }
                     }
                 ],
                 "responses": {
                     "10000": {
-                        "description": "删除成功，code=10000",
+                        "description": "撤销成功，code=10000",
                         "schema": {
                             "$ref": "#/definitions/response.BasicResponse-any"
                         }
                     },
                     "10001": {
-                        "description": "业务逻辑失败，code=10001",
+                        "description": "服务器内部错误，code=10001",
                         "schema": {
                             "$ref": "#/definitions/response.BasicResponse-any"
                         }
@@ -952,8 +994,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.BasicResponse-any"
                         }
                     },
-                    "10008": {
-                        "description": "认证失败，code=10008",
+                    "20000": {
+                        "description": "原邮箱已被注册，code=20000",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20032": {
+                        "description": "链接无效或已过期，code=20032",
                         "schema": {
                             "$ref": "#/definitions/response.BasicResponse-any"
                         }
@@ -961,14 +1009,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/role/detail": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "根据角色ID获取角色详细信息",
+        "/public/user/forgot-password": {
+            "post": {
+                "description": "向邮箱发送重置密码的验证码,邮箱未注册时同样返回成功",
                 "consumes": [
                     "application/json"
                 ],
@@ -976,33 +1019,41 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "角色管理"
+                    "用户管理"
                 ],
-                "summary": "获取角色详情",
+                "summary": "找回密码",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "角色ID",
-                        "name": "id",
-                        "in": "query",
-                        "required": true
+                        "description": "找回密码请求参数",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.ForgotPasswordRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "10000": {
-                        "description": "获取成功，code=10000",
+                        "description": "发送成功，code=10000",
                         "schema": {
-                            "$ref": "#/definitions/response.BasicResponse-response_RoleDetailResponse"
+                            "$ref": "#/definitions/response.BasicResponse-any"
                         }
                     },
-                    "10001": {
-                        "description": "服务器内部错误，code=10001",
+                    "10002": {
+                        "description": "参数验证失败，code=10002",
                         "schema": {
                             "$ref": "#/definitions/response.BasicResponse-any"
                         }
                     },
-                    "10002": {
-                        "description": "参数验证失败，code=10002",
+                    "20011": {
+                        "description": "请求过于频繁，code=20011",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "30000": {
+                        "description": "验证码错误，code=30000",
                         "schema": {
                             "$ref": "#/definitions/response.BasicResponse-any"
                         }
@@ -1010,14 +1061,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/role/list": {
+        "/public/user/invitation": {
             "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "分页获取角色列表，支持关键词搜索",
+                "description": "根据邀请邮件中的邀请码查询邀请的邮箱、邀请人和过期时间,用于接受邀请页面的预填",
                 "consumes": [
                     "application/json"
                 ],
@@ -1025,36 +1071,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "角色管理"
+                    "用户管理"
                 ],
-                "summary": "获取角色列表",
+                "summary": "查询注册邀请",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "搜索关键词",
-                        "name": "keyword",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "每页数量",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 0,
-                        "description": "偏移量",
-                        "name": "offset",
-                        "in": "query"
+                        "description": "邀请码",
+                        "name": "code",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "10000": {
                         "description": "获取成功，code=10000",
                         "schema": {
-                            "$ref": "#/definitions/response.BasicResponse-response_DataList-response_RoleListRowResponse"
+                            "$ref": "#/definitions/response.BasicResponse-response_InvitationResponse"
                         }
                     },
                     "10001": {
@@ -1068,18 +1101,25 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/response.BasicResponse-any"
                         }
+                    },
+                    "20030": {
+                        "description": "注册已关闭，code=20030",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20031": {
+                        "description": "邀请不存在、已过期或已被接受，code=20031",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
                     }
                 }
             }
         },
-        "/role/update": {
+        "/public/user/invitation/accept": {
             "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "更新角色信息",
+                "description": "使用邀请码设置密码完成注册,创建的用户无需激活并关联邀请中预设的角色和部门",
                 "consumes": [
                     "application/json"
                 ],
@@ -1087,29 +1127,29 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "角色管理"
+                    "用户管理"
                 ],
-                "summary": "更新角色",
+                "summary": "接受注册邀请",
                 "parameters": [
                     {
-                        "description": "更新角色请求参数",
+                        "description": "接受注册邀请请求参数",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/request.UpdateRoleRequest"
+                            "$ref": "#/definitions/request.AcceptInvitationRequest"
                         }
                     }
                 ],
                 "responses": {
                     "10000": {
-                        "description": "更新成功，code=10000",
+                        "description": "注册成功，code=10000",
                         "schema": {
                             "$ref": "#/definitions/response.BasicResponse-any"
                         }
                     },
                     "10001": {
-                        "description": "业务逻辑失败，code=10001",
+                        "description": "服务器内部错误，code=10001",
                         "schema": {
                             "$ref": "#/definitions/response.BasicResponse-any"
                         }
@@ -1120,8 +1160,26 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.BasicResponse-any"
                         }
                     },
-                    "10008": {
-                        "description": "认证失败，code=10008",
+                    "20000": {
+                        "description": "邮箱已被注册，code=20000",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20030": {
+                        "description": "注册已关闭，code=20030",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20031": {
+                        "description": "邀请不存在、已过期或已被接受，code=20031",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20033": {
+                        "description": "密码不符合密码策略，code=20033~20039",
                         "schema": {
                             "$ref": "#/definitions/response.BasicResponse-any"
                         }
@@ -1129,14 +1187,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/list": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "分页获取用户列表，支持关键词搜索",
+        "/public/user/login": {
+            "post": {
+                "description": "用户通过邮箱和密码进行登录,开启两步验证的用户返回 mfa_ticket,需要调用 /public/user/login/mfa 完成登录;\n密码超过有效期的用户返回 password_ticket,需要调用 /public/user/password/expired 修改密码后完成登录;\n登录失败后需要等待一段时间才能重试,失败次数较多时需要携带 /public/captcha/login 的验证码,达到阈值后临时锁定",
                 "consumes": [
                     "application/json"
                 ],
@@ -1146,34 +1199,145 @@ const docTemplate = `{
                 "tags": [
                     "用户管理"
                 ],
-                "summary": "获取用户列表",
+                "summary": "用户登录",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "搜索关键词",
-                        "name": "keyword",
-                        "in": "query"
+                        "description": "登录请求参数",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "10000": {
+                        "description": "登录成功，code=10000",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-response_LoginResponse"
+                        }
+                    },
+                    "10002": {
+                        "description": "参数验证失败，code=10002",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "10010": {
+                        "description": "登录失败后的等待期内，code=10010",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20001": {
+                        "description": "登录失败，code=20001",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20024": {
+                        "description": "账户已被临时锁定，code=20024",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20025": {
+                        "description": "需要验证码，code=20025",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
                     },
+                    "30000": {
+                        "description": "验证码错误，code=30000",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/public/user/login/mfa": {
+            "post": {
+                "description": "使用登录接口返回的 mfa_ticket 和 TOTP 验证码(或恢复码)换取 token,凭证只能使用一次,验证码错误时需要重新登录",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户管理"
+                ],
+                "summary": "两步验证登录",
+                "parameters": [
                     {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "每页数量",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "两步验证登录请求参数",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.LoginMFARequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "10000": {
+                        "description": "登录成功，code=10000",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-response_LoginResponse"
+                        }
+                    },
+                    "10002": {
+                        "description": "参数验证失败，code=10002",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20015": {
+                        "description": "验证码错误，code=20015",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
                     },
+                    "20016": {
+                        "description": "登录凭证无效，code=20016",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/public/user/login/sms": {
+            "post": {
+                "description": "使用绑定的手机号和短信验证码登录,开启两步验证的用户返回 mfa_ticket,需要调用 /public/user/login/mfa 完成登录",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户管理"
+                ],
+                "summary": "手机号验证码登录",
+                "parameters": [
                     {
-                        "type": "integer",
-                        "default": 0,
-                        "description": "偏移量",
-                        "name": "offset",
-                        "in": "query"
+                        "description": "手机号验证码登录请求参数",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.LoginSMSRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "10000": {
-                        "description": "获取成功，code=10000",
+                        "description": "登录成功，code=10000",
                         "schema": {
-                            "$ref": "#/definitions/response.BasicResponse-response_DataList-response_UserListRowResponse"
+                            "$ref": "#/definitions/response.BasicResponse-response_LoginResponse"
                         }
                     },
                     "10001": {
@@ -1187,13 +1351,37 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/response.BasicResponse-any"
                         }
+                    },
+                    "20005": {
+                        "description": "用户未激活，code=20005",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20006": {
+                        "description": "用户已禁用，code=20006",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20042": {
+                        "description": "手机号格式错误，code=20042",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20043": {
+                        "description": "验证码错误或已过期，code=20043",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
                     }
                 }
             }
         },
-        "/user/logout": {
+        "/public/user/password/expired": {
             "post": {
-                "description": "用户登出(退出登录)",
+                "description": "使用登录接口返回的 password_ticket 修改超过有效期的密码,修改成功后其他登录会话全部失效并继续完成登录,\n开启两步验证的用户返回 mfa_ticket",
                 "consumes": [
                     "application/json"
                 ],
@@ -1203,16 +1391,39 @@ const docTemplate = `{
                 "tags": [
                     "用户管理"
                 ],
-                "summary": "退出登录",
+                "summary": "修改过期密码",
+                "parameters": [
+                    {
+                        "description": "修改过期密码请求参数",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.ChangeExpiredPasswordRequest"
+                        }
+                    }
+                ],
                 "responses": {
                     "10000": {
-                        "description": "登出成功，code=10000",
+                        "description": "修改成功，code=10000",
                         "schema": {
                             "$ref": "#/definitions/response.BasicResponse-response_LoginResponse"
                         }
                     },
-                    "20001": {
-                        "description": "登出失败，code=20001",
+                    "10002": {
+                        "description": "参数验证失败，code=10002",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20033": {
+                        "description": "密码不符合密码策略，code=20033~20040",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20041": {
+                        "description": "修改密码凭证无效，code=20041",
                         "schema": {
                             "$ref": "#/definitions/response.BasicResponse-any"
                         }
@@ -1220,14 +1431,67 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/profile": {
-            "get": {
-                "security": [
+        "/public/user/reset-password": {
+            "post": {
+                "description": "使用邮件中的验证码重置密码,重置成功后用户在全部设备上的登录会话失效",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户管理"
+                ],
+                "summary": "重置密码",
+                "parameters": [
                     {
-                        "BearerAuth": []
+                        "description": "重置密码请求参数",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.ResetPasswordRequest"
+                        }
                     }
                 ],
-                "description": "获取当前登录用户的详细资料信息",
+                "responses": {
+                    "10000": {
+                        "description": "重置成功，code=10000",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "10002": {
+                        "description": "参数验证失败，code=10002",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20010": {
+                        "description": "验证码错误或已过期，code=20010",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20011": {
+                        "description": "请求过于频繁，code=20011",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20033": {
+                        "description": "密码不符合密码策略，code=20033~20040",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/public/user/signup": {
+            "post": {
+                "description": "用户通过邮箱和密码进行注册",
                 "consumes": [
                     "application/json"
                 ],
@@ -1237,35 +1501,2766 @@ const docTemplate = `{
                 "tags": [
                     "用户管理"
                 ],
-                "summary": "获取用户资料",
+                "summary": "用户注册",
+                "parameters": [
+                    {
+                        "description": "注册请求参数",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.SignUpRequest"
+                        }
+                    }
+                ],
                 "responses": {
                     "10000": {
-                        "description": "获取成功，code=10000",
+                        "description": "注册成功，code=10000",
                         "schema": {
-                            "$ref": "#/definitions/response.BasicResponse-response_ProfileResponse"
+                            "$ref": "#/definitions/response.BasicResponse-any"
                         }
                     },
                     "10001": {
-                        "description": "服务器内部错误，code=10001",
+                        "description": "业务逻辑失败，code=10001",
                         "schema": {
                             "$ref": "#/definitions/response.BasicResponse-any"
                         }
                     },
-                    "20005": {
-                        "description": "用户不存在，code=20005",
+                    "10002": {
+                        "description": "参数验证失败，code=10002",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20004": {
+                        "description": "邮箱格式错误，code=20004",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20030": {
+                        "description": "注册已关闭，code=20030",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20033": {
+                        "description": "密码不符合密码策略，code=20033~20039",
                         "schema": {
                             "$ref": "#/definitions/response.BasicResponse-any"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "constant.PermissionType": {
-            "type": "integer",
-            "format": "int32",
-            "enum": [
+        },
+        "/public/user/sms/login-code": {
+            "post": {
+                "description": "校验图形验证码(登录验证码)后向手机号发送登录短信验证码,手机号未绑定时同样返回成功",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户管理"
+                ],
+                "summary": "发送登录验证码",
+                "parameters": [
+                    {
+                        "description": "发送登录验证码请求参数",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.SendLoginSMSCodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "10000": {
+                        "description": "发送成功，code=10000",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "10001": {
+                        "description": "服务器内部错误，code=10001",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "10002": {
+                        "description": "参数验证失败，code=10002",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20042": {
+                        "description": "手机号格式错误，code=20042",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20044": {
+                        "description": "发送过于频繁，code=20044",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "30000": {
+                        "description": "图形验证码错误，code=30000",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/public/user/sso/providers": {
+            "get": {
+                "description": "获取已配置的单点登录身份提供方标识列表",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户管理"
+                ],
+                "summary": "获取单点登录身份提供方",
+                "responses": {
+                    "10000": {
+                        "description": "获取成功，code=10000",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-array_string"
+                        }
+                    }
+                }
+            }
+        },
+        "/public/user/sso/{provider}/authorize": {
+            "get": {
+                "description": "生成身份提供方的授权地址(授权码模式 + PKCE),前端跳转至该地址完成认证,state同时写入HttpOnly的cookie,登录时校验",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户管理"
+                ],
+                "summary": "获取单点登录授权地址",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "身份提供方标识",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "10000": {
+                        "description": "获取成功，code=10000",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-response_SSOAuthorizeResponse"
+                        }
+                    },
+                    "20017": {
+                        "description": "身份提供方不存在，code=20017",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/public/user/sso/{provider}/login": {
+            "post": {
+                "description": "使用身份提供方回调的 code 和 state 登录,state需要与发起授权时写入的cookie一致,开启两步验证的用户返回 mfa_ticket,需要调用 /public/user/login/mfa 完成登录",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户管理"
+                ],
+                "summary": "单点登录",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "身份提供方标识",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "单点登录请求参数",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.SSOLoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "10000": {
+                        "description": "登录成功，code=10000",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-response_LoginResponse"
+                        }
+                    },
+                    "10002": {
+                        "description": "参数验证失败，code=10002",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20017": {
+                        "description": "身份提供方不存在，code=20017",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20018": {
+                        "description": "state无效或已过期，code=20018",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20019": {
+                        "description": "单点登录失败，code=20019",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20030": {
+                        "description": "注册已关闭，未关联的外部账户不能自动创建用户，code=20030",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/role/create": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "创建新的角色",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "角色管理"
+                ],
+                "summary": "创建角色",
+                "parameters": [
+                    {
+                        "description": "创建角色请求参数",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.CreateRoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "10000": {
+                        "description": "创建成功，code=10000",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "10001": {
+                        "description": "业务逻辑失败，code=10001",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "10002": {
+                        "description": "参数验证失败，code=10002",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "10008": {
+                        "description": "认证失败，code=10008",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/role/delete": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "根据角色ID删除角色",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "角色管理"
+                ],
+                "summary": "删除角色",
+                "parameters": [
+                    {
+                        "description": "删除角色请求参数",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.DeleteRoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "10000": {
+                        "description": "删除成功，code=10000",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "10001": {
+                        "description": "业务逻辑失败，code=10001",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "10002": {
+                        "description": "参数验证失败，code=10002",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "10008": {
+                        "description": "认证失败，code=10008",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/role/detail": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "根据角色ID获取角色详细信息",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "角色管理"
+                ],
+                "summary": "获取角色详情",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "角色ID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "10000": {
+                        "description": "获取成功，code=10000",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-response_RoleDetailResponse"
+                        }
+                    },
+                    "10001": {
+                        "description": "服务器内部错误，code=10001",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "10002": {
+                        "description": "参数验证失败，code=10002",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/role/list": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "分页获取角色列表，支持关键词搜索",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "角色管理"
+                ],
+                "summary": "获取角色列表",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "搜索关键词",
+                        "name": "keyword",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "每页数量",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "偏移量",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "10000": {
+                        "description": "获取成功，code=10000",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-response_DataList-response_RoleListRowResponse"
+                        }
+                    },
+                    "10001": {
+                        "description": "服务器内部错误，code=10001",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "10002": {
+                        "description": "参数验证失败，code=10002",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/role/update": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "更新角色信息",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "角色管理"
+                ],
+                "summary": "更新角色",
+                "parameters": [
+                    {
+                        "description": "更新角色请求参数",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.UpdateRoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "10000": {
+                        "description": "更新成功，code=10000",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "10001": {
+                        "description": "业务逻辑失败，code=10001",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "10002": {
+                        "description": "参数验证失败，code=10002",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "10008": {
+                        "description": "认证失败，code=10008",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/api-keys": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "获取当前用户的API密钥列表,不包含密钥明文",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户管理"
+                ],
+                "summary": "获取API密钥",
+                "responses": {
+                    "10000": {
+                        "description": "获取成功，code=10000",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-array_response_APIKeyResponse"
+                        }
+                    },
+                    "10001": {
+                        "description": "服务器内部错误，code=10001",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/api-keys/create": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "创建供脚本等机器客户端使用的API密钥,请求时通过 X-API-Key 头携带,权限范围只能是当前用户已拥有的接口权限,密钥明文仅返回一次",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户管理"
+                ],
+                "summary": "创建API密钥",
+                "parameters": [
+                    {
+                        "description": "创建API密钥请求参数",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.CreateAPIKeyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "10000": {
+                        "description": "创建成功，code=10000",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-response_CreateAPIKeyResponse"
+                        }
+                    },
+                    "10002": {
+                        "description": "参数验证失败，code=10002",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20022": {
+                        "description": "API密钥数量已达上限，code=20022",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20023": {
+                        "description": "权限范围超出用户权限，code=20023",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "50001": {
+                        "description": "权限不存在，code=50001",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/api-keys/delete": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "删除当前用户的API密钥,删除后立即失效",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户管理"
+                ],
+                "summary": "删除API密钥",
+                "parameters": [
+                    {
+                        "description": "删除API密钥请求参数",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.DeleteAPIKeyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "10000": {
+                        "description": "删除成功，code=10000",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "10002": {
+                        "description": "参数验证失败，code=10002",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20021": {
+                        "description": "API密钥不存在，code=20021",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/assign": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "管理员替换用户的角色和部门,被移除角色时用户已签发的token立即失效",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户管理"
+                ],
+                "summary": "分配角色和部门",
+                "parameters": [
+                    {
+                        "description": "分配角色和部门请求参数",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.AssignUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "10000": {
+                        "description": "分配成功，code=10000",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "10002": {
+                        "description": "参数验证失败，code=10002",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20004": {
+                        "description": "用户不存在，code=20004",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20026": {
+                        "description": "不能管理自己或系统管理员，code=20026",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/create": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "管理员创建用户并设置角色和部门,发送激活邮件时用户需激活后才能登录,否则直接启用",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户管理"
+                ],
+                "summary": "创建用户",
+                "parameters": [
+                    {
+                        "description": "创建用户请求参数",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.CreateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "10000": {
+                        "description": "创建成功，code=10000",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "10001": {
+                        "description": "服务器内部错误，code=10001",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "10002": {
+                        "description": "参数验证失败，code=10002",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20000": {
+                        "description": "邮箱已被注册，code=20000",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20033": {
+                        "description": "密码不符合密码策略，code=20033~20039",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/data-export": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "异步打包当前用户的个人资料、角色、部门、附件信息和登录记录,完成后向用户邮箱发送下载链接,\n打包结果为zip文件,每类数据为一个json文件;两次申请之间需要间隔一段时间",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户管理"
+                ],
+                "summary": "导出个人数据",
+                "responses": {
+                    "10000": {
+                        "description": "申请成功，code=10000",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "10001": {
+                        "description": "服务器内部错误，code=10001",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20029": {
+                        "description": "模拟登录期间不允许该操作，code=20029",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20046": {
+                        "description": "申请过于频繁，code=20046",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/delete": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "管理员删除用户(软删除),删除后用户全部的登录会话被注销,已签发的token立即失效",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户管理"
+                ],
+                "summary": "删除用户",
+                "parameters": [
+                    {
+                        "description": "删除用户请求参数",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.DeleteUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "10000": {
+                        "description": "删除成功，code=10000",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "10002": {
+                        "description": "参数验证失败，code=10002",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20004": {
+                        "description": "用户不存在，code=20004",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20026": {
+                        "description": "不能管理自己或系统管理员，code=20026",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/email/change": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "校验当前密码后向新邮箱发送确认链接,通过 /public/user/email/confirm 确认后邮箱才会变更",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户管理"
+                ],
+                "summary": "修改邮箱",
+                "parameters": [
+                    {
+                        "description": "修改邮箱请求参数",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.ChangeEmailRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "10000": {
+                        "description": "确认邮件已发送，code=10000",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "10001": {
+                        "description": "服务器内部错误，code=10001",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "10002": {
+                        "description": "参数验证失败，code=10002",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20000": {
+                        "description": "邮箱已被注册，code=20000",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20003": {
+                        "description": "邮箱格式错误，code=20003",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20012": {
+                        "description": "密码错误，code=20012",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/erase": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "管理员注销用户(已删除的用户同样可以注销),清除用户的个人信息、角色和部门关联、两步验证、绑定的外部账户、API密钥和登录记录,\n用户记录保留为已注销的匿名账户,其创建和更新的角色、权限、部门等数据不受影响;注销后不能恢复",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户管理"
+                ],
+                "summary": "注销用户",
+                "parameters": [
+                    {
+                        "description": "注销用户请求参数",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.EraseUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "10000": {
+                        "description": "注销成功，code=10000",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "10002": {
+                        "description": "参数验证失败，code=10002",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20004": {
+                        "description": "用户不存在，code=20004",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20026": {
+                        "description": "不能管理自己或系统管理员，code=20026",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20048": {
+                        "description": "用户已注销，code=20048",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "按条件导出用户为csv或xlsx文件,导出的文件可以直接用于批量导入",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "用户管理"
+                ],
+                "summary": "导出用户",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "搜索关键词",
+                        "name": "keyword",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "账户状态",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "角色ID",
+                        "name": "role_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "部门ID",
+                        "name": "department_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "csv",
+                            "xlsx"
+                        ],
+                        "type": "string",
+                        "default": "csv",
+                        "description": "文件格式",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "10001": {
+                        "description": "服务器内部错误，code=10001",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "10002": {
+                        "description": "参数验证失败，code=10002",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "200": {
+                        "description": "导出的文件",
+                        "schema": {
+                            "type": "file"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/impersonate": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "管理员以指定用户的身份获取短token,用于复现用户看到的菜单和权限;token有效期较短且不签发refresh token,\n沿用管理员当前的登录会话,期间的请求按被模拟用户的权限鉴权,写操作记录为管理员本人",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户管理"
+                ],
+                "summary": "模拟登录",
+                "parameters": [
+                    {
+                        "description": "模拟登录请求参数",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.ImpersonateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "10000": {
+                        "description": "模拟登录成功，code=10000",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-response_ImpersonateResponse"
+                        }
+                    },
+                    "10001": {
+                        "description": "服务器内部错误，code=10001",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "10002": {
+                        "description": "参数验证失败，code=10002",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20004": {
+                        "description": "用户不存在，code=20004",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20005": {
+                        "description": "用户未激活，code=20005",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20006": {
+                        "description": "用户已禁用，code=20006",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20026": {
+                        "description": "不能模拟自己、系统管理员或权限超过自己的用户，code=20026",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20029": {
+                        "description": "模拟登录期间不允许该操作，code=20029",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/impersonate/stop": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "使用模拟登录的token调用,吊销该token并记录结束时间,管理员本人的登录会话不受影响",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户管理"
+                ],
+                "summary": "结束模拟登录",
+                "responses": {
+                    "10000": {
+                        "description": "结束成功，code=10000",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "10001": {
+                        "description": "服务器内部错误，code=10001",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "10003": {
+                        "description": "不是模拟登录的token，code=10003",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/impersonations": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "分页获取模拟登录的审计记录,支持按操作人和被模拟用户过滤",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户管理"
+                ],
+                "summary": "获取模拟登录记录",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "操作人ID",
+                        "name": "actor_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "被模拟的用户ID",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "每页数量",
+                        "name": "limit",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "偏移量",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "10000": {
+                        "description": "获取成功，code=10000",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-response_DataList-models_UserImpersonation"
+                        }
+                    },
+                    "10001": {
+                        "description": "服务器内部错误，code=10001",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "10002": {
+                        "description": "参数验证失败，code=10002",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/import": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "上传csv或xlsx文件批量导入用户,首行为表头,包含email、nickname、departments(部门路径,如\"总部/研发部\",多个用逗号分隔)和roles(角色名称,多个用逗号分隔)列;\n全部行校验通过后才会按批次创建用户,存在校验错误时返回每一行的错误且不创建任何用户",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户管理"
+                ],
+                "summary": "批量导入用户",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "csv或xlsx文件",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "是否仅校验",
+                        "name": "dry_run",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "是否发送激活邮件",
+                        "name": "send_active_email",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "10000": {
+                        "description": "导入完成，code=10000",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-response_ImportUsersResponse"
+                        }
+                    },
+                    "10001": {
+                        "description": "服务器内部错误，code=10001",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "10002": {
+                        "description": "参数验证失败，code=10002",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20027": {
+                        "description": "导入文件无效，code=20027",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20028": {
+                        "description": "导入文件行数超过限制，code=20028",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/invitations": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "分页获取注册邀请,包含邀请人、预设的角色和部门以及接受状态",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户管理"
+                ],
+                "summary": "获取注册邀请列表",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "邮箱关键字",
+                        "name": "keyword",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "每页数量",
+                        "name": "limit",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "偏移量",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "10000": {
+                        "description": "获取成功，code=10000",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-response_DataList-models_UserInvitation"
+                        }
+                    },
+                    "10001": {
+                        "description": "服务器内部错误，code=10001",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "10002": {
+                        "description": "参数验证失败，code=10002",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/invitations/create": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "向指定邮箱发送注册邀请,可以预设角色和部门,被邀请人接受邀请后直接创建已激活的用户;关闭注册时不能创建邀请",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户管理"
+                ],
+                "summary": "创建注册邀请",
+                "parameters": [
+                    {
+                        "description": "创建注册邀请请求参数",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.CreateInvitationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "10000": {
+                        "description": "创建成功，code=10000",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "10001": {
+                        "description": "服务器内部错误，code=10001",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "10002": {
+                        "description": "参数验证失败，code=10002",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20000": {
+                        "description": "邮箱已被注册，code=20000",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20030": {
+                        "description": "注册已关闭，code=20030",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/invitations/revoke": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "撤销未被接受的注册邀请,撤销后邀请邮件中的链接失效",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户管理"
+                ],
+                "summary": "撤销注册邀请",
+                "parameters": [
+                    {
+                        "description": "撤销注册邀请请求参数",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.RevokeInvitationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "10000": {
+                        "description": "撤销成功，code=10000",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "10001": {
+                        "description": "服务器内部错误，code=10001",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "10002": {
+                        "description": "参数验证失败，code=10002",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20031": {
+                        "description": "邀请不存在或已被接受，code=20031",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/list": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "分页获取用户列表，支持按关键词、账户状态、角色和部门过滤",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户管理"
+                ],
+                "summary": "获取用户列表",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "搜索关键词",
+                        "name": "keyword",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "账户状态",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "角色ID",
+                        "name": "role_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "部门ID",
+                        "name": "department_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "每页数量",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "偏移量",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "10000": {
+                        "description": "获取成功，code=10000",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-response_DataList-response_UserListRowResponse"
+                        }
+                    },
+                    "10001": {
+                        "description": "服务器内部错误，code=10001",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "10002": {
+                        "description": "参数验证失败，code=10002",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/login-histories": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "分页查询全部用户的登录记录,支持按用户、邮箱、登录方式、结果、IP和时间范围过滤",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户管理"
+                ],
+                "summary": "查询登录记录",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "用户ID",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "登录邮箱(模糊匹配)",
+                        "name": "email",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "password",
+                            "mfa",
+                            "sso",
+                            "sms",
+                            "refresh"
+                        ],
+                        "type": "string",
+                        "description": "登录方式",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "是否成功",
+                        "name": "success",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IP",
+                        "name": "ip",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "开始时间(秒级时间戳)",
+                        "name": "start",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "结束时间(秒级时间戳)",
+                        "name": "end",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "每页数量",
+                        "name": "limit",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "偏移量",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "10000": {
+                        "description": "获取成功，code=10000",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-response_DataList-models_UserLoginHistory"
+                        }
+                    },
+                    "10001": {
+                        "description": "服务器内部错误，code=10001",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "10002": {
+                        "description": "参数验证失败，code=10002",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/login-history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "分页获取当前用户的登录记录,包括失败的登录尝试和token刷新",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户管理"
+                ],
+                "summary": "获取登录记录",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "每页数量",
+                        "name": "limit",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "偏移量",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "10000": {
+                        "description": "获取成功，code=10000",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-response_DataList-models_UserLoginHistory"
+                        }
+                    },
+                    "10001": {
+                        "description": "服务器内部错误，code=10001",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "10002": {
+                        "description": "参数验证失败，code=10002",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/login/unlock": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "管理员解除因登录失败次数过多导致的邮箱(及ip)锁定,并清空失败次数",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户管理"
+                ],
+                "summary": "解除登录锁定",
+                "parameters": [
+                    {
+                        "description": "解除登录锁定请求参数",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.UnlockLoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "10000": {
+                        "description": "解除成功，code=10000",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "10001": {
+                        "description": "服务器内部错误，code=10001",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "10002": {
+                        "description": "参数验证失败，code=10002",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/logout": {
+            "post": {
+                "description": "用户登出(退出登录),仅注销当前会话",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户管理"
+                ],
+                "summary": "退出登录",
+                "responses": {
+                    "10000": {
+                        "description": "登出成功，code=10000",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-response_LoginResponse"
+                        }
+                    },
+                    "20001": {
+                        "description": "登出失败，code=20001",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/mfa/disable": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "校验 TOTP 验证码或恢复码后关闭两步验证",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户管理"
+                ],
+                "summary": "关闭两步验证",
+                "parameters": [
+                    {
+                        "description": "验证码或恢复码",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.MFACodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "10000": {
+                        "description": "关闭成功，code=10000",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20013": {
+                        "description": "未开启两步验证，code=20013",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20015": {
+                        "description": "验证码错误，code=20015",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/mfa/enroll": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "生成 TOTP 密钥及二维码,使用认证器App扫码后调用 /user/mfa/verify 完成绑定",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户管理"
+                ],
+                "summary": "绑定两步验证",
+                "responses": {
+                    "10000": {
+                        "description": "生成成功，code=10000",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-response_MFAEnrollResponse"
+                        }
+                    },
+                    "20014": {
+                        "description": "已开启两步验证，code=20014",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/mfa/recovery-codes": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "校验 TOTP 验证码后重新生成恢复码,旧的恢复码全部失效",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户管理"
+                ],
+                "summary": "重新生成恢复码",
+                "parameters": [
+                    {
+                        "description": "验证码",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.MFACodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "10000": {
+                        "description": "生成成功，code=10000",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-response_MFARecoveryCodesResponse"
+                        }
+                    },
+                    "20013": {
+                        "description": "未开启两步验证，code=20013",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20015": {
+                        "description": "验证码错误，code=20015",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/mfa/verify": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "校验认证器App生成的验证码,校验通过后开启两步验证并返回恢复码(仅返回一次)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户管理"
+                ],
+                "summary": "开启两步验证",
+                "parameters": [
+                    {
+                        "description": "验证码",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.MFACodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "10000": {
+                        "description": "开启成功，code=10000",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-response_MFARecoveryCodesResponse"
+                        }
+                    },
+                    "20013": {
+                        "description": "未绑定两步验证，code=20013",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20015": {
+                        "description": "验证码错误，code=20015",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/password/change": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "校验原密码后修改密码,修改成功后除当前会话外的其他登录会话全部失效",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户管理"
+                ],
+                "summary": "修改密码",
+                "parameters": [
+                    {
+                        "description": "修改密码请求参数",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.ChangePasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "10000": {
+                        "description": "修改成功，code=10000",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "10002": {
+                        "description": "参数验证失败，code=10002",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20012": {
+                        "description": "原密码错误，code=20012",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20033": {
+                        "description": "密码不符合密码策略，code=20033~20040",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/phone/bind": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "校验短信验证码后绑定手机号,已绑定的手机号会被替换,绑定后可使用手机号验证码登录",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户管理"
+                ],
+                "summary": "绑定手机号",
+                "parameters": [
+                    {
+                        "description": "绑定手机号请求参数",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.BindPhoneRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "10000": {
+                        "description": "绑定成功，code=10000",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "10001": {
+                        "description": "服务器内部错误，code=10001",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "10002": {
+                        "description": "参数验证失败，code=10002",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20029": {
+                        "description": "模拟登录期间不允许该操作，code=20029",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20042": {
+                        "description": "手机号格式错误，code=20042",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20043": {
+                        "description": "验证码错误或已过期，code=20043",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20045": {
+                        "description": "手机号已被其他账户绑定，code=20045",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/phone/code": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "向待绑定的手机号发送短信验证码,同一手机号受重新发送间隔和每日发送次数限制",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户管理"
+                ],
+                "summary": "发送绑定手机号验证码",
+                "parameters": [
+                    {
+                        "description": "发送绑定手机号验证码请求参数",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.SendBindPhoneCodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "10000": {
+                        "description": "发送成功，code=10000",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "10001": {
+                        "description": "服务器内部错误，code=10001",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "10002": {
+                        "description": "参数验证失败，code=10002",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20029": {
+                        "description": "模拟登录期间不允许该操作，code=20029",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20042": {
+                        "description": "手机号格式错误，code=20042",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20044": {
+                        "description": "发送过于频繁，code=20044",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20045": {
+                        "description": "手机号已被其他账户绑定，code=20045",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/profile": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "获取当前登录用户的详细资料信息",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户管理"
+                ],
+                "summary": "获取用户资料",
+                "responses": {
+                    "10000": {
+                        "description": "获取成功，code=10000",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-response_ProfileResponse"
+                        }
+                    },
+                    "10001": {
+                        "description": "服务器内部错误，code=10001",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20005": {
+                        "description": "用户不存在，code=20005",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/profile/update": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "更新当前登录用户的昵称、头像、性别、简介和生日,未传递的字段会被清空",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户管理"
+                ],
+                "summary": "更新个人资料",
+                "parameters": [
+                    {
+                        "description": "个人资料",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.UpdateProfileRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "10000": {
+                        "description": "更新成功，code=10000",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "10001": {
+                        "description": "服务器内部错误，code=10001",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "10002": {
+                        "description": "参数验证失败，code=10002",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/refresh-token": {
+            "post": {
+                "description": "使用 refresh token 换取新的 token 和 refresh token,旧的 refresh token 随即失效,重复使用会使该次登录签发的全部 refresh token 失效",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户管理"
+                ],
+                "summary": "刷新token",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "refresh token",
+                        "name": "Refresh-Token",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "10000": {
+                        "description": "刷新成功，code=10000",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-response_RefreshTokenResponse"
+                        }
+                    },
+                    "10006": {
+                        "description": "refresh token 无效，code=10006",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "10011": {
+                        "description": "refresh token 被重复使用，code=10011",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "管理员恢复已删除的用户,角色和部门随之恢复",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户管理"
+                ],
+                "summary": "恢复用户",
+                "parameters": [
+                    {
+                        "description": "恢复用户请求参数",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.RestoreUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "10000": {
+                        "description": "恢复成功，code=10000",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "10002": {
+                        "description": "参数验证失败，code=10002",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20004": {
+                        "description": "已删除的用户不存在，code=20004",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20048": {
+                        "description": "用户已注销，code=20048",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "获取当前用户在各个设备上的登录会话",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户管理"
+                ],
+                "summary": "获取登录会话",
+                "responses": {
+                    "10000": {
+                        "description": "获取成功，code=10000",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-array_response_UserSessionResponse"
+                        }
+                    },
+                    "10001": {
+                        "description": "服务器内部错误，code=10001",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/sessions/revoke": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "注销当前用户指定的登录会话,被注销会话的 token 和 refresh token 随即失效",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户管理"
+                ],
+                "summary": "注销登录会话",
+                "parameters": [
+                    {
+                        "description": "注销会话请求参数",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.RevokeSessionsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "10000": {
+                        "description": "注销成功，code=10000",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "10001": {
+                        "description": "服务器内部错误，code=10001",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "10002": {
+                        "description": "参数验证失败，code=10002",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/sessions/revoke-all": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "管理员注销指定用户在全部设备上的登录会话",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户管理"
+                ],
+                "summary": "注销用户全部登录会话",
+                "parameters": [
+                    {
+                        "description": "注销用户会话请求参数",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.RevokeUserSessionsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "10000": {
+                        "description": "注销成功，code=10000",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "10001": {
+                        "description": "服务器内部错误，code=10001",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "10002": {
+                        "description": "参数验证失败，code=10002",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20005": {
+                        "description": "用户不存在，code=20005",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/status": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "管理员启用或禁用用户,禁用后用户全部的登录会话被注销,已签发的token立即失效",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户管理"
+                ],
+                "summary": "启用/禁用用户",
+                "parameters": [
+                    {
+                        "description": "更新用户状态请求参数",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.UpdateUserStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "10000": {
+                        "description": "更新成功，code=10000",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "10002": {
+                        "description": "参数验证失败，code=10002",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20004": {
+                        "description": "用户不存在，code=20004",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    },
+                    "20026": {
+                        "description": "不能管理自己或系统管理员，code=20026",
+                        "schema": {
+                            "$ref": "#/definitions/response.BasicResponse-any"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "constant.CaptchaDriver": {
+            "type": "string",
+            "enum": [
+                "digit",
+                "string",
+                "math",
+                "audio",
+                "chinese"
+            ],
+            "x-enum-comments": {
+                "AudioCaptcha": "数字语音",
+                "ChineseCaptcha": "汉字图片",
+                "DigitCaptcha": "数字图片",
+                "MathCaptcha": "算术题图片",
+                "StringCaptcha": "字母数字图片"
+            },
+            "x-enum-descriptions": [
+                "数字图片",
+                "字母数字图片",
+                "算术题图片",
+                "数字语音",
+                "汉字图片"
+            ],
+            "x-enum-varnames": [
+                "DigitCaptcha",
+                "StringCaptcha",
+                "MathCaptcha",
+                "AudioCaptcha",
+                "ChineseCaptcha"
+            ]
+        },
+        "constant.DataScope": {
+            "type": "integer",
+            "format": "int32",
+            "enum": [
+                1,
+                2,
+                3,
+                4,
+                5
+            ],
+            "x-enum-comments": {
+                "DataScopeAll": "all",
+                "DataScopeCustom": "custom",
+                "DataScopeDepartment": "department",
+                "DataScopeDepartmentTree": "departmentTree",
+                "DataScopeSelf": "self"
+            },
+            "x-enum-descriptions": [
+                "all",
+                "department",
+                "departmentTree",
+                "custom",
+                "self"
+            ],
+            "x-enum-varnames": [
+                "DataScopeAll",
+                "DataScopeDepartment",
+                "DataScopeDepartmentTree",
+                "DataScopeCustom",
+                "DataScopeSelf"
+            ]
+        },
+        "constant.LoginType": {
+            "type": "string",
+            "enum": [
+                "password",
+                "mfa",
+                "sso",
+                "sms",
+                "refresh"
+            ],
+            "x-enum-comments": {
+                "LoginMFA": "两步验证登录",
+                "LoginPassword": "账号密码登录",
+                "LoginRefresh": "刷新token",
+                "LoginSMS": "手机验证码登录",
+                "LoginSSO": "单点登录"
+            },
+            "x-enum-descriptions": [
+                "账号密码登录",
+                "两步验证登录",
+                "单点登录",
+                "手机验证码登录",
+                "刷新token"
+            ],
+            "x-enum-varnames": [
+                "LoginPassword",
+                "LoginMFA",
+                "LoginSSO",
+                "LoginSMS",
+                "LoginRefresh"
+            ]
+        },
+        "constant.PermissionType": {
+            "type": "integer",
+            "format": "int32",
+            "enum": [
                 1,
                 2,
                 3,
@@ -1283,350 +4278,1005 @@ const docTemplate = `{
                 "viewResource",
                 "apiRoute"
             ],
-            "x-enum-varnames": [
-                "ViewRoute",
-                "ViewMenu",
-                "ViewResource",
-                "ApiRoute"
-            ]
+            "x-enum-varnames": [
+                "ViewRoute",
+                "ViewMenu",
+                "ViewResource",
+                "ApiRoute"
+            ]
+        },
+        "constant.UserGender": {
+            "type": "integer",
+            "enum": [
+                1,
+                2
+            ],
+            "x-enum-comments": {
+                "Females": "females",
+                "Male": "male"
+            },
+            "x-enum-descriptions": [
+                "male",
+                "females"
+            ],
+            "x-enum-varnames": [
+                "Male",
+                "Females"
+            ]
+        },
+        "constant.UserStatus": {
+            "type": "integer",
+            "enum": [
+                1,
+                2,
+                3,
+                4
+            ],
+            "x-enum-comments": {
+                "Disabled": "disabled",
+                "Erased": "erased",
+                "Inactive": "inactive",
+                "Normal": "normal"
+            },
+            "x-enum-descriptions": [
+                "inactive",
+                "normal",
+                "disabled",
+                "erased"
+            ],
+            "x-enum-varnames": [
+                "Inactive",
+                "Normal",
+                "Disabled",
+                "Erased"
+            ]
+        },
+        "models.Department": {
+            "type": "object",
+            "properties": {
+                "ancestors": {
+                    "description": "祖先部门路径逗号拼接的字符串",
+                    "type": "string"
+                },
+                "children": {
+                    "description": "不建议使用 gorm 预加载",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Department"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "creator": {
+                    "$ref": "#/definitions/models.User"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "leaders": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.User"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parent": {
+                    "$ref": "#/definitions/models.Department"
+                },
+                "parent_id": {
+                    "description": "父级部门ID,nil则为顶级部门",
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "updater": {
+                    "$ref": "#/definitions/models.User"
+                },
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.User"
+                    }
+                }
+            }
+        },
+        "models.Permission": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "creator": {
+                    "$ref": "#/definitions/models.User"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "method": {
+                    "description": "API权限的请求方法,*表示任意方法",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "resource": {
+                    "type": "string"
+                },
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Role"
+                    }
+                },
+                "type": {
+                    "$ref": "#/definitions/constant.PermissionType"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "updater": {
+                    "$ref": "#/definitions/models.User"
+                }
+            }
+        },
+        "models.Role": {
+            "type": "object",
+            "properties": {
+                "ancestors": {
+                    "type": "string"
+                },
+                "children": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Role"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "creator": {
+                    "$ref": "#/definitions/models.User"
+                },
+                "data_departments": {
+                    "description": "自定义数据权限的部门",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Department"
+                    }
+                },
+                "data_scope": {
+                    "description": "数据权限范围",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/constant.DataScope"
+                        }
+                    ]
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parent": {
+                    "$ref": "#/definitions/models.Role"
+                },
+                "parent_id": {
+                    "type": "integer"
+                },
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Permission"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "updater": {
+                    "$ref": "#/definitions/models.User"
+                },
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.User"
+                    }
+                }
+            }
+        },
+        "models.User": {
+            "type": "object",
+            "properties": {
+                "about": {
+                    "type": "string"
+                },
+                "avatar": {
+                    "type": "string"
+                },
+                "birthday": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "departments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Department"
+                    }
+                },
+                "email": {
+                    "type": "string"
+                },
+                "gender": {
+                    "$ref": "#/definitions/constant.UserGender"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "nickname": {
+                    "type": "string"
+                },
+                "phone": {
+                    "description": "手机号,绑定后可用于验证码登录",
+                    "type": "string"
+                },
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Role"
+                    }
+                },
+                "status": {
+                    "$ref": "#/definitions/constant.UserStatus"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.UserImpersonation": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "$ref": "#/definitions/models.User"
+                },
+                "actor_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "expired_at": {
+                    "description": "token的过期时间",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "ip": {
+                    "description": "操作人的IP",
+                    "type": "string"
+                },
+                "reason": {
+                    "description": "模拟登录的原因",
+                    "type": "string"
+                },
+                "stopped_at": {
+                    "description": "主动结束的时间,为空时直到token过期",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/models.User"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.UserInvitation": {
+            "type": "object",
+            "properties": {
+                "accepted_at": {
+                    "description": "接受时间,为空时未接受",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "departments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Department"
+                    }
+                },
+                "email": {
+                    "type": "string"
+                },
+                "expired_at": {
+                    "description": "过期时间",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "inviter": {
+                    "$ref": "#/definitions/models.User"
+                },
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Role"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "description": "接受邀请后创建的用户ID",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.UserLoginHistory": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "device": {
+                    "description": "设备名称",
+                    "type": "string"
+                },
+                "email": {
+                    "description": "登录邮箱",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "ip": {
+                    "description": "IP",
+                    "type": "string"
+                },
+                "mfa": {
+                    "description": "是否经过两步验证",
+                    "type": "boolean"
+                },
+                "reason": {
+                    "description": "失败原因",
+                    "type": "string"
+                },
+                "success": {
+                    "description": "是否成功",
+                    "type": "boolean"
+                },
+                "type": {
+                    "description": "登录方式",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/constant.LoginType"
+                        }
+                    ]
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_agent": {
+                    "description": "UA",
+                    "type": "string"
+                },
+                "user_id": {
+                    "description": "用户ID,邮箱未注册时为空",
+                    "type": "integer"
+                }
+            }
+        },
+        "request.AcceptInvitationRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "password"
+            ],
+            "properties": {
+                "code": {
+                    "description": "邀请码",
+                    "type": "string"
+                },
+                "nickname": {
+                    "description": "昵称",
+                    "type": "string",
+                    "maxLength": 20,
+                    "minLength": 1
+                },
+                "password": {
+                    "description": "密码",
+                    "type": "string"
+                }
+            }
         },
-        "constant.UserGender": {
-            "type": "integer",
-            "enum": [
-                1,
-                2
+        "request.AssignUserRequest": {
+            "type": "object",
+            "required": [
+                "id"
             ],
-            "x-enum-comments": {
-                "Females": "females",
-                "Male": "male"
-            },
-            "x-enum-descriptions": [
-                "male",
-                "females"
+            "properties": {
+                "departments": {
+                    "description": "部门,为空时清空",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "id": {
+                    "description": "ID",
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "roles": {
+                    "description": "角色,为空时清空",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "request.BindPhoneRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "phone"
             ],
-            "x-enum-varnames": [
-                "Male",
-                "Females"
-            ]
+            "properties": {
+                "code": {
+                    "description": "短信验证码",
+                    "type": "string",
+                    "maxLength": 10
+                },
+                "phone": {
+                    "description": "手机号",
+                    "type": "string"
+                }
+            }
         },
-        "constant.UserStatus": {
-            "type": "integer",
-            "enum": [
-                1,
-                2,
-                3
+        "request.ChangeEmailRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
             ],
-            "x-enum-comments": {
-                "Disabled": "disabled",
-                "Inactive": "inactive",
-                "Normal": "normal"
-            },
-            "x-enum-descriptions": [
-                "inactive",
-                "normal",
-                "disabled"
+            "properties": {
+                "email": {
+                    "description": "新邮箱",
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "password": {
+                    "description": "当前密码",
+                    "type": "string"
+                }
+            }
+        },
+        "request.ChangeExpiredPasswordRequest": {
+            "type": "object",
+            "required": [
+                "password",
+                "ticket"
             ],
-            "x-enum-varnames": [
-                "Inactive",
-                "Normal",
-                "Disabled"
-            ]
+            "properties": {
+                "password": {
+                    "description": "新密码",
+                    "type": "string"
+                },
+                "ticket": {
+                    "description": "登录凭证",
+                    "type": "string"
+                }
+            }
         },
-        "models.Department": {
+        "request.ChangePasswordRequest": {
             "type": "object",
+            "required": [
+                "new_password",
+                "old_password"
+            ],
             "properties": {
-                "ancestors": {
-                    "description": "祖先部门路径逗号拼接的字符串",
+                "new_password": {
+                    "description": "新密码",
                     "type": "string"
                 },
-                "children": {
-                    "description": "不建议使用 gorm 预加载",
+                "old_password": {
+                    "description": "原密码",
+                    "type": "string"
+                }
+            }
+        },
+        "request.CreateAPIKeyRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "permissions"
+            ],
+            "properties": {
+                "expired_at": {
+                    "description": "过期时间,为空时永不过期",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "名称",
+                    "type": "string",
+                    "maxLength": 50,
+                    "minLength": 1
+                },
+                "permissions": {
+                    "description": "权限范围",
+                    "type": "array",
+                    "maxItems": 200,
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "request.CreateDepartmentRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "leaders": {
+                    "description": "部门leader集合",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "name": {
+                    "description": "部门名称",
+                    "type": "string",
+                    "maxLength": 20,
+                    "minLength": 1
+                },
+                "parent_id": {
+                    "description": "父部门ID",
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "users": {
+                    "description": "部门用户ID集合",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "request.CreateInvitationRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "expires"
+            ],
+            "properties": {
+                "departments": {
+                    "description": "预设的部门",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "email": {
+                    "description": "被邀请人的邮箱",
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "expires": {
+                    "description": "有效期,单位小时",
+                    "type": "integer",
+                    "maximum": 720,
+                    "minimum": 1
+                },
+                "roles": {
+                    "description": "预设的角色",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "request.CreatePermissionRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "resource",
+                "type"
+            ],
+            "properties": {
+                "method": {
+                    "description": "API权限的请求方法,*或为空时匹配任意方法",
+                    "type": "string",
+                    "enum": [
+                        "GET",
+                        "POST",
+                        "PUT",
+                        "PATCH",
+                        "DELETE",
+                        "*"
+                    ]
+                },
+                "name": {
+                    "description": "权限名称",
+                    "type": "string",
+                    "maxLength": 20,
+                    "minLength": 1
+                },
+                "resource": {
+                    "description": "资源名称",
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 1
+                },
+                "roles": {
+                    "description": "资源关联的角色",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "type": {
+                    "description": "资源类型",
+                    "enum": [
+                        1,
+                        2,
+                        3,
+                        4
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/constant.PermissionType"
+                        }
+                    ]
+                }
+            }
+        },
+        "request.CreateRoleRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "data_departments": {
+                    "description": "自定义数据权限的部门",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.Department"
+                        "type": "integer"
                     }
                 },
-                "created_at": {
-                    "type": "string"
+                "data_scope": {
+                    "description": "数据权限范围,默认为全部数据",
+                    "enum": [
+                        1,
+                        2,
+                        3,
+                        4,
+                        5
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/constant.DataScope"
+                        }
+                    ]
                 },
-                "creator": {
-                    "$ref": "#/definitions/models.User"
+                "name": {
+                    "description": "角色名称",
+                    "type": "string",
+                    "maxLength": 20,
+                    "minLength": 1
                 },
-                "id": {
-                    "type": "integer"
+                "parent_id": {
+                    "description": "父角色ID",
+                    "type": "integer",
+                    "minimum": 1
                 },
-                "leaders": {
+                "permissions": {
+                    "description": "角色关联权限",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.User"
+                        "type": "integer"
                     }
                 },
-                "name": {
-                    "type": "string"
-                },
-                "parent": {
-                    "$ref": "#/definitions/models.Department"
-                },
-                "parent_id": {
-                    "description": "父级部门ID,nil则为顶级部门",
-                    "type": "integer"
-                },
-                "updated_at": {
-                    "type": "string"
-                },
-                "updater": {
-                    "$ref": "#/definitions/models.User"
-                },
                 "users": {
+                    "description": "角色关联用户",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.User"
+                        "type": "integer"
                     }
                 }
             }
         },
-        "models.Permission": {
+        "request.CreateUserRequest": {
             "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
             "properties": {
-                "created_at": {
-                    "type": "string"
-                },
-                "creator": {
-                    "$ref": "#/definitions/models.User"
+                "departments": {
+                    "description": "部门",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
                 },
-                "id": {
-                    "type": "integer"
+                "email": {
+                    "description": "邮箱",
+                    "type": "string",
+                    "maxLength": 50
                 },
-                "name": {
-                    "type": "string"
+                "nickname": {
+                    "description": "昵称",
+                    "type": "string",
+                    "maxLength": 20,
+                    "minLength": 1
                 },
-                "resource": {
+                "password": {
+                    "description": "密码",
                     "type": "string"
                 },
                 "roles": {
+                    "description": "角色",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.Role"
+                        "type": "integer"
                     }
                 },
-                "type": {
-                    "$ref": "#/definitions/constant.PermissionType"
-                },
-                "updated_at": {
-                    "type": "string"
-                },
-                "updater": {
-                    "$ref": "#/definitions/models.User"
+                "send_active_email": {
+                    "description": "是否发送激活邮件,为否时用户直接启用",
+                    "type": "boolean"
                 }
             }
         },
-        "models.Role": {
+        "request.DeleteAPIKeyRequest": {
             "type": "object",
+            "required": [
+                "id"
+            ],
             "properties": {
-                "ancestors": {
-                    "type": "string"
-                },
-                "children": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.Role"
-                    }
-                },
-                "created_at": {
+                "id": {
+                    "description": "ID",
+                    "type": "integer",
+                    "minimum": 1
+                }
+            }
+        },
+        "request.DeletePermissionRequest": {
+            "type": "object",
+            "required": [
+                "id"
+            ],
+            "properties": {
+                "id": {
+                    "description": "ID",
+                    "type": "integer",
+                    "minimum": 1
+                }
+            }
+        },
+        "request.DeleteRoleRequest": {
+            "type": "object",
+            "required": [
+                "id"
+            ],
+            "properties": {
+                "id": {
+                    "description": "ID",
+                    "type": "integer",
+                    "minimum": 1
+                }
+            }
+        },
+        "request.DeleteUserRequest": {
+            "type": "object",
+            "required": [
+                "id"
+            ],
+            "properties": {
+                "id": {
+                    "description": "ID",
+                    "type": "integer",
+                    "minimum": 1
+                }
+            }
+        },
+        "request.EmailChangeCodeRequest": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "description": "邮件中链接携带的code",
                     "type": "string"
-                },
-                "creator": {
-                    "$ref": "#/definitions/models.User"
-                },
+                }
+            }
+        },
+        "request.EraseUserRequest": {
+            "type": "object",
+            "required": [
+                "id"
+            ],
+            "properties": {
                 "id": {
-                    "type": "integer"
-                },
-                "name": {
+                    "description": "ID",
+                    "type": "integer",
+                    "minimum": 1
+                }
+            }
+        },
+        "request.ForgotPasswordRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "email",
+                "id"
+            ],
+            "properties": {
+                "code": {
+                    "description": "验证码内容",
                     "type": "string"
                 },
-                "parent": {
-                    "$ref": "#/definitions/models.Role"
-                },
-                "parent_id": {
-                    "type": "integer"
-                },
-                "permissions": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.Permission"
-                    }
+                "email": {
+                    "description": "邮箱",
+                    "type": "string",
+                    "maxLength": 50
                 },
-                "updated_at": {
+                "id": {
+                    "description": "验证码ID",
                     "type": "string"
-                },
-                "updater": {
-                    "$ref": "#/definitions/models.User"
-                },
-                "users": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.User"
-                    }
                 }
             }
         },
-        "models.User": {
+        "request.ImpersonateRequest": {
             "type": "object",
+            "required": [
+                "id",
+                "reason"
+            ],
             "properties": {
-                "about": {
-                    "type": "string"
+                "id": {
+                    "description": "被模拟的用户ID",
+                    "type": "integer",
+                    "minimum": 1
                 },
-                "avatar": {
-                    "type": "string"
+                "reason": {
+                    "description": "模拟登录的原因",
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 1
+                }
+            }
+        },
+        "request.LoginMFARequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "ticket"
+            ],
+            "properties": {
+                "code": {
+                    "description": "TOTP验证码或恢复码",
+                    "type": "string",
+                    "maxLength": 20,
+                    "minLength": 6
                 },
-                "birthday": {
+                "ticket": {
+                    "description": "登录凭证",
                     "type": "string"
-                },
-                "created_at": {
+                }
+            }
+        },
+        "request.LoginRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "code": {
+                    "description": "验证码内容,登录失败次数较多时必填",
                     "type": "string"
                 },
-                "departments": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.Department"
-                    }
+                "device": {
+                    "description": "设备名称",
+                    "type": "string",
+                    "maxLength": 50
                 },
                 "email": {
-                    "type": "string"
-                },
-                "gender": {
-                    "$ref": "#/definitions/constant.UserGender"
+                    "description": "邮箱",
+                    "type": "string",
+                    "maxLength": 50
                 },
                 "id": {
-                    "type": "integer"
-                },
-                "nickname": {
+                    "description": "验证码ID,登录失败次数较多时必填",
                     "type": "string"
                 },
-                "roles": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.Role"
-                    }
-                },
-                "status": {
-                    "$ref": "#/definitions/constant.UserStatus"
-                },
-                "updated_at": {
+                "password": {
+                    "description": "密码",
                     "type": "string"
                 }
             }
         },
-        "request.CreateDepartmentRequest": {
+        "request.LoginSMSRequest": {
             "type": "object",
             "required": [
-                "name"
+                "code",
+                "phone"
             ],
             "properties": {
-                "leaders": {
-                    "description": "部门leader集合",
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
-                    }
-                },
-                "name": {
-                    "description": "部门名称",
+                "code": {
+                    "description": "短信验证码",
                     "type": "string",
-                    "maxLength": 20,
-                    "minLength": 1
+                    "maxLength": 10
                 },
-                "parent_id": {
-                    "description": "父部门ID",
-                    "type": "integer",
-                    "minimum": 1
+                "device": {
+                    "description": "设备名称",
+                    "type": "string",
+                    "maxLength": 50
                 },
-                "users": {
-                    "description": "部门用户ID集合",
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
-                    }
+                "phone": {
+                    "description": "手机号",
+                    "type": "string"
                 }
             }
         },
-        "request.CreatePermissionRequest": {
+        "request.MFACodeRequest": {
             "type": "object",
             "required": [
-                "name",
-                "resource",
-                "type"
+                "code"
             ],
             "properties": {
-                "name": {
-                    "description": "权限名称",
+                "code": {
+                    "description": "TOTP验证码或恢复码",
                     "type": "string",
                     "maxLength": 20,
-                    "minLength": 1
-                },
-                "resource": {
-                    "description": "资源名称",
-                    "type": "string",
-                    "maxLength": 255,
-                    "minLength": 1
-                },
-                "roles": {
-                    "description": "资源关联的角色",
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
-                    }
-                },
-                "type": {
-                    "description": "资源类型",
-                    "enum": [
-                        1,
-                        2,
-                        3,
-                        4
-                    ],
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/constant.PermissionType"
-                        }
-                    ]
+                    "minLength": 6
                 }
             }
         },
-        "request.CreateRoleRequest": {
+        "request.ResetPasswordRequest": {
             "type": "object",
             "required": [
-                "name"
+                "code",
+                "email",
+                "password"
             ],
             "properties": {
-                "name": {
-                    "description": "角色名称",
-                    "type": "string",
-                    "maxLength": 20,
-                    "minLength": 1
-                },
-                "parent_id": {
-                    "description": "父角色ID",
-                    "type": "integer",
-                    "minimum": 1
+                "code": {
+                    "description": "邮件中的验证码",
+                    "type": "string"
                 },
-                "permissions": {
-                    "description": "角色关联权限",
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
-                    }
+                "email": {
+                    "description": "邮箱",
+                    "type": "string",
+                    "maxLength": 50
                 },
-                "users": {
-                    "description": "角色关联用户",
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
-                    }
+                "password": {
+                    "description": "新密码",
+                    "type": "string"
                 }
             }
         },
-        "request.DeletePermissionRequest": {
+        "request.RestoreUserRequest": {
             "type": "object",
             "required": [
                 "id"
@@ -1639,7 +5289,7 @@ const docTemplate = `{
                 }
             }
         },
-        "request.DeleteRoleRequest": {
+        "request.RevokeInvitationRequest": {
             "type": "object",
             "required": [
                 "id"
@@ -1652,20 +5302,88 @@ const docTemplate = `{
                 }
             }
         },
-        "request.LoginRequest": {
+        "request.RevokeSessionsRequest": {
             "type": "object",
             "required": [
-                "email",
-                "password"
+                "ids"
             ],
             "properties": {
-                "email": {
-                    "description": "邮箱",
+                "ids": {
+                    "description": "会话ID集合",
+                    "type": "array",
+                    "maxItems": 50,
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "request.RevokeUserSessionsRequest": {
+            "type": "object",
+            "required": [
+                "uid"
+            ],
+            "properties": {
+                "uid": {
+                    "description": "用户ID",
+                    "type": "integer",
+                    "minimum": 1
+                }
+            }
+        },
+        "request.SSOLoginRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "state"
+            ],
+            "properties": {
+                "code": {
+                    "description": "身份提供方回调的授权码",
+                    "type": "string"
+                },
+                "device": {
+                    "description": "设备名称",
                     "type": "string",
                     "maxLength": 50
                 },
-                "password": {
-                    "description": "密码",
+                "state": {
+                    "description": "身份提供方回调的state",
+                    "type": "string"
+                }
+            }
+        },
+        "request.SendBindPhoneCodeRequest": {
+            "type": "object",
+            "required": [
+                "phone"
+            ],
+            "properties": {
+                "phone": {
+                    "description": "手机号",
+                    "type": "string"
+                }
+            }
+        },
+        "request.SendLoginSMSCodeRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "id",
+                "phone"
+            ],
+            "properties": {
+                "code": {
+                    "description": "图形验证码内容",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "图形验证码ID",
+                    "type": "string"
+                },
+                "phone": {
+                    "description": "手机号",
                     "type": "string"
                 }
             }
@@ -1698,6 +5416,23 @@ const docTemplate = `{
                 }
             }
         },
+        "request.UnlockLoginRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "description": "邮箱",
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "ip": {
+                    "description": "ip,不为空时同时解除该ip的锁定",
+                    "type": "string"
+                }
+            }
+        },
         "request.UpdatePermissionRequest": {
             "type": "object",
             "required": [
@@ -1712,6 +5447,18 @@ const docTemplate = `{
                     "type": "integer",
                     "minimum": 1
                 },
+                "method": {
+                    "description": "API权限的请求方法,*或为空时匹配任意方法",
+                    "type": "string",
+                    "enum": [
+                        "GET",
+                        "POST",
+                        "PUT",
+                        "PATCH",
+                        "DELETE",
+                        "*"
+                    ]
+                },
                 "name": {
                     "description": "权限名称",
                     "type": "string",
@@ -1731,29 +5478,88 @@ const docTemplate = `{
                         "type": "integer"
                     }
                 },
-                "type": {
-                    "description": "资源类型",
+                "type": {
+                    "description": "资源类型",
+                    "enum": [
+                        1,
+                        2,
+                        3,
+                        4
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/constant.PermissionType"
+                        }
+                    ]
+                }
+            }
+        },
+        "request.UpdateProfileRequest": {
+            "type": "object",
+            "properties": {
+                "about": {
+                    "description": "关于",
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "avatar": {
+                    "description": "头像文件URL",
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "birthday": {
+                    "description": "生日",
+                    "type": "string"
+                },
+                "gender": {
+                    "description": "性别",
+                    "enum": [
+                        1,
+                        2
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/constant.UserGender"
+                        }
+                    ]
+                },
+                "nickname": {
+                    "description": "昵称",
+                    "type": "string",
+                    "maxLength": 20,
+                    "minLength": 1
+                }
+            }
+        },
+        "request.UpdateRoleRequest": {
+            "type": "object",
+            "required": [
+                "id",
+                "name"
+            ],
+            "properties": {
+                "data_departments": {
+                    "description": "自定义数据权限的部门",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "data_scope": {
+                    "description": "数据权限范围,默认为全部数据",
                     "enum": [
                         1,
                         2,
                         3,
-                        4
+                        4,
+                        5
                     ],
                     "allOf": [
                         {
-                            "$ref": "#/definitions/constant.PermissionType"
+                            "$ref": "#/definitions/constant.DataScope"
                         }
                     ]
-                }
-            }
-        },
-        "request.UpdateRoleRequest": {
-            "type": "object",
-            "required": [
-                "id",
-                "name"
-            ],
-            "properties": {
+                },
                 "id": {
                     "description": "ID",
                     "type": "integer",
@@ -1786,6 +5592,68 @@ const docTemplate = `{
                 }
             }
         },
+        "request.UpdateUserStatusRequest": {
+            "type": "object",
+            "required": [
+                "id",
+                "status"
+            ],
+            "properties": {
+                "id": {
+                    "description": "ID",
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "status": {
+                    "description": "账户状态:2启用 3禁用",
+                    "enum": [
+                        2,
+                        3
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/constant.UserStatus"
+                        }
+                    ]
+                }
+            }
+        },
+        "response.APIKeyResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expired_at": {
+                    "description": "过期时间,为空时永不过期",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_used_at": {
+                    "description": "最后使用时间",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "permissions": {
+                    "description": "权限范围",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.PermissionDetailResponse"
+                    }
+                },
+                "prefix": {
+                    "description": "密钥明文的前缀部分,便于用户识别",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
         "response.BasicResponse-any": {
             "type": "object",
             "properties": {
@@ -1798,6 +5666,23 @@ const docTemplate = `{
                 }
             }
         },
+        "response.BasicResponse-array_response_APIKeyResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.APIKeyResponse"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
         "response.BasicResponse-array_response_DepartmentTreeResponse": {
             "type": "object",
             "properties": {
@@ -1815,6 +5700,40 @@ const docTemplate = `{
                 }
             }
         },
+        "response.BasicResponse-array_response_UserSessionResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.UserSessionResponse"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.BasicResponse-array_string": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
         "response.BasicResponse-int": {
             "type": "object",
             "properties": {
@@ -1829,6 +5748,62 @@ const docTemplate = `{
                 }
             }
         },
+        "response.BasicResponse-response_CreateAPIKeyResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/response.CreateAPIKeyResponse"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.BasicResponse-response_DataList-models_UserImpersonation": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/response.DataList-models_UserImpersonation"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.BasicResponse-response_DataList-models_UserInvitation": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/response.DataList-models_UserInvitation"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.BasicResponse-response_DataList-models_UserLoginHistory": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/response.DataList-models_UserLoginHistory"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
         "response.BasicResponse-response_DataList-response_PermissionDetailRole": {
             "type": "object",
             "properties": {
@@ -1916,6 +5891,48 @@ const docTemplate = `{
                 }
             }
         },
+        "response.BasicResponse-response_ImpersonateResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/response.ImpersonateResponse"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.BasicResponse-response_ImportUsersResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/response.ImportUsersResponse"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.BasicResponse-response_InvitationResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/response.InvitationResponse"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
         "response.BasicResponse-response_LoginResponse": {
             "type": "object",
             "properties": {
@@ -1930,6 +5947,34 @@ const docTemplate = `{
                 }
             }
         },
+        "response.BasicResponse-response_MFAEnrollResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/response.MFAEnrollResponse"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.BasicResponse-response_MFARecoveryCodesResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/response.MFARecoveryCodesResponse"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
         "response.BasicResponse-response_PermissionDetailResponse": {
             "type": "object",
             "properties": {
@@ -1944,44 +5989,112 @@ const docTemplate = `{
                 }
             }
         },
-        "response.BasicResponse-response_ProfileResponse": {
+        "response.BasicResponse-response_ProfileResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/response.ProfileResponse"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.BasicResponse-response_RefreshTokenResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/response.RefreshTokenResponse"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.BasicResponse-response_RoleDetailResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/response.RoleDetailResponse"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.BasicResponse-response_SSOAuthorizeResponse": {
             "type": "object",
             "properties": {
                 "code": {
                     "type": "integer"
                 },
                 "data": {
-                    "$ref": "#/definitions/response.ProfileResponse"
+                    "$ref": "#/definitions/response.SSOAuthorizeResponse"
                 },
                 "message": {
                     "type": "string"
                 }
             }
         },
-        "response.BasicResponse-response_RoleDetailResponse": {
+        "response.BasicResponse-string": {
             "type": "object",
             "properties": {
                 "code": {
                     "type": "integer"
                 },
                 "data": {
-                    "$ref": "#/definitions/response.RoleDetailResponse"
+                    "type": "string"
                 },
                 "message": {
                     "type": "string"
                 }
             }
         },
-        "response.BasicResponse-string": {
+        "response.CreateAPIKeyResponse": {
             "type": "object",
             "properties": {
-                "code": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expired_at": {
+                    "description": "过期时间,为空时永不过期",
+                    "type": "string"
+                },
+                "id": {
                     "type": "integer"
                 },
-                "data": {
+                "key": {
+                    "description": "密钥明文",
                     "type": "string"
                 },
-                "message": {
+                "last_used_at": {
+                    "description": "最后使用时间",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "permissions": {
+                    "description": "权限范围",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.PermissionDetailResponse"
+                    }
+                },
+                "prefix": {
+                    "description": "密钥明文的前缀部分,便于用户识别",
+                    "type": "string"
+                },
+                "updated_at": {
                     "type": "string"
                 }
             }
@@ -2013,11 +6126,57 @@ const docTemplate = `{
                 "nickname": {
                     "type": "string"
                 },
+                "phone": {
+                    "description": "手机号,绑定后可用于验证码登录",
+                    "type": "string"
+                },
                 "roles": {},
                 "status": {},
                 "updated_at": {}
             }
         },
+        "response.DataList-models_UserImpersonation": {
+            "type": "object",
+            "properties": {
+                "list": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.UserImpersonation"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "response.DataList-models_UserInvitation": {
+            "type": "object",
+            "properties": {
+                "list": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.UserInvitation"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "response.DataList-models_UserLoginHistory": {
+            "type": "object",
+            "properties": {
+                "list": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.UserLoginHistory"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
         "response.DataList-response_PermissionDetailRole": {
             "type": "object",
             "properties": {
@@ -2182,18 +6341,133 @@ const docTemplate = `{
             "type": "object",
             "properties": {
                 "base64": {
-                    "description": "图片base64",
+                    "description": "图片或语音的base64(data url)",
                     "type": "string"
                 },
                 "id": {
                     "description": "ID",
                     "type": "string"
+                },
+                "type": {
+                    "description": "生成方式,audio为语音,其余为图片",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/constant.CaptchaDriver"
+                        }
+                    ]
+                }
+            }
+        },
+        "response.ImpersonateResponse": {
+            "type": "object",
+            "properties": {
+                "expired_at": {
+                    "description": "token的过期时间",
+                    "type": "string"
+                },
+                "token": {
+                    "description": "模拟登录的token,不签发refresh token",
+                    "type": "string"
+                },
+                "user": {
+                    "description": "被模拟的用户",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/response.LoginUser"
+                        }
+                    ]
+                }
+            }
+        },
+        "response.ImportUserRowError": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "错误码",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/response.StatusCode"
+                        }
+                    ]
+                },
+                "column": {
+                    "description": "列名",
+                    "type": "string"
+                },
+                "message": {
+                    "description": "错误信息",
+                    "type": "string"
+                },
+                "row": {
+                    "description": "行号,从表头所在的第1行开始计数",
+                    "type": "integer"
+                },
+                "value": {
+                    "description": "单元格的值",
+                    "type": "string"
+                }
+            }
+        },
+        "response.ImportUsersResponse": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "description": "创建的用户数",
+                    "type": "integer"
+                },
+                "dry_run": {
+                    "description": "是否仅校验",
+                    "type": "boolean"
+                },
+                "errors": {
+                    "description": "校验失败的行,存在时不会创建任何用户",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.ImportUserRowError"
+                    }
+                },
+                "total": {
+                    "description": "数据行数",
+                    "type": "integer"
+                }
+            }
+        },
+        "response.InvitationResponse": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "description": "被邀请人的邮箱",
+                    "type": "string"
+                },
+                "expired_at": {
+                    "description": "过期时间",
+                    "type": "string"
+                },
+                "inviter": {
+                    "description": "邀请人",
+                    "type": "string"
                 }
             }
         },
         "response.LoginResponse": {
             "type": "object",
             "properties": {
+                "mfa_required": {
+                    "description": "是否需要两步验证,为true时token为空,需要使用mfa_ticket完成登录",
+                    "type": "boolean"
+                },
+                "mfa_ticket": {
+                    "description": "两步验证登录凭证",
+                    "type": "string"
+                },
+                "password_expired": {
+                    "description": "密码是否已过期,为true时token为空,需要使用password_ticket修改密码后完成登录",
+                    "type": "boolean"
+                },
+                "password_ticket": {
+                    "description": "修改过期密码的登录凭证",
+                    "type": "string"
+                },
                 "refresh_token": {
                     "description": "refresh token",
                     "type": "string"
@@ -2229,6 +6503,35 @@ const docTemplate = `{
                 }
             }
         },
+        "response.MFAEnrollResponse": {
+            "type": "object",
+            "properties": {
+                "qr_code": {
+                    "description": "base64格式的二维码",
+                    "type": "string"
+                },
+                "secret": {
+                    "description": "TOTP密钥",
+                    "type": "string"
+                },
+                "uri": {
+                    "description": "otpauth地址",
+                    "type": "string"
+                }
+            }
+        },
+        "response.MFARecoveryCodesResponse": {
+            "type": "object",
+            "properties": {
+                "codes": {
+                    "description": "恢复码,仅返回一次",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
         "response.PermissionDetailResponse": {
             "type": "object",
             "properties": {
@@ -2241,6 +6544,10 @@ const docTemplate = `{
                 "id": {
                     "type": "integer"
                 },
+                "method": {
+                    "description": "API权限的请求方法,*表示任意方法",
+                    "type": "string"
+                },
                 "name": {
                     "type": "string"
                 },
@@ -2282,6 +6589,21 @@ const docTemplate = `{
                 "creator": {
                     "description": "创建者"
                 },
+                "data_departments": {
+                    "description": "自定义数据权限的部门",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Department"
+                    }
+                },
+                "data_scope": {
+                    "description": "数据权限范围",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/constant.DataScope"
+                        }
+                    ]
+                },
                 "id": {
                     "type": "integer"
                 },
@@ -2325,6 +6647,10 @@ const docTemplate = `{
                 "id": {
                     "type": "integer"
                 },
+                "method": {
+                    "description": "API权限的请求方法,*表示任意方法",
+                    "type": "string"
+                },
                 "name": {
                     "type": "string"
                 },
@@ -2384,6 +6710,10 @@ const docTemplate = `{
                 "nickname": {
                     "type": "string"
                 },
+                "phone": {
+                    "description": "手机号,绑定后可用于验证码登录",
+                    "type": "string"
+                },
                 "roles": {
                     "description": "角色",
                     "type": "array",
@@ -2425,6 +6755,19 @@ const docTemplate = `{
                 }
             }
         },
+        "response.RefreshTokenResponse": {
+            "type": "object",
+            "properties": {
+                "refresh_token": {
+                    "description": "refresh token",
+                    "type": "string"
+                },
+                "token": {
+                    "description": "token",
+                    "type": "string"
+                }
+            }
+        },
         "response.RoleDetailPermission": {
             "type": "object",
             "properties": {
@@ -2437,6 +6780,10 @@ const docTemplate = `{
                 "id": {
                     "type": "integer"
                 },
+                "method": {
+                    "description": "API权限的请求方法,*表示任意方法",
+                    "type": "string"
+                },
                 "name": {
                     "type": "string"
                 },
@@ -2475,9 +6822,31 @@ const docTemplate = `{
                 "creator": {
                     "description": "创建者"
                 },
+                "data_departments": {
+                    "description": "自定义数据权限的部门",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Department"
+                    }
+                },
+                "data_scope": {
+                    "description": "数据权限范围",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/constant.DataScope"
+                        }
+                    ]
+                },
                 "id": {
                     "type": "integer"
                 },
+                "inherited": {
+                    "description": "按角色继承方向从后代角色或祖先角色继承的权限",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.RoleDetailPermission"
+                    }
+                },
                 "name": {
                     "type": "string"
                 },
@@ -2532,6 +6901,21 @@ const docTemplate = `{
                         }
                     ]
                 },
+                "data_departments": {
+                    "description": "自定义数据权限的部门",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Department"
+                    }
+                },
+                "data_scope": {
+                    "description": "数据权限范围",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/constant.DataScope"
+                        }
+                    ]
+                },
                 "id": {
                     "type": "integer"
                 },
@@ -2563,6 +6947,15 @@ const docTemplate = `{
                 }
             }
         },
+        "response.SSOAuthorizeResponse": {
+            "type": "object",
+            "properties": {
+                "url": {
+                    "description": "身份提供方的授权地址",
+                    "type": "string"
+                }
+            }
+        },
         "response.SimpleUser": {
             "type": "object",
             "properties": {
@@ -2590,11 +6983,326 @@ const docTemplate = `{
                 "nickname": {
                     "type": "string"
                 },
+                "phone": {
+                    "description": "手机号,绑定后可用于验证码登录",
+                    "type": "string"
+                },
                 "roles": {},
                 "status": {},
                 "updated_at": {}
             }
         },
+        "response.StatusCode": {
+            "type": "integer",
+            "enum": [
+                10000,
+                10001,
+                10002,
+                10003,
+                10004,
+                10005,
+                10006,
+                10007,
+                10008,
+                10009,
+                10010,
+                10011,
+                10012,
+                20000,
+                20001,
+                20002,
+                20003,
+                20004,
+                20005,
+                20006,
+                20007,
+                20008,
+                20009,
+                20010,
+                20011,
+                20012,
+                20013,
+                20014,
+                20015,
+                20016,
+                20017,
+                20018,
+                20019,
+                20020,
+                20021,
+                20022,
+                20023,
+                20024,
+                20025,
+                20026,
+                20027,
+                20028,
+                20029,
+                20030,
+                20031,
+                20032,
+                20033,
+                20034,
+                20035,
+                20036,
+                20037,
+                20038,
+                20039,
+                20040,
+                20041,
+                20042,
+                20043,
+                20044,
+                20045,
+                20046,
+                20047,
+                20048,
+                30000,
+                40000,
+                40001,
+                40002,
+                40003,
+                40004,
+                50000,
+                50001,
+                50002,
+                50000,
+                60000,
+                60001,
+                60002
+            ],
+            "x-enum-comments": {
+                "APIKeyLimitExceeded": "apiKeyLimitExceeded",
+                "APIKeyNotExist": "apiKeyNotExist",
+                "APIKeyScopeExceeded": "apiKeyScopeExceeded",
+                "AuthErr": "authErr",
+                "Busy": "busy",
+                "CancelRequest": "cancelRequest",
+                "CaptchaVerifyFail": "captchaVerifyFail",
+                "DataExportCodeInvalid": "dataExportCodeInvalid",
+                "DataExportTooFrequent": "dataExportTooFrequent",
+                "DeptCreateDuplicate": "deptCreateDuplicate",
+                "DeptExistUserRef": "deptExistUserRef",
+                "DeptNotExist": "deptNotExist",
+                "EmailChangeCodeInvalid": "emailChangeCodeInvalid",
+                "EmailValidErr": "emailValidErr",
+                "Error": "error",
+                "IdentityProviderNotExist": "identityProviderNotExist",
+                "ImpersonationForbidden": "impersonationForbidden",
+                "ImportRowsExceeded": "importRowsExceeded",
+                "InvalidAPIKey": "invalidAPIKey",
+                "InvalidActiveCode": "invalidActiveCode",
+                "InvalidAttachmentLength": "invalidAttachmentLength",
+                "InvalidImportFile": "invalidImportFile",
+                "InvalidMFACode": "invalidMFACode",
+                "InvalidMFATicket": "invalidMFATicket",
+                "InvalidParams": "invalidParams",
+                "InvalidPasswordTicket": "invalidPasswordTicket",
+                "InvalidRefreshToken": "invalidRefreshToken",
+                "InvalidResetPasswordCode": "invalidResetPasswordCode",
+                "InvalidSSOState": "invalidSSOState",
+                "InvalidToken": "invalidToken",
+                "InvitationNotExist": "invitationNotExist",
+                "LoginCaptchaRequired": "loginCaptchaRequired",
+                "MFAAlreadyEnabled": "mfaAlreadyEnabled",
+                "MFANotEnabled": "mfaNotEnabled",
+                "NoValidRoles": "noValidRoles",
+                "Ok": "ok",
+                "OldPasswordIncorrect": "oldPasswordIncorrect",
+                "PasswordBreached": "passwordBreached",
+                "PasswordRequireDigit": "passwordRequireDigit",
+                "PasswordRequireLower": "passwordRequireLower",
+                "PasswordRequireSymbol": "passwordRequireSymbol",
+                "PasswordRequireUpper": "passwordRequireUpper",
+                "PasswordReused": "passwordReused",
+                "PasswordTooLong": "passwordTooLong",
+                "PasswordTooShort": "passwordTooShort",
+                "PasswordValidErr": "passwordValidErr",
+                "PermissionCreateDuplicate": "permissionCreateDuplicate",
+                "PermissionExistRoleRef": "permissionExistRoleRef",
+                "PermissionNotExist": "permissionNotExist",
+                "PhoneAlreadyBound": "phoneAlreadyBound",
+                "PhoneValidErr": "phoneValidErr",
+                "ReActiveErr": "reActiveErr",
+                "RecoveryError": "recoveryError",
+                "RefreshTokenReused": "refreshTokenReused",
+                "ResetPasswordTooFrequent": "resetPasswordTooFrequent",
+                "RoleCreateDuplicateName": "roleCreateDuplicateName",
+                "RoleExistPermissionRef": "roleExistPermissionRef",
+                "RoleExistUserRef": "roleExistUserRef",
+                "RoleNotExist": "roleNotExist",
+                "SMSCodeInvalid": "smsCodeInvalid",
+                "SMSTooFrequent": "smsTooFrequent",
+                "SSOLoginFail": "ssoLoginFail",
+                "SignupClosed": "signupClosed",
+                "TimeoutErr": "timeoutErr",
+                "UnnecessaryRefreshToken": "unnecessaryRefreshToken",
+                "UserCreateDuplicateEmail": "userCreateDuplicateEmail",
+                "UserDisabled": "userDisabled",
+                "UserErased": "userErased",
+                "UserIdentityNotExist": "userIdentityNotExist",
+                "UserInactive": "userInactive",
+                "UserLoginFail": "userLoginFail",
+                "UserLoginLocked": "userLoginLocked",
+                "UserLogoutFail": "userLogoutFail",
+                "UserManageForbidden": "userManageForbidden",
+                "UserNotExist": "userNotExist"
+            },
+            "x-enum-descriptions": [
+                "ok",
+                "error",
+                "invalidParams",
+                "invalidToken",
+                "cancelRequest",
+                "recoveryError",
+                "invalidRefreshToken",
+                "unnecessaryRefreshToken",
+                "authErr",
+                "timeoutErr",
+                "busy",
+                "refreshTokenReused",
+                "invalidAPIKey",
+                "userCreateDuplicateEmail",
+                "userLoginFail",
+                "passwordValidErr",
+                "emailValidErr",
+                "userNotExist",
+                "userInactive",
+                "userDisabled",
+                "invalidActiveCode",
+                "reActiveErr",
+                "userLogoutFail",
+                "invalidResetPasswordCode",
+                "resetPasswordTooFrequent",
+                "oldPasswordIncorrect",
+                "mfaNotEnabled",
+                "mfaAlreadyEnabled",
+                "invalidMFACode",
+                "invalidMFATicket",
+                "identityProviderNotExist",
+                "invalidSSOState",
+                "ssoLoginFail",
+                "userIdentityNotExist",
+                "apiKeyNotExist",
+                "apiKeyLimitExceeded",
+                "apiKeyScopeExceeded",
+                "userLoginLocked",
+                "loginCaptchaRequired",
+                "userManageForbidden",
+                "invalidImportFile",
+                "importRowsExceeded",
+                "impersonationForbidden",
+                "signupClosed",
+                "invitationNotExist",
+                "emailChangeCodeInvalid",
+                "passwordTooShort",
+                "passwordTooLong",
+                "passwordRequireUpper",
+                "passwordRequireLower",
+                "passwordRequireDigit",
+                "passwordRequireSymbol",
+                "passwordBreached",
+                "passwordReused",
+                "invalidPasswordTicket",
+                "phoneValidErr",
+                "smsCodeInvalid",
+                "smsTooFrequent",
+                "phoneAlreadyBound",
+                "dataExportTooFrequent",
+                "dataExportCodeInvalid",
+                "userErased",
+                "captchaVerifyFail",
+                "roleCreateDuplicateName",
+                "noValidRoles",
+                "roleNotExist",
+                "roleExistPermissionRef",
+                "roleExistUserRef",
+                "permissionCreateDuplicate",
+                "permissionNotExist",
+                "permissionExistRoleRef",
+                "invalidAttachmentLength",
+                "deptCreateDuplicate",
+                "deptNotExist",
+                "deptExistUserRef"
+            ],
+            "x-enum-varnames": [
+                "Ok",
+                "Error",
+                "InvalidParams",
+                "InvalidToken",
+                "CancelRequest",
+                "RecoveryError",
+                "InvalidRefreshToken",
+                "UnnecessaryRefreshToken",
+                "AuthErr",
+                "TimeoutErr",
+                "Busy",
+                "RefreshTokenReused",
+                "InvalidAPIKey",
+                "UserCreateDuplicateEmail",
+                "UserLoginFail",
+                "PasswordValidErr",
+                "EmailValidErr",
+                "UserNotExist",
+                "UserInactive",
+                "UserDisabled",
+                "InvalidActiveCode",
+                "ReActiveErr",
+                "UserLogoutFail",
+                "InvalidResetPasswordCode",
+                "ResetPasswordTooFrequent",
+                "OldPasswordIncorrect",
+                "MFANotEnabled",
+                "MFAAlreadyEnabled",
+                "InvalidMFACode",
+                "InvalidMFATicket",
+                "IdentityProviderNotExist",
+                "InvalidSSOState",
+                "SSOLoginFail",
+                "UserIdentityNotExist",
+                "APIKeyNotExist",
+                "APIKeyLimitExceeded",
+                "APIKeyScopeExceeded",
+                "UserLoginLocked",
+                "LoginCaptchaRequired",
+                "UserManageForbidden",
+                "InvalidImportFile",
+                "ImportRowsExceeded",
+                "ImpersonationForbidden",
+                "SignupClosed",
+                "InvitationNotExist",
+                "EmailChangeCodeInvalid",
+                "PasswordTooShort",
+                "PasswordTooLong",
+                "PasswordRequireUpper",
+                "PasswordRequireLower",
+                "PasswordRequireDigit",
+                "PasswordRequireSymbol",
+                "PasswordBreached",
+                "PasswordReused",
+                "InvalidPasswordTicket",
+                "PhoneValidErr",
+                "SMSCodeInvalid",
+                "SMSTooFrequent",
+                "PhoneAlreadyBound",
+                "DataExportTooFrequent",
+                "DataExportCodeInvalid",
+                "UserErased",
+                "CaptchaVerifyFail",
+                "RoleCreateDuplicateName",
+                "NoValidRoles",
+                "RoleNotExist",
+                "RoleExistPermissionRef",
+                "RoleExistUserRef",
+                "PermissionCreateDuplicate",
+                "PermissionNotExist",
+                "PermissionExistRoleRef",
+                "InvalidAttachmentLength",
+                "DeptCreateDuplicate",
+                "DeptNotExist",
+                "DeptExistUserRef"
+            ]
+        },
         "response.Updater": {
             "type": "object",
             "properties": {
@@ -2622,6 +7330,10 @@ const docTemplate = `{
                 "nickname": {
                     "type": "string"
                 },
+                "phone": {
+                    "description": "手机号,绑定后可用于验证码登录",
+                    "type": "string"
+                },
                 "roles": {},
                 "status": {},
                 "updated_at": {}
@@ -2661,6 +7373,10 @@ const docTemplate = `{
                 "nickname": {
                     "type": "string"
                 },
+                "phone": {
+                    "description": "手机号,绑定后可用于验证码登录",
+                    "type": "string"
+                },
                 "roles": {
                     "description": "角色",
                     "type": "array",
@@ -2675,6 +7391,43 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "response.UserSessionResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "登录时间",
+                    "type": "string"
+                },
+                "current": {
+                    "description": "是否为当前会话",
+                    "type": "boolean"
+                },
+                "device": {
+                    "description": "设备名称",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "会话ID",
+                    "type": "string"
+                },
+                "ip": {
+                    "description": "IP",
+                    "type": "string"
+                },
+                "last_seen": {
+                    "description": "最近活跃时间",
+                    "type": "string"
+                },
+                "uid": {
+                    "description": "用户ID",
+                    "type": "integer"
+                },
+                "user_agent": {
+                    "description": "UA",
+                    "type": "string"
+                }
+            }
         }
     }
 }`
@@ -4,13 +4,16 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/supuwoerc/weaver/conf"
 	"github.com/supuwoerc/weaver/middleware"
+	"github.com/supuwoerc/weaver/pkg/jwt"
 	"github.com/supuwoerc/weaver/pkg/logger"
 	local "github.com/supuwoerc/weaver/pkg/redis"
 	"github.com/supuwoerc/weaver/router"
 	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
-func NewEngine(emailClient *EmailClient, rc *local.CommonRedisClient, logger *logger.Logger, conf *conf.Config) *gin.Engine {
+func NewEngine(
+	emailClient *EmailClient, rc *local.CommonRedisClient, tb *jwt.TokenBuilder, logger *logger.Logger, conf *conf.Config,
+) *gin.Engine {
 	initDebugLogger(logger)
 	// 不携带日志和Recovery中间件，自己添加中间件，为了方便收集Recovery日志
 	r := gin.New()
@@ -46,6 +49,8 @@ func NewEngine(emailClient *EmailClient, rc *local.CommonRedisClient, logger *lo
 	router.InitSystemWebRouter(r)
 	// consul健康检查路由
 	router.InitHealthCheckRouter(r)
+	// token签名公钥路由
+	router.InitJWKSRouter(r, tb)
 	// swag相关路由
 	router.InitSwagWebRouter(r, conf)
 	// prometheus相关路由
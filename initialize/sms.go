@@ -0,0 +1,45 @@
+package initialize
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/supuwoerc/weaver/conf"
+	"github.com/supuwoerc/weaver/pkg/constant"
+	"github.com/supuwoerc/weaver/pkg/sms"
+)
+
+const smsProviderHTTP = "http"
+
+type SMSClient struct {
+	sender sms.Sender
+	conf   *conf.Config
+}
+
+// NewSMSClient 按配置选择短信服务,未配置时只打印日志
+func NewSMSClient(logger ClientLogger, conf *conf.Config) *SMSClient {
+	var sender sms.Sender
+	if conf.SMS.Provider == smsProviderHTTP {
+		if strings.TrimSpace(conf.SMS.URL) == "" {
+			panic("sms.url is required")
+		}
+		sender = sms.NewHTTPSender(conf.SMS.URL, conf.SMS.Token, conf.SMS.Timeout*time.Second)
+	} else {
+		sender = sms.NewLogSender(logger)
+	}
+	return &SMSClient{
+		sender: sender,
+		conf:   conf,
+	}
+}
+
+// Send 使用场景对应的短信模板发送短信
+func (s *SMSClient) Send(ctx context.Context, to string, scene constant.SMSScene, params map[string]string) error {
+	return s.sender.Send(ctx, &sms.Message{
+		Phone:    to,
+		Sign:     s.conf.SMS.Sign,
+		Template: s.conf.SMS.Templates[string(scene)],
+		Params:   params,
+	})
+}
@@ -3,28 +3,37 @@ package middleware
 import (
 	"context"
 	"errors"
-	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/supuwoerc/weaver/conf"
+	"github.com/supuwoerc/weaver/models"
 	"github.com/supuwoerc/weaver/pkg/constant"
 	"github.com/supuwoerc/weaver/pkg/jwt"
 	"github.com/supuwoerc/weaver/pkg/response"
+	"github.com/supuwoerc/weaver/pkg/utils"
 
 	"github.com/gin-gonic/gin"
 )
 
-const (
-	refreshUrl = "/api/v1/user/refresh-token"
-)
-
 type AuthMiddlewareTokenRepo interface {
-	CacheRefreshToken(ctx context.Context, email, refreshToken string, expiration time.Duration) error
+	TouchSession(ctx context.Context, sid string) (bool, error)
+	IsAccessTokenDenied(ctx context.Context, jti string) (bool, error)
 }
 
 type AuthMiddlewarePermissionRepo interface {
-	CheckUserPermission(ctx context.Context, uid uint, resource string, permissionType constant.PermissionType) (bool, error)
+	GetGoverningRoutes(ctx context.Context, method, route string) ([]models.RoutePattern, error)
+	CheckUserRoutePermission(ctx context.Context, uid uint, method, route string) (bool, error)
+}
+
+type AuthMiddlewareDataScopeRepo interface {
+	GetUserDataScope(ctx context.Context, uid uint) (*models.DataScope, error)
+}
+
+type AuthMiddlewareAPIKeyRepo interface {
+	GetByKey(ctx context.Context, key string) (*models.UserAPIKey, error)
+	Touch(ctx context.Context, id uint, now time.Time, interval time.Duration) error
 }
 
 type AuthMiddleware struct {
@@ -32,6 +41,10 @@ type AuthMiddleware struct {
 	tokenRepo         AuthMiddlewareTokenRepo
 	jwtBuilder        *jwt.TokenBuilder
 	permissionChecker AuthMiddlewarePermissionRepo
+	apiKeyRepo        AuthMiddlewareAPIKeyRepo
+	dataScopeRepo     AuthMiddlewareDataScopeRepo
+	routesMu          sync.Mutex
+	routes            []*models.RegisteredRoute // 通过PermissionGroup注册的需要API权限的路由
 }
 
 func NewAuthMiddleware(
@@ -39,68 +52,161 @@ func NewAuthMiddleware(
 	tokenRepo AuthMiddlewareTokenRepo,
 	jwtBuilder *jwt.TokenBuilder,
 	permissionCheck AuthMiddlewarePermissionRepo,
+	apiKeyRepo AuthMiddlewareAPIKeyRepo,
+	dataScopeRepo AuthMiddlewareDataScopeRepo,
 ) *AuthMiddleware {
 	return &AuthMiddleware{
 		conf:              conf,
 		tokenRepo:         tokenRepo,
 		jwtBuilder:        jwtBuilder,
 		permissionChecker: permissionCheck,
+		apiKeyRepo:        apiKeyRepo,
+		dataScopeRepo:     dataScopeRepo,
 	}
 }
 
-// LoginRequired 检查token和refresh_token的有效性
+// LoginRequired 检查token的有效性、是否已被吊销及所属会话是否已被注销,refresh_token不能作为token使用,
+// 携带api key的请求按api key认证
 func (l *AuthMiddleware) LoginRequired() gin.HandlerFunc {
 	tokenKey := l.conf.JWT.TokenKey
-	refreshTokenKey := l.conf.JWT.RefreshTokenKey
 	prefix := l.conf.JWT.TokenPrefix
+	apiKeyHeader := l.conf.APIKey.Header
 	return func(ctx *gin.Context) {
+		if key := ctx.GetHeader(apiKeyHeader); apiKeyHeader != "" && key != "" {
+			l.apiKeyRequired(ctx, key)
+			return
+		}
 		token := ctx.GetHeader(tokenKey)
 		if token == "" || !strings.HasPrefix(token, prefix) {
 			response.FailWithError(ctx, response.InvalidToken)
 			return
 		}
 		token = strings.TrimPrefix(token, prefix)
-		claims, err := l.jwtBuilder.ParseToken(token)
-		isRefresh := errors.Is(err, response.InvalidToken) &&
-			ctx.Request.URL.Path == refreshUrl &&
-			ctx.Request.Method == http.MethodPost &&
-			claims != nil &&
-			claims.User != nil &&
-			claims.User.Email != ""
-		if err == nil {
-			if ctx.Request.URL.Path == refreshUrl {
-				response.FailWithError(ctx, response.UnnecessaryRefreshToken)
-				return
-			}
-			ctx.Set(constant.ClaimsContextKey, claims)
-		} else if isRefresh {
-			// 短token错误,检查是否满足刷新token的情况
-			refreshToken := ctx.GetHeader(refreshTokenKey)
-			if strings.TrimSpace(refreshToken) == "" {
-				response.FailWithError(ctx, response.InvalidRefreshToken)
-				return
-			}
-			cachedRefreshToken, tempErr := l.jwtBuilder.GetRefreshToken(ctx, claims.User.Email)
-			if cachedRefreshToken == "" || tempErr != nil || cachedRefreshToken != refreshToken {
-				response.FailWithError(ctx, response.InvalidRefreshToken)
-				return
-			}
-			newToken, refreshErr := l.jwtBuilder.GenerateAccessToken(claims.User, time.Now())
-			if refreshErr != nil {
-				response.FailWithError(ctx, err)
+		claims, err := l.jwtBuilder.ParseAccessToken(token)
+		if err != nil {
+			response.FailWithError(ctx, response.InvalidToken)
+			return
+		}
+		denied, err := l.tokenRepo.IsAccessTokenDenied(ctx, claims.ID)
+		if err != nil {
+			response.FailWithError(ctx, err)
+			return
+		}
+		if denied {
+			response.FailWithError(ctx, response.InvalidToken)
+			return
+		}
+		valid, err := l.tokenRepo.TouchSession(ctx, claims.Session)
+		if err != nil {
+			response.FailWithError(ctx, err)
+			return
+		}
+		if !valid {
+			response.FailWithError(ctx, response.InvalidToken)
+			return
+		}
+		ctx.Set(constant.ClaimsContextKey, claims)
+		l.injectDataScope(ctx, claims)
+	}
+}
+
+// checkAPIKeyPermission api key的权限范围需要包含对请求生效的API权限,与用户的API权限判断规则一致
+func (l *AuthMiddleware) checkAPIKeyPermission(ctx *gin.Context, apiKey *models.UserAPIKey) error {
+	governing, err := l.permissionChecker.GetGoverningRoutes(ctx, ctx.Request.Method, requestRoute(ctx))
+	if err != nil {
+		return err
+	}
+	if !apiKey.HasPermission(governing) {
+		return response.AuthErr
+	}
+	return nil
+}
+
+// apiKeyRequired 检查api key的有效性,api key只能访问其权限范围内的接口,
+// 未受权限控制的接口(如修改密码、管理api key)同样拒绝,避免api key越权
+func (l *AuthMiddleware) apiKeyRequired(ctx *gin.Context, key string) {
+	if !strings.HasPrefix(key, l.conf.APIKey.Prefix) {
+		response.FailWithError(ctx, response.InvalidAPIKey)
+		return
+	}
+	apiKey, err := l.apiKeyRepo.GetByKey(ctx, utils.Sha256(key))
+	if err != nil {
+		if errors.Is(err, response.APIKeyNotExist) {
+			response.FailWithError(ctx, response.InvalidAPIKey)
+			return
+		}
+		response.FailWithError(ctx, err)
+		return
+	}
+	now := time.Now()
+	if apiKey.IsExpired(now) || apiKey.User.Status != constant.Normal {
+		response.FailWithError(ctx, response.InvalidAPIKey)
+		return
+	}
+	if err = l.checkAPIKeyPermission(ctx, apiKey); err != nil {
+		response.FailWithError(ctx, err)
+		return
+	}
+	if err = l.apiKeyRepo.Touch(ctx, apiKey.ID, now, l.conf.APIKey.TouchInterval*time.Second); err != nil {
+		response.FailWithError(ctx, err)
+		return
+	}
+	claims := &jwt.TokenClaims{
+		User: &jwt.TokenClaimsBasic{
+			ID:       apiKey.User.ID,
+			Email:    apiKey.User.Email,
+			Nickname: apiKey.User.Nickname,
+		},
+		APIKey: apiKey.ID,
+	}
+	ctx.Set(constant.APIKeyContextKey, apiKey)
+	ctx.Set(constant.ClaimsContextKey, claims)
+	l.injectDataScope(ctx, claims)
+}
+
+// injectDataScope 在请求上下文中注入调用者的数据权限范围,列表查询时按需解析且同一请求内只解析一次,
+// 管理员不限制范围,模拟登录时按被模拟用户的范围过滤
+func (l *AuthMiddleware) injectDataScope(ctx *gin.Context, claims *jwt.TokenClaims) {
+	var (
+		once  sync.Once
+		scope *models.DataScope
+		err   error
+	)
+	ctx.Set(constant.DataScopeContextKey, models.DataScopeLoader(func() (*models.DataScope, error) {
+		once.Do(func() {
+			if claims.User.Email == l.conf.System.Admin.Email {
+				scope = &models.DataScope{UserID: claims.User.ID, All: true}
 				return
 			}
-			response.SuccessWithData[response.RefreshTokenResponse](ctx, response.RefreshTokenResponse{
-				Token: newToken,
-			})
-			ctx.Abort()
-		} else {
-			// 其他情况直接返回错误信息
-			response.FailWithError(ctx, response.InvalidToken)
+			scope, err = l.dataScopeRepo.GetUserDataScope(ctx, claims.User.ID)
+		})
+		return scope, err
+	}))
+}
+
+// NotImpersonated 模拟登录期间禁止修改凭证等敏感操作,需要在LoginRequired之后使用
+func (l *AuthMiddleware) NotImpersonated() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		claims, err := utils.GetContextClaims(ctx)
+		if err != nil {
+			response.FailWithError(ctx, response.AuthErr)
+			return
+		}
+		if claims.Actor != nil {
+			response.FailWithError(ctx, response.ImpersonationForbidden)
 		}
 	}
 }
 
+// requestRoute 请求匹配的gin路由模板,API权限按路由模板匹配,未匹配到路由时使用请求路径
+func requestRoute(ctx *gin.Context) string {
+	if route := ctx.FullPath(); route != "" {
+		return route
+	}
+	return ctx.Request.URL.Path
+}
+
+// PermissionRequired 按请求方法和路由模板检查API权限,匹配请求的权限中具体程度最高的权限生效
 func (l *AuthMiddleware) PermissionRequired() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// 获取用户信息
@@ -114,12 +220,24 @@ func (l *AuthMiddleware) PermissionRequired() gin.HandlerFunc {
 			response.FailWithError(c, response.AuthErr)
 			return
 		}
+		// api key的权限为密钥权限范围与用户权限的交集
+		if value, exists := c.Get(constant.APIKeyContextKey); exists {
+			apiKey, ok := value.(*models.UserAPIKey)
+			if !ok {
+				response.FailWithError(c, response.AuthErr)
+				return
+			}
+			if err := l.checkAPIKeyPermission(c, apiKey); err != nil {
+				response.FailWithError(c, err)
+				return
+			}
+		}
 		if tokenClaims.User.Email == l.conf.System.Admin.Email {
 			c.Next()
 			return
 		}
-		// 检查API权限
-		hasPermission, err := l.permissionChecker.CheckUserPermission(c, tokenClaims.User.ID, c.Request.URL.Path, constant.ApiRoute)
+		// 检查API权限,模拟登录时按被模拟用户的权限检查
+		hasPermission, err := l.permissionChecker.CheckUserRoutePermission(c, tokenClaims.User.ID, c.Request.Method, requestRoute(c))
 		if err != nil {
 			response.FailWithError(c, err)
 			return
@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/supuwoerc/weaver/pkg/constant"
+	"github.com/supuwoerc/weaver/pkg/jwt"
 	"github.com/supuwoerc/weaver/pkg/logger"
 )
 
@@ -53,6 +55,12 @@ func (e *EnginLoggerMiddleware) Logger() gin.HandlerFunc {
 			"size", param.BodySize,
 			"client", param.ClientIP,
 		}
+		// 模拟登录的请求同时记录真实操作人
+		if value, exists := c.Get(constant.ClaimsContextKey); exists {
+			if claims, ok := value.(*jwt.TokenClaims); ok && claims.Actor != nil {
+				infos = append(infos, "uid", claims.User.ID, "actor", claims.Actor.ID)
+			}
+		}
 		if param.ErrorMessage != "" {
 			infos = append(infos, "error", param.ErrorMessage)
 		}
@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"net/http"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/supuwoerc/weaver/models"
+)
+
+// PermissionGroup 注册需要API权限的路由,注册时在路由的处理函数前插入PermissionRequired并记录路由,用于同步API权限
+type PermissionGroup struct {
+	group *gin.RouterGroup
+	auth  *AuthMiddleware
+}
+
+// PermissionGroup 在路由组上注册需要API权限的路由
+func (l *AuthMiddleware) PermissionGroup(group *gin.RouterGroup) *PermissionGroup {
+	return &PermissionGroup{group: group, auth: l}
+}
+
+// Handle 注册路由,PermissionRequired位于其他中间件之后、路由的处理函数之前
+func (g *PermissionGroup) Handle(method, relativePath string, handlers ...gin.HandlerFunc) gin.IRoutes {
+	if len(handlers) == 0 {
+		panic("there must be at least one handler")
+	}
+	last := len(handlers) - 1
+	chain := append(slices.Clone(handlers[:last]), g.auth.PermissionRequired(), handlers[last])
+	g.auth.recordRoute(method, joinRoutePath(g.group.BasePath(), relativePath))
+	return g.group.Handle(method, relativePath, chain...)
+}
+
+func (g *PermissionGroup) GET(relativePath string, handlers ...gin.HandlerFunc) gin.IRoutes {
+	return g.Handle(http.MethodGet, relativePath, handlers...)
+}
+
+func (g *PermissionGroup) POST(relativePath string, handlers ...gin.HandlerFunc) gin.IRoutes {
+	return g.Handle(http.MethodPost, relativePath, handlers...)
+}
+
+func (l *AuthMiddleware) recordRoute(method, route string) {
+	l.routesMu.Lock()
+	defer l.routesMu.Unlock()
+	l.routes = append(l.routes, &models.RegisteredRoute{Method: method, Path: route})
+}
+
+// PermissionRoutes 通过PermissionGroup注册的需要API权限的路由,需要在全部路由注册完成后调用
+func (l *AuthMiddleware) PermissionRoutes() []*models.RegisteredRoute {
+	l.routesMu.Lock()
+	defer l.routesMu.Unlock()
+	routes := make([]*models.RegisteredRoute, 0, len(l.routes))
+	for _, item := range l.routes {
+		route := *item
+		routes = append(routes, &route)
+	}
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return routes[i].Method < routes[j].Method
+	})
+	return routes
+}
+
+// joinRoutePath 与gin拼接路由组路径的规则一致,保留相对路径末尾的斜杠
+func joinRoutePath(basePath, relativePath string) string {
+	if relativePath == "" {
+		return basePath
+	}
+	route := path.Join(basePath, relativePath)
+	if strings.HasSuffix(relativePath, "/") && !strings.HasSuffix(route, "/") {
+		return route + "/"
+	}
+	return route
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/supuwoerc/weaver/conf"
+	"github.com/supuwoerc/weaver/models"
+	"github.com/supuwoerc/weaver/pkg/constant"
+	"github.com/supuwoerc/weaver/pkg/jwt"
+)
+
+func TestAuthMiddleware_PermissionGroup(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	config := &conf.Config{}
+	config.System.Admin.Email = "admin@weaver.com"
+	auth := NewAuthMiddleware(config, nil, nil, nil, nil, nil)
+	engine := gin.New()
+	var steps []string
+	loginRequired := func(c *gin.Context) {
+		steps = append(steps, "login")
+		c.Set(constant.ClaimsContextKey, &jwt.TokenClaims{User: &jwt.TokenClaimsBasic{Email: config.System.Admin.Email}})
+	}
+	handler := func(c *gin.Context) {
+		steps = append(steps, "handler")
+		c.Status(http.StatusOK)
+	}
+	group := engine.Group("/api/v1")
+	group.GET("ping", handler)
+	auth.PermissionGroup(group).GET("user/list", loginRequired, handler)
+	roleGroup := auth.PermissionGroup(group.Group("role", loginRequired))
+	roleGroup.GET("detail/:id", handler)
+	roleGroup.POST("delete", handler)
+
+	assert.Equal(t, []*models.RegisteredRoute{
+		{Method: http.MethodPost, Path: "/api/v1/role/delete"},
+		{Method: http.MethodGet, Path: "/api/v1/role/detail/:id"},
+		{Method: http.MethodGet, Path: "/api/v1/user/list"},
+	}, auth.PermissionRoutes())
+
+	// 权限检查位于路由的其他中间件之后,缺少登录信息时不会执行处理函数
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/user/list", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []string{"login", "handler"}, steps)
+	steps = nil
+	unauthorized := gin.New()
+	auth.PermissionGroup(unauthorized.Group("/api/v1")).GET("user/list", handler)
+	w = httptest.NewRecorder()
+	unauthorized.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/user/list", nil))
+	assert.Empty(t, steps)
+}
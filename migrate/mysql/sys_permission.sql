@@ -5,6 +5,7 @@ create table sys_permission
     name       varchar(20)      not null comment '权限名',
     resource   varchar(255)     not null comment '资源名',
     type       tinyint(1)       not null comment '资源类型',
+    method     varchar(10)      default '*' not null comment 'API权限的请求方法,*表示任意方法',
     creator_id bigint unsigned  not null comment '创建人ID',
     updater_id bigint unsigned  not null comment '更新人ID',
     created_at datetime(3)      not null comment '创建时间',
@@ -12,31 +13,47 @@ create table sys_permission
     deleted_at bigint default 0 not null comment '删除时间',
     constraint uni_sys_permission_name_deleted_at
         unique (name, deleted_at),
-    constraint uni_sys_permission_resource_deleted_at
-        unique (resource, deleted_at)
+    constraint uni_sys_permission_resource_method_deleted_at
+        unique (resource, method, deleted_at)
 )
     comment '权限表';
 
 create index idx_sys_permission_deleted_at
     on sys_permission (deleted_at);
 
-INSERT INTO gin_web.sys_permission (id, name, resource, type, creator_id, updater_id, created_at, updated_at, deleted_at) VALUES (1, '系统设置-用户管理', '/setting/user', 1, 1, 1, '2025-09-05 07:48:01.000', '2025-09-05 07:48:03.000', 0);
-INSERT INTO gin_web.sys_permission (id, name, resource, type, creator_id, updater_id, created_at, updated_at, deleted_at) VALUES (2, '系统设置-部门管理', '/setting/department', 1, 1, 1, '2025-09-09 21:00:40.000', '2025-09-09 21:00:42.000', 0);
-INSERT INTO gin_web.sys_permission (id, name, resource, type, creator_id, updater_id, created_at, updated_at, deleted_at) VALUES (3, '系统设置-角色管理', '/setting/role', 1, 1, 1, '2025-09-09 21:01:08.000', '2025-09-09 21:01:09.000', 0);
-INSERT INTO gin_web.sys_permission (id, name, resource, type, creator_id, updater_id, created_at, updated_at, deleted_at) VALUES (4, '系统设置-权限管理', '/setting/permission', 1, 1, 1, '2025-09-09 21:01:31.000', '2025-09-09 21:01:33.000', 0);
-INSERT INTO gin_web.sys_permission (id, name, resource, type, creator_id, updater_id, created_at, updated_at, deleted_at) VALUES (5, '系统设置-用户管理-菜单', 'router.setting.user', 2, 1, 1, '2025-09-09 21:04:58.000', '2025-09-09 21:05:01.000', 0);
-INSERT INTO gin_web.sys_permission (id, name, resource, type, creator_id, updater_id, created_at, updated_at, deleted_at) VALUES (6, '系统设置-部门管理-菜单', 'router.setting.department', 2, 1, 1, '2025-09-09 21:07:16.000', '2025-09-09 21:07:18.000', 0);
-INSERT INTO gin_web.sys_permission (id, name, resource, type, creator_id, updater_id, created_at, updated_at, deleted_at) VALUES (7, '系统设置-角色管理-菜单', 'router.setting.role', 2, 1, 1, '2025-09-09 21:09:23.000', '2025-09-09 21:09:25.000', 0);
-INSERT INTO gin_web.sys_permission (id, name, resource, type, creator_id, updater_id, created_at, updated_at, deleted_at) VALUES (8, '系统设置-权限管理-菜单', 'router.setting.permission', 2, 1, 1, '2025-09-09 21:10:03.000', '2025-09-09 21:10:05.000', 0);
-INSERT INTO gin_web.sys_permission (id, name, resource, type, creator_id, updater_id, created_at, updated_at, deleted_at) VALUES (9, '系统设置-用户管理-列表接口', '/api/v1/user/list', 4, 1, 1, '2025-09-09 21:13:32.000', '2025-09-09 21:13:33.000', 0);
-INSERT INTO gin_web.sys_permission (id, name, resource, type, creator_id, updater_id, created_at, updated_at, deleted_at) VALUES (10, '系统设置-部门管理-创建接口', '/api/v1/department/create', 4, 1, 1, '2025-09-09 21:14:26.000', '2025-09-09 21:14:27.000', 0);
-INSERT INTO gin_web.sys_permission (id, name, resource, type, creator_id, updater_id, created_at, updated_at, deleted_at) VALUES (11, '系统设置-权限管理-创建接口', '/api/v1/permission/create', 4, 1, 1, '2025-09-09 21:16:38.000', '2025-09-09 21:16:46.000', 0);
-INSERT INTO gin_web.sys_permission (id, name, resource, type, creator_id, updater_id, created_at, updated_at, deleted_at) VALUES (12, '系统设置-权限管理-列表接口', '/api/v1/permission/list', 4, 1, 1, '2025-09-09 21:16:40.000', '2025-09-09 21:16:48.000', 0);
-INSERT INTO gin_web.sys_permission (id, name, resource, type, creator_id, updater_id, created_at, updated_at, deleted_at) VALUES (13, '系统设置-权限管理-详情接口', '/api/v1/permission/detail', 4, 1, 1, '2025-09-09 21:16:41.000', '2025-09-09 21:16:49.000', 0);
-INSERT INTO gin_web.sys_permission (id, name, resource, type, creator_id, updater_id, created_at, updated_at, deleted_at) VALUES (14, '系统设置-权限管理-更新接口', '/api/v1/permission/update', 4, 1, 1, '2025-09-09 21:16:43.000', '2025-09-09 21:16:50.000', 0);
-INSERT INTO gin_web.sys_permission (id, name, resource, type, creator_id, updater_id, created_at, updated_at, deleted_at) VALUES (15, '系统设置-权限管理-删除接口', '/api/v1/permission/delete', 4, 1, 1, '2025-09-09 21:16:44.000', '2025-09-09 21:16:52.000', 0);
-INSERT INTO gin_web.sys_permission (id, name, resource, type, creator_id, updater_id, created_at, updated_at, deleted_at) VALUES (16, '系统设置-角色管理-列表接口', '/api/v1/role/list', 4, 1, 1, '2025-09-10 08:29:46.000', '2025-09-10 08:29:48.000', 0);
-INSERT INTO gin_web.sys_permission (id, name, resource, type, creator_id, updater_id, created_at, updated_at, deleted_at) VALUES (17, '系统设置-角色管理-详情接口', '/api/v1/role/detail', 4, 1, 1, '2025-09-11 06:46:27.000', '2025-09-11 06:46:30.000', 0);
-INSERT INTO gin_web.sys_permission (id, name, resource, type, creator_id, updater_id, created_at, updated_at, deleted_at) VALUES (18, '系统设置-角色管理-更新接口', '/api/v1/role/update', 4, 1, 1, '2025-09-11 06:47:32.000', '2025-09-11 06:47:34.000', 0);
-INSERT INTO gin_web.sys_permission (id, name, resource, type, creator_id, updater_id, created_at, updated_at, deleted_at) VALUES (19, '系统设置-角色管理-删除接口', '/api/v1/role/delete', 4, 1, 1, '2025-09-11 06:47:32.000', '2025-09-11 06:47:34.000', 0);
-INSERT INTO gin_web.sys_permission (id, name, resource, type, creator_id, updater_id, created_at, updated_at, deleted_at) VALUES (20, '系统设置-角色管理-创建接口', '/api/v1/role/create', 4, 1, 1, '2025-09-11 06:47:32.000', '2025-09-11 06:47:34.000', 0);
+INSERT INTO gin_web.sys_permission (id, name, resource, type, method, creator_id, updater_id, created_at, updated_at, deleted_at) VALUES (1, '系统设置-用户管理', '/setting/user', 1, '*', 1, 1, '2025-09-05 07:48:01.000', '2025-09-05 07:48:03.000', 0);
+INSERT INTO gin_web.sys_permission (id, name, resource, type, method, creator_id, updater_id, created_at, updated_at, deleted_at) VALUES (2, '系统设置-部门管理', '/setting/department', 1, '*', 1, 1, '2025-09-09 21:00:40.000', '2025-09-09 21:00:42.000', 0);
+INSERT INTO gin_web.sys_permission (id, name, resource, type, method, creator_id, updater_id, created_at, updated_at, deleted_at) VALUES (3, '系统设置-角色管理', '/setting/role', 1, '*', 1, 1, '2025-09-09 21:01:08.000', '2025-09-09 21:01:09.000', 0);
+INSERT INTO gin_web.sys_permission (id, name, resource, type, method, creator_id, updater_id, created_at, updated_at, deleted_at) VALUES (4, '系统设置-权限管理', '/setting/permission', 1, '*', 1, 1, '2025-09-09 21:01:31.000', '2025-09-09 21:01:33.000', 0);
+INSERT INTO gin_web.sys_permission (id, name, resource, type, method, creator_id, updater_id, created_at, updated_at, deleted_at) VALUES (5, '系统设置-用户管理-菜单', 'router.setting.user', 2, '*', 1, 1, '2025-09-09 21:04:58.000', '2025-09-09 21:05:01.000', 0);
+INSERT INTO gin_web.sys_permission (id, name, resource, type, method, creator_id, updater_id, created_at, updated_at, deleted_at) VALUES (6, '系统设置-部门管理-菜单', 'router.setting.department', 2, '*', 1, 1, '2025-09-09 21:07:16.000', '2025-09-09 21:07:18.000', 0);
+INSERT INTO gin_web.sys_permission (id, name, resource, type, method, creator_id, updater_id, created_at, updated_at, deleted_at) VALUES (7, '系统设置-角色管理-菜单', 'router.setting.role', 2, '*', 1, 1, '2025-09-09 21:09:23.000', '2025-09-09 21:09:25.000', 0);
+INSERT INTO gin_web.sys_permission (id, name, resource, type, method, creator_id, updater_id, created_at, updated_at, deleted_at) VALUES (8, '系统设置-权限管理-菜单', 'router.setting.permission', 2, '*', 1, 1, '2025-09-09 21:10:03.000', '2025-09-09 21:10:05.000', 0);
+INSERT INTO gin_web.sys_permission (id, name, resource, type, method, creator_id, updater_id, created_at, updated_at, deleted_at) VALUES (9, '系统设置-用户管理-列表接口', '/api/v1/user/list', 4, 'GET', 1, 1, '2025-09-09 21:13:32.000', '2025-09-09 21:13:33.000', 0);
+INSERT INTO gin_web.sys_permission (id, name, resource, type, method, creator_id, updater_id, created_at, updated_at, deleted_at) VALUES (10, '系统设置-部门管理-创建接口', '/api/v1/department/create', 4, 'POST', 1, 1, '2025-09-09 21:14:26.000', '2025-09-09 21:14:27.000', 0);
+INSERT INTO gin_web.sys_permission (id, name, resource, type, method, creator_id, updater_id, created_at, updated_at, deleted_at) VALUES (11, '系统设置-权限管理-创建接口', '/api/v1/permission/create', 4, 'POST', 1, 1, '2025-09-09 21:16:38.000', '2025-09-09 21:16:46.000', 0);
+INSERT INTO gin_web.sys_permission (id, name, resource, type, method, creator_id, updater_id, created_at, updated_at, deleted_at) VALUES (12, '系统设置-权限管理-列表接口', '/api/v1/permission/list', 4, 'GET', 1, 1, '2025-09-09 21:16:40.000', '2025-09-09 21:16:48.000', 0);
+INSERT INTO gin_web.sys_permission (id, name, resource, type, method, creator_id, updater_id, created_at, updated_at, deleted_at) VALUES (13, '系统设置-权限管理-详情接口', '/api/v1/permission/detail', 4, 'GET', 1, 1, '2025-09-09 21:16:41.000', '2025-09-09 21:16:49.000', 0);
+INSERT INTO gin_web.sys_permission (id, name, resource, type, method, creator_id, updater_id, created_at, updated_at, deleted_at) VALUES (14, '系统设置-权限管理-更新接口', '/api/v1/permission/update', 4, 'POST', 1, 1, '2025-09-09 21:16:43.000', '2025-09-09 21:16:50.000', 0);
+INSERT INTO gin_web.sys_permission (id, name, resource, type, method, creator_id, updater_id, created_at, updated_at, deleted_at) VALUES (15, '系统设置-权限管理-删除接口', '/api/v1/permission/delete', 4, 'POST', 1, 1, '2025-09-09 21:16:44.000', '2025-09-09 21:16:52.000', 0);
+INSERT INTO gin_web.sys_permission (id, name, resource, type, method, creator_id, updater_id, created_at, updated_at, deleted_at) VALUES (16, '系统设置-角色管理-列表接口', '/api/v1/role/list', 4, 'GET', 1, 1, '2025-09-10 08:29:46.000', '2025-09-10 08:29:48.000', 0);
+INSERT INTO gin_web.sys_permission (id, name, resource, type, method, creator_id, updater_id, created_at, updated_at, deleted_at) VALUES (17, '系统设置-角色管理-详情接口', '/api/v1/role/detail', 4, 'GET', 1, 1, '2025-09-11 06:46:27.000', '2025-09-11 06:46:30.000', 0);
+INSERT INTO gin_web.sys_permission (id, name, resource, type, method, creator_id, updater_id, created_at, updated_at, deleted_at) VALUES (18, '系统设置-角色管理-更新接口', '/api/v1/role/update', 4, 'POST', 1, 1, '2025-09-11 06:47:32.000', '2025-09-11 06:47:34.000', 0);
+INSERT INTO gin_web.sys_permission (id, name, resource, type, method, creator_id, updater_id, created_at, updated_at, deleted_at) VALUES (19, '系统设置-角色管理-删除接口', '/api/v1/role/delete', 4, 'POST', 1, 1, '2025-09-11 06:47:32.000', '2025-09-11 06:47:34.000', 0);
+INSERT INTO gin_web.sys_permission (id, name, resource, type, method, creator_id, updater_id, created_at, updated_at, deleted_at) VALUES (20, '系统设置-角色管理-创建接口', '/api/v1/role/create', 4, 'POST', 1, 1, '2025-09-11 06:47:32.000', '2025-09-11 06:47:34.000', 0);
+INSERT INTO gin_web.sys_permission (id, name, resource, type, method, creator_id, updater_id, created_at, updated_at, deleted_at) VALUES (21, '系统设置-用户管理-注销会话接口', '/api/v1/user/sessions/revoke-all', 4, 'POST', 1, 1, '2025-10-18 10:00:00.000', '2025-10-18 10:00:00.000', 0);
+INSERT INTO gin_web.sys_permission (id, name, resource, type, method, creator_id, updater_id, created_at, updated_at, deleted_at) VALUES (22, '系统设置-用户管理-解除登录锁定接口', '/api/v1/user/login/unlock', 4, 'POST', 1, 1, '2025-10-18 10:00:00.000', '2025-10-18 10:00:00.000', 0);
+INSERT INTO gin_web.sys_permission (id, name, resource, type, method, creator_id, updater_id, created_at, updated_at, deleted_at) VALUES (23, '系统设置-用户管理-创建接口', '/api/v1/user/create', 4, 'POST', 1, 1, '2025-10-18 10:00:00.000', '2025-10-18 10:00:00.000', 0);
+INSERT INTO gin_web.sys_permission (id, name, resource, type, method, creator_id, updater_id, created_at, updated_at, deleted_at) VALUES (24, '系统设置-用户管理-启用禁用接口', '/api/v1/user/status', 4, 'POST', 1, 1, '2025-10-18 10:00:00.000', '2025-10-18 10:00:00.000', 0);
+INSERT INTO gin_web.sys_permission (id, name, resource, type, method, creator_id, updater_id, created_at, updated_at, deleted_at) VALUES (25, '系统设置-用户管理-删除接口', '/api/v1/user/delete', 4, 'POST', 1, 1, '2025-10-18 10:00:00.000', '2025-10-18 10:00:00.000', 0);
+INSERT INTO gin_web.sys_permission (id, name, resource, type, method, creator_id, updater_id, created_at, updated_at, deleted_at) VALUES (26, '系统设置-用户管理-恢复接口', '/api/v1/user/restore', 4, 'POST', 1, 1, '2025-10-18 10:00:00.000', '2025-10-18 10:00:00.000', 0);
+INSERT INTO gin_web.sys_permission (id, name, resource, type, method, creator_id, updater_id, created_at, updated_at, deleted_at) VALUES (27, '系统设置-用户管理-分配角色部门接口', '/api/v1/user/assign', 4, 'POST', 1, 1, '2025-10-18 10:00:00.000', '2025-10-18 10:00:00.000', 0);
+INSERT INTO gin_web.sys_permission (id, name, resource, type, method, creator_id, updater_id, created_at, updated_at, deleted_at) VALUES (28, '系统设置-用户管理-导入接口', '/api/v1/user/import', 4, 'POST', 1, 1, '2025-10-18 10:00:00.000', '2025-10-18 10:00:00.000', 0);
+INSERT INTO gin_web.sys_permission (id, name, resource, type, method, creator_id, updater_id, created_at, updated_at, deleted_at) VALUES (29, '系统设置-用户管理-导出接口', '/api/v1/user/export', 4, 'GET', 1, 1, '2025-10-18 10:00:00.000', '2025-10-18 10:00:00.000', 0);
+INSERT INTO gin_web.sys_permission (id, name, resource, type, method, creator_id, updater_id, created_at, updated_at, deleted_at) VALUES (30, '系统设置-用户管理-模拟登录接口', '/api/v1/user/impersonate', 4, 'POST', 1, 1, '2025-10-18 10:00:00.000', '2025-10-18 10:00:00.000', 0);
+INSERT INTO gin_web.sys_permission (id, name, resource, type, method, creator_id, updater_id, created_at, updated_at, deleted_at) VALUES (31, '系统设置-用户管理-模拟登录记录接口', '/api/v1/user/impersonations', 4, 'GET', 1, 1, '2025-10-18 10:00:00.000', '2025-10-18 10:00:00.000', 0);
+INSERT INTO gin_web.sys_permission (id, name, resource, type, method, creator_id, updater_id, created_at, updated_at, deleted_at) VALUES (32, '系统设置-用户管理-邀请列表接口', '/api/v1/user/invitations', 4, 'GET', 1, 1, '2025-10-18 10:00:00.000', '2025-10-18 10:00:00.000', 0);
+INSERT INTO gin_web.sys_permission (id, name, resource, type, method, creator_id, updater_id, created_at, updated_at, deleted_at) VALUES (33, '系统设置-用户管理-创建邀请接口', '/api/v1/user/invitations/create', 4, 'POST', 1, 1, '2025-10-18 10:00:00.000', '2025-10-18 10:00:00.000', 0);
+INSERT INTO gin_web.sys_permission (id, name, resource, type, method, creator_id, updater_id, created_at, updated_at, deleted_at) VALUES (34, '系统设置-用户管理-撤销邀请接口', '/api/v1/user/invitations/revoke', 4, 'POST', 1, 1, '2025-10-18 10:00:00.000', '2025-10-18 10:00:00.000', 0);
+INSERT INTO gin_web.sys_permission (id, name, resource, type, method, creator_id, updater_id, created_at, updated_at, deleted_at) VALUES (35, '系统设置-用户管理-登录记录接口', '/api/v1/user/login-histories', 4, 'GET', 1, 1, '2025-10-18 10:00:00.000', '2025-10-18 10:00:00.000', 0);
+INSERT INTO gin_web.sys_permission (id, name, resource, type, method, creator_id, updater_id, created_at, updated_at, deleted_at) VALUES (36, '系统设置-用户管理-注销用户接口', '/api/v1/user/erase', 4, 'POST', 1, 1, '2025-10-18 10:00:00.000', '2025-10-18 10:00:00.000', 0);
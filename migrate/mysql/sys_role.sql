@@ -3,6 +3,7 @@ create table sys_role
     id         bigint unsigned auto_increment comment '主键ID'
         primary key,
     name       varchar(20)      not null comment '角色名',
+    data_scope tinyint default 1 not null comment '数据权限范围:1全部,2本部门,3本部门及子部门,4自定义部门,5仅本人',
     creator_id bigint unsigned  not null comment '创建人ID',
     updater_id bigint unsigned  not null comment '更新人ID',
     created_at datetime(3)      not null comment '创建时间',
@@ -16,4 +17,4 @@ create table sys_role
 create index idx_sys_role_deleted_at
     on sys_role (deleted_at);
 
-INSERT INTO gin_web.sys_role (id, name, creator_id, updater_id, created_at, updated_at, deleted_at) VALUES (1, '测试角色', 1, 1, '2025-09-05 07:48:29.000', '2025-09-05 07:48:31.000', 0);
+INSERT INTO gin_web.sys_role (id, name, data_scope, creator_id, updater_id, created_at, updated_at, deleted_at) VALUES (1, '测试角色', 1, 1, 1, '2025-09-05 07:48:29.000', '2025-09-05 07:48:31.000', 0);
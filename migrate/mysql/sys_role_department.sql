@@ -0,0 +1,13 @@
+create table sys_role_department
+(
+    role_id       bigint unsigned not null comment '角色ID',
+    department_id bigint unsigned not null comment '部门ID',
+    primary key (role_id, department_id)
+)
+    comment '角色-自定义数据权限部门中间表';
+
+create index idx_sys_role_department_department_id
+    on sys_role_department (department_id);
+
+create index idx_sys_role_department_role_id
+    on sys_role_department (role_id);
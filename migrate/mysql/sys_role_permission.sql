@@ -32,3 +32,19 @@ INSERT INTO gin_web.sys_role_permission (role_id, permission_id) VALUES (1, 17);
 INSERT INTO gin_web.sys_role_permission (role_id, permission_id) VALUES (1, 18);
 INSERT INTO gin_web.sys_role_permission (role_id, permission_id) VALUES (1, 19);
 INSERT INTO gin_web.sys_role_permission (role_id, permission_id) VALUES (1, 20);
+INSERT INTO gin_web.sys_role_permission (role_id, permission_id) VALUES (1, 21);
+INSERT INTO gin_web.sys_role_permission (role_id, permission_id) VALUES (1, 22);
+INSERT INTO gin_web.sys_role_permission (role_id, permission_id) VALUES (1, 23);
+INSERT INTO gin_web.sys_role_permission (role_id, permission_id) VALUES (1, 24);
+INSERT INTO gin_web.sys_role_permission (role_id, permission_id) VALUES (1, 25);
+INSERT INTO gin_web.sys_role_permission (role_id, permission_id) VALUES (1, 26);
+INSERT INTO gin_web.sys_role_permission (role_id, permission_id) VALUES (1, 27);
+INSERT INTO gin_web.sys_role_permission (role_id, permission_id) VALUES (1, 28);
+INSERT INTO gin_web.sys_role_permission (role_id, permission_id) VALUES (1, 29);
+INSERT INTO gin_web.sys_role_permission (role_id, permission_id) VALUES (1, 30);
+INSERT INTO gin_web.sys_role_permission (role_id, permission_id) VALUES (1, 31);
+INSERT INTO gin_web.sys_role_permission (role_id, permission_id) VALUES (1, 32);
+INSERT INTO gin_web.sys_role_permission (role_id, permission_id) VALUES (1, 33);
+INSERT INTO gin_web.sys_role_permission (role_id, permission_id) VALUES (1, 34);
+INSERT INTO gin_web.sys_role_permission (role_id, permission_id) VALUES (1, 35);
+INSERT INTO gin_web.sys_role_permission (role_id, permission_id) VALUES (1, 36);
@@ -1,20 +1,24 @@
 create table sys_user
 (
-    id         bigint unsigned auto_increment comment '主键ID'
+    id                  bigint unsigned auto_increment comment '主键ID'
         primary key,
-    email      varchar(50)      not null comment '邮箱',
-    password   varchar(60)      not null comment '密码',
-    nickname   varchar(20)      null comment '昵称',
-    avatar     varchar(255)     null comment '头像文件URL',
-    gender     tinyint unsigned null comment '性别',
-    about      varchar(100)     null comment '关于',
-    birthday   datetime(3)      null comment '生日',
-    status     tinyint(1)       not null comment '账户状态',
-    created_at datetime(3)      not null comment '创建时间',
-    updated_at datetime(3)      not null comment '更新时间',
-    deleted_at bigint default 0 not null comment '删除标志',
+    email               varchar(50)      not null comment '邮箱',
+    phone               varchar(20)      null comment '手机号',
+    password            varchar(60)      not null comment '密码',
+    nickname            varchar(20)      null comment '昵称',
+    avatar              varchar(255)     null comment '头像文件URL',
+    gender              tinyint unsigned null comment '性别',
+    about               varchar(100)     null comment '关于',
+    birthday            datetime(3)      null comment '生日',
+    status              tinyint(1)       not null comment '账户状态',
+    password_changed_at datetime(3)      null comment '密码修改时间',
+    created_at          datetime(3)      not null comment '创建时间',
+    updated_at          datetime(3)      not null comment '更新时间',
+    deleted_at          bigint default 0 not null comment '删除标志',
     constraint uni_sys_user_email
-        unique (email)
+        unique (email),
+    constraint uni_sys_user_phone
+        unique (phone)
 )
     comment '用户表';
 
@@ -0,0 +1,24 @@
+create table sys_user_api_key
+(
+    id           bigint unsigned auto_increment comment '主键ID'
+        primary key,
+    user_id      bigint unsigned  not null comment '用户ID',
+    name         varchar(50)      not null comment '名称',
+    prefix       varchar(20)      not null comment '密钥前缀',
+    `key`        varchar(64)      not null comment '密钥摘要',
+    expired_at   datetime(3)      null comment '过期时间',
+    last_used_at datetime(3)      null comment '最后使用时间',
+    created_at   datetime(3)      not null comment '创建时间',
+    updated_at   datetime(3)      not null comment '更新时间',
+    deleted_at   bigint default 0 not null comment '删除标志',
+    constraint uni_sys_user_api_key_key
+        unique (`key`)
+)
+    comment '用户API密钥表';
+
+create index idx_sys_user_api_key_deleted_at
+    on sys_user_api_key (deleted_at);
+
+create index idx_sys_user_api_key_user_id
+    on sys_user_api_key (user_id);
+
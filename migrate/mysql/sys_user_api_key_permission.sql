@@ -0,0 +1,11 @@
+create table sys_user_api_key_permission
+(
+    user_api_key_id bigint unsigned not null comment 'API密钥ID',
+    permission_id   bigint unsigned not null comment '权限ID',
+    primary key (user_api_key_id, permission_id)
+)
+    comment 'API密钥-权限中间表';
+
+create index idx_sys_user_api_key_permission_permission_id
+    on sys_user_api_key_permission (permission_id);
+
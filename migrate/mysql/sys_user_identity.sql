@@ -0,0 +1,22 @@
+create table sys_user_identity
+(
+    id         bigint unsigned auto_increment comment '主键ID'
+        primary key,
+    user_id    bigint unsigned  not null comment '用户ID',
+    provider   varchar(50)      not null comment '身份提供方标识',
+    subject    varchar(255)     not null comment '外部账户唯一标识',
+    email      varchar(255)     not null comment '外部账户邮箱',
+    created_at datetime(3)      not null comment '创建时间',
+    updated_at datetime(3)      not null comment '更新时间',
+    deleted_at bigint default 0 not null comment '删除标志',
+    constraint uni_sys_user_identity_provider_subject
+        unique (provider, subject)
+)
+    comment '用户外部账户关联表';
+
+create index idx_sys_user_identity_deleted_at
+    on sys_user_identity (deleted_at);
+
+create index idx_sys_user_identity_user_id
+    on sys_user_identity (user_id);
+
@@ -0,0 +1,28 @@
+create table sys_user_impersonation
+(
+    id         bigint unsigned auto_increment comment '主键ID'
+        primary key,
+    actor_id   bigint unsigned  not null comment '操作人ID',
+    user_id    bigint unsigned  not null comment '被模拟的用户ID',
+    token_id   varchar(36)      not null comment '模拟登录token的jti',
+    reason     varchar(255)     not null comment '模拟登录的原因',
+    ip         varchar(50)      not null comment '操作人IP',
+    expired_at datetime(3)      not null comment 'token过期时间',
+    stopped_at datetime(3)      null comment '主动结束时间',
+    created_at datetime(3)      not null comment '创建时间',
+    updated_at datetime(3)      not null comment '更新时间',
+    deleted_at bigint default 0 not null comment '删除标志',
+    constraint uni_sys_user_impersonation_token_id
+        unique (token_id)
+)
+    comment '用户模拟登录记录表';
+
+create index idx_sys_user_impersonation_deleted_at
+    on sys_user_impersonation (deleted_at);
+
+create index idx_sys_user_impersonation_actor_id
+    on sys_user_impersonation (actor_id);
+
+create index idx_sys_user_impersonation_user_id
+    on sys_user_impersonation (user_id);
+
@@ -0,0 +1,24 @@
+create table sys_user_invitation
+(
+    id          bigint unsigned auto_increment comment '主键ID'
+        primary key,
+    email       varchar(50)      not null comment '受邀邮箱',
+    code        varchar(64)      not null comment '邀请码摘要',
+    inviter_id  bigint unsigned  not null comment '邀请人ID',
+    expired_at  datetime(3)      not null comment '过期时间',
+    accepted_at datetime(3)      null comment '接受时间',
+    user_id     bigint unsigned  null comment '接受邀请后创建的用户ID',
+    created_at  datetime(3)      not null comment '创建时间',
+    updated_at  datetime(3)      not null comment '更新时间',
+    deleted_at  bigint default 0 not null comment '删除标志',
+    constraint uni_sys_user_invitation_code
+        unique (code)
+)
+    comment '用户注册邀请表';
+
+create index idx_sys_user_invitation_deleted_at
+    on sys_user_invitation (deleted_at);
+
+create index idx_sys_user_invitation_email
+    on sys_user_invitation (email);
+
@@ -0,0 +1,10 @@
+create table sys_user_invitation_department
+(
+    user_invitation_id bigint unsigned not null comment '邀请ID',
+    department_id      bigint unsigned not null comment '部门ID',
+    primary key (user_invitation_id, department_id)
+)
+    comment '邀请-部门中间表';
+
+create index idx_sys_user_invitation_department_department_id
+    on sys_user_invitation_department (department_id);
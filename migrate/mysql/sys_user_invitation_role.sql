@@ -0,0 +1,10 @@
+create table sys_user_invitation_role
+(
+    user_invitation_id bigint unsigned not null comment '邀请ID',
+    role_id            bigint unsigned not null comment '角色ID',
+    primary key (user_invitation_id, role_id)
+)
+    comment '邀请-角色中间表';
+
+create index idx_sys_user_invitation_role_role_id
+    on sys_user_invitation_role (role_id);
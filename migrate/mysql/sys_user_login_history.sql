@@ -0,0 +1,30 @@
+create table sys_user_login_history
+(
+    id         bigint unsigned auto_increment comment '主键ID'
+        primary key,
+    user_id    bigint unsigned  null comment '用户ID,邮箱未注册时为空',
+    email      varchar(50)      not null comment '登录邮箱',
+    type       varchar(20)      not null comment '登录方式',
+    success    tinyint(1)       not null comment '是否成功',
+    reason     varchar(50)      not null comment '失败原因',
+    mfa        tinyint(1)       not null comment '是否经过两步验证',
+    ip         varchar(50)      not null comment 'IP',
+    user_agent varchar(255)     not null comment 'UA',
+    device     varchar(100)     not null comment '设备名称',
+    created_at datetime(3)      not null comment '创建时间',
+    updated_at datetime(3)      not null comment '更新时间',
+    deleted_at bigint default 0 not null comment '删除标志'
+)
+    comment '用户登录记录表';
+
+create index idx_sys_user_login_history_deleted_at
+    on sys_user_login_history (deleted_at);
+
+create index idx_sys_user_login_history_user_id
+    on sys_user_login_history (user_id, created_at);
+
+create index idx_sys_user_login_history_email
+    on sys_user_login_history (email);
+
+create index idx_sys_user_login_history_created_at
+    on sys_user_login_history (created_at);
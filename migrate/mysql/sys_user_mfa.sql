@@ -0,0 +1,19 @@
+create table sys_user_mfa
+(
+    id         bigint unsigned auto_increment comment '主键ID'
+        primary key,
+    user_id    bigint unsigned      not null comment '用户ID',
+    secret     varchar(64)          not null comment 'TOTP密钥',
+    enabled    tinyint(1) default 0 not null comment '是否已启用',
+    enabled_at datetime(3)          null comment '启用时间',
+    created_at datetime(3)          not null comment '创建时间',
+    updated_at datetime(3)          not null comment '更新时间',
+    deleted_at bigint default 0     not null comment '删除标志',
+    constraint uni_sys_user_mfa_user_id
+        unique (user_id)
+)
+    comment '用户两步验证表';
+
+create index idx_sys_user_mfa_deleted_at
+    on sys_user_mfa (deleted_at);
+
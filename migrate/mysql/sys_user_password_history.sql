@@ -0,0 +1,17 @@
+create table sys_user_password_history
+(
+    id         bigint unsigned auto_increment comment '主键ID'
+        primary key,
+    user_id    bigint unsigned  not null comment '用户ID',
+    password   varchar(60)      not null comment '密码摘要',
+    created_at datetime(3)      not null comment '创建时间',
+    updated_at datetime(3)      not null comment '更新时间',
+    deleted_at bigint default 0 not null comment '删除标志'
+)
+    comment '用户历史密码表';
+
+create index idx_sys_user_password_history_deleted_at
+    on sys_user_password_history (deleted_at);
+
+create index idx_sys_user_password_history_user_id
+    on sys_user_password_history (user_id);
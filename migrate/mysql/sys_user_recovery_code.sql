@@ -0,0 +1,19 @@
+create table sys_user_recovery_code
+(
+    id         bigint unsigned auto_increment comment '主键ID'
+        primary key,
+    user_id    bigint unsigned  not null comment '用户ID',
+    code       varchar(64)      not null comment '恢复码摘要',
+    used_at    datetime(3)      null comment '使用时间',
+    created_at datetime(3)      not null comment '创建时间',
+    updated_at datetime(3)      not null comment '更新时间',
+    deleted_at bigint default 0 not null comment '删除标志'
+)
+    comment '用户两步验证恢复码表';
+
+create index idx_sys_user_recovery_code_deleted_at
+    on sys_user_recovery_code (deleted_at);
+
+create index idx_sys_user_recovery_code_user_id
+    on sys_user_recovery_code (user_id);
+
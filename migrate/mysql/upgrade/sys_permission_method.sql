@@ -0,0 +1,49 @@
+-- API权限按请求方法和gin的路由模板匹配,为已有的权限增加请求方法
+-- 已有的权限的请求方法默认为*(匹配任意方法),与升级前仅按路径匹配的行为一致;内置的接口权限改为路由注册的请求方法
+alter table sys_permission
+    add method varchar(10) default '*' not null comment 'API权限的请求方法,*表示任意方法' after type;
+
+alter table sys_permission
+    drop index uni_sys_permission_resource_deleted_at,
+    add constraint uni_sys_permission_resource_method_deleted_at
+        unique (resource, method, deleted_at);
+
+update sys_permission
+set method = 'GET'
+where type = 4
+  and resource in (
+                   '/api/v1/user/list',
+                   '/api/v1/permission/list',
+                   '/api/v1/permission/detail',
+                   '/api/v1/role/list',
+                   '/api/v1/role/detail',
+                   '/api/v1/user/export',
+                   '/api/v1/user/impersonations',
+                   '/api/v1/user/invitations',
+                   '/api/v1/user/login-histories'
+    );
+
+update sys_permission
+set method = 'POST'
+where type = 4
+  and resource in (
+                   '/api/v1/department/create',
+                   '/api/v1/permission/create',
+                   '/api/v1/permission/update',
+                   '/api/v1/permission/delete',
+                   '/api/v1/role/update',
+                   '/api/v1/role/delete',
+                   '/api/v1/role/create',
+                   '/api/v1/user/sessions/revoke-all',
+                   '/api/v1/user/login/unlock',
+                   '/api/v1/user/create',
+                   '/api/v1/user/status',
+                   '/api/v1/user/delete',
+                   '/api/v1/user/restore',
+                   '/api/v1/user/assign',
+                   '/api/v1/user/import',
+                   '/api/v1/user/impersonate',
+                   '/api/v1/user/invitations/create',
+                   '/api/v1/user/invitations/revoke',
+                   '/api/v1/user/erase'
+    );
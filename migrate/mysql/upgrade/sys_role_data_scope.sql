@@ -0,0 +1,18 @@
+-- 角色增加数据权限范围,已有的角色默认为全部数据,与升级前的行为一致
+alter table sys_role
+    add data_scope tinyint default 1 not null comment '数据权限范围:1全部,2本部门,3本部门及子部门,4自定义部门,5仅本人' after name;
+
+-- 数据权限范围为自定义部门时角色可见的部门
+create table sys_role_department
+(
+    role_id       bigint unsigned not null comment '角色ID',
+    department_id bigint unsigned not null comment '部门ID',
+    primary key (role_id, department_id)
+)
+    comment '角色-自定义数据权限部门中间表';
+
+create index idx_sys_role_department_department_id
+    on sys_role_department (department_id);
+
+create index idx_sys_role_department_role_id
+    on sys_role_department (role_id);
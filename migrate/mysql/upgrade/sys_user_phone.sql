@@ -0,0 +1,7 @@
+-- 用户增加绑定的手机号,用于短信验证码登录;已有用户未绑定手机号,唯一约束允许多个空值
+alter table sys_user
+    add phone varchar(20) null comment '手机号' after email;
+
+alter table sys_user
+    add constraint uni_sys_user_phone
+        unique (phone);
@@ -0,0 +1,18 @@
+package models
+
+// DataScope 调用者的数据权限范围,由调用者持有的角色的数据权限范围取并集得到;
+// All为true时不限制范围,否则可见属于DepartmentIDs中的部门的数据,Self为true时还可见属于本人的数据
+type DataScope struct {
+	UserID        uint
+	All           bool
+	Self          bool
+	DepartmentIDs []uint
+}
+
+// Limited 是否限制了数据范围,为nil时表示调用者不是登录用户(如命令行、定时任务),不限制范围
+func (d *DataScope) Limited() bool {
+	return d != nil && !d.All
+}
+
+// DataScopeLoader 按需解析调用者的数据权限范围,由登录中间件注入请求上下文,同一请求内只解析一次
+type DataScopeLoader func() (*DataScope, error)
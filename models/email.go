@@ -3,3 +3,45 @@ package models
 type SignUpVariable struct {
 	ActiveURL string `json:"active_url"`
 }
+
+type InvitationVariable struct {
+	Inviter   string `json:"inviter"`    // 邀请人
+	AcceptURL string `json:"accept_url"` // 接受邀请的地址
+	ExpiredAt string `json:"expired_at"` // 过期时间
+}
+
+type ChangeEmailVariable struct {
+	Email      string `json:"email"`       // 新邮箱
+	ConfirmURL string `json:"confirm_url"` // 确认修改的地址
+	Expiration int    `json:"expiration"`  // 有效时长(分钟)
+}
+
+type EmailChangedVariable struct {
+	Email      string `json:"email"`      // 新邮箱
+	RevertURL  string `json:"revert_url"` // 撤销修改的地址
+	Expiration int    `json:"expiration"` // 有效时长(小时)
+}
+
+type LoginAlertVariable struct {
+	Time      string `json:"time"`       // 登录时间
+	IP        string `json:"ip"`         // 登录IP
+	Device    string `json:"device"`     // 设备名称
+	UserAgent string `json:"user_agent"` // UA
+}
+
+type DataExportVariable struct {
+	DownloadURL string `json:"download_url"` // 下载地址
+	Expiration  int    `json:"expiration"`   // 有效时长(小时)
+}
+
+// EmailChange 修改邮箱的上下文,存储于redis,确认或撤销时校验并销毁
+type EmailChange struct {
+	UID      uint   `json:"uid"`       // 用户ID
+	OldEmail string `json:"old_email"` // 修改前的邮箱
+	NewEmail string `json:"new_email"` // 修改后的邮箱
+}
+
+type ResetPasswordVariable struct {
+	Code       string `json:"code"`
+	Expiration int    `json:"expiration"` // 有效时长(分钟)
+}
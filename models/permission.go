@@ -2,6 +2,7 @@ package models
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/supuwoerc/weaver/pkg/constant"
 	"github.com/supuwoerc/weaver/pkg/database"
@@ -13,6 +14,7 @@ type Permission struct {
 	Name      string                  `json:"name" gorm:"not null;"`
 	Resource  string                  `json:"resource" gorm:"not null;"`
 	Type      constant.PermissionType `json:"type" gorm:"not null;"`
+	Method    string                  `json:"method" gorm:"not null;default:*"` // API权限的请求方法,*表示任意方法
 	Roles     []*Role                 `json:"roles" gorm:"many2many:role_permission;"`
 	CreatorID uint                    `json:"-" gorm:"not null;"`
 	Creator   User                    `json:"creator" gorm:"foreignKey:CreatorID;references:ID"`
@@ -41,3 +43,142 @@ func (p *Permission) IsApiPermission() bool {
 func (p *Permission) IsViewPermission() bool {
 	return p.Type == constant.ViewRoute || p.Type == constant.ViewResource
 }
+
+// GetRoutePattern 获取API权限匹配的请求方法和路由模板
+func (p *Permission) GetRoutePattern() RoutePattern {
+	return RoutePattern{
+		Method:  p.Method,
+		Pattern: p.Resource,
+	}
+}
+
+// RoutePattern API权限匹配的请求方法和路由模板,模板中单独的*匹配一段路径,位于结尾时匹配剩余的一段或多段路径
+type RoutePattern struct {
+	Method  string `json:"method"`
+	Pattern string `json:"pattern"`
+}
+
+func (r RoutePattern) String() string {
+	return fmt.Sprintf("%s %s", r.Method, r.Pattern)
+}
+
+func splitRoute(route string) []string {
+	return strings.Split(strings.Trim(route, "/"), "/")
+}
+
+// Match 判断是否匹配请求的方法和gin的路由模板
+func (r RoutePattern) Match(method, route string) bool {
+	if r.Method != constant.AnyMethod && !strings.EqualFold(r.Method, method) {
+		return false
+	}
+	patternSegments := splitRoute(r.Pattern)
+	routeSegments := splitRoute(route)
+	for i, segment := range patternSegments {
+		if segment == "*" && i == len(patternSegments)-1 {
+			return len(routeSegments) > i
+		}
+		if i >= len(routeSegments) || (segment != "*" && segment != routeSegments[i]) {
+			return false
+		}
+	}
+	return len(patternSegments) == len(routeSegments)
+}
+
+// Specificity 具体程度,固定的路径段越多越具体,相同时不以*结尾的更具体,再相同时指定了请求方法的更具体
+func (r RoutePattern) Specificity() int {
+	segments := splitRoute(r.Pattern)
+	literals := lo.CountBy(segments, func(item string) bool {
+		return item != "*"
+	})
+	score := literals * 4
+	if segments[len(segments)-1] != "*" {
+		score += 2
+	}
+	if r.Method != constant.AnyMethod {
+		score++
+	}
+	return score
+}
+
+// GoverningRoutes 找出对请求生效的API权限:匹配请求的权限中具体程度最高的权限,没有匹配的权限时返回nil
+func GoverningRoutes(patterns []RoutePattern, method, route string) []RoutePattern {
+	var (
+		governing []RoutePattern
+		best      = -1
+	)
+	for _, item := range patterns {
+		if !item.Match(method, route) {
+			continue
+		}
+		score := item.Specificity()
+		switch {
+		case score > best:
+			best = score
+			governing = []RoutePattern{item}
+		case score == best:
+			governing = append(governing, item)
+		}
+	}
+	return governing
+}
+
+// RegisteredRoute gin中注册的需要权限控制的路由,Summary为swagger中该接口的摘要
+type RegisteredRoute struct {
+	Method  string `json:"method"`
+	Path    string `json:"path"`
+	Summary string `json:"summary"`
+}
+
+// PermissionSet 用户编译后的权限集合,非API权限按权限类型索引资源,API权限按请求方法和路由模板索引
+type PermissionSet struct {
+	Resources map[constant.PermissionType]map[string]struct{} `json:"resources"`
+	Routes    map[string]struct{}                             `json:"routes"`
+}
+
+// NewPermissionSet 将权限列表编译为权限集合
+func NewPermissionSet(permissions []*Permission) *PermissionSet {
+	set := &PermissionSet{
+		Resources: make(map[constant.PermissionType]map[string]struct{}),
+		Routes:    make(map[string]struct{}),
+	}
+	for _, item := range permissions {
+		if item.IsApiPermission() {
+			set.Routes[item.GetRoutePattern().String()] = struct{}{}
+			continue
+		}
+		if set.Resources[item.Type] == nil {
+			set.Resources[item.Type] = make(map[string]struct{})
+		}
+		set.Resources[item.Type][item.Resource] = struct{}{}
+	}
+	return set
+}
+
+// Has 判断权限集合是否包含指定类型的非API资源
+func (s *PermissionSet) Has(resource string, permissionType constant.PermissionType) bool {
+	_, ok := s.Resources[permissionType][resource]
+	return ok
+}
+
+// Contains 判断权限集合是否包含另一个权限集合的全部权限
+func (s *PermissionSet) Contains(other *PermissionSet) bool {
+	for permissionType, resources := range other.Resources {
+		for resource := range resources {
+			if !s.Has(resource, permissionType) {
+				return false
+			}
+		}
+	}
+	for route := range other.Routes {
+		if _, ok := s.Routes[route]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// HasRoute 判断权限集合是否包含指定的API权限
+func (s *PermissionSet) HasRoute(pattern RoutePattern) bool {
+	_, ok := s.Routes[pattern.String()]
+	return ok
+}
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/supuwoerc/weaver/pkg/constant"
 	"github.com/supuwoerc/weaver/pkg/database"
 )
 
@@ -35,3 +36,77 @@ func TestPermission_GetRoleIds(t *testing.T) {
 		assert.Equal(t, len(ids), len(p.Roles))
 	})
 }
+
+func TestRoutePattern_Match(t *testing.T) {
+	tests := []struct {
+		pattern RoutePattern
+		method  string
+		route   string
+		want    bool
+	}{
+		{RoutePattern{Method: "GET", Pattern: "/api/v1/role/detail"}, "GET", "/api/v1/role/detail", true},
+		{RoutePattern{Method: "GET", Pattern: "/api/v1/role/detail"}, "POST", "/api/v1/role/detail", false},
+		{RoutePattern{Method: "get", Pattern: "/api/v1/role/detail"}, "GET", "/api/v1/role/detail", true},
+		{RoutePattern{Method: "*", Pattern: "/api/v1/role/detail"}, "DELETE", "/api/v1/role/detail", true},
+		{RoutePattern{Method: "GET", Pattern: "/api/v1/role/:id"}, "GET", "/api/v1/role/:id", true},
+		{RoutePattern{Method: "GET", Pattern: "/api/v1/role/:id"}, "GET", "/api/v1/role/1", false},
+		{RoutePattern{Method: "*", Pattern: "/api/v1/role/*"}, "POST", "/api/v1/role/update", true},
+		{RoutePattern{Method: "*", Pattern: "/api/v1/role/*"}, "GET", "/api/v1/role/:id/users", true},
+		{RoutePattern{Method: "*", Pattern: "/api/v1/role/*"}, "GET", "/api/v1/role", false},
+		{RoutePattern{Method: "*", Pattern: "/api/v1/*/list"}, "GET", "/api/v1/user/list", true},
+		{RoutePattern{Method: "*", Pattern: "/api/v1/*/list"}, "GET", "/api/v1/user/list/all", false},
+		{RoutePattern{Method: "*", Pattern: "/api/v1/user"}, "GET", "/api/v1/user/list", false},
+		{RoutePattern{Method: "*", Pattern: "/*"}, "GET", "/api/v1/user/list", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern.String()+" "+tt.method+" "+tt.route, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.pattern.Match(tt.method, tt.route))
+		})
+	}
+}
+
+func TestGoverningRoutes(t *testing.T) {
+	prefix := RoutePattern{Method: "*", Pattern: "/api/v1/role/*"}
+	exact := RoutePattern{Method: "*", Pattern: "/api/v1/role/delete"}
+	exactPost := RoutePattern{Method: "POST", Pattern: "/api/v1/role/delete"}
+	middle := RoutePattern{Method: "*", Pattern: "/api/v1/*/delete"}
+	assert.Greater(t, exactPost.Specificity(), exact.Specificity())
+	assert.Greater(t, exact.Specificity(), middle.Specificity())
+	assert.Greater(t, middle.Specificity(), prefix.Specificity())
+
+	patterns := []RoutePattern{prefix, exact, middle}
+	assert.Equal(t, []RoutePattern{exact}, GoverningRoutes(patterns, "POST", "/api/v1/role/delete"))
+	assert.Equal(t, []RoutePattern{prefix}, GoverningRoutes(patterns, "GET", "/api/v1/role/list"))
+	assert.Equal(t, []RoutePattern{exactPost}, GoverningRoutes(append(patterns, exactPost), "POST", "/api/v1/role/delete"))
+	assert.Nil(t, GoverningRoutes(patterns, "GET", "/api/v1/user/list"))
+}
+
+func TestPermissionSet_Has(t *testing.T) {
+	set := NewPermissionSet([]*Permission{
+		{Resource: "/api/v1/user/list", Type: constant.ApiRoute, Method: "GET"},
+		{Resource: "/user", Type: constant.ViewRoute, Method: constant.AnyMethod},
+	})
+	assert.True(t, set.HasRoute(RoutePattern{Method: "GET", Pattern: "/api/v1/user/list"}))
+	assert.False(t, set.HasRoute(RoutePattern{Method: "*", Pattern: "/api/v1/user/list"}))
+	assert.True(t, set.Has("/user", constant.ViewRoute))
+	assert.False(t, set.Has("/user", constant.ViewMenu))
+	assert.False(t, set.Has("/api/v1/user/list", constant.ApiRoute))
+	assert.False(t, NewPermissionSet(nil).Has("/user", constant.ViewRoute))
+}
+
+func TestPermissionSet_Contains(t *testing.T) {
+	set := NewPermissionSet([]*Permission{
+		{Resource: "/api/v1/user/list", Type: constant.ApiRoute, Method: "GET"},
+		{Resource: "/user", Type: constant.ViewRoute, Method: constant.AnyMethod},
+	})
+	assert.True(t, set.Contains(NewPermissionSet(nil)))
+	assert.True(t, set.Contains(NewPermissionSet([]*Permission{
+		{Resource: "/user", Type: constant.ViewRoute, Method: constant.AnyMethod},
+	})))
+	assert.False(t, set.Contains(NewPermissionSet([]*Permission{
+		{Resource: "/api/v1/user/list", Type: constant.ApiRoute, Method: "POST"},
+	})))
+	assert.False(t, set.Contains(NewPermissionSet([]*Permission{
+		{Resource: "/user", Type: constant.ViewMenu, Method: constant.AnyMethod},
+	})))
+}
@@ -1,18 +1,43 @@
 package models
 
-import "github.com/supuwoerc/weaver/pkg/database"
+import (
+	"strconv"
+	"strings"
+
+	"github.com/supuwoerc/weaver/pkg/constant"
+	"github.com/supuwoerc/weaver/pkg/database"
+)
 
 type Role struct {
-	Name        string        `json:"name" gorm:"not null"`
-	Users       []*User       `json:"users" gorm:"many2many:user_role;"`
-	Permissions []*Permission `json:"permissions" gorm:"many2many:role_permission;"`
-	ParentID    *uint         `json:"parent_id"`
-	Parent      *Role         `json:"parent" gorm:"foreignKey:ParentID;references:ID"`
-	Children    []*Role       `json:"children" gorm:"foreignKey:ParentID"`
-	Ancestors   *string       `json:"ancestors"`
-	CreatorID   uint          `json:"-" gorm:"not null;"`
-	Creator     User          `json:"creator" gorm:"foreignKey:CreatorID;references:ID"`
-	UpdaterID   uint          `json:"-" gorm:"not null;"`
-	Updater     User          `json:"updater" gorm:"foreignKey:UpdaterID;references:ID"`
+	Name            string             `json:"name" gorm:"not null"`
+	Users           []*User            `json:"users" gorm:"many2many:user_role;"`
+	Permissions     []*Permission      `json:"permissions" gorm:"many2many:role_permission;"`
+	ParentID        *uint              `json:"parent_id"`
+	Parent          *Role              `json:"parent" gorm:"foreignKey:ParentID;references:ID"`
+	Children        []*Role            `json:"children" gorm:"foreignKey:ParentID"`
+	Ancestors       *string            `json:"ancestors"`
+	DataScope       constant.DataScope `json:"data_scope" gorm:"not null;default:1"`               // 数据权限范围
+	DataDepartments []*Department      `json:"data_departments" gorm:"many2many:role_department;"` // 自定义数据权限的部门
+	CreatorID       uint               `json:"-" gorm:"not null;"`
+	Creator         User               `json:"creator" gorm:"foreignKey:CreatorID;references:ID"`
+	UpdaterID       uint               `json:"-" gorm:"not null;"`
+	Updater         User               `json:"updater" gorm:"foreignKey:UpdaterID;references:ID"`
 	database.BasicModel
 }
+
+// GetAncestorIds 解析祖先角色路径,按从根角色到父角色的顺序返回
+func (r *Role) GetAncestorIds() []uint {
+	if r.Ancestors == nil || *r.Ancestors == "" {
+		return nil
+	}
+	parts := strings.Split(*r.Ancestors, ",")
+	ids := make([]uint, 0, len(parts))
+	for _, part := range parts {
+		id, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || id <= 0 {
+			continue
+		}
+		ids = append(ids, uint(id))
+	}
+	return ids
+}
@@ -0,0 +1,6 @@
+package models
+
+type RoleDepartment struct {
+	RoleID       uint
+	DepartmentID uint
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRole_GetAncestorIds(t *testing.T) {
+	t.Run("top level role", func(t *testing.T) {
+		assert.Nil(t, (&Role{}).GetAncestorIds())
+		assert.Nil(t, (&Role{Ancestors: lo.ToPtr("")}).GetAncestorIds())
+	})
+	t.Run("nested role", func(t *testing.T) {
+		role := &Role{Ancestors: lo.ToPtr("1,3,7")}
+		assert.Equal(t, []uint{1, 3, 7}, role.GetAncestorIds())
+	})
+	t.Run("invalid segments are ignored", func(t *testing.T) {
+		role := &Role{Ancestors: lo.ToPtr("1,,x,4")}
+		assert.Equal(t, []uint{1, 4}, role.GetAncestorIds())
+	})
+}
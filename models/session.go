@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// LoginClient 登录客户端信息
+type LoginClient struct {
+	Device    string `json:"device"`     // 设备名称
+	IP        string `json:"ip"`         // IP
+	UserAgent string `json:"user_agent"` // UA
+}
+
+// UserSession 用户登录会话,存储于redis,每次登录产生一个会话,刷新token时沿用
+type UserSession struct {
+	LoginClient
+	ID        string    `json:"id"`         // 会话ID
+	UID       uint      `json:"uid"`        // 用户ID
+	CreatedAt time.Time `json:"created_at"` // 登录时间
+	LastSeen  time.Time `json:"last_seen"`  // 最近活跃时间
+}
+
+// SSOState 单点登录授权请求的上下文,存储于redis,回调时校验并销毁
+type SSOState struct {
+	Provider string `json:"provider"` // 身份提供方标识
+	Nonce    string `json:"nonce"`    // id_token nonce
+	Verifier string `json:"verifier"` // PKCE code_verifier
+}
@@ -8,15 +8,25 @@ import (
 )
 
 type User struct {
-	Email       string               `json:"email" gorm:"unique;not null;"`
-	Password    string               `json:"-"`
-	Status      constant.UserStatus  `json:"status"`
-	Nickname    *string              `json:"nickname"`
-	Avatar      *string              `json:"avatar"`
-	Gender      *constant.UserGender `json:"gender"`
-	About       *string              `json:"about"`
-	Birthday    *time.Time           `json:"birthday"`
-	Roles       []*Role              `json:"roles" gorm:"many2many:user_role;"`
-	Departments []*Department        `json:"departments" gorm:"many2many:user_department;"`
+	Email             string               `json:"email" gorm:"unique;not null;"`
+	Phone             *string              `json:"phone" gorm:"unique"` // 手机号,绑定后可用于验证码登录
+	Password          string               `json:"-"`
+	PasswordChangedAt *time.Time           `json:"-"` // 密码修改时间,为空时以创建时间计算密码有效期
+	Status            constant.UserStatus  `json:"status"`
+	Nickname          *string              `json:"nickname"`
+	Avatar            *string              `json:"avatar"`
+	Gender            *constant.UserGender `json:"gender"`
+	About             *string              `json:"about"`
+	Birthday          *time.Time           `json:"birthday"`
+	Roles             []*Role              `json:"roles" gorm:"many2many:user_role;"`
+	Departments       []*Department        `json:"departments" gorm:"many2many:user_department;"`
 	database.BasicModel
 }
+
+// UserFilter 用户列表的查询条件,零值表示不过滤
+type UserFilter struct {
+	Keyword      string              // 昵称或邮箱关键字
+	Status       constant.UserStatus // 账户状态
+	RoleID       uint                // 角色ID
+	DepartmentID uint                // 部门ID
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/supuwoerc/weaver/pkg/database"
+
+	"github.com/samber/lo"
+)
+
+// UserAPIKey 用户的API密钥,供脚本等机器客户端调用接口,权限为用户权限与密钥权限范围的交集
+type UserAPIKey struct {
+	UserID      uint          `json:"-"`
+	User        User          `json:"-" gorm:"foreignKey:UserID;references:ID"`
+	Name        string        `json:"name"`
+	Prefix      string        `json:"prefix"`       // 密钥明文的前缀部分,便于用户识别
+	Key         string        `json:"-"`            // 密钥的sha256摘要
+	ExpiredAt   *time.Time    `json:"expired_at"`   // 过期时间,为空时永不过期
+	LastUsedAt  *time.Time    `json:"last_used_at"` // 最后使用时间
+	Permissions []*Permission `json:"permissions" gorm:"many2many:user_api_key_permission;"`
+	database.BasicModel
+}
+
+// IsExpired 判断密钥是否已过期
+func (k *UserAPIKey) IsExpired(now time.Time) bool {
+	return k.ExpiredAt != nil && !now.Before(*k.ExpiredAt)
+}
+
+// HasPermission 判断密钥的权限范围是否包含对请求生效的API权限,governing为 GoverningRoutes 找出的权限,
+// 与用户的API权限判断规则一致:更具体的权限生效时,范围更大的通配权限不再生效
+func (k *UserAPIKey) HasPermission(governing []RoutePattern) bool {
+	return lo.ContainsBy(k.Permissions, func(item *Permission) bool {
+		return item.IsApiPermission() && lo.Contains(governing, item.GetRoutePattern())
+	})
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supuwoerc/weaver/pkg/constant"
+)
+
+func TestUserAPIKey_IsExpired(t *testing.T) {
+	now := time.Now()
+	t.Run("never expires", func(t *testing.T) {
+		key := &UserAPIKey{}
+		assert.False(t, key.IsExpired(now))
+	})
+	t.Run("not expired", func(t *testing.T) {
+		expiredAt := now.Add(time.Hour)
+		key := &UserAPIKey{ExpiredAt: &expiredAt}
+		assert.False(t, key.IsExpired(now))
+	})
+	t.Run("expired", func(t *testing.T) {
+		key := &UserAPIKey{ExpiredAt: &now}
+		assert.True(t, key.IsExpired(now))
+	})
+}
+
+func TestUserAPIKey_HasPermission(t *testing.T) {
+	key := &UserAPIKey{
+		Permissions: []*Permission{
+			{Resource: "/api/v1/user/list", Type: constant.ApiRoute, Method: "GET"},
+			{Resource: "/api/v1/role/*", Type: constant.ApiRoute, Method: constant.AnyMethod},
+			{Resource: "/dashboard", Type: constant.ViewRoute, Method: constant.AnyMethod},
+		},
+	}
+	catalog := []RoutePattern{
+		{Method: "GET", Pattern: "/api/v1/user/list"},
+		{Method: "POST", Pattern: "/api/v1/user/list"},
+		{Method: constant.AnyMethod, Pattern: "/api/v1/role/*"},
+		{Method: "POST", Pattern: "/api/v1/role/delete"},
+		{Method: "GET", Pattern: "/api/v1/department/list"},
+	}
+	hasPermission := func(method, route string) bool {
+		return key.HasPermission(GoverningRoutes(catalog, method, route))
+	}
+	assert.True(t, hasPermission("GET", "/api/v1/user/list"))
+	assert.False(t, hasPermission("POST", "/api/v1/user/list"))
+	assert.True(t, hasPermission("GET", "/api/v1/role/list"))
+	// 更具体的权限生效,通配权限不再覆盖该路由
+	assert.False(t, hasPermission("POST", "/api/v1/role/delete"))
+	assert.False(t, hasPermission("GET", "/api/v1/department/list"))
+	assert.False(t, hasPermission("GET", "/dashboard"))
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"github.com/supuwoerc/weaver/pkg/constant"
+	"github.com/supuwoerc/weaver/pkg/database"
+)
+
+// DataExportProfile 导出的个人资料
+type DataExportProfile struct {
+	ID        uint                 `json:"id"`
+	Email     string               `json:"email"`
+	Phone     *string              `json:"phone"`
+	Nickname  *string              `json:"nickname"`
+	Avatar    *string              `json:"avatar"`
+	Gender    *constant.UserGender `json:"gender"`
+	About     *string              `json:"about"`
+	Birthday  *time.Time           `json:"birthday"`
+	Status    constant.UserStatus  `json:"status"`
+	CreatedAt database.UpsertTime  `json:"created_at"`
+}
+
+// DataExportRelation 导出的角色或部门
+type DataExportRelation struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+// DataExportAttachment 导出的附件信息,不包含文件内容
+type DataExportAttachment struct {
+	ID        uint                `json:"id"`
+	Name      string              `json:"name"`
+	Type      int8                `json:"type"`
+	Size      int64               `json:"size"`
+	Hash      string              `json:"hash"`
+	CreatedAt database.UpsertTime `json:"created_at"`
+}
@@ -0,0 +1,12 @@
+package models
+
+import "github.com/supuwoerc/weaver/pkg/database"
+
+// UserIdentity 用户关联的外部账户(单点登录)
+type UserIdentity struct {
+	UserID   uint   `json:"user_id"`
+	Provider string `json:"provider"` // 身份提供方标识
+	Subject  string `json:"subject"`  // 外部账户在身份提供方的唯一标识
+	Email    string `json:"email"`    // 外部账户邮箱
+	database.BasicModel
+}
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"github.com/supuwoerc/weaver/pkg/database"
+)
+
+// UserImpersonation 模拟登录记录,记录操作人以其他用户身份登录的开始和结束
+type UserImpersonation struct {
+	ActorID   uint       `json:"actor_id"`
+	Actor     User       `json:"actor" gorm:"foreignKey:ActorID;references:ID"`
+	UserID    uint       `json:"user_id"`
+	User      User       `json:"user" gorm:"foreignKey:UserID;references:ID"`
+	TokenID   string     `json:"-"`          // 模拟登录token的唯一标识(jti)
+	Reason    string     `json:"reason"`     // 模拟登录的原因
+	IP        string     `json:"ip"`         // 操作人的IP
+	ExpiredAt time.Time  `json:"expired_at"` // token的过期时间
+	StoppedAt *time.Time `json:"stopped_at"` // 主动结束的时间,为空时直到token过期
+	database.BasicModel
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"github.com/supuwoerc/weaver/pkg/database"
+)
+
+// UserInvitation 注册邀请,接受邀请后创建已激活的用户并关联预设的角色和部门
+type UserInvitation struct {
+	Email       string        `json:"email"`
+	Code        string        `json:"-"` // 邀请码的sha256摘要
+	InviterID   uint          `json:"-"`
+	Inviter     User          `json:"inviter" gorm:"foreignKey:InviterID;references:ID"`
+	Roles       []*Role       `json:"roles" gorm:"many2many:user_invitation_role;"`
+	Departments []*Department `json:"departments" gorm:"many2many:user_invitation_department;"`
+	ExpiredAt   time.Time     `json:"expired_at"`  // 过期时间
+	AcceptedAt  *time.Time    `json:"accepted_at"` // 接受时间,为空时未接受
+	UserID      *uint         `json:"user_id"`     // 接受邀请后创建的用户ID
+	database.BasicModel
+}
+
+// IsValid 判断邀请是否未接受且未过期
+func (i *UserInvitation) IsValid(now time.Time) bool {
+	return i.AcceptedAt == nil && now.Before(i.ExpiredAt)
+}
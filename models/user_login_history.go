@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"github.com/supuwoerc/weaver/pkg/constant"
+	"github.com/supuwoerc/weaver/pkg/database"
+)
+
+// UserLoginHistory 登录记录,记录每次登录尝试(包括失败的尝试)和token刷新
+type UserLoginHistory struct {
+	UserID    *uint              `json:"user_id"`    // 用户ID,邮箱未注册时为空
+	Email     string             `json:"email"`      // 登录邮箱
+	Type      constant.LoginType `json:"type"`       // 登录方式
+	Success   bool               `json:"success"`    // 是否成功
+	Reason    string             `json:"reason"`     // 失败原因
+	MFA       bool               `json:"mfa"`        // 是否经过两步验证
+	IP        string             `json:"ip"`         // IP
+	UserAgent string             `json:"user_agent"` // UA
+	Device    string             `json:"device"`     // 设备名称
+	database.BasicModel
+}
+
+// LoginHistoryFilter 查询登录记录的条件
+type LoginHistoryFilter struct {
+	UserID  uint
+	Email   string
+	Type    constant.LoginType
+	Success *bool
+	IP      string
+	Start   *time.Time
+	End     *time.Time
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"github.com/supuwoerc/weaver/pkg/database"
+)
+
+// UserMFA 用户的两步验证(TOTP)信息
+type UserMFA struct {
+	UserID    uint       `json:"user_id" gorm:"uniqueIndex"`
+	Secret    string     `json:"-"`          // TOTP密钥(base32)
+	Enabled   bool       `json:"enabled"`    // 是否已启用,完成首次验证前为false
+	EnabledAt *time.Time `json:"enabled_at"` // 启用时间
+	database.BasicModel
+}
+
+// UserRecoveryCode 两步验证的恢复码,每个恢复码只能使用一次
+type UserRecoveryCode struct {
+	UserID uint       `json:"user_id"`
+	Code   string     `json:"-"`       // 恢复码的sha256摘要
+	UsedAt *time.Time `json:"used_at"` // 使用时间
+	database.BasicModel
+}
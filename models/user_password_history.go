@@ -0,0 +1,10 @@
+package models
+
+import "github.com/supuwoerc/weaver/pkg/database"
+
+// UserPasswordHistory 用户使用过的密码摘要,用于禁止重复使用最近的密码
+type UserPasswordHistory struct {
+	UserID   uint   `json:"user_id"`
+	Password string `json:"-"`
+	database.BasicModel
+}
@@ -0,0 +1,6 @@
+package models
+
+type UserRole struct {
+	RoleID uint
+	UserID uint
+}
@@ -1,6 +1,26 @@
 package captcha
 
-import "github.com/mojocn/base64Captcha"
+import (
+	"errors"
+
+	"github.com/supuwoerc/weaver/conf"
+	"github.com/supuwoerc/weaver/pkg/constant"
+
+	"github.com/mojocn/base64Captcha"
+	"github.com/samber/lo"
+)
+
+var ErrUnsupportedDriver = errors.New("unsupported captcha driver")
+
+// 未配置时使用的默认参数
+const (
+	defaultHeight        = 80
+	defaultWidth         = 240
+	defaultLength        = 6
+	defaultChineseLength = 4
+	defaultLanguage      = "en"
+	chineseFont          = "wqy-microhei.ttc" // 内置字体中唯一包含汉字的字体
+)
 
 type CommonCaptchaInfo struct {
 	ID     string
@@ -9,17 +29,61 @@ type CommonCaptchaInfo struct {
 }
 
 type Captcha struct {
+	driver        constant.CaptchaDriver
 	captchaClient *base64Captcha.Captcha
 }
 
-func NewCaptcha(height int, width int, length int, maxSkew float64, dotCount int, store base64Captcha.Store) *Captcha {
+// NewCaptcha 按配置创建验证码生成器,未配置生成方式时使用数字图片
+func NewCaptcha(config *conf.CaptchaDriverConfig, store base64Captcha.Store) (*Captcha, error) {
+	driverType := config.Driver
+	if driverType == "" {
+		driverType = constant.DigitCaptcha
+	}
+	driver, err := newDriver(driverType, config)
+	if err != nil {
+		return nil, err
+	}
 	return &Captcha{
-		captchaClient: base64Captcha.NewCaptcha(base64Captcha.NewDriverDigit(height, width, length, maxSkew, dotCount),
-			store,
-		),
+		driver:        driverType,
+		captchaClient: base64Captcha.NewCaptcha(driver, store),
+	}, nil
+}
+
+func newDriver(driverType constant.CaptchaDriver, config *conf.CaptchaDriverConfig) (base64Captcha.Driver, error) {
+	height := lo.CoalesceOrEmpty(config.Height, defaultHeight)
+	width := lo.CoalesceOrEmpty(config.Width, defaultWidth)
+	switch driverType {
+	case constant.DigitCaptcha:
+		return base64Captcha.NewDriverDigit(height, width, lo.CoalesceOrEmpty(config.Length, defaultLength),
+			config.MaxSkew, config.DotCount), nil
+	case constant.StringCaptcha:
+		source := lo.CoalesceOrEmpty(config.Source, base64Captcha.TxtSimpleCharaters)
+		return base64Captcha.NewDriverString(height, width, config.NoiseCount, config.ShowLineOptions,
+			lo.CoalesceOrEmpty(config.Length, defaultLength), source, nil, nil, config.Fonts), nil
+	case constant.MathCaptcha:
+		return base64Captcha.NewDriverMath(height, width, config.NoiseCount, config.ShowLineOptions,
+			nil, nil, config.Fonts), nil
+	case constant.AudioCaptcha:
+		return base64Captcha.NewDriverAudio(lo.CoalesceOrEmpty(config.Length, defaultLength),
+			lo.CoalesceOrEmpty(config.Language, defaultLanguage)), nil
+	case constant.ChineseCaptcha:
+		source := lo.CoalesceOrEmpty(config.Source, base64Captcha.TxtChineseCharaters)
+		fonts := config.Fonts
+		if len(fonts) == 0 {
+			fonts = []string{chineseFont}
+		}
+		return base64Captcha.NewDriverChinese(height, width, config.NoiseCount, config.ShowLineOptions,
+			lo.CoalesceOrEmpty(config.Length, defaultChineseLength), source, nil, nil, fonts), nil
+	default:
+		return nil, ErrUnsupportedDriver
 	}
 }
 
+// Driver 验证码的生成方式,客户端据此决定展示图片还是播放语音
+func (c *Captcha) Driver() constant.CaptchaDriver {
+	return c.driver
+}
+
 func (c *Captcha) Generate() (*CommonCaptchaInfo, error) {
 	id, b64s, answer, err := c.captchaClient.Generate()
 	if err != nil {
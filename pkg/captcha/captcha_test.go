@@ -0,0 +1,49 @@
+package captcha
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/supuwoerc/weaver/conf"
+	"github.com/supuwoerc/weaver/pkg/constant"
+
+	"github.com/mojocn/base64Captcha"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCaptcha(t *testing.T) {
+	testCases := []struct {
+		name   string
+		config conf.CaptchaDriverConfig
+		driver constant.CaptchaDriver
+		prefix string
+	}{
+		{"default digit", conf.CaptchaDriverConfig{}, constant.DigitCaptcha, "data:image/png"},
+		{"string", conf.CaptchaDriverConfig{Driver: constant.StringCaptcha, NoiseCount: 5}, constant.StringCaptcha, "data:image/png"},
+		{"math", conf.CaptchaDriverConfig{Driver: constant.MathCaptcha, ShowLineOptions: 2}, constant.MathCaptcha, "data:image/png"},
+		{"audio", conf.CaptchaDriverConfig{Driver: constant.AudioCaptcha, Language: "zh"}, constant.AudioCaptcha, "data:audio/wav"},
+		{"chinese", conf.CaptchaDriverConfig{Driver: constant.ChineseCaptcha}, constant.ChineseCaptcha, "data:image/png"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			store := base64Captcha.NewMemoryStore(10, time.Minute)
+			client, err := NewCaptcha(&tc.config, store)
+			require.NoError(t, err)
+			assert.Equal(t, tc.driver, client.Driver())
+			info, err := client.Generate()
+			require.NoError(t, err)
+			assert.True(t, strings.HasPrefix(info.Base64, tc.prefix))
+			assert.NotEmpty(t, info.Answer)
+			assert.True(t, client.Verify(info.ID, info.Answer))
+			// 校验后验证码即失效
+			assert.False(t, client.Verify(info.ID, info.Answer))
+		})
+	}
+
+	t.Run("unsupported driver", func(t *testing.T) {
+		_, err := NewCaptcha(&conf.CaptchaDriverConfig{Driver: "slider"}, base64Captcha.DefaultMemStore)
+		assert.ErrorIs(t, err, ErrUnsupportedDriver)
+	})
+}
@@ -3,6 +3,7 @@ package captcha
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/supuwoerc/weaver/conf"
@@ -47,7 +48,8 @@ func (r *RedisStore) Get(id string, clear bool) string {
 	return result
 }
 
+// Verify 校验验证码,字母不区分大小写,验证码不存在或已过期时校验失败
 func (r *RedisStore) Verify(id, answer string, clear bool) bool {
 	result := r.Get(id, clear)
-	return result == answer
+	return result != "" && strings.EqualFold(result, answer)
 }
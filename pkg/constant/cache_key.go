@@ -7,4 +7,8 @@ const (
 	DepartmentTreeWithCrewSfgKey CacheKey = "department_cache:tree_with_crew"
 	DepartmentTreeRefreshSfgKey  CacheKey = "department_cache:tree:refresh"
 	DepartmentTreeCleanSfgKey    CacheKey = "department_cache:tree:clean"
+	PermissionSetVersionKey      CacheKey = "permission_cache:version"    // 用户权限缓存的全局版本号
+	PermissionInvalidateChannel  CacheKey = "permission_cache:invalidate" // 用户权限缓存失效的广播频道
+	PermissionRouteCatalogKey    CacheKey = "permission_cache:routes"     // 全部API权限的请求方法和路由模板
+	LoginHistoryQueueKey         CacheKey = "login_history:queue"         // 待写入的登录记录
 )
@@ -3,6 +3,19 @@ package constant
 type CaptchaType string
 
 const (
-	Default CaptchaType = "defaultCaptcha"
-	SignUp  CaptchaType = "signupCaptcha"
+	Default       CaptchaType = "defaultCaptcha"
+	SignUp        CaptchaType = "signupCaptcha"
+	ResetPassword CaptchaType = "resetPasswordCaptcha"
+	Login         CaptchaType = "loginCaptcha"
+)
+
+// CaptchaDriver 验证码的生成方式
+type CaptchaDriver string
+
+const (
+	DigitCaptcha   CaptchaDriver = "digit"   // 数字图片
+	StringCaptcha  CaptchaDriver = "string"  // 字母数字图片
+	MathCaptcha    CaptchaDriver = "math"    // 算术题图片
+	AudioCaptcha   CaptchaDriver = "audio"   // 数字语音
+	ChineseCaptcha CaptchaDriver = "chinese" // 汉字图片
 )
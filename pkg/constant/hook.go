@@ -1,5 +1,7 @@
 package constant
 
 const (
-	AutoManageDeptCache = "autoManageDeptCache" // 管理部门缓存
+	AutoManageDeptCache       = "autoManageDeptCache"       // 管理部门缓存
+	AutoManagePermissionCache = "autoManagePermissionCache" // 管理用户权限缓存
+	AutoSyncRoutePermissions  = "autoSyncRoutePermissions"  // 启动时同步路由的API权限
 )
@@ -4,7 +4,9 @@ package constant
 type JobName string
 
 const (
-	ServerStatus JobName = "serverStatus"
+	ServerStatus      JobName = "serverStatus"
+	JWTKeyRotation    JobName = "jwtKeyRotation"
+	LoginHistoryFlush JobName = "loginHistoryFlush"
 )
 
 // JobStillMode 上一个定时任务还在执行中,当前任务的模式
@@ -11,4 +11,5 @@ const (
 	VideoMP4        MIME = "video/mp4"
 	ApplicationJSON MIME = "application/json"
 	ApplicationPDF  MIME = "application/pdf"
+	ApplicationZIP  MIME = "application/zip"
 )
@@ -9,3 +9,15 @@ const (
 	ViewResource                           // viewResource
 	ApiRoute                               // apiRoute
 )
+
+// AnyMethod API权限匹配任意请求方法,非API权限的请求方法固定为该值
+const AnyMethod = "*"
+
+// RoleInheritance 角色继承方向,决定用户持有角色时还拥有哪些角色的权限
+type RoleInheritance string
+
+const (
+	InheritDescendants RoleInheritance = "descendants" // 同时拥有后代角色的权限
+	InheritAncestors   RoleInheritance = "ancestors"   // 同时拥有祖先角色的权限
+	InheritNone        RoleInheritance = "none"        // 仅拥有直接持有的角色的权限
+)
@@ -3,18 +3,41 @@ package constant
 type Prefix string
 
 const (
-	PermissionIdPrefix       Prefix = "lock:permission:id"
-	PermissionNamePrefix     Prefix = "lock:permission:name"
-	PermissionResourcePrefix Prefix = "lock:permission:resource"
-	RoleIdPrefix             Prefix = "lock:role:id"
-	RoleNamePrefix           Prefix = "lock:role:name"
-	SignUpEmailPrefix        Prefix = "lock:signup:email"
-	DepartmentIdPrefix       Prefix = "lock:department:id"
-	DepartmentNamePrefix     Prefix = "lock:department:name"
-	UserIdPrefix             Prefix = "lock:user:id"
+	PermissionIdPrefix        Prefix = "lock:permission:id"
+	PermissionNamePrefix      Prefix = "lock:permission:name"
+	PermissionResourcePrefix  Prefix = "lock:permission:resource"
+	PermissionRouteSyncPrefix Prefix = "lock:permission:route_sync"
+	RoleIdPrefix              Prefix = "lock:role:id"
+	RoleNamePrefix            Prefix = "lock:role:name"
+	SignUpEmailPrefix         Prefix = "lock:signup:email"
+	DepartmentIdPrefix        Prefix = "lock:department:id"
+	DepartmentNamePrefix      Prefix = "lock:department:name"
+	UserIdPrefix              Prefix = "lock:user:id"
+	PhonePrefix               Prefix = "lock:phone"
 )
 
 const (
-	CaptchaCodePrefix   Prefix = "captcha:"
-	ActiveAccountPrefix Prefix = "active:account:"
+	CaptchaCodePrefix         Prefix = "captcha:"
+	ActiveAccountPrefix       Prefix = "active:account:"
+	ResetPasswordPrefix       Prefix = "reset:password:"
+	ForgotPasswordLimitPrefix Prefix = "limit:forgot_password:"
+	ResetPasswordLimitPrefix  Prefix = "limit:reset_password:"
+	MFATicketPrefix           Prefix = "mfa:ticket:"
+	MFAUsedStepPrefix         Prefix = "mfa:step:"
+	PasswordTicketPrefix      Prefix = "password:ticket:"
+	SSOStatePrefix            Prefix = "sso:state:"
+	ChangeEmailPrefix         Prefix = "change:email:"
+	RevertEmailPrefix         Prefix = "revert:email:"
+	LoginFailEmailPrefix      Prefix = "limit:login_fail:email:"
+	LoginFailIPPrefix         Prefix = "limit:login_fail:ip:"
+	LoginDelayPrefix          Prefix = "login:delay:"
+	LoginLockEmailPrefix      Prefix = "login:lock:email:"
+	LoginLockIPPrefix         Prefix = "login:lock:ip:"
+	SMSCodePrefix             Prefix = "sms:code:"
+	SMSCooldownPrefix         Prefix = "sms:cooldown:"
+	SMSLimitPrefix            Prefix = "limit:sms:"
+	DataExportPrefix          Prefix = "data:export:"
+	DataExportLimitPrefix     Prefix = "limit:data_export:"
+	PermissionSetPrefix       Prefix = "permission:set:"
+	PermissionVersionPrefix   Prefix = "permission:version:"
 )
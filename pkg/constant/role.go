@@ -0,0 +1,14 @@
+package constant
+
+// DataScope 角色的数据权限范围,用户持有多个角色时取并集
+//
+//go:generate stringer -type=DataScope -linecomment -output data_scope_string.go
+type DataScope int8
+
+const (
+	DataScopeAll            DataScope = iota + 1 // all
+	DataScopeDepartment                          // department
+	DataScopeDepartmentTree                      // departmentTree
+	DataScopeCustom                              // custom
+	DataScopeSelf                                // self
+)
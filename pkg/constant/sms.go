@@ -0,0 +1,9 @@
+package constant
+
+// SMSScene 短信验证码的使用场景,不同场景的验证码互不通用
+type SMSScene string
+
+const (
+	SMSBindPhone SMSScene = "bind"  // 绑定手机号
+	SMSLogin     SMSScene = "login" // 手机号验证码登录
+)
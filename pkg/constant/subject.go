@@ -7,4 +7,10 @@ const (
 	CronRecover     Subject = "Cron Recover"
 	Recover         Subject = "Recover"
 	ServiceRegister Subject = "Service Register"
+	PasswordReset   Subject = "Password Reset"
+	Invitation      Subject = "Invitation"
+	ChangeEmail     Subject = "Confirm Email Change"
+	EmailChanged    Subject = "Email Changed"
+	LoginAlert      Subject = "New Sign-in"
+	DataExport      Subject = "Your Data Export"
 )
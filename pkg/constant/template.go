@@ -3,5 +3,11 @@ package constant
 type Template string
 
 const (
-	SignupTemplate Template = "register-activate-account.html"
+	SignupTemplate        Template = "register-activate-account.html"
+	ResetPasswordTemplate Template = "reset-password.html"
+	InvitationTemplate    Template = "invitation.html"
+	ChangeEmailTemplate   Template = "change-email.html"
+	EmailChangedTemplate  Template = "email-changed.html"
+	LoginAlertTemplate    Template = "login-alert.html"
+	DataExportTemplate    Template = "data-export.html"
 )
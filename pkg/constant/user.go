@@ -2,14 +2,59 @@ package constant
 
 const (
 	EmailRegexPattern   = `^[a-zA-Z0-9_-]+@[a-zA-Z0-9_-]+(\.[a-zA-Z0-9_-]+)+$`
-	PasswdRegexPattern  = `^[a-fA-F0-9]{32}$`
 	PhoneRegexPattern   = `^(13[0-9]|14[01456879]|15[0-35-9]|16[2567]|17[0-8]|18[0-9]|19[0-35-9])\d{8}$`
 	ClaimsContextKey    = "gin_context_claims"
-	UserRefreshTokenKey = "user:refresh_token"
+	APIKeyContextKey    = "gin_context_api_key"
+	DataScopeContextKey = "gin_context_data_scope"
+	UserSessionKey      = "user:session"       // 登录会话
+	UserSessionsKey     = "user:sessions"      // 用户的登录会话集合
+	UserAccessTokensKey = "user:access_tokens" // 用户已签发且未过期的短token
+	TokenDenylistKey    = "token:denylist"     // 已吊销的短token
+	JWTSigningKeysKey   = "jwt:signing_keys"   // 非对称签名密钥
+	SSOStateCookie      = "sso_state"          // 发起单点登录的浏览器持有的state
 )
 
 const (
-	UserActiveCodeLength = 16 // 账户激活码长度
+	UserActiveCodeLength    = 16  // 账户激活码长度
+	ResetPasswordCodeLength = 6   // 重置密码验证码长度
+	MFATicketLength         = 32  // 两步验证登录凭证长度
+	MFASkew                 = 1   // TOTP验证码允许的时钟偏差(周期数)
+	MFAQRCodeSize           = 256 // TOTP二维码尺寸
+	RecoveryCodeCount       = 10  // 两步验证恢复码数量
+	RecoveryCodeLength      = 10  // 两步验证恢复码长度
+	InvitationCodeLength    = 32  // 邀请码长度
+	EmailChangeCodeLength   = 32  // 修改邮箱确认码长度
+	SSOStateLength          = 32  // 单点登录授权state长度
+	NicknameMaxLength       = 20  // 昵称最大长度
+	APIKeyLength            = 40  // API密钥长度(不含前缀)
+	APIKeyDisplayLength     = 6   // API密钥用于识别的明文长度(不含前缀)
+	SMSCodeLength           = 6   // 短信验证码默认长度
+	SMSDailyWindow          = 24  // 短信发送次数的统计窗口(小时)
+	DataExportCodeLength    = 32  // 个人数据下载码长度
+	LoginHistoryBatchSize   = 100 // 每次从队列取出的登录记录数量
+)
+
+// ErasedEmailFormat 注销后的账户使用的占位邮箱,保留用户ID以满足邮箱的唯一约束
+const ErasedEmailFormat = "erased-%d@erased.invalid"
+
+// SignupMode 注册方式
+type SignupMode string
+
+const (
+	SignupOpen     SignupMode = "open"     // 开放注册
+	SignupInvite   SignupMode = "invite"   // 仅允许通过邀请注册
+	SignupDisabled SignupMode = "disabled" // 关闭注册,只能由管理员创建用户
+)
+
+// LoginType 登录记录的登录方式
+type LoginType string
+
+const (
+	LoginPassword LoginType = "password" // 账号密码登录
+	LoginMFA      LoginType = "mfa"      // 两步验证登录
+	LoginSSO      LoginType = "sso"      // 单点登录
+	LoginSMS      LoginType = "sms"      // 手机验证码登录
+	LoginRefresh  LoginType = "refresh"  // 刷新token
 )
 
 //go:generate stringer -type=UserStatus -linecomment -output user_status_string.go
@@ -19,6 +64,7 @@ const (
 	Inactive UserStatus = iota + 1 // inactive
 	Normal                         // normal
 	Disabled                       // disabled
+	Erased                         // erased
 )
 
 //go:generate stringer -type=UserGender -linecomment -output user_gender_string.go
@@ -11,11 +11,12 @@ func _() {
 	_ = x[Inactive-1]
 	_ = x[Normal-2]
 	_ = x[Disabled-3]
+	_ = x[Erased-4]
 }
 
-const _UserStatus_name = "inactivenormaldisabled"
+const _UserStatus_name = "inactivenormaldisablederased"
 
-var _UserStatus_index = [...]uint8{0, 8, 14, 22}
+var _UserStatus_index = [...]uint8{0, 8, 14, 22, 28}
 
 func (i UserStatus) String() string {
 	i -= 1
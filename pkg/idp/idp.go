@@ -0,0 +1,110 @@
+package idp
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/supuwoerc/weaver/conf"
+	"github.com/supuwoerc/weaver/pkg/response"
+)
+
+const (
+	TypeOIDC = "oidc"
+)
+
+// Identity 身份提供方返回的外部账户信息
+type Identity struct {
+	Subject       string // 外部账户在身份提供方的唯一标识
+	Email         string // 邮箱
+	EmailVerified bool   // 邮箱是否已被身份提供方验证
+	Name          string // 名称
+}
+
+// AuthRequest 发起授权时生成的参数,需要在回调时原样使用
+type AuthRequest struct {
+	State    string // 防止CSRF的随机值
+	Nonce    string // 防止id_token重放的随机值
+	Verifier string // PKCE code_verifier
+}
+
+// Provider 身份提供方
+type Provider interface {
+	// Config 身份提供方的配置
+	Config() *conf.IdentityProviderConfig
+	// AuthCodeURL 生成跳转到身份提供方的授权地址
+	AuthCodeURL(req *AuthRequest) string
+	// Exchange 使用授权码换取外部账户信息
+	Exchange(ctx context.Context, code string, req *AuthRequest) (*Identity, error)
+}
+
+// Factory 根据配置创建身份提供方
+type Factory func(ctx context.Context, config *conf.IdentityProviderConfig) (Provider, error)
+
+// Manager 管理配置中的全部身份提供方,身份提供方在首次使用时初始化,初始化失败时下次使用会重试
+type Manager struct {
+	configs   map[string]*conf.IdentityProviderConfig
+	factories map[string]Factory
+	providers map[string]Provider
+	mu        sync.Mutex
+}
+
+func NewManager(config *conf.Config) *Manager {
+	configs := make(map[string]*conf.IdentityProviderConfig, len(config.SSO.Providers))
+	for i := range config.SSO.Providers {
+		item := &config.SSO.Providers[i]
+		configs[item.Name] = item
+	}
+	return &Manager{
+		configs: configs,
+		factories: map[string]Factory{
+			TypeOIDC: NewOIDCProvider,
+		},
+		providers: make(map[string]Provider),
+	}
+}
+
+// RegisterFactory 注册新的身份提供方类型
+func (m *Manager) RegisterFactory(typ string, factory Factory) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.factories[typ] = factory
+}
+
+// Names 获取全部身份提供方的标识
+func (m *Manager) Names() []string {
+	names := make([]string, 0, len(m.configs))
+	for _, item := range m.configs {
+		names = append(names, item.Name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Get 获取身份提供方,未配置时返回 response.IdentityProviderNotExist
+func (m *Manager) Get(ctx context.Context, name string) (Provider, error) {
+	config, ok := m.configs[name]
+	if !ok {
+		return nil, response.IdentityProviderNotExist
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if provider, exist := m.providers[name]; exist {
+		return provider, nil
+	}
+	typ := config.Type
+	if typ == "" {
+		typ = TypeOIDC
+	}
+	factory, ok := m.factories[typ]
+	if !ok {
+		return nil, fmt.Errorf("unsupported identity provider type %s", typ)
+	}
+	provider, err := factory(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	m.providers[name] = provider
+	return provider, nil
+}
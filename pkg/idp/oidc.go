@@ -0,0 +1,79 @@
+package idp
+
+import (
+	"context"
+	"errors"
+
+	"github.com/supuwoerc/weaver/conf"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/samber/lo"
+	"golang.org/x/oauth2"
+)
+
+// OIDCProvider 基于 OpenID Connect 授权码 + PKCE 流程的身份提供方
+type OIDCProvider struct {
+	config   *conf.IdentityProviderConfig
+	oauth2   *oauth2.Config
+	verifier *oidc.IDTokenVerifier
+}
+
+// NewOIDCProvider 通过 issuer 的服务发现创建身份提供方
+func NewOIDCProvider(ctx context.Context, config *conf.IdentityProviderConfig) (Provider, error) {
+	provider, err := oidc.NewProvider(ctx, config.Issuer)
+	if err != nil {
+		return nil, err
+	}
+	scopes := lo.Uniq(append([]string{oidc.ScopeOpenID}, config.Scopes...))
+	return &OIDCProvider{
+		config: config,
+		oauth2: &oauth2.Config{
+			ClientID:     config.ClientID,
+			ClientSecret: config.ClientSecret,
+			Endpoint:     provider.Endpoint(),
+			RedirectURL:  config.RedirectURL,
+			Scopes:       scopes,
+		},
+		verifier: provider.Verifier(&oidc.Config{ClientID: config.ClientID}),
+	}, nil
+}
+
+func (o *OIDCProvider) Config() *conf.IdentityProviderConfig {
+	return o.config
+}
+
+func (o *OIDCProvider) AuthCodeURL(req *AuthRequest) string {
+	return o.oauth2.AuthCodeURL(req.State, oidc.Nonce(req.Nonce), oauth2.S256ChallengeOption(req.Verifier))
+}
+
+func (o *OIDCProvider) Exchange(ctx context.Context, code string, req *AuthRequest) (*Identity, error) {
+	token, err := o.oauth2.Exchange(ctx, code, oauth2.VerifierOption(req.Verifier))
+	if err != nil {
+		return nil, err
+	}
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok || rawIDToken == "" {
+		return nil, errors.New("missing id_token in token response")
+	}
+	idToken, err := o.verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, err
+	}
+	if idToken.Nonce != req.Nonce {
+		return nil, errors.New("id_token nonce mismatch")
+	}
+	var claims struct {
+		Email         string `json:"email"`
+		EmailVerified bool   `json:"email_verified"`
+		Name          string `json:"name"`
+	}
+	if err = idToken.Claims(&claims); err != nil {
+		return nil, err
+	}
+	return &Identity{
+		Subject:       idToken.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified,
+		Name:          claims.Name,
+	}, nil
+}
@@ -0,0 +1,231 @@
+package idp
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/supuwoerc/weaver/conf"
+	"github.com/supuwoerc/weaver/pkg/response"
+)
+
+const (
+	mockClientID     = "weaver"
+	mockClientSecret = "secret"
+	mockKeyID        = "mock-key"
+)
+
+// mockAuthorization 授权请求中携带的参数
+type mockAuthorization struct {
+	challenge string
+	nonce     string
+}
+
+// mockOIDCServer 本地模拟的OIDC身份提供方
+type mockOIDCServer struct {
+	*httptest.Server
+	key            *rsa.PrivateKey
+	mu             sync.Mutex
+	authorizations map[string]*mockAuthorization
+	claims         jwt.MapClaims
+}
+
+func newMockOIDCServer(t *testing.T) *mockOIDCServer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	m := &mockOIDCServer{
+		key:            key,
+		authorizations: make(map[string]*mockAuthorization),
+		claims: jwt.MapClaims{
+			"sub":            "external-1",
+			"email":          "sso@example.com",
+			"email_verified": true,
+			"name":           "sso user",
+		},
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		m.writeJSON(w, map[string]any{
+			"issuer":                                m.URL,
+			"authorization_endpoint":                m.URL + "/authorize",
+			"token_endpoint":                        m.URL + "/token",
+			"jwks_uri":                              m.URL + "/jwks",
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		m.writeJSON(w, map[string]any{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"alg": "RS256",
+				"use": "sig",
+				"kid": mockKeyID,
+				"n":   base64.RawURLEncoding.EncodeToString(m.key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(m.key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", m.token)
+	m.Server = httptest.NewServer(mux)
+	t.Cleanup(m.Close)
+	return m
+}
+
+func (m *mockOIDCServer) writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// authorize 模拟用户在身份提供方完成登录,返回授权码
+func (m *mockOIDCServer) authorize(t *testing.T, authURL string) (code string, state string) {
+	parsed, err := url.Parse(authURL)
+	require.NoError(t, err)
+	query := parsed.Query()
+	require.Equal(t, "S256", query.Get("code_challenge_method"))
+	require.Equal(t, mockClientID, query.Get("client_id"))
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	code = "code-" + query.Get("state")
+	m.authorizations[code] = &mockAuthorization{
+		challenge: query.Get("code_challenge"),
+		nonce:     query.Get("nonce"),
+	}
+	return code, query.Get("state")
+}
+
+func (m *mockOIDCServer) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok {
+		clientID, clientSecret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+	m.mu.Lock()
+	authorization, exist := m.authorizations[r.PostForm.Get("code")]
+	delete(m.authorizations, r.PostForm.Get("code"))
+	m.mu.Unlock()
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if clientID != mockClientID || clientSecret != mockClientSecret || !exist ||
+		base64.RawURLEncoding.EncodeToString(sum[:]) != authorization.challenge {
+		w.WriteHeader(http.StatusBadRequest)
+		m.writeJSON(w, map[string]string{"error": "invalid_grant"})
+		return
+	}
+	claims := jwt.MapClaims{
+		"iss":   m.URL,
+		"aud":   mockClientID,
+		"iat":   time.Now().Unix(),
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"nonce": authorization.nonce,
+	}
+	for k, v := range m.claims {
+		claims[k] = v
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = mockKeyID
+	idToken, err := token.SignedString(m.key)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	m.writeJSON(w, map[string]any{
+		"access_token": "access-token",
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     idToken,
+	})
+}
+
+func newMockProviderConfig(server *mockOIDCServer) *conf.Config {
+	return &conf.Config{
+		SSO: conf.SSOConfig{
+			Providers: []conf.IdentityProviderConfig{{
+				Name:         "mock",
+				Type:         TypeOIDC,
+				Issuer:       server.URL,
+				ClientID:     mockClientID,
+				ClientSecret: mockClientSecret,
+				RedirectURL:  "http://localhost/callback",
+				Scopes:       []string{"email", "profile"},
+			}},
+		},
+	}
+}
+
+func TestOIDCProvider_Exchange(t *testing.T) {
+	ctx := context.Background()
+	server := newMockOIDCServer(t)
+	manager := NewManager(newMockProviderConfig(server))
+	provider, err := manager.Get(ctx, "mock")
+	require.NoError(t, err)
+
+	t.Run("authorization code with pkce", func(t *testing.T) {
+		req := &AuthRequest{State: "state-1", Nonce: "nonce-1", Verifier: "verifier-1-aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"}
+		code, state := server.authorize(t, provider.AuthCodeURL(req))
+		assert.Equal(t, req.State, state)
+		identity, err := provider.Exchange(ctx, code, req)
+		require.NoError(t, err)
+		assert.Equal(t, "external-1", identity.Subject)
+		assert.Equal(t, "sso@example.com", identity.Email)
+		assert.True(t, identity.EmailVerified)
+		assert.Equal(t, "sso user", identity.Name)
+	})
+
+	t.Run("wrong code verifier", func(t *testing.T) {
+		req := &AuthRequest{State: "state-2", Nonce: "nonce-2", Verifier: "verifier-2-aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"}
+		code, _ := server.authorize(t, provider.AuthCodeURL(req))
+		_, err := provider.Exchange(ctx, code, &AuthRequest{Nonce: req.Nonce, Verifier: "other-verifier"})
+		assert.Error(t, err)
+	})
+
+	t.Run("nonce mismatch", func(t *testing.T) {
+		req := &AuthRequest{State: "state-3", Nonce: "nonce-3", Verifier: "verifier-3-aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"}
+		code, _ := server.authorize(t, provider.AuthCodeURL(req))
+		_, err := provider.Exchange(ctx, code, &AuthRequest{Nonce: "other-nonce", Verifier: req.Verifier})
+		assert.Error(t, err)
+	})
+
+	t.Run("code can only be used once", func(t *testing.T) {
+		req := &AuthRequest{State: "state-4", Nonce: "nonce-4", Verifier: "verifier-4-aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"}
+		code, _ := server.authorize(t, provider.AuthCodeURL(req))
+		_, err := provider.Exchange(ctx, code, req)
+		require.NoError(t, err)
+		_, err = provider.Exchange(ctx, code, req)
+		assert.Error(t, err)
+	})
+}
+
+func TestManager_Get(t *testing.T) {
+	ctx := context.Background()
+	server := newMockOIDCServer(t)
+	config := newMockProviderConfig(server)
+	config.SSO.Providers = append(config.SSO.Providers, conf.IdentityProviderConfig{Name: "unknown", Type: "saml"})
+	manager := NewManager(config)
+	assert.Equal(t, []string{"mock", "unknown"}, manager.Names())
+
+	provider, err := manager.Get(ctx, "mock")
+	require.NoError(t, err)
+	cached, err := manager.Get(ctx, "mock")
+	require.NoError(t, err)
+	assert.Same(t, provider, cached)
+
+	_, err = manager.Get(ctx, "missing")
+	assert.ErrorIs(t, err, response.IdentityProviderNotExist)
+	_, err = manager.Get(ctx, "unknown")
+	assert.Error(t, err)
+}
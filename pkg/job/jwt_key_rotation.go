@@ -0,0 +1,41 @@
+package job
+
+import (
+	"context"
+
+	"github.com/supuwoerc/weaver/pkg/constant"
+	"github.com/supuwoerc/weaver/pkg/logger"
+)
+
+type KeyRotator interface {
+	RotateKeys(ctx context.Context) error
+}
+
+// JWTKeyRotation 定时轮换token签名密钥并清理过期密钥
+type JWTKeyRotation struct {
+	rotator KeyRotator
+	logger  *logger.Logger
+}
+
+func NewJWTKeyRotation(rotator KeyRotator, logger *logger.Logger) *JWTKeyRotation {
+	return &JWTKeyRotation{
+		rotator: rotator,
+		logger:  logger,
+	}
+}
+
+func (j *JWTKeyRotation) Name() string {
+	return string(constant.JWTKeyRotation)
+}
+func (j *JWTKeyRotation) IfStillRunning() constant.JobStillMode {
+	return constant.Skip
+}
+func (j *JWTKeyRotation) Interval() string {
+	return "0 * * * * *"
+}
+
+func (j *JWTKeyRotation) Handle() {
+	if err := j.rotator.RotateKeys(context.Background()); err != nil {
+		j.logger.Errorw("rotate jwt signing keys fail", "err", err.Error())
+	}
+}
@@ -0,0 +1,41 @@
+package job
+
+import (
+	"context"
+
+	"github.com/supuwoerc/weaver/pkg/constant"
+	"github.com/supuwoerc/weaver/pkg/logger"
+)
+
+type LoginHistoryFlusher interface {
+	FlushLoginHistories(ctx context.Context) error
+}
+
+// LoginHistoryFlush 定时写入队列中的登录记录并发送登录提醒邮件
+type LoginHistoryFlush struct {
+	flusher LoginHistoryFlusher
+	logger  *logger.Logger
+}
+
+func NewLoginHistoryFlush(flusher LoginHistoryFlusher, logger *logger.Logger) *LoginHistoryFlush {
+	return &LoginHistoryFlush{
+		flusher: flusher,
+		logger:  logger,
+	}
+}
+
+func (j *LoginHistoryFlush) Name() string {
+	return string(constant.LoginHistoryFlush)
+}
+func (j *LoginHistoryFlush) IfStillRunning() constant.JobStillMode {
+	return constant.Skip
+}
+func (j *LoginHistoryFlush) Interval() string {
+	return "*/10 * * * * *"
+}
+
+func (j *LoginHistoryFlush) Handle() {
+	if err := j.flusher.FlushLoginHistories(context.Background()); err != nil {
+		j.logger.Errorw("flush login histories fail", "err", err.Error())
+	}
+}
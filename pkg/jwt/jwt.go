@@ -2,6 +2,7 @@ package jwt
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/supuwoerc/weaver/conf"
@@ -9,6 +10,7 @@ import (
 	"github.com/supuwoerc/weaver/pkg/response"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
@@ -20,50 +22,148 @@ type TokenClaimsBasic struct {
 
 type TokenClaims struct {
 	jwt.RegisteredClaims
-	User *TokenClaimsBasic
+	User    *TokenClaimsBasic
+	Session string `json:"sid,omitempty"`     // 登录会话ID,同一会话签发的长短token共用
+	Refresh bool   `json:"refresh,omitempty"` // 是否为长token
+	APIKey  uint   `json:"-"`                 // 通过api key认证时为密钥ID,此时没有登录会话
+	// Actor 模拟登录时的真实操作人,此时User为被模拟的用户
+	Actor *TokenClaimsBasic `json:"act,omitempty"`
 }
 
-type TokenBuilderRepo interface {
-	GetRefreshToken(ctx context.Context, email string) (string, error)
+// Operator 实际发起请求的用户,模拟登录时为真实操作人
+func (c *TokenClaims) Operator() *TokenClaimsBasic {
+	if c.Actor != nil {
+		return c.Actor
+	}
+	return c.User
+}
+
+// TokenPair 长短token
+type TokenPair struct {
+	AccessToken     string    // 短token
+	AccessID        string    // 短token的唯一标识(jti)
+	AccessExpiresAt time.Time // 短token的过期时间
+	RefreshToken    string    // 长token
+	RefreshID       string    // 长token的唯一标识(jti)
+	Session         string    // 登录会话ID,每次登录生成一个新的会话,刷新时沿用
 }
+
 type TokenBuilder struct {
 	db          *gorm.DB
 	redisClient *redis.CommonRedisClient
 	conf        *conf.Config
-	repo        TokenBuilderRepo
+	keys        *keySet
 }
 
-func NewJwtBuilder(db *gorm.DB, r *redis.CommonRedisClient, conf *conf.Config, repo TokenBuilderRepo) *TokenBuilder {
+func NewJwtBuilder(db *gorm.DB, r *redis.CommonRedisClient, conf *conf.Config) *TokenBuilder {
 	return &TokenBuilder{
 		db:          db,
 		redisClient: r,
 		conf:        conf,
-		repo:        repo,
+		keys:        newKeySet(r, conf),
 	}
 }
 
-// generateToken 生成token
-func (j *TokenBuilder) generateToken(user *TokenClaimsBasic, createAt time.Time, duration time.Duration) (string, error) {
-	claims := TokenClaims{
-		RegisteredClaims: jwt.RegisteredClaims{
-			Issuer:    j.conf.JWT.Issuer,
-			IssuedAt:  jwt.NewNumericDate(createAt),
-			ExpiresAt: jwt.NewNumericDate(createAt.Add(duration)),
-		},
-		User: user,
+// asymmetric 是否使用非对称算法签名
+func (j *TokenBuilder) asymmetric() bool {
+	return j.conf.JWT.Algorithm == AlgorithmRS256 || j.conf.JWT.Algorithm == AlgorithmES256
+}
+
+// generateToken 生成token,非对称算法在header中携带kid
+func (j *TokenBuilder) generateToken(claims TokenClaims, createAt time.Time, duration time.Duration) (string, error) {
+	claims.Issuer = j.conf.JWT.Issuer
+	claims.IssuedAt = jwt.NewNumericDate(createAt)
+	claims.ExpiresAt = jwt.NewNumericDate(createAt.Add(duration))
+	if !j.asymmetric() {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		return token.SignedString([]byte(j.conf.JWT.Secret))
+	}
+	key, err := j.keys.current(context.Background())
+	if err != nil {
+		return "", err
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(j.conf.JWT.Secret))
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(key.Algorithm), claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.signer)
+}
+
+// keyFunc 获取校验token签名的密钥,非对称算法根据header中的kid查找公钥
+func (j *TokenBuilder) keyFunc(token *jwt.Token) (any, error) {
+	if !j.asymmetric() {
+		return []byte(j.conf.JWT.Secret), nil
+	}
+	kid, _ := token.Header["kid"].(string)
+	key, err := j.keys.get(context.Background(), kid)
+	if err != nil {
+		return nil, err
+	}
+	if token.Method.Alg() != key.Algorithm {
+		return nil, fmt.Errorf("unexpected signing method %s for key %s", token.Method.Alg(), kid)
+	}
+	return key.signer.Public(), nil
+}
+
+// validMethods 允许的签名算法,避免算法混淆
+func (j *TokenBuilder) validMethods() []string {
+	if !j.asymmetric() {
+		return []string{AlgorithmHS256}
+	}
+	return []string{AlgorithmRS256, AlgorithmES256}
+}
+
+// RotateKeys 轮换非对称签名密钥,使用HS256时不做任何处理
+func (j *TokenBuilder) RotateKeys(ctx context.Context) error {
+	if !j.asymmetric() {
+		return nil
+	}
+	return j.keys.rotate(ctx, time.Now())
+}
+
+// JWKS 获取校验token的公钥集合,使用HS256时为空
+func (j *TokenBuilder) JWKS(ctx context.Context) (*JWKS, error) {
+	if !j.asymmetric() {
+		return &JWKS{Keys: []JWK{}}, nil
+	}
+	return j.keys.jwks(ctx)
 }
 
 // GenerateAccessToken 生成短token
-func (j *TokenBuilder) GenerateAccessToken(user *TokenClaimsBasic, createAt time.Time) (string, error) {
-	return j.generateToken(user, createAt, j.getAccessTokenExpiration())
+func (j *TokenBuilder) GenerateAccessToken(user *TokenClaimsBasic, id, session string, createAt time.Time) (string, error) {
+	claims := TokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{ID: id},
+		User:             user,
+		Session:          session,
+	}
+	return j.generateToken(claims, createAt, j.getAccessTokenExpiration())
+}
+
+// GenerateImpersonationToken 生成模拟登录的短token,沿用操作人的登录会话且不签发长token
+func (j *TokenBuilder) GenerateImpersonationToken(
+	user, actor *TokenClaimsBasic, id, session string, createAt time.Time,
+) (string, time.Time, error) {
+	claims := TokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{ID: id},
+		User:             user,
+		Session:          session,
+		Actor:            actor,
+	}
+	duration := j.conf.JWT.ImpersonationExpires * time.Minute
+	token, err := j.generateToken(claims, createAt, duration)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return token, createAt.Add(duration), nil
 }
 
 // generateRefreshToken 生成长token
-func (j *TokenBuilder) generateRefreshToken(createAt time.Time) (string, error) {
-	return j.generateToken(&TokenClaimsBasic{}, createAt, j.GetRefreshTokenExpiration())
+func (j *TokenBuilder) generateRefreshToken(user *TokenClaimsBasic, id, session string, createAt time.Time) (string, error) {
+	claims := TokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{ID: id},
+		User:             user,
+		Session:          session,
+		Refresh:          true,
+	}
+	return j.generateToken(claims, createAt, j.GetRefreshTokenExpiration())
 }
 
 func (j *TokenBuilder) getAccessTokenExpiration() time.Duration {
@@ -74,33 +174,57 @@ func (j *TokenBuilder) GetRefreshTokenExpiration() time.Duration {
 	return j.conf.JWT.RefreshTokenExpires * time.Minute
 }
 
-// GenerateAccessAndRefreshToken 生成长短token
-func (j *TokenBuilder) GenerateAccessAndRefreshToken(user *TokenClaimsBasic) (string, string, error) {
+// GenerateAccessAndRefreshToken 生成长短token,session为空时生成新的会话ID
+func (j *TokenBuilder) GenerateAccessAndRefreshToken(user *TokenClaimsBasic, session string) (*TokenPair, error) {
 	createAt := time.Now()
-	newAccessToken, err := j.GenerateAccessToken(user, createAt)
+	if session == "" {
+		session = uuid.NewString()
+	}
+	accessID := uuid.NewString()
+	newAccessToken, err := j.GenerateAccessToken(user, accessID, session, createAt)
 	if err != nil {
-		return "", "", err
+		return nil, err
 	}
-	newRefreshToken, err := j.generateRefreshToken(createAt)
+	refreshID := uuid.NewString()
+	newRefreshToken, err := j.generateRefreshToken(user, refreshID, session, createAt)
 	if err != nil {
-		return "", "", err
+		return nil, err
 	}
-	return newAccessToken, newRefreshToken, nil
+	return &TokenPair{
+		AccessToken:     newAccessToken,
+		AccessID:        accessID,
+		AccessExpiresAt: createAt.Add(j.getAccessTokenExpiration()),
+		RefreshToken:    newRefreshToken,
+		RefreshID:       refreshID,
+		Session:         session,
+	}, nil
 }
 
 // ParseToken 解析token
 func (j *TokenBuilder) ParseToken(tokenString string) (*TokenClaims, error) {
 	var claims TokenClaims
-	token, err := jwt.ParseWithClaims(tokenString, &claims, func(token *jwt.Token) (interface{}, error) {
-		return []byte(j.conf.JWT.Secret), nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, &claims, j.keyFunc,
+		jwt.WithValidMethods(j.validMethods()), jwt.WithExpirationRequired())
 	if err != nil || !token.Valid {
 		return &claims, response.InvalidToken
 	}
 	return &claims, nil
 }
 
-// GetRefreshToken 获取缓存的RefreshToken
-func (j *TokenBuilder) GetRefreshToken(ctx context.Context, email string) (string, error) {
-	return j.repo.GetRefreshToken(ctx, email)
+// ParseAccessToken 解析短token,长token或缺少会话ID、jti的token视为无效
+func (j *TokenBuilder) ParseAccessToken(tokenString string) (*TokenClaims, error) {
+	claims, err := j.ParseToken(tokenString)
+	if err != nil || claims.Refresh || claims.Session == "" || claims.ID == "" || claims.User == nil {
+		return nil, response.InvalidToken
+	}
+	return claims, nil
+}
+
+// ParseRefreshToken 解析长token,短token或缺少会话ID、jti的token视为无效
+func (j *TokenBuilder) ParseRefreshToken(tokenString string) (*TokenClaims, error) {
+	claims, err := j.ParseToken(tokenString)
+	if err != nil || !claims.Refresh || claims.Session == "" || claims.ID == "" || claims.User == nil || claims.User.ID == 0 {
+		return nil, response.InvalidRefreshToken
+	}
+	return claims, nil
 }
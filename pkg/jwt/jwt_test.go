@@ -0,0 +1,136 @@
+package jwt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/supuwoerc/weaver/conf"
+	"github.com/supuwoerc/weaver/pkg/response"
+)
+
+func newTestTokenBuilder() *TokenBuilder {
+	return NewJwtBuilder(nil, nil, &conf.Config{
+		JWT: conf.JWTConfig{
+			Expires:              15,
+			RefreshTokenExpires:  60,
+			Secret:               "secret",
+			Issuer:               "weaver",
+			ImpersonationExpires: 30,
+		},
+	})
+}
+
+func TestTokenBuilder_GenerateAccessAndRefreshToken(t *testing.T) {
+	builder := newTestTokenBuilder()
+	user := &TokenClaimsBasic{ID: 1, Email: "test@example.com"}
+
+	t.Run("new session", func(t *testing.T) {
+		pair, err := builder.GenerateAccessAndRefreshToken(user, "")
+		require.NoError(t, err)
+		assert.NotEmpty(t, pair.Session)
+		assert.NotEmpty(t, pair.RefreshID)
+		accessClaims, err := builder.ParseAccessToken(pair.AccessToken)
+		require.NoError(t, err)
+		assert.Equal(t, pair.Session, accessClaims.Session)
+		assert.False(t, accessClaims.Refresh)
+		assert.Equal(t, user.ID, accessClaims.User.ID)
+		refreshClaims, err := builder.ParseRefreshToken(pair.RefreshToken)
+		require.NoError(t, err)
+		assert.Equal(t, pair.Session, refreshClaims.Session)
+		assert.Equal(t, pair.RefreshID, refreshClaims.ID)
+		assert.Equal(t, user.Email, refreshClaims.User.Email)
+	})
+
+	t.Run("keep session", func(t *testing.T) {
+		pair, err := builder.GenerateAccessAndRefreshToken(user, "session")
+		require.NoError(t, err)
+		assert.Equal(t, "session", pair.Session)
+		next, err := builder.GenerateAccessAndRefreshToken(user, pair.Session)
+		require.NoError(t, err)
+		assert.Equal(t, pair.Session, next.Session)
+		assert.NotEqual(t, pair.RefreshID, next.RefreshID)
+	})
+}
+
+func TestTokenBuilder_ParseRefreshToken(t *testing.T) {
+	builder := newTestTokenBuilder()
+	user := &TokenClaimsBasic{ID: 1, Email: "test@example.com"}
+
+	t.Run("access token is not a refresh token", func(t *testing.T) {
+		accessToken, err := builder.GenerateAccessToken(user, "id", "session", time.Now())
+		require.NoError(t, err)
+		_, err = builder.ParseRefreshToken(accessToken)
+		assert.ErrorIs(t, err, response.InvalidRefreshToken)
+	})
+
+	t.Run("expired refresh token", func(t *testing.T) {
+		refreshToken, err := builder.generateRefreshToken(user, "id", "session", time.Now().Add(-2*time.Hour))
+		require.NoError(t, err)
+		_, err = builder.ParseRefreshToken(refreshToken)
+		assert.ErrorIs(t, err, response.InvalidRefreshToken)
+	})
+
+	t.Run("invalid signature", func(t *testing.T) {
+		other := NewJwtBuilder(nil, nil, &conf.Config{JWT: conf.JWTConfig{RefreshTokenExpires: 60, Secret: "other"}})
+		pair, err := other.GenerateAccessAndRefreshToken(user, "")
+		require.NoError(t, err)
+		_, err = builder.ParseRefreshToken(pair.RefreshToken)
+		assert.ErrorIs(t, err, response.InvalidRefreshToken)
+	})
+}
+
+func TestTokenBuilder_ParseAccessToken(t *testing.T) {
+	builder := newTestTokenBuilder()
+	user := &TokenClaimsBasic{ID: 1, Email: "test@example.com"}
+
+	t.Run("refresh token is not an access token", func(t *testing.T) {
+		pair, err := builder.GenerateAccessAndRefreshToken(user, "")
+		require.NoError(t, err)
+		_, err = builder.ParseAccessToken(pair.RefreshToken)
+		assert.ErrorIs(t, err, response.InvalidToken)
+	})
+
+	t.Run("access token without session", func(t *testing.T) {
+		accessToken, err := builder.GenerateAccessToken(user, "id", "", time.Now())
+		require.NoError(t, err)
+		_, err = builder.ParseAccessToken(accessToken)
+		assert.ErrorIs(t, err, response.InvalidToken)
+	})
+
+	t.Run("access token without jti", func(t *testing.T) {
+		accessToken, err := builder.GenerateAccessToken(user, "", "session", time.Now())
+		require.NoError(t, err)
+		_, err = builder.ParseAccessToken(accessToken)
+		assert.ErrorIs(t, err, response.InvalidToken)
+	})
+
+	t.Run("access token carries jti", func(t *testing.T) {
+		pair, err := builder.GenerateAccessAndRefreshToken(user, "")
+		require.NoError(t, err)
+		claims, err := builder.ParseAccessToken(pair.AccessToken)
+		require.NoError(t, err)
+		assert.Equal(t, pair.AccessID, claims.ID)
+		assert.WithinDuration(t, pair.AccessExpiresAt, claims.ExpiresAt.Time, time.Second)
+	})
+}
+
+func TestTokenBuilder_GenerateImpersonationToken(t *testing.T) {
+	builder := newTestTokenBuilder()
+	user := &TokenClaimsBasic{ID: 2, Email: "user@example.com"}
+	actor := &TokenClaimsBasic{ID: 1, Email: "admin@example.com"}
+	createAt := time.Now()
+	token, expiresAt, err := builder.GenerateImpersonationToken(user, actor, "id", "session", createAt)
+	require.NoError(t, err)
+	assert.WithinDuration(t, createAt.Add(30*time.Minute), expiresAt, time.Second)
+	claims, err := builder.ParseAccessToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, claims.User.ID)
+	require.NotNil(t, claims.Actor)
+	assert.Equal(t, actor.ID, claims.Actor.ID)
+	assert.Equal(t, actor.ID, claims.Operator().ID)
+	assert.Equal(t, "session", claims.Session)
+	_, err = builder.ParseRefreshToken(token)
+	assert.ErrorIs(t, err, response.InvalidRefreshToken)
+}
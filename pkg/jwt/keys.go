@@ -0,0 +1,385 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/supuwoerc/weaver/conf"
+	"github.com/supuwoerc/weaver/pkg/constant"
+	"github.com/supuwoerc/weaver/pkg/redis"
+)
+
+// 签名算法
+const (
+	AlgorithmHS256 = "HS256" // 对称签名,使用 JWT.Secret
+	AlgorithmRS256 = "RS256"
+	AlgorithmES256 = "ES256"
+)
+
+const (
+	rsaKeyBits         = 2048
+	defaultKeyRotation = 7 * 24 * time.Hour
+	keyReloadInterval  = 10 * time.Second // 遇到未知kid时重新加载密钥的最小间隔
+)
+
+// keyEncryptionInfo 由 JWT.Secret 派生私钥加密密钥时使用的info
+const keyEncryptionInfo = "weaver jwt signing key"
+
+var (
+	errKeyNotFound         = errors.New("signing key not found")
+	errKeyEncryptionSecret = errors.New("jwt secret is required to encrypt signing keys")
+)
+
+// signingKey 非对称签名密钥,私钥以PKCS8 PEM格式经 JWT.Secret 派生的密钥加密(AES-GCM)后存储于redis,供所有实例共享
+type signingKey struct {
+	ID           string    `json:"kid"`
+	Algorithm    string    `json:"alg"`
+	EncryptedKey string    `json:"encrypted_key"`
+	CreatedAt    time.Time `json:"created_at"`
+	signer       crypto.Signer
+}
+
+// JWK 公钥信息,用于其他服务校验token
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// JWKS 公钥集合
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// keySet 非对称签名的密钥集合,按创建时间升序排列,最后一个为当前的签名密钥;
+// 密钥按轮换周期生成,被替换的密钥在其签发的token全部过期前仍可用于校验
+type keySet struct {
+	mu          sync.RWMutex
+	keys        []*signingKey
+	loadedAt    time.Time
+	redisClient *redis.CommonRedisClient
+	conf        *conf.Config
+}
+
+func newKeySet(r *redis.CommonRedisClient, conf *conf.Config) *keySet {
+	return &keySet{
+		redisClient: r,
+		conf:        conf,
+	}
+}
+
+func (k *keySet) algorithm() string {
+	return k.conf.JWT.Algorithm
+}
+
+func (k *keySet) rotation() time.Duration {
+	if k.conf.JWT.KeyRotation <= 0 {
+		return defaultKeyRotation
+	}
+	return k.conf.JWT.KeyRotation * time.Minute
+}
+
+// maxTokenLifetime 签发的token的最长有效期,密钥被替换后需要保留到此时长之后
+func (k *keySet) maxTokenLifetime() time.Duration {
+	return max(k.conf.JWT.Expires, k.conf.JWT.RefreshTokenExpires) * time.Minute
+}
+
+// encryptionKey 由 JWT.Secret 派生的私钥加密密钥,redis中只保存加密后的私钥
+func (k *keySet) encryptionKey() ([]byte, error) {
+	if k.conf.JWT.Secret == "" {
+		return nil, errKeyEncryptionSecret
+	}
+	return hkdf.Key(sha256.New, []byte(k.conf.JWT.Secret), nil, keyEncryptionInfo, 32)
+}
+
+// load 从redis加载全部密钥
+func (k *keySet) load(ctx context.Context) error {
+	values, err := k.redisClient.Client.HGetAll(ctx, constant.JWTSigningKeysKey).Result()
+	if err != nil {
+		return err
+	}
+	kek, err := k.encryptionKey()
+	if err != nil {
+		return err
+	}
+	keys := make([]*signingKey, 0, len(values))
+	for _, value := range values {
+		var key signingKey
+		if err = json.Unmarshal([]byte(value), &key); err != nil {
+			return err
+		}
+		if key.signer, err = decryptPrivateKey(kek, key.ID, key.EncryptedKey); err != nil {
+			return fmt.Errorf("parse signing key %s: %w", key.ID, err)
+		}
+		keys = append(keys, &key)
+	}
+	slices.SortFunc(keys, func(a, b *signingKey) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.keys = keys
+	k.loadedAt = time.Now()
+	return nil
+}
+
+// rotate 当前轮换周期内没有密钥时生成新密钥,并删除已无有效token的旧密钥;
+// kid由算法和轮换周期的起始时间组成,多个实例同时轮换时只有一个实例的密钥会被保存
+func (k *keySet) rotate(ctx context.Context, now time.Time) error {
+	if err := k.load(ctx); err != nil {
+		return err
+	}
+	kid := fmt.Sprintf("%s-%d", k.algorithm(), now.Truncate(k.rotation()).Unix())
+	if _, exists := k.lookup(kid); !exists {
+		kek, err := k.encryptionKey()
+		if err != nil {
+			return err
+		}
+		key, err := generateKey(kid, k.algorithm(), now, kek)
+		if err != nil {
+			return err
+		}
+		value, err := json.Marshal(key)
+		if err != nil {
+			return err
+		}
+		if err = k.redisClient.Client.HSetNX(ctx, constant.JWTSigningKeysKey, kid, value).Err(); err != nil {
+			return err
+		}
+		// 其他实例可能同时生成了相同kid的密钥,重新加载以保存成功的密钥为准
+		if err = k.load(ctx); err != nil {
+			return err
+		}
+	}
+	return k.prune(ctx, now)
+}
+
+// prune 删除已被替换且替换后超过token最长有效期的密钥
+func (k *keySet) prune(ctx context.Context, now time.Time) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	var expired []string
+	for len(k.keys) > 1 && k.keys[1].CreatedAt.Add(k.maxTokenLifetime()).Before(now) {
+		expired = append(expired, k.keys[0].ID)
+		k.keys = k.keys[1:]
+	}
+	if len(expired) == 0 {
+		return nil
+	}
+	return k.redisClient.Client.HDel(ctx, constant.JWTSigningKeysKey, expired...).Err()
+}
+
+func (k *keySet) lookup(kid string) (*signingKey, bool) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	for _, key := range k.keys {
+		if key.ID == kid {
+			return key, true
+		}
+	}
+	return nil, false
+}
+
+// current 获取当前的签名密钥,尚未生成或算法已变更时立即轮换
+func (k *keySet) current(ctx context.Context) (*signingKey, error) {
+	k.mu.RLock()
+	var key *signingKey
+	if len(k.keys) > 0 {
+		key = k.keys[len(k.keys)-1]
+	}
+	k.mu.RUnlock()
+	if key != nil && key.Algorithm == k.algorithm() {
+		return key, nil
+	}
+	if err := k.rotate(ctx, time.Now()); err != nil {
+		return nil, err
+	}
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	if len(k.keys) == 0 {
+		return nil, errKeyNotFound
+	}
+	return k.keys[len(k.keys)-1], nil
+}
+
+// get 根据kid获取密钥,本地不存在时从redis重新加载(其他实例可能已轮换出新密钥)
+func (k *keySet) get(ctx context.Context, kid string) (*signingKey, error) {
+	if key, exists := k.lookup(kid); exists {
+		return key, nil
+	}
+	k.mu.RLock()
+	stale := time.Since(k.loadedAt) > keyReloadInterval
+	k.mu.RUnlock()
+	if stale {
+		if err := k.load(ctx); err != nil {
+			return nil, err
+		}
+		if key, exists := k.lookup(kid); exists {
+			return key, nil
+		}
+	}
+	return nil, errKeyNotFound
+}
+
+// jwks 获取全部密钥的公钥
+func (k *keySet) jwks(ctx context.Context) (*JWKS, error) {
+	k.mu.RLock()
+	stale := time.Since(k.loadedAt) > keyReloadInterval
+	k.mu.RUnlock()
+	if stale {
+		if err := k.load(ctx); err != nil {
+			return nil, err
+		}
+	}
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	result := &JWKS{Keys: make([]JWK, 0, len(k.keys))}
+	for _, key := range k.keys {
+		jwk, err := toJWK(key)
+		if err != nil {
+			return nil, err
+		}
+		result.Keys = append(result.Keys, jwk)
+	}
+	return result, nil
+}
+
+func generateKey(kid, algorithm string, createdAt time.Time, kek []byte) (*signingKey, error) {
+	var (
+		signer crypto.Signer
+		err    error
+	)
+	switch algorithm {
+	case AlgorithmRS256:
+		signer, err = rsa.GenerateKey(rand.Reader, rsaKeyBits)
+	case AlgorithmES256:
+		signer, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm %q", algorithm)
+	}
+	if err != nil {
+		return nil, err
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(signer)
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := encryptPrivateKey(kek, kid, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	if err != nil {
+		return nil, err
+	}
+	return &signingKey{
+		ID:           kid,
+		Algorithm:    algorithm,
+		EncryptedKey: encrypted,
+		CreatedAt:    createdAt,
+		signer:       signer,
+	}, nil
+}
+
+// encryptPrivateKey 使用AES-GCM加密私钥,kid作为附加数据,密文不能挪用到其他kid;返回 base64(nonce || 密文)
+func encryptPrivateKey(kek []byte, kid string, data []byte) (string, error) {
+	aead, err := newKeyCipher(kek)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(aead.Seal(nonce, nonce, data, []byte(kid))), nil
+}
+
+// decryptPrivateKey 解密并解析 encryptPrivateKey 加密的私钥
+func decryptPrivateKey(kek []byte, kid string, data string) (crypto.Signer, error) {
+	raw, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newKeyCipher(kek)
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) < aead.NonceSize() {
+		return nil, errors.New("invalid encrypted key")
+	}
+	plain, err := aead.Open(nil, raw[:aead.NonceSize()], raw[aead.NonceSize():], []byte(kid))
+	if err != nil {
+		return nil, err
+	}
+	return parsePrivateKey(plain)
+}
+
+func newKeyCipher(kek []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func parsePrivateKey(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("invalid pem data")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type %T", key)
+	}
+	return signer, nil
+}
+
+func toJWK(key *signingKey) (JWK, error) {
+	jwk := JWK{Kid: key.ID, Use: "sig", Alg: key.Algorithm}
+	encode := base64.RawURLEncoding.EncodeToString
+	switch pub := key.signer.Public().(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = encode(pub.N.Bytes())
+		jwk.E = encode(big.NewInt(int64(pub.E)).Bytes())
+	case *ecdsa.PublicKey:
+		point, err := pub.ECDH()
+		if err != nil {
+			return JWK{}, err
+		}
+		// 未压缩格式: 0x04 || X || Y
+		raw := point.Bytes()[1:]
+		size := len(raw) / 2
+		jwk.Kty = "EC"
+		jwk.Crv = pub.Curve.Params().Name
+		jwk.X = encode(raw[:size])
+		jwk.Y = encode(raw[size:])
+	default:
+		return JWK{}, fmt.Errorf("unsupported public key type %T", pub)
+	}
+	return jwk, nil
+}
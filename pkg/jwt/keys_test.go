@@ -0,0 +1,137 @@
+package jwt
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	goredislib "github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/supuwoerc/weaver/conf"
+	"github.com/supuwoerc/weaver/pkg/constant"
+	"github.com/supuwoerc/weaver/pkg/redis"
+	"github.com/supuwoerc/weaver/pkg/response"
+)
+
+func newTestAsymmetricBuilder(t *testing.T, algorithm string) (*TokenBuilder, *redis.CommonRedisClient) {
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+	t.Cleanup(mr.Close)
+	client := goredislib.NewClient(&goredislib.Options{Addr: mr.Addr()})
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+	r := redis.NewCommonRedisClient(client, nil)
+	return NewJwtBuilder(nil, r, &conf.Config{
+		JWT: conf.JWTConfig{
+			Expires:             15,
+			RefreshTokenExpires: 60,
+			Secret:              "weaver_secret",
+			Issuer:              "weaver",
+			Algorithm:           algorithm,
+			KeyRotation:         60,
+		},
+	}), r
+}
+
+func TestTokenBuilder_Asymmetric(t *testing.T) {
+	user := &TokenClaimsBasic{ID: 1, Email: "test@example.com"}
+	for _, algorithm := range []string{AlgorithmRS256, AlgorithmES256} {
+		t.Run(algorithm, func(t *testing.T) {
+			builder, r := newTestAsymmetricBuilder(t, algorithm)
+			pair, err := builder.GenerateAccessAndRefreshToken(user, "")
+			require.NoError(t, err)
+			claims, err := builder.ParseAccessToken(pair.AccessToken)
+			require.NoError(t, err)
+			assert.Equal(t, user.ID, claims.User.ID)
+			_, err = builder.ParseRefreshToken(pair.RefreshToken)
+			require.NoError(t, err)
+
+			jwks, err := builder.JWKS(context.Background())
+			require.NoError(t, err)
+			require.Len(t, jwks.Keys, 1)
+			assert.Equal(t, algorithm, jwks.Keys[0].Alg)
+			assert.Equal(t, "sig", jwks.Keys[0].Use)
+
+			// 其他实例共享redis中的密钥
+			other := NewJwtBuilder(nil, r, builder.conf)
+			_, err = other.ParseAccessToken(pair.AccessToken)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestTokenBuilder_AsymmetricRejectsHS256(t *testing.T) {
+	builder, _ := newTestAsymmetricBuilder(t, AlgorithmRS256)
+	hs := newTestTokenBuilder()
+	token, err := hs.GenerateAccessToken(&TokenClaimsBasic{ID: 1}, "id", "session", time.Now())
+	require.NoError(t, err)
+	_, err = builder.ParseAccessToken(token)
+	assert.ErrorIs(t, err, response.InvalidToken)
+}
+
+func TestKeySet_EncryptedPrivateKey(t *testing.T) {
+	ctx := context.Background()
+	builder, r := newTestAsymmetricBuilder(t, AlgorithmRS256)
+	key, err := builder.keys.current(ctx)
+	require.NoError(t, err)
+	value, err := r.Client.HGet(ctx, constant.JWTSigningKeysKey, key.ID).Result()
+	require.NoError(t, err)
+	assert.NotContains(t, value, "PRIVATE KEY")
+
+	// 没有相同secret的实例无法解密私钥
+	other := *builder.conf
+	other.JWT.Secret = "other_secret"
+	assert.Error(t, newKeySet(r, &other).load(ctx))
+	other.JWT.Secret = ""
+	assert.ErrorIs(t, newKeySet(r, &other).load(ctx), errKeyEncryptionSecret)
+
+	// 密文不能挪用到其他kid
+	kek, err := builder.keys.encryptionKey()
+	require.NoError(t, err)
+	_, err = decryptPrivateKey(kek, "other-kid", key.EncryptedKey)
+	assert.Error(t, err)
+}
+
+func TestKeySet_Rotate(t *testing.T) {
+	ctx := context.Background()
+	builder, r := newTestAsymmetricBuilder(t, AlgorithmES256)
+	keys := builder.keys
+	now := time.Now().Truncate(time.Hour)
+
+	require.NoError(t, keys.rotate(ctx, now))
+	first, err := keys.current(ctx)
+	require.NoError(t, err)
+	token, err := builder.GenerateAccessToken(&TokenClaimsBasic{ID: 1}, "id", "session", time.Now())
+	require.NoError(t, err)
+
+	t.Run("same period keeps key", func(t *testing.T) {
+		require.NoError(t, keys.rotate(ctx, now.Add(30*time.Minute)))
+		current, err := keys.current(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, first.ID, current.ID)
+	})
+
+	t.Run("next period adds key and old key still verifies", func(t *testing.T) {
+		require.NoError(t, keys.rotate(ctx, now.Add(time.Hour)))
+		current, err := keys.current(ctx)
+		require.NoError(t, err)
+		assert.NotEqual(t, first.ID, current.ID)
+		_, err = builder.ParseAccessToken(token)
+		assert.NoError(t, err)
+		jwks, err := builder.JWKS(ctx)
+		require.NoError(t, err)
+		assert.Len(t, jwks.Keys, 2)
+	})
+
+	t.Run("prune key after token lifetime", func(t *testing.T) {
+		require.NoError(t, keys.rotate(ctx, now.Add(2*time.Hour+time.Minute)))
+		exists, err := r.Client.HExists(ctx, constant.JWTSigningKeysKey, first.ID).Result()
+		require.NoError(t, err)
+		assert.False(t, exists)
+		_, err = keys.get(ctx, first.ID)
+		assert.ErrorIs(t, err, errKeyNotFound)
+	})
+}
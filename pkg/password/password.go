@@ -0,0 +1,79 @@
+package password
+
+import (
+	_ "embed"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/supuwoerc/weaver/conf"
+	"github.com/supuwoerc/weaver/pkg/response"
+
+	"github.com/samber/lo"
+)
+
+// maxBytes bcrypt接受的密码最大字节数,超过时生成哈希返回错误
+const maxBytes = 72
+
+// breachedList 内置的常见泄露密码,每行一个,均为小写
+//
+//go:embed breached.txt
+var breachedList string
+
+var breached = sync.OnceValue(func() map[string]struct{} {
+	lines := strings.Split(breachedList, "\n")
+	result := make(map[string]struct{}, len(lines))
+	for _, line := range lines {
+		if line = strings.TrimSpace(line); line != "" {
+			result[line] = struct{}{}
+		}
+	}
+	return result
+})
+
+// IsBreached 判断密码是否在内置的泄露密码库中,忽略大小写
+func IsBreached(password string) bool {
+	_, ok := breached()[strings.ToLower(password)]
+	return ok
+}
+
+// Validate 按密码策略校验密码,不满足时返回对应的状态码,需要展示限制值的状态码附带翻译参数
+func Validate(policy *conf.PasswordConfig, password string) error {
+	length := utf8.RuneCountInString(password)
+	if length < policy.MinLength {
+		return response.WithTemplateData(response.PasswordTooShort, map[string]any{"MinLength": policy.MinLength})
+	}
+	// MaxLength按字符数限制,多字节字符较多时可能先超过bcrypt的字节数限制
+	if (policy.MaxLength > 0 && length > policy.MaxLength) || len(password) > maxBytes {
+		return response.WithTemplateData(response.PasswordTooLong, map[string]any{
+			"MaxLength": lo.Ternary(policy.MaxLength > 0, min(policy.MaxLength, maxBytes), maxBytes),
+		})
+	}
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			symbol = true
+		}
+	}
+	switch {
+	case policy.RequireUpper && !upper:
+		return response.PasswordRequireUpper
+	case policy.RequireLower && !lower:
+		return response.PasswordRequireLower
+	case policy.RequireDigit && !digit:
+		return response.PasswordRequireDigit
+	case policy.RequireSymbol && !symbol:
+		return response.PasswordRequireSymbol
+	case policy.CheckBreached && IsBreached(password):
+		return response.PasswordBreached
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package password
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supuwoerc/weaver/conf"
+	"github.com/supuwoerc/weaver/pkg/response"
+)
+
+func TestIsBreached(t *testing.T) {
+	assert.True(t, IsBreached("password"))
+	assert.True(t, IsBreached("P@ssw0rd"))
+	assert.False(t, IsBreached("correct-horse-battery-staple"))
+	assert.False(t, IsBreached(""))
+}
+
+func TestValidate(t *testing.T) {
+	policy := &conf.PasswordConfig{
+		MinLength:     8,
+		MaxLength:     20,
+		RequireUpper:  true,
+		RequireLower:  true,
+		RequireDigit:  true,
+		RequireSymbol: true,
+		CheckBreached: true,
+	}
+	testCases := []struct {
+		name     string
+		password string
+		err      error
+	}{
+		{"too short", "Ab1!", response.PasswordTooShort},
+		{"too long", "Abcdefgh1!Abcdefgh1!x", response.PasswordTooLong},
+		{"missing upper", "abcdefg1!", response.PasswordRequireUpper},
+		{"missing lower", "ABCDEFG1!", response.PasswordRequireLower},
+		{"missing digit", "Abcdefgh!", response.PasswordRequireDigit},
+		{"missing symbol", "Abcdefgh1", response.PasswordRequireSymbol},
+		{"breached", "P@ssw0rd123", response.PasswordBreached},
+		{"valid", "Weaver#2025", nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := Validate(policy, tc.password)
+			if tc.err == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tc.err)
+		})
+	}
+
+	t.Run("length limits in template data", func(t *testing.T) {
+		var templateErr *response.TemplateError
+		assert.ErrorAs(t, Validate(policy, "Ab1!"), &templateErr)
+		assert.Equal(t, map[string]any{"MinLength": 8}, templateErr.Data)
+	})
+
+	t.Run("bcrypt byte limit", func(t *testing.T) {
+		// 27个字符未超过字符数限制,但超过了72个字节
+		password := "Ab1!" + strings.Repeat("密", 23)
+		assert.ErrorIs(t, Validate(&conf.PasswordConfig{MaxLength: 64}, password), response.PasswordTooLong)
+		assert.ErrorIs(t, Validate(&conf.PasswordConfig{}, password), response.PasswordTooLong)
+		assert.NoError(t, Validate(&conf.PasswordConfig{MaxLength: 64}, "Ab1!"+strings.Repeat("密", 22)))
+	})
+
+	t.Run("empty policy accepts anything", func(t *testing.T) {
+		assert.NoError(t, Validate(&conf.PasswordConfig{}, "password"))
+	})
+}
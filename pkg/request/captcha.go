@@ -0,0 +1,6 @@
+package request
+
+// GetCaptchaRequest 获取验证码的参数
+type GetCaptchaRequest struct {
+	Audio bool `json:"audio" form:"audio"` // 是否获取语音验证码代替图片验证码
+}
@@ -2,12 +2,13 @@ package request
 
 import "github.com/supuwoerc/weaver/pkg/constant"
 
-// CreatePermissionRequest 创建新权限的请求参数
+// CreatePermissionRequest 创建新权限的请求参数,API权限的资源为gin的路由模板,模板中单独的*匹配一段路径,位于结尾时匹配剩余的全部路径
 type CreatePermissionRequest struct {
-	Name     string                  `json:"name" binding:"required,min=1,max=20"`      // 权限名称
-	Resource string                  `json:"resource" binding:"required,min=1,max=255"` // 资源名称
-	Type     constant.PermissionType `json:"type" binding:"required,oneof=1 2 3 4"`     // 资源类型
-	Roles    []uint                  `json:"roles" binding:"omitempty,dive,min=1"`      // 资源关联的角色
+	Name     string                  `json:"name" binding:"required,min=1,max=20"`                         // 权限名称
+	Resource string                  `json:"resource" binding:"required,min=1,max=255"`                    // 资源名称
+	Type     constant.PermissionType `json:"type" binding:"required,oneof=1 2 3 4"`                        // 资源类型
+	Method   string                  `json:"method" binding:"omitempty,oneof=GET POST PUT PATCH DELETE *"` // API权限的请求方法,*或为空时匹配任意方法
+	Roles    []uint                  `json:"roles" binding:"omitempty,dive,min=1"`                         // 资源关联的角色
 }
 
 // GetPermissionListRequest 查询权限列表的参数
@@ -1,11 +1,15 @@
 package request
 
+import "github.com/supuwoerc/weaver/pkg/constant"
+
 // CreateRoleRequest 创建新角色的请求参数
 type CreateRoleRequest struct {
-	Name        string `json:"name" binding:"required,min=1,max=20"`       // 角色名称
-	Users       []uint `json:"users" binding:"omitempty,dive,min=1"`       // 角色关联用户
-	Permissions []uint `json:"permissions" binding:"omitempty,dive,min=1"` // 角色关联权限
-	ParentID    *uint  `json:"parent_id" binding:"omitempty,min=1"`        // 父角色ID
+	Name            string             `json:"name" binding:"required,min=1,max=20"`                                    // 角色名称
+	Users           []uint             `json:"users" binding:"omitempty,dive,min=1"`                                    // 角色关联用户
+	Permissions     []uint             `json:"permissions" binding:"omitempty,dive,min=1"`                              // 角色关联权限
+	ParentID        *uint              `json:"parent_id" binding:"omitempty,min=1"`                                     // 父角色ID
+	DataScope       constant.DataScope `json:"data_scope" binding:"omitempty,oneof=1 2 3 4 5"`                          // 数据权限范围,默认为全部数据
+	DataDepartments []uint             `json:"data_departments" binding:"required_if=DataScope 4,omitempty,dive,min=1"` // 自定义数据权限的部门
 }
 
 // GetRoleListRequest 查询角色列表的参数
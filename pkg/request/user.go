@@ -1,5 +1,11 @@
 package request
 
+import (
+	"time"
+
+	"github.com/supuwoerc/weaver/pkg/constant"
+)
+
 // SignUpRequest 注册请求参数
 type SignUpRequest struct {
 	Email    string `json:"email" binding:"required,email,max=50"` // 邮箱
@@ -12,13 +18,89 @@ type SignUpRequest struct {
 type LoginRequest struct {
 	Email    string `json:"email" binding:"required,email,max=50"` // 邮箱
 	Password string `json:"password" binding:"required"`           // 密码
+	Device   string `json:"device" binding:"omitempty,max=50"`     // 设备名称
+	ID       string `json:"id" binding:"omitempty"`                // 验证码ID,登录失败次数较多时必填
+	Code     string `json:"code" binding:"omitempty"`              // 验证码内容,登录失败次数较多时必填
+}
+
+// ForgotPasswordRequest 找回密码请求参数
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email,max=50"` // 邮箱
+	ID    string `json:"id" binding:"required"`                 // 验证码ID
+	Code  string `json:"code" binding:"required"`               // 验证码内容
+}
+
+// ResetPasswordRequest 重置密码请求参数
+type ResetPasswordRequest struct {
+	Email    string `json:"email" binding:"required,email,max=50"` // 邮箱
+	Code     string `json:"code" binding:"required,len=6"`         // 邮件中的验证码
+	Password string `json:"password" binding:"required"`           // 新密码
+}
+
+// UpdateProfileRequest 更新个人资料的请求参数,未传递的字段会被清空
+type UpdateProfileRequest struct {
+	Nickname *string              `json:"nickname" binding:"omitempty,min=1,max=20"`        // 昵称
+	Avatar   *string              `json:"avatar" binding:"omitempty,url,max=255"`           // 头像文件URL
+	Gender   *constant.UserGender `json:"gender" binding:"omitempty,oneof=1 2"`             // 性别
+	About    *string              `json:"about" binding:"omitempty,max=100"`                // 关于
+	Birthday *string              `json:"birthday" binding:"omitempty,datetime=2006-01-02"` // 生日
+}
+
+// ChangePasswordRequest 修改密码的请求参数
+type ChangePasswordRequest struct {
+	OldPassword string `json:"old_password" binding:"required"` // 原密码
+	NewPassword string `json:"new_password" binding:"required"` // 新密码
+}
+
+// MFACodeRequest 两步验证码请求参数
+type MFACodeRequest struct {
+	Code string `json:"code" binding:"required,min=6,max=20"` // TOTP验证码或恢复码
+}
+
+// LoginMFARequest 两步验证登录请求参数
+type LoginMFARequest struct {
+	Ticket string `json:"ticket" binding:"required,len=32"`     // 登录凭证
+	Code   string `json:"code" binding:"required,min=6,max=20"` // TOTP验证码或恢复码
+}
+
+// ChangeExpiredPasswordRequest 修改过期密码的请求参数
+type ChangeExpiredPasswordRequest struct {
+	Ticket   string `json:"ticket" binding:"required,len=32"` // 登录凭证
+	Password string `json:"password" binding:"required"`      // 新密码
+}
+
+// SSOLoginRequest 单点登录请求参数
+type SSOLoginRequest struct {
+	Code   string `json:"code" binding:"required"`           // 身份提供方回调的授权码
+	State  string `json:"state" binding:"required,len=32"`   // 身份提供方回调的state
+	Device string `json:"device" binding:"omitempty,max=50"` // 设备名称
+}
+
+// UserFilterRequest 用户列表的过滤条件
+type UserFilterRequest struct {
+	Keyword      string              `json:"keyword" form:"keyword" binding:"omitempty,min=1,max=20"`      // 关键字
+	Status       constant.UserStatus `json:"status" form:"status" binding:"omitempty,oneof=1 2 3"`         // 账户状态
+	RoleID       uint                `json:"role_id" form:"role_id" binding:"omitempty,min=1"`             // 角色ID
+	DepartmentID uint                `json:"department_id" form:"department_id" binding:"omitempty,min=1"` // 部门ID
 }
 
 // GetUserListRequest 查询用户列表的参数
 type GetUserListRequest struct {
-	Keyword string `json:"keyword" form:"keyword" binding:"omitempty,min=1,max=20"` // 关键字
-	Limit   int    `json:"limit" form:"limit" binding:"required,min=1,max=200"`     // 分页数量
-	Offset  int    `json:"offset"  form:"offset" binding:"min=0"`                   // 分页偏移
+	UserFilterRequest
+	Limit  int `json:"limit" form:"limit" binding:"required,min=1,max=200"` // 分页数量
+	Offset int `json:"offset"  form:"offset" binding:"min=0"`               // 分页偏移
+}
+
+// ExportUsersRequest 导出用户的参数
+type ExportUsersRequest struct {
+	UserFilterRequest
+	Format string `json:"format" form:"format" binding:"omitempty,oneof=csv xlsx"` // 文件格式,默认为csv
+}
+
+// ImportUsersRequest 导入用户的参数,文件通过表单字段file上传
+type ImportUsersRequest struct {
+	DryRun          bool `json:"dry_run" form:"dry_run"`                     // 是否仅校验,不创建用户
+	SendActiveEmail bool `json:"send_active_email" form:"send_active_email"` // 是否发送激活邮件,为否时用户直接启用
 }
 
 // ActiveAccountRequest 激活账户的请求参数
@@ -26,3 +108,171 @@ type ActiveAccountRequest struct {
 	ActiveCode string `json:"active_code" form:"active_code" binding:"required,len=16"` // 激活码
 	ID         uint   `json:"id" form:"id" binding:"required,min=1"`                    // ID
 }
+
+// RevokeSessionsRequest 注销登录会话的请求参数
+type RevokeSessionsRequest struct {
+	IDs []string `json:"ids" binding:"required,min=1,max=50,dive,required"` // 会话ID集合
+}
+
+// RevokeUserSessionsRequest 注销用户全部登录会话的请求参数
+type RevokeUserSessionsRequest struct {
+	UID uint `json:"uid" binding:"required,min=1"` // 用户ID
+}
+
+// UnlockLoginRequest 解除登录锁定的请求参数
+type UnlockLoginRequest struct {
+	Email string `json:"email" binding:"required,email,max=50"` // 邮箱
+	IP    string `json:"ip" binding:"omitempty,ip"`             // ip,不为空时同时解除该ip的锁定
+}
+
+// CreateAPIKeyRequest 创建API密钥的请求参数
+type CreateAPIKeyRequest struct {
+	Name        string     `json:"name" binding:"required,min=1,max=50"`                    // 名称
+	ExpiredAt   *time.Time `json:"expired_at" binding:"omitempty"`                          // 过期时间,为空时永不过期
+	Permissions []uint     `json:"permissions" binding:"required,min=1,max=200,dive,min=1"` // 权限范围
+}
+
+// DeleteAPIKeyRequest 删除API密钥的请求参数
+type DeleteAPIKeyRequest struct {
+	ID uint `json:"id" binding:"required,min=1"` // ID
+}
+
+// CreateUserRequest 管理员创建用户的参数
+type CreateUserRequest struct {
+	Email           string  `json:"email" binding:"required,email,max=50"`      // 邮箱
+	Password        string  `json:"password" binding:"required"`                // 密码
+	Nickname        *string `json:"nickname" binding:"omitempty,min=1,max=20"`  // 昵称
+	Roles           []uint  `json:"roles" binding:"omitempty,dive,min=1"`       // 角色
+	Departments     []uint  `json:"departments" binding:"omitempty,dive,min=1"` // 部门
+	SendActiveEmail bool    `json:"send_active_email"`                          // 是否发送激活邮件,为否时用户直接启用
+}
+
+// UpdateUserStatusRequest 启用/禁用用户的参数
+type UpdateUserStatusRequest struct {
+	ID     uint                `json:"id" binding:"required,min=1"`         // ID
+	Status constant.UserStatus `json:"status" binding:"required,oneof=2 3"` // 账户状态:2启用 3禁用
+}
+
+// DeleteUserRequest 删除用户的参数
+type DeleteUserRequest struct {
+	ID uint `json:"id" binding:"required,min=1"` // ID
+}
+
+// RestoreUserRequest 恢复已删除用户的参数
+type RestoreUserRequest = DeleteUserRequest
+
+// EraseUserRequest 注销用户的参数
+type EraseUserRequest = DeleteUserRequest
+
+// AssignUserRequest 替换用户角色和部门的参数
+type AssignUserRequest struct {
+	ID          uint   `json:"id" binding:"required,min=1"`                // ID
+	Roles       []uint `json:"roles" binding:"omitempty,dive,min=1"`       // 角色,为空时清空
+	Departments []uint `json:"departments" binding:"omitempty,dive,min=1"` // 部门,为空时清空
+}
+
+// ImpersonateRequest 模拟登录的参数
+type ImpersonateRequest struct {
+	ID     uint   `json:"id" binding:"required,min=1"`             // 被模拟的用户ID
+	Reason string `json:"reason" binding:"required,min=1,max=255"` // 模拟登录的原因
+}
+
+// GetImpersonationsRequest 查询模拟登录记录的参数
+type GetImpersonationsRequest struct {
+	ActorID uint `json:"actor_id" form:"actor_id" binding:"omitempty,min=1"`  // 操作人ID
+	UserID  uint `json:"user_id" form:"user_id" binding:"omitempty,min=1"`    // 被模拟的用户ID
+	Limit   int  `json:"limit" form:"limit" binding:"required,min=1,max=200"` // 分页数量
+	Offset  int  `json:"offset" form:"offset" binding:"min=0"`                // 分页偏移
+}
+
+// SendBindPhoneCodeRequest 发送绑定手机号验证码的参数
+type SendBindPhoneCodeRequest struct {
+	Phone string `json:"phone" binding:"required"` // 手机号
+}
+
+// BindPhoneRequest 绑定手机号的参数
+type BindPhoneRequest struct {
+	Phone string `json:"phone" binding:"required"`               // 手机号
+	Code  string `json:"code" binding:"required,numeric,max=10"` // 短信验证码
+}
+
+// SendLoginSMSCodeRequest 发送登录验证码的参数
+type SendLoginSMSCodeRequest struct {
+	Phone string `json:"phone" binding:"required"` // 手机号
+	ID    string `json:"id" binding:"required"`    // 图形验证码ID
+	Code  string `json:"code" binding:"required"`  // 图形验证码内容
+}
+
+// LoginSMSRequest 手机号验证码登录的参数
+type LoginSMSRequest struct {
+	Phone  string `json:"phone" binding:"required"`               // 手机号
+	Code   string `json:"code" binding:"required,numeric,max=10"` // 短信验证码
+	Device string `json:"device" binding:"omitempty,max=50"`      // 设备名称
+}
+
+// GetLoginHistoryRequest 查询本人登录记录的参数
+type GetLoginHistoryRequest struct {
+	Limit  int `json:"limit" form:"limit" binding:"required,min=1,max=200"` // 分页数量
+	Offset int `json:"offset" form:"offset" binding:"min=0"`                // 分页偏移
+}
+
+// GetLoginHistoriesRequest 查询登录记录的参数
+type GetLoginHistoriesRequest struct {
+	UserID  uint   `json:"user_id" form:"user_id" binding:"omitempty,min=1"`                        // 用户ID
+	Email   string `json:"email" form:"email" binding:"omitempty,max=50"`                           // 登录邮箱(模糊匹配)
+	Type    string `json:"type" form:"type" binding:"omitempty,oneof=password mfa sso sms refresh"` // 登录方式
+	Success *bool  `json:"success" form:"success"`                                                  // 是否成功
+	IP      string `json:"ip" form:"ip" binding:"omitempty,ip"`                                     // IP
+	Start   *int64 `json:"start" form:"start" binding:"omitempty,min=0"`                            // 开始时间(秒级时间戳)
+	End     *int64 `json:"end" form:"end" binding:"omitempty,min=0"`                                // 结束时间(秒级时间戳)
+	Limit   int    `json:"limit" form:"limit" binding:"required,min=1,max=200"`                     // 分页数量
+	Offset  int    `json:"offset" form:"offset" binding:"min=0"`                                    // 分页偏移
+}
+
+// CreateInvitationRequest 创建注册邀请的参数
+type CreateInvitationRequest struct {
+	Email       string `json:"email" binding:"required,email,max=50"`      // 被邀请人的邮箱
+	Roles       []uint `json:"roles" binding:"omitempty,dive,min=1"`       // 预设的角色
+	Departments []uint `json:"departments" binding:"omitempty,dive,min=1"` // 预设的部门
+	Expires     int    `json:"expires" binding:"required,min=1,max=720"`   // 有效期,单位小时
+}
+
+// GetInvitationRequest 查询注册邀请的参数
+type GetInvitationRequest struct {
+	Code string `json:"code" form:"code" binding:"required,len=32"` // 邀请码
+}
+
+// DownloadDataExportRequest 下载个人数据的参数
+type DownloadDataExportRequest struct {
+	Code string `json:"code" form:"code" binding:"required,len=32"` // 下载码
+}
+
+// AcceptInvitationRequest 接受注册邀请的参数
+type AcceptInvitationRequest struct {
+	Code     string  `json:"code" binding:"required,len=32"`            // 邀请码
+	Password string  `json:"password" binding:"required"`               // 密码
+	Nickname *string `json:"nickname" binding:"omitempty,min=1,max=20"` // 昵称
+}
+
+// GetInvitationsRequest 查询注册邀请列表的参数
+type GetInvitationsRequest struct {
+	Keyword string `json:"keyword" form:"keyword" binding:"omitempty,min=1,max=20"` // 邮箱关键字
+	Limit   int    `json:"limit" form:"limit" binding:"required,min=1,max=200"`     // 分页数量
+	Offset  int    `json:"offset" form:"offset" binding:"min=0"`                    // 分页偏移
+}
+
+// RevokeInvitationRequest 撤销注册邀请的参数
+type RevokeInvitationRequest struct {
+	ID uint `json:"id" binding:"required,min=1"` // ID
+}
+
+// ChangeEmailRequest 修改邮箱的参数
+type ChangeEmailRequest struct {
+	Email    string `json:"email" binding:"required,email,max=50"` // 新邮箱
+	Password string `json:"password" binding:"required"`           // 当前密码
+}
+
+// EmailChangeCodeRequest 确认或撤销修改邮箱的参数
+type EmailChangeCodeRequest struct {
+	Code string `json:"code" binding:"required,len=32"` // 邮件中链接携带的code
+}
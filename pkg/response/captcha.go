@@ -1,6 +1,9 @@
 package response
 
+import "github.com/supuwoerc/weaver/pkg/constant"
+
 type GetCaptchaResponse struct {
-	ID     string `json:"id"`     // ID
-	Base64 string `json:"base64"` // 图片base64
+	ID     string                 `json:"id"`     // ID
+	Base64 string                 `json:"base64"` // 图片或语音的base64(data url)
+	Type   constant.CaptchaDriver `json:"type"`   // 生成方式,audio为语音,其余为图片
 }
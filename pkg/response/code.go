@@ -22,6 +22,8 @@ const (
 	AuthErr                 StatusCode = 10008 // authErr
 	TimeoutErr              StatusCode = 10009 // timeoutErr
 	Busy                    StatusCode = 10010 // busy
+	RefreshTokenReused      StatusCode = 10011 // refreshTokenReused
+	InvalidAPIKey           StatusCode = 10012 // invalidAPIKey
 )
 
 const (
@@ -35,6 +37,45 @@ const (
 	InvalidActiveCode        StatusCode = 20007 // invalidActiveCode
 	ReActiveErr              StatusCode = 20008 // reActiveErr
 	UserLogoutFail           StatusCode = 20009 // userLogoutFail
+	InvalidResetPasswordCode StatusCode = 20010 // invalidResetPasswordCode
+	ResetPasswordTooFrequent StatusCode = 20011 // resetPasswordTooFrequent
+	OldPasswordIncorrect     StatusCode = 20012 // oldPasswordIncorrect
+	MFANotEnabled            StatusCode = 20013 // mfaNotEnabled
+	MFAAlreadyEnabled        StatusCode = 20014 // mfaAlreadyEnabled
+	InvalidMFACode           StatusCode = 20015 // invalidMFACode
+	InvalidMFATicket         StatusCode = 20016 // invalidMFATicket
+	IdentityProviderNotExist StatusCode = 20017 // identityProviderNotExist
+	InvalidSSOState          StatusCode = 20018 // invalidSSOState
+	SSOLoginFail             StatusCode = 20019 // ssoLoginFail
+	UserIdentityNotExist     StatusCode = 20020 // userIdentityNotExist
+	APIKeyNotExist           StatusCode = 20021 // apiKeyNotExist
+	APIKeyLimitExceeded      StatusCode = 20022 // apiKeyLimitExceeded
+	APIKeyScopeExceeded      StatusCode = 20023 // apiKeyScopeExceeded
+	UserLoginLocked          StatusCode = 20024 // userLoginLocked
+	LoginCaptchaRequired     StatusCode = 20025 // loginCaptchaRequired
+	UserManageForbidden      StatusCode = 20026 // userManageForbidden
+	InvalidImportFile        StatusCode = 20027 // invalidImportFile
+	ImportRowsExceeded       StatusCode = 20028 // importRowsExceeded
+	ImpersonationForbidden   StatusCode = 20029 // impersonationForbidden
+	SignupClosed             StatusCode = 20030 // signupClosed
+	InvitationNotExist       StatusCode = 20031 // invitationNotExist
+	EmailChangeCodeInvalid   StatusCode = 20032 // emailChangeCodeInvalid
+	PasswordTooShort         StatusCode = 20033 // passwordTooShort
+	PasswordTooLong          StatusCode = 20034 // passwordTooLong
+	PasswordRequireUpper     StatusCode = 20035 // passwordRequireUpper
+	PasswordRequireLower     StatusCode = 20036 // passwordRequireLower
+	PasswordRequireDigit     StatusCode = 20037 // passwordRequireDigit
+	PasswordRequireSymbol    StatusCode = 20038 // passwordRequireSymbol
+	PasswordBreached         StatusCode = 20039 // passwordBreached
+	PasswordReused           StatusCode = 20040 // passwordReused
+	InvalidPasswordTicket    StatusCode = 20041 // invalidPasswordTicket
+	PhoneValidErr            StatusCode = 20042 // phoneValidErr
+	SMSCodeInvalid           StatusCode = 20043 // smsCodeInvalid
+	SMSTooFrequent           StatusCode = 20044 // smsTooFrequent
+	PhoneAlreadyBound        StatusCode = 20045 // phoneAlreadyBound
+	DataExportTooFrequent    StatusCode = 20046 // dataExportTooFrequent
+	DataExportCodeInvalid    StatusCode = 20047 // dataExportCodeInvalid
+	UserErased               StatusCode = 20048 // userErased
 )
 
 const (
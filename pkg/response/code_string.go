@@ -19,6 +19,8 @@ func _() {
 	_ = x[AuthErr-10008]
 	_ = x[TimeoutErr-10009]
 	_ = x[Busy-10010]
+	_ = x[RefreshTokenReused-10011]
+	_ = x[InvalidAPIKey-10012]
 	_ = x[UserCreateDuplicateEmail-20000]
 	_ = x[UserLoginFail-20001]
 	_ = x[PasswordValidErr-20002]
@@ -29,6 +31,45 @@ func _() {
 	_ = x[InvalidActiveCode-20007]
 	_ = x[ReActiveErr-20008]
 	_ = x[UserLogoutFail-20009]
+	_ = x[InvalidResetPasswordCode-20010]
+	_ = x[ResetPasswordTooFrequent-20011]
+	_ = x[OldPasswordIncorrect-20012]
+	_ = x[MFANotEnabled-20013]
+	_ = x[MFAAlreadyEnabled-20014]
+	_ = x[InvalidMFACode-20015]
+	_ = x[InvalidMFATicket-20016]
+	_ = x[IdentityProviderNotExist-20017]
+	_ = x[InvalidSSOState-20018]
+	_ = x[SSOLoginFail-20019]
+	_ = x[UserIdentityNotExist-20020]
+	_ = x[APIKeyNotExist-20021]
+	_ = x[APIKeyLimitExceeded-20022]
+	_ = x[APIKeyScopeExceeded-20023]
+	_ = x[UserLoginLocked-20024]
+	_ = x[LoginCaptchaRequired-20025]
+	_ = x[UserManageForbidden-20026]
+	_ = x[InvalidImportFile-20027]
+	_ = x[ImportRowsExceeded-20028]
+	_ = x[ImpersonationForbidden-20029]
+	_ = x[SignupClosed-20030]
+	_ = x[InvitationNotExist-20031]
+	_ = x[EmailChangeCodeInvalid-20032]
+	_ = x[PasswordTooShort-20033]
+	_ = x[PasswordTooLong-20034]
+	_ = x[PasswordRequireUpper-20035]
+	_ = x[PasswordRequireLower-20036]
+	_ = x[PasswordRequireDigit-20037]
+	_ = x[PasswordRequireSymbol-20038]
+	_ = x[PasswordBreached-20039]
+	_ = x[PasswordReused-20040]
+	_ = x[InvalidPasswordTicket-20041]
+	_ = x[PhoneValidErr-20042]
+	_ = x[SMSCodeInvalid-20043]
+	_ = x[SMSTooFrequent-20044]
+	_ = x[PhoneAlreadyBound-20045]
+	_ = x[DataExportTooFrequent-20046]
+	_ = x[DataExportCodeInvalid-20047]
+	_ = x[UserErased-20048]
 	_ = x[CaptchaVerifyFail-30000]
 	_ = x[RoleCreateDuplicateName-40000]
 	_ = x[NoValidRoles-40001]
@@ -45,8 +86,8 @@ func _() {
 }
 
 const (
-	_StatusCode_name_0 = "okerrorinvalidParamsinvalidTokencancelRequestrecoveryErrorinvalidRefreshTokenunnecessaryRefreshTokenauthErrtimeoutErrbusy"
-	_StatusCode_name_1 = "userCreateDuplicateEmailuserLoginFailpasswordValidErremailValidErruserNotExistuserInactiveuserDisabledinvalidActiveCodereActiveErruserLogoutFail"
+	_StatusCode_name_0 = "okerrorinvalidParamsinvalidTokencancelRequestrecoveryErrorinvalidRefreshTokenunnecessaryRefreshTokenauthErrtimeoutErrbusyrefreshTokenReusedinvalidAPIKey"
+	_StatusCode_name_1 = "userCreateDuplicateEmailuserLoginFailpasswordValidErremailValidErruserNotExistuserInactiveuserDisabledinvalidActiveCodereActiveErruserLogoutFailinvalidResetPasswordCoderesetPasswordTooFrequentoldPasswordIncorrectmfaNotEnabledmfaAlreadyEnabledinvalidMFACodeinvalidMFATicketidentityProviderNotExistinvalidSSOStatessoLoginFailuserIdentityNotExistapiKeyNotExistapiKeyLimitExceededapiKeyScopeExceededuserLoginLockedloginCaptchaRequireduserManageForbiddeninvalidImportFileimportRowsExceededimpersonationForbiddensignupClosedinvitationNotExistemailChangeCodeInvalidpasswordTooShortpasswordTooLongpasswordRequireUpperpasswordRequireLowerpasswordRequireDigitpasswordRequireSymbolpasswordBreachedpasswordReusedinvalidPasswordTicketphoneValidErrsmsCodeInvalidsmsTooFrequentphoneAlreadyBounddataExportTooFrequentdataExportCodeInvaliduserErased"
 	_StatusCode_name_2 = "captchaVerifyFail"
 	_StatusCode_name_3 = "roleCreateDuplicateNamenoValidRolesroleNotExistroleExistPermissionRefroleExistUserRef"
 	_StatusCode_name_4 = "permissionCreateDuplicatepermissionNotExistpermissionExistRoleRef"
@@ -54,8 +95,8 @@ const (
 )
 
 var (
-	_StatusCode_index_0 = [...]uint8{0, 2, 7, 20, 32, 45, 58, 77, 100, 107, 117, 121}
-	_StatusCode_index_1 = [...]uint8{0, 24, 37, 53, 66, 78, 90, 102, 119, 130, 144}
+	_StatusCode_index_0 = [...]uint8{0, 2, 7, 20, 32, 45, 58, 77, 100, 107, 117, 121, 139, 152}
+	_StatusCode_index_1 = [...]uint16{0, 24, 37, 53, 66, 78, 90, 102, 119, 130, 144, 168, 192, 212, 225, 242, 256, 272, 296, 311, 323, 343, 357, 376, 395, 410, 430, 449, 466, 484, 506, 518, 536, 558, 574, 589, 609, 629, 649, 670, 686, 700, 721, 734, 748, 762, 779, 800, 821, 831}
 	_StatusCode_index_3 = [...]uint8{0, 23, 35, 47, 69, 85}
 	_StatusCode_index_4 = [...]uint8{0, 25, 43, 65}
 	_StatusCode_index_5 = [...]uint8{0, 19, 31, 47}
@@ -63,10 +104,10 @@ var (
 
 func (i StatusCode) String() string {
 	switch {
-	case 10000 <= i && i <= 10010:
+	case 10000 <= i && i <= 10012:
 		i -= 10000
 		return _StatusCode_name_0[_StatusCode_index_0[i]:_StatusCode_index_0[i+1]]
-	case 20000 <= i && i <= 20009:
+	case 20000 <= i && i <= 20048:
 		i -= 20000
 		return _StatusCode_name_1[_StatusCode_index_1[i]:_StatusCode_index_1[i+1]]
 	case i == 30000:
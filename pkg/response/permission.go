@@ -67,3 +67,11 @@ func ToFrontEndPermissionResponse(permission *models.Permission) *FrontEndPermis
 		Type:     permission.Type,
 	}
 }
+
+// SyncRoutePermissionsResponse 同步路由权限的结果
+type SyncRoutePermissionsResponse struct {
+	Total   int                  `json:"total"`   // 需要权限控制的路由数
+	DryRun  bool                 `json:"dry_run"` // 是否仅对比差异
+	Created []*models.Permission `json:"created"` // 新建(仅对比差异时为待新建)的API权限
+	Orphans []*models.Permission `json:"orphans"` // 不再匹配任何路由的API权限,需要人工确认后删除
+}
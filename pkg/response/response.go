@@ -64,6 +64,36 @@ func HttpResponse[T any](ctx *gin.Context, code StatusCode, data T, config *i18n
 	})
 }
 
+// Localize 翻译状态码对应的消息,上下文中没有翻译器时返回空字符串
+func Localize(ctx *gin.Context, code StatusCode) string {
+	translator, exists := ctx.Get(string(I18nTranslatorKey))
+	if !exists {
+		return ""
+	}
+	return translator.(ILocalizer).MustLocalize(&i18n.LocalizeConfig{
+		MessageID: code.String(),
+	})
+}
+
+// TemplateError 携带翻译参数的状态码错误,用于消息中需要展示具体限制的场景
+type TemplateError struct {
+	Code StatusCode
+	Data map[string]any
+}
+
+func (e *TemplateError) Error() string {
+	return e.Code.Error()
+}
+
+func (e *TemplateError) Unwrap() error {
+	return e.Code
+}
+
+// WithTemplateData 为状态码附加翻译参数
+func WithTemplateData(code StatusCode, data map[string]any) error {
+	return &TemplateError{Code: code, Data: data}
+}
+
 // Success 成功响应-不携带数据
 func Success(ctx *gin.Context) {
 	HttpResponse[any](ctx, Ok, nil, nil, nil)
@@ -99,6 +129,14 @@ func FailWithCode(ctx *gin.Context, code StatusCode) {
 
 // FailWithError 失败响应
 func FailWithError(ctx *gin.Context, err error) {
+	var templateErr *TemplateError
+	if errors.As(err, &templateErr) {
+		HttpResponse[any](ctx, templateErr.Code, nil, &i18n.LocalizeConfig{
+			MessageID:    templateErr.Code.String(),
+			TemplateData: templateErr.Data,
+		}, nil)
+		return
+	}
 	var code StatusCode
 	if errors.As(err, &code) {
 		FailWithCode(ctx, code)
@@ -555,6 +555,25 @@ func TestFailWithError(t *testing.T) {
 	}
 }
 
+func TestFailWithError_TemplateData(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Set(string(I18nTranslatorKey), &mockLocalizer{
+		localizeFunc: func(config *i18n.LocalizeConfig) (string, error) {
+			data := config.TemplateData.(map[string]any)
+			return fmt.Sprintf("%s:%v", config.MessageID, data["MinLength"]), nil
+		},
+	})
+	err := WithTemplateData(PasswordTooShort, map[string]any{"MinLength": 8})
+	assert.ErrorIs(t, err, PasswordTooShort)
+	FailWithError(c, fmt.Errorf("wrapped: %w", err))
+	var response BasicResponse[any]
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, int(PasswordTooShort), response.Code)
+	assert.Equal(t, "passwordTooShort:8", response.Message)
+}
+
 // Mock validator.FieldError
 type mockFieldError struct {
 	field       string
@@ -32,6 +32,7 @@ type RoleDetailResponse struct {
 	*models.Role
 	Users       []*SimpleUser           `json:"users"`             // 用户
 	Permissions []*RoleDetailPermission `json:"permissions"`       // 权限
+	Inherited   []*RoleDetailPermission `json:"inherited"`         // 按角色继承方向从后代角色或祖先角色继承的权限
 	Creator     any                     `json:"creator,omitempty"` // 创建者
 	Updater     any                     `json:"updater,omitempty"` // 更新者
 }
@@ -43,8 +44,13 @@ type RoleDetailPermission struct {
 	Updater any `json:"updater,omitempty"` // 更新者
 }
 
-// ToRoleDetailResponse 将permission转为响应
-func ToRoleDetailResponse(role *models.Role) *RoleDetailResponse {
+// ToRoleDetailResponse 将role转为响应,inherited为角色继承的权限
+func ToRoleDetailResponse(role *models.Role, inherited []*models.Permission) *RoleDetailResponse {
+	toPermission := func(item *models.Permission, _ int) *RoleDetailPermission {
+		return &RoleDetailPermission{
+			Permission: item,
+		}
+	}
 	return &RoleDetailResponse{
 		Role: role,
 		Users: lo.Map(role.Users, func(item *models.User, _ int) *SimpleUser {
@@ -52,10 +58,7 @@ func ToRoleDetailResponse(role *models.Role) *RoleDetailResponse {
 				User: item,
 			}
 		}),
-		Permissions: lo.Map(role.Permissions, func(item *models.Permission, _ int) *RoleDetailPermission {
-			return &RoleDetailPermission{
-				Permission: item,
-			}
-		}),
+		Permissions: lo.Map(role.Permissions, toPermission),
+		Inherited:   lo.Map(inherited, toPermission),
 	}
 }
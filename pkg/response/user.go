@@ -1,15 +1,21 @@
 package response
 
 import (
+	"time"
+
 	"github.com/samber/lo"
 	"github.com/supuwoerc/weaver/models"
 )
 
 // LoginResponse 登录响应
 type LoginResponse struct {
-	User         LoginUser `json:"user"`          // 用户信息
-	Token        string    `json:"token"`         // token
-	RefreshToken string    `json:"refresh_token"` // refresh token
+	User            LoginUser `json:"user"`                      // 用户信息
+	Token           string    `json:"token"`                     // token
+	RefreshToken    string    `json:"refresh_token"`             // refresh token
+	MFARequired     bool      `json:"mfa_required"`              // 是否需要两步验证,为true时token为空,需要使用mfa_ticket完成登录
+	MFATicket       string    `json:"mfa_ticket,omitempty"`      // 两步验证登录凭证
+	PasswordExpired bool      `json:"password_expired"`          // 密码是否已过期,为true时token为空,需要使用password_ticket修改密码后完成登录
+	PasswordTicket  string    `json:"password_ticket,omitempty"` // 修改过期密码的登录凭证
 }
 
 type LoginUser struct {
@@ -20,7 +26,31 @@ type LoginUser struct {
 
 // RefreshTokenResponse 刷新 token 的响应
 type RefreshTokenResponse struct {
-	Token string `json:"token"` // token
+	Token        string `json:"token"`         // token
+	RefreshToken string `json:"refresh_token"` // refresh token
+}
+
+// UserSessionResponse 登录会话响应
+type UserSessionResponse struct {
+	*models.UserSession
+	Current bool `json:"current"` // 是否为当前会话
+}
+
+// MFAEnrollResponse 绑定两步验证的响应
+type MFAEnrollResponse struct {
+	Secret string `json:"secret"`  // TOTP密钥
+	URI    string `json:"uri"`     // otpauth地址
+	QRCode string `json:"qr_code"` // base64格式的二维码
+}
+
+// MFARecoveryCodesResponse 两步验证恢复码的响应
+type MFARecoveryCodesResponse struct {
+	Codes []string `json:"codes"` // 恢复码,仅返回一次
+}
+
+// SSOAuthorizeResponse 单点登录授权地址的响应
+type SSOAuthorizeResponse struct {
+	URL string `json:"url"` // 身份提供方的授权地址
 }
 
 // ProfileResponse 个人信息响应
@@ -62,3 +92,56 @@ func ToUserListRowResponse(user *models.User) *UserListRowResponse {
 		}),
 	}
 }
+
+// APIKeyResponse API密钥的响应
+type APIKeyResponse struct {
+	*models.UserAPIKey
+	Permissions []*PermissionDetailResponse `json:"permissions"` // 权限范围
+}
+
+// ToAPIKeyResponse 将API密钥转为响应
+func ToAPIKeyResponse(key *models.UserAPIKey) *APIKeyResponse {
+	return &APIKeyResponse{
+		UserAPIKey: key,
+		Permissions: lo.Map(key.Permissions, func(item *models.Permission, _ int) *PermissionDetailResponse {
+			return ToPermissionDetailResponse(item)
+		}),
+	}
+}
+
+// CreateAPIKeyResponse 创建API密钥的响应,密钥明文仅在创建时返回一次
+type CreateAPIKeyResponse struct {
+	*APIKeyResponse
+	Key string `json:"key"` // 密钥明文
+}
+
+// ImportUsersResponse 批量导入用户的响应
+type ImportUsersResponse struct {
+	Total   int                   `json:"total"`   // 数据行数
+	Created int                   `json:"created"` // 创建的用户数
+	DryRun  bool                  `json:"dry_run"` // 是否仅校验
+	Errors  []*ImportUserRowError `json:"errors"`  // 校验失败的行,存在时不会创建任何用户
+}
+
+// ImportUserRowError 导入行的校验错误
+type ImportUserRowError struct {
+	Row     int        `json:"row"`     // 行号,从表头所在的第1行开始计数
+	Column  string     `json:"column"`  // 列名
+	Value   string     `json:"value"`   // 单元格的值
+	Code    StatusCode `json:"code"`    // 错误码
+	Message string     `json:"message"` // 错误信息
+}
+
+// ImpersonateResponse 模拟登录的响应
+type ImpersonateResponse struct {
+	User      LoginUser `json:"user"`       // 被模拟的用户
+	Token     string    `json:"token"`      // 模拟登录的token,不签发refresh token
+	ExpiredAt time.Time `json:"expired_at"` // token的过期时间
+}
+
+// InvitationResponse 注册邀请的公开信息,用于接受邀请页面的预填
+type InvitationResponse struct {
+	Email     string    `json:"email"`      // 被邀请人的邮箱
+	Inviter   string    `json:"inviter"`    // 邀请人
+	ExpiredAt time.Time `json:"expired_at"` // 过期时间
+}
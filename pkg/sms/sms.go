@@ -0,0 +1,84 @@
+package sms
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/supuwoerc/weaver/pkg/logger"
+)
+
+// Message 短信内容,模板和参数由短信服务渲染
+type Message struct {
+	Phone    string            `json:"phone"`    // 手机号
+	Sign     string            `json:"sign"`     // 短信签名
+	Template string            `json:"template"` // 短信模板
+	Params   map[string]string `json:"params"`   // 模板参数
+}
+
+// Sender 短信发送接口,不同的短信服务提供不同的实现
+type Sender interface {
+	Send(ctx context.Context, message *Message) error
+}
+
+// LogSender 只打印短信内容不实际发送,用于开发和测试环境
+type LogSender struct {
+	logger logger.LogCtxInterface
+}
+
+func NewLogSender(logger logger.LogCtxInterface) *LogSender {
+	return &LogSender{logger: logger}
+}
+
+func (l *LogSender) Send(ctx context.Context, message *Message) error {
+	l.logger.WithContext(ctx).Infow("Sending sms in log provider",
+		"Phone", message.Phone, "Template", message.Template, "Params", message.Params)
+	return nil
+}
+
+// HTTPSender 以json请求调用短信服务的http接口,响应状态码为2xx时视为发送成功
+type HTTPSender struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+func NewHTTPSender(url string, token string, timeout time.Duration) *HTTPSender {
+	return &HTTPSender{
+		url:    url,
+		token:  token,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (h *HTTPSender) Send(ctx context.Context, message *Message) error {
+	body, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if h.token != "" {
+		req.Header.Set("Authorization", "Bearer "+h.token)
+	}
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		// 只读取部分响应内容用于排查问题
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("sms provider responded %d: %s", resp.StatusCode, detail)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package sms
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPSender_Send(t *testing.T) {
+	message := &Message{
+		Phone:    "13800000000",
+		Sign:     "weaver",
+		Template: "login",
+		Params:   map[string]string{"code": "123456"},
+	}
+
+	t.Run("send json message with token", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+			var received Message
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+			assert.Equal(t, *message, received)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+		err := NewHTTPSender(server.URL, "secret", time.Second).Send(context.Background(), message)
+		assert.NoError(t, err)
+	})
+
+	t.Run("provider error", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte("invalid template"))
+		}))
+		defer server.Close()
+		err := NewHTTPSender(server.URL, "", time.Second).Send(context.Background(), message)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid template")
+	})
+}
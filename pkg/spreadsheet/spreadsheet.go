@@ -0,0 +1,133 @@
+package spreadsheet
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Format 表格文件格式
+type Format string
+
+const (
+	CSV  Format = "csv"
+	XLSX Format = "xlsx"
+)
+
+const sheetName = "Sheet1"
+
+var ErrUnsupportedFormat = errors.New("unsupported spreadsheet format")
+
+// utf8BOM Excel 打开不带BOM的UTF-8 CSV时中文会乱码
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// formulaPrefixes 表格软件打开CSV时以这些字符开头的单元格会被当作公式执行
+const formulaPrefixes = "=+-@\t\r"
+
+// FormatOf 根据文件扩展名获取表格格式
+func FormatOf(filename string) (Format, error) {
+	format := Format(strings.ToLower(strings.TrimPrefix(filepath.Ext(filename), ".")))
+	switch format {
+	case CSV, XLSX:
+		return format, nil
+	default:
+		return "", ErrUnsupportedFormat
+	}
+}
+
+// ContentType 表格格式对应的MIME类型
+func (f Format) ContentType() string {
+	if f == XLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// Read 读取表格的全部行,xlsx只读取第一个工作表
+func Read(r io.Reader, format Format) ([][]string, error) {
+	switch format {
+	case CSV:
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+		reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, utf8BOM)))
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = true
+		return reader.ReadAll()
+	case XLSX:
+		file, err := excelize.OpenReader(r)
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			_ = file.Close()
+		}()
+		return file.GetRows(file.GetSheetName(0))
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+// Write 将全部行写入表格,CSV中可能被当作公式的单元格会添加'前缀
+func Write(w io.Writer, format Format, rows [][]string) error {
+	switch format {
+	case CSV:
+		if _, err := w.Write(utf8BOM); err != nil {
+			return err
+		}
+		writer := csv.NewWriter(w)
+		for _, row := range rows {
+			cells := make([]string, len(row))
+			for i, value := range row {
+				cells[i] = escapeFormula(value)
+			}
+			if err := writer.Write(cells); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	case XLSX:
+		file := excelize.NewFile()
+		defer func() {
+			_ = file.Close()
+		}()
+		stream, err := file.NewStreamWriter(sheetName)
+		if err != nil {
+			return err
+		}
+		for i, row := range rows {
+			cells := make([]any, len(row))
+			for j, value := range row {
+				cells[j] = value
+			}
+			cell, err := excelize.CoordinatesToCellName(1, i+1)
+			if err != nil {
+				return err
+			}
+			if err = stream.SetRow(cell, cells); err != nil {
+				return err
+			}
+		}
+		if err = stream.Flush(); err != nil {
+			return err
+		}
+		return file.Write(w)
+	default:
+		return ErrUnsupportedFormat
+	}
+}
+
+// escapeFormula 为以公式字符开头的单元格添加'前缀,避免CSV注入
+func escapeFormula(value string) string {
+	if value != "" && strings.ContainsRune(formulaPrefixes, rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
@@ -0,0 +1,78 @@
+package spreadsheet
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatOf(t *testing.T) {
+	testCases := []struct {
+		filename string
+		format   Format
+		err      error
+	}{
+		{"users.csv", CSV, nil},
+		{"users.XLSX", XLSX, nil},
+		{"users.xls", "", ErrUnsupportedFormat},
+		{"users", "", ErrUnsupportedFormat},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.filename, func(t *testing.T) {
+			format, err := FormatOf(tc.filename)
+			assert.ErrorIs(t, err, tc.err)
+			assert.Equal(t, tc.format, format)
+		})
+	}
+}
+
+func TestReadWrite(t *testing.T) {
+	rows := [][]string{
+		{"email", "nickname", "department", "roles"},
+		{"a@example.com", "张三", "总部/研发部", "admin,dev"},
+		{"b@example.com", "", "", ""},
+	}
+	for _, format := range []Format{CSV, XLSX} {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, Write(&buf, format, rows))
+			result, err := Read(&buf, format)
+			require.NoError(t, err)
+			require.Len(t, result, len(rows))
+			assert.Equal(t, rows[0], result[0])
+			assert.Equal(t, rows[1], result[1])
+			// xlsx 会省略行尾的空单元格
+			assert.Equal(t, "b@example.com", result[2][0])
+		})
+	}
+}
+
+func TestWrite_CSVFormula(t *testing.T) {
+	rows := [][]string{
+		{"=1+1", "+1", "-1", "@SUM(A1)", "\tcmd", "\rcmd", "a=b", ""},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, CSV, rows))
+	result, err := Read(&buf, CSV)
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"'=1+1", "'+1", "'-1", "'@SUM(A1)", "'\tcmd", "'\rcmd", "a=b", ""},
+	}, result)
+}
+
+func TestRead_CSV(t *testing.T) {
+	t.Run("strip bom and allow ragged rows", func(t *testing.T) {
+		data := "\xEF\xBB\xBFemail,nickname\na@example.com\n"
+		rows, err := Read(strings.NewReader(data), CSV)
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{{"email", "nickname"}, {"a@example.com"}}, rows)
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		_, err := Read(strings.NewReader(""), "xls")
+		assert.ErrorIs(t, err, ErrUnsupportedFormat)
+	})
+}
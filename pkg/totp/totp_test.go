@@ -0,0 +1,78 @@
+package totp
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rfc6238Secret RFC 6238 附录B中SHA1测试向量使用的密钥 "12345678901234567890"
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestGenerateCode(t *testing.T) {
+	// RFC 6238 附录B的8位验证码取后6位
+	cases := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+	for _, c := range cases {
+		code, err := GenerateCode(rfc6238Secret, Step(time.Unix(c.unix, 0)))
+		require.NoError(t, err)
+		assert.Equal(t, c.code, code, "unix=%d", c.unix)
+	}
+	_, err := GenerateCode("not-base32!", 1)
+	assert.Error(t, err)
+}
+
+func TestValidate(t *testing.T) {
+	secret, err := GenerateSecret()
+	require.NoError(t, err)
+	now := time.Now()
+	code, err := GenerateCode(secret, Step(now))
+	require.NoError(t, err)
+
+	t.Run("current step", func(t *testing.T) {
+		step, ok := Validate(secret, code, now, 1)
+		assert.True(t, ok)
+		assert.Equal(t, Step(now), step)
+	})
+
+	t.Run("clock skew", func(t *testing.T) {
+		_, ok := Validate(secret, code, now.Add(Period*time.Second), 1)
+		assert.True(t, ok)
+		_, ok = Validate(secret, code, now.Add(3*Period*time.Second), 1)
+		assert.False(t, ok)
+	})
+
+	t.Run("invalid code", func(t *testing.T) {
+		_, ok := Validate(secret, "12345", now, 1)
+		assert.False(t, ok)
+		_, ok = Validate(secret, "abcdef", now, 1)
+		assert.False(t, ok)
+	})
+}
+
+func TestURI(t *testing.T) {
+	uri := URI("weaver", "test@example.com", rfc6238Secret)
+	parsed, err := url.Parse(uri)
+	require.NoError(t, err)
+	assert.Equal(t, "otpauth", parsed.Scheme)
+	assert.Equal(t, "totp", parsed.Host)
+	assert.Equal(t, "/weaver:test@example.com", parsed.Path)
+	assert.Equal(t, rfc6238Secret, parsed.Query().Get("secret"))
+	assert.Equal(t, "weaver", parsed.Query().Get("issuer"))
+	qr, err := QRCode(uri, 128)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(qr, "data:image/png;base64,"))
+}
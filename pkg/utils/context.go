@@ -1,6 +1,9 @@
 package utils
 
 import (
+	"context"
+
+	"github.com/supuwoerc/weaver/models"
 	"github.com/supuwoerc/weaver/pkg/constant"
 	"github.com/supuwoerc/weaver/pkg/jwt"
 	"github.com/supuwoerc/weaver/pkg/response"
@@ -20,3 +23,12 @@ func GetContextClaims(ctx *gin.Context) (*jwt.TokenClaims, error) {
 	}
 	return nil, response.UserNotExist
 }
+
+// GetContextDataScope 解析上下文中调用者的数据权限范围,上下文中没有登录用户时返回nil,表示不限制范围
+func GetContextDataScope(ctx context.Context) (*models.DataScope, error) {
+	loader, ok := ctx.Value(constant.DataScopeContextKey).(models.DataScopeLoader)
+	if !ok || loader == nil {
+		return nil, nil
+	}
+	return loader()
+}
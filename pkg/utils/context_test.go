@@ -1,8 +1,10 @@
 package utils
 
 import (
+	"errors"
 	"testing"
 
+	"github.com/supuwoerc/weaver/models"
 	"github.com/supuwoerc/weaver/pkg/constant"
 	"github.com/supuwoerc/weaver/pkg/jwt"
 	"github.com/supuwoerc/weaver/pkg/response"
@@ -82,3 +84,54 @@ func TestGetContextClaims(t *testing.T) {
 		})
 	}
 }
+
+func TestGetContextDataScope(t *testing.T) {
+	emptyContext, _ := gin.CreateTestContext(nil)
+	scope := &models.DataScope{UserID: 100, Self: true, DepartmentIDs: []uint{1, 2}}
+	contextWithScope, _ := gin.CreateTestContext(nil)
+	contextWithScope.Set(constant.DataScopeContextKey, models.DataScopeLoader(func() (*models.DataScope, error) {
+		return scope, nil
+	}))
+	loadErr := errors.New("load data scope fail")
+	contextWithErr, _ := gin.CreateTestContext(nil)
+	contextWithErr.Set(constant.DataScopeContextKey, models.DataScopeLoader(func() (*models.DataScope, error) {
+		return nil, loadErr
+	}))
+	tests := []struct {
+		name    string
+		ctx     *gin.Context
+		want    *models.DataScope
+		limited bool
+		err     error
+	}{
+		{
+			name:    "EmptyContext",
+			ctx:     emptyContext,
+			want:    nil,
+			limited: false,
+			err:     nil,
+		},
+		{
+			name:    "ValidScope",
+			ctx:     contextWithScope,
+			want:    scope,
+			limited: true,
+			err:     nil,
+		},
+		{
+			name:    "LoadFail",
+			ctx:     contextWithErr,
+			want:    nil,
+			limited: false,
+			err:     loadErr,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetContextDataScope(tt.ctx)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.limited, got.Limited())
+		})
+	}
+}
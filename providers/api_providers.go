@@ -14,6 +14,8 @@ import (
 	"github.com/supuwoerc/weaver/initialize"
 	"github.com/supuwoerc/weaver/middleware"
 	"github.com/supuwoerc/weaver/pkg/captcha"
+	"github.com/supuwoerc/weaver/pkg/idp"
+	"github.com/supuwoerc/weaver/pkg/job"
 	"github.com/supuwoerc/weaver/repository/cache"
 	"github.com/supuwoerc/weaver/repository/dao"
 	"github.com/supuwoerc/weaver/service"
@@ -38,21 +40,30 @@ var basicServiceProvider = wire.NewSet(
 	service.NewBasicService,
 )
 
+var attachmentDAOProvider = wire.NewSet(
+	wire.Bind(new(attachment.DAO), new(*dao.AttachmentDAO)),
+	wire.Bind(new(user.AttachmentDAO), new(*dao.AttachmentDAO)),
+	dao.NewAttachmentDAO,
+)
+
 var attachmentApiProvider = wire.NewSet(
 	wire.Bind(new(attachmentApi.Service), new(*attachment.Service)),
-	wire.Bind(new(attachment.DAO), new(*dao.AttachmentDAO)),
 	wire.Bind(new(attachment.Storage), new(*initialize.S3CompatibleStorage)),
 	initialize.NewS3CompatibleStorage,
-	dao.NewAttachmentDAO,
+	attachmentDAOProvider,
 	attachment.NewAttachmentService,
 	attachmentApi.NewAttachmentApi,
 )
 
-var captchaApiProvider = wire.NewSet(
-	wire.Bind(new(captchaApi.Service), new(*captchaService.Service)),
+var captchaServiceProvider = wire.NewSet(
 	wire.Bind(new(base64Captcha.Store), new(*captcha.RedisStore)),
 	captcha.NewRedisStore,
 	captchaService.NewCaptchaService,
+)
+
+var captchaApiProvider = wire.NewSet(
+	wire.Bind(new(captchaApi.Service), new(*captchaService.Service)),
+	captchaServiceProvider,
 	captchaApi.NewCaptchaApi,
 )
 
@@ -61,6 +72,9 @@ var departmentServiceProvider = wire.NewSet(
 	wire.Bind(new(departmentApi.Service), new(*department.Service)),
 	wire.Bind(new(department.DAO), new(*dao.DepartmentDAO)),
 	wire.Bind(new(department.Cache), new(*cache.DepartmentCache)),
+	wire.Bind(new(user.DepartmentDAO), new(*dao.DepartmentDAO)),
+	wire.Bind(new(user.DepartmentCache), new(*cache.DepartmentCache)),
+	wire.Bind(new(role.DepartmentDAO), new(*dao.DepartmentDAO)),
 	dao.NewDepartmentDAO,
 	cache.NewDepartmentCache,
 	department.NewDepartmentService,
@@ -74,13 +88,14 @@ var departmentApiProvider = wire.NewSet(
 var roleDAOProvider = wire.NewSet(
 	wire.Bind(new(permission.RoleDAO), new(*dao.RoleDAO)),
 	wire.Bind(new(role.DAO), new(*dao.RoleDAO)),
+	wire.Bind(new(user.RoleDAO), new(*dao.RoleDAO)),
 	dao.NewRoleDAO,
 )
 
 var permissionDAOProvider = wire.NewSet(
 	wire.Bind(new(permission.DAO), new(*dao.PermissionDAO)),
 	wire.Bind(new(role.PermissionDAO), new(*dao.PermissionDAO)),
-	wire.Bind(new(middleware.AuthMiddlewarePermissionRepo), new(*dao.PermissionDAO)),
+	wire.Bind(new(user.PermissionDAO), new(*dao.PermissionDAO)),
 	dao.NewPermissionDAO,
 )
 var rolePermissionDAOProvider = wire.NewSet(
@@ -90,6 +105,8 @@ var rolePermissionDAOProvider = wire.NewSet(
 var permissionServiceProvider = wire.NewSet(
 	wire.Bind(new(PermissionCache), new(*permission.Service)),
 	wire.Bind(new(permissionApi.Service), new(*permission.Service)),
+	wire.Bind(new(middleware.AuthMiddlewarePermissionRepo), new(*permission.Service)),
+	wire.Bind(new(user.PermissionService), new(*permission.Service)),
 	permissionDAOProvider,
 	permission.NewPermissionService,
 )
@@ -107,17 +124,42 @@ var pingApiProvider = wire.NewSet(
 
 var roleApiProvider = wire.NewSet(
 	wire.Bind(new(roleApi.Service), new(*role.Service)),
+	wire.Bind(new(middleware.AuthMiddlewareDataScopeRepo), new(*role.Service)),
 	roleDAOProvider,
 	rolePermissionDAOProvider,
 	role.NewRoleService,
 	roleApi.NewRoleApi,
 )
 
-var userApiProvider = wire.NewSet(
-	wire.Bind(new(userApi.Service), new(*user.Service)),
+var userServiceProvider = wire.NewSet(
 	wire.Bind(new(user.DAO), new(*dao.UserDAO)),
+	wire.Bind(new(permission.UserDAO), new(*dao.UserDAO)),
+	wire.Bind(new(user.MFADAO), new(*dao.UserMFADAO)),
+	wire.Bind(new(user.IdentityDAO), new(*dao.UserIdentityDAO)),
+	wire.Bind(new(user.APIKeyDAO), new(*dao.UserAPIKeyDAO)),
+	wire.Bind(new(user.ImpersonationDAO), new(*dao.UserImpersonationDAO)),
+	wire.Bind(new(user.InvitationDAO), new(*dao.UserInvitationDAO)),
+	wire.Bind(new(user.PasswordHistoryDAO), new(*dao.UserPasswordHistoryDAO)),
+	wire.Bind(new(user.LoginHistoryDAO), new(*dao.UserLoginHistoryDAO)),
+	wire.Bind(new(user.SMSClient), new(*initialize.SMSClient)),
+	wire.Bind(new(middleware.AuthMiddlewareAPIKeyRepo), new(*dao.UserAPIKeyDAO)),
+	wire.Bind(new(job.LoginHistoryFlusher), new(*user.Service)),
 	dao.NewUserDAO,
+	dao.NewUserMFADAO,
+	dao.NewUserIdentityDAO,
+	dao.NewUserAPIKeyDAO,
+	dao.NewUserImpersonationDAO,
+	dao.NewUserInvitationDAO,
+	dao.NewUserPasswordHistoryDAO,
+	dao.NewUserLoginHistoryDAO,
+	idp.NewManager,
+	initialize.NewSMSClient,
 	user.NewUserService,
+)
+
+var userApiProvider = wire.NewSet(
+	wire.Bind(new(userApi.Service), new(*user.Service)),
+	userServiceProvider,
 	userApi.NewUserApi,
 )
 
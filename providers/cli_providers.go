@@ -0,0 +1,20 @@
+package providers
+
+import (
+	"github.com/google/wire"
+	"github.com/supuwoerc/weaver/pkg/jwt"
+)
+
+// CliProvider 命令行脚本依赖的服务
+var CliProvider = wire.NewSet(
+	basicServiceProvider,
+	basicDAOProvider,
+	attachmentDAOProvider,
+	captchaServiceProvider,
+	departmentServiceProvider,
+	permissionServiceProvider,
+	rolePermissionDAOProvider,
+	roleDAOProvider,
+	userServiceProvider,
+	jwt.NewJwtBuilder,
+)
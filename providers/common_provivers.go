@@ -3,18 +3,27 @@ package providers
 import (
 	"github.com/google/wire"
 	"github.com/supuwoerc/weaver/middleware"
-	"github.com/supuwoerc/weaver/pkg/jwt"
 	"github.com/supuwoerc/weaver/repository/cache"
+	"github.com/supuwoerc/weaver/service/permission"
+	"github.com/supuwoerc/weaver/service/role"
 	"github.com/supuwoerc/weaver/service/user"
 )
 
 var userCacheProvider = wire.NewSet(
 	wire.Bind(new(middleware.AuthMiddlewareTokenRepo), new(*cache.UserCache)),
-	wire.Bind(new(jwt.TokenBuilderRepo), new(*cache.UserCache)),
 	wire.Bind(new(user.Cache), new(*cache.UserCache)),
+	wire.Bind(new(role.TokenCache), new(*cache.UserCache)),
 	cache.NewUserCache,
 )
 
+var permissionCacheProvider = wire.NewSet(
+	wire.Bind(new(permission.Cache), new(*cache.PermissionCache)),
+	wire.Bind(new(role.PermissionCache), new(*cache.PermissionCache)),
+	wire.Bind(new(user.PermissionCache), new(*cache.PermissionCache)),
+	cache.NewPermissionCache,
+)
+
 var CommonProvider = wire.NewSet(
 	userCacheProvider,
+	permissionCacheProvider,
 )
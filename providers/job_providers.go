@@ -5,12 +5,15 @@ import (
 
 	"github.com/google/wire"
 	"github.com/supuwoerc/weaver/pkg/job"
+	"github.com/supuwoerc/weaver/pkg/jwt"
 	"github.com/supuwoerc/weaver/pkg/logger"
 )
 
-func SystemJobs(logger *logger.Logger) []job.SystemJob {
+func SystemJobs(logger *logger.Logger, tb *jwt.TokenBuilder, historyFlusher job.LoginHistoryFlusher) []job.SystemJob {
 	return []job.SystemJob{
 		job.NewServerStatus(10*time.Second, logger),
+		job.NewJWTKeyRotation(tb, logger),
+		job.NewLoginHistoryFlush(historyFlusher, logger),
 	}
 }
 
@@ -0,0 +1,331 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/supuwoerc/weaver/conf"
+	"github.com/supuwoerc/weaver/models"
+	"github.com/supuwoerc/weaver/pkg/constant"
+	"github.com/supuwoerc/weaver/pkg/redis"
+
+	lru "github.com/hashicorp/golang-lru"
+	goredislib "github.com/redis/go-redis/v9"
+	"github.com/samber/lo"
+)
+
+// 未配置时使用的默认缓存时长
+const (
+	defaultPermissionSetExpiration      = 30 * time.Minute
+	defaultLocalPermissionSetExpiration = time.Minute
+	invalidateAllPermissions            = "*"
+)
+
+// getPermissionSetScript 读取版本号及该版本下缓存的数据,返回 {版本号, 数据(不存在时为nil)};
+// 传入KEYS[3]时版本号由全局版本号和用户版本号组成
+var getPermissionSetScript = goredislib.NewScript(`
+local version = redis.call('GET', KEYS[1]) or '0'
+if KEYS[3] then
+	version = version .. ':' .. (redis.call('GET', KEYS[3]) or '0')
+end
+return {version, redis.call('HGET', KEYS[2], version)}
+`)
+
+// cachePermissionSetScript 版本号在编译期间未变化时缓存数据,返回 1:缓存成功 0:版本号已变化
+var cachePermissionSetScript = goredislib.NewScript(`
+local version = redis.call('GET', KEYS[1]) or '0'
+if KEYS[3] then
+	version = version .. ':' .. (redis.call('GET', KEYS[3]) or '0')
+end
+if version ~= ARGV[1] then
+	return 0
+end
+redis.call('DEL', KEYS[2])
+redis.call('HSET', KEYS[2], ARGV[1], ARGV[2])
+redis.call('PEXPIRE', KEYS[2], ARGV[3])
+return 1
+`)
+
+// invalidatePermissionSetScript 递增用户版本号并删除缓存的集合,KEYS按 {用户版本号, 集合} 成对传入;
+// 用户版本号的过期时间与集合一致,失效前开始编译的集合无法再写入
+var invalidatePermissionSetScript = goredislib.NewScript(`
+for i = 1, #KEYS, 2 do
+	redis.call('INCR', KEYS[i])
+	redis.call('PEXPIRE', KEYS[i], ARGV[1])
+	redis.call('DEL', KEYS[i + 1])
+end
+return 1
+`)
+
+type localPermissionSet struct {
+	set       *models.PermissionSet
+	expiredAt time.Time
+}
+
+type localRouteCatalog struct {
+	catalog   []models.RoutePattern
+	expiredAt time.Time
+}
+
+// PermissionCache 用户权限集合及全部API权限的缓存,权限或角色变更时递增全局版本号使全部缓存失效,用户角色变更时递增该用户的版本号;
+// 启用进程内缓存时通过redis频道广播失效通知
+type PermissionCache struct {
+	redis           *redis.CommonRedisClient
+	expiration      time.Duration
+	local           *lru.Cache
+	localCatalog    atomic.Pointer[localRouteCatalog]
+	localExpiration time.Duration
+	mu              sync.Mutex
+	pubsub          *goredislib.PubSub
+}
+
+func NewPermissionCache(r *redis.CommonRedisClient, conf *conf.Config) *PermissionCache {
+	c := &PermissionCache{
+		redis:           r,
+		expiration:      lo.CoalesceOrEmpty(conf.Permission.CacheExpiration*time.Second, defaultPermissionSetExpiration),
+		localExpiration: lo.CoalesceOrEmpty(conf.Permission.LocalCacheExpiration*time.Second, defaultLocalPermissionSetExpiration),
+	}
+	if conf.Permission.LocalCacheSize > 0 {
+		// 容量大于0时不会返回错误
+		c.local, _ = lru.New(conf.Permission.LocalCacheSize)
+	}
+	return c
+}
+
+func (p *PermissionCache) permissionSetKey(uid uint) string {
+	return fmt.Sprintf("%s%d", constant.PermissionSetPrefix, uid)
+}
+
+func (p *PermissionCache) permissionVersionKey(uid uint) string {
+	return fmt.Sprintf("%s%d", constant.PermissionVersionPrefix, uid)
+}
+
+// getVersioned 读取当前的版本号及该版本下缓存的数据,未缓存时ok为false;versionKey为用户版本号,为空时仅使用全局版本号
+func (p *PermissionCache) getVersioned(ctx context.Context, key, versionKey string, value any) (string, bool, error) {
+	result, err := getPermissionSetScript.Run(ctx, p.redis.Client, p.versionedKeys(key, versionKey)).Slice()
+	if err != nil {
+		return "", false, err
+	}
+	version, _ := result[0].(string)
+	data, ok := result[1].(string)
+	if !ok {
+		return version, false, nil
+	}
+	if err = json.Unmarshal([]byte(data), value); err != nil {
+		return version, false, err
+	}
+	return version, true, nil
+}
+
+func (p *PermissionCache) versionedKeys(key, versionKey string) []string {
+	keys := []string{string(constant.PermissionSetVersionKey), key}
+	if versionKey != "" {
+		keys = append(keys, versionKey)
+	}
+	return keys
+}
+
+// cacheVersioned 版本号未变化时缓存数据,返回是否缓存成功
+func (p *PermissionCache) cacheVersioned(ctx context.Context, key, versionKey, version string, value any) (bool, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return false, err
+	}
+	result, err := cachePermissionSetScript.Run(ctx, p.redis.Client,
+		p.versionedKeys(key, versionKey), version, data, p.expiration.Milliseconds()).Int()
+	if err != nil {
+		return false, err
+	}
+	return result == 1, nil
+}
+
+// GetPermissionSet 获取用户的权限集合及当前的版本号,未缓存时返回的集合为nil,
+// 编译后的集合需要使用该版本号写入,避免覆盖编译期间发生的失效
+func (p *PermissionCache) GetPermissionSet(ctx context.Context, uid uint) (*models.PermissionSet, string, error) {
+	if p.local != nil {
+		if value, ok := p.local.Get(uid); ok {
+			entry := value.(*localPermissionSet)
+			if time.Now().Before(entry.expiredAt) {
+				return entry.set, "", nil
+			}
+			p.local.Remove(uid)
+		}
+	}
+	var set models.PermissionSet
+	version, ok, err := p.getVersioned(ctx, p.permissionSetKey(uid), p.permissionVersionKey(uid), &set)
+	if err != nil || !ok {
+		return nil, version, err
+	}
+	p.cacheLocal(uid, &set)
+	return &set, version, nil
+}
+
+// CachePermissionSet 缓存用户的权限集合,version为编译前获取的版本号,全局或用户的版本号已变化时放弃缓存
+func (p *PermissionCache) CachePermissionSet(ctx context.Context, uid uint, version string, set *models.PermissionSet) error {
+	ok, err := p.cacheVersioned(ctx, p.permissionSetKey(uid), p.permissionVersionKey(uid), version, set)
+	if err != nil {
+		return err
+	}
+	if ok {
+		p.cacheLocal(uid, set)
+	}
+	return nil
+}
+
+// GetRouteCatalog 获取全部API权限的请求方法和路由模板及当前的全局版本号,未缓存时返回的列表为nil
+func (p *PermissionCache) GetRouteCatalog(ctx context.Context) ([]models.RoutePattern, string, error) {
+	if entry := p.localCatalog.Load(); entry != nil && time.Now().Before(entry.expiredAt) {
+		return entry.catalog, "", nil
+	}
+	catalog := make([]models.RoutePattern, 0)
+	version, ok, err := p.getVersioned(ctx, string(constant.PermissionRouteCatalogKey), "", &catalog)
+	if err != nil || !ok {
+		return nil, version, err
+	}
+	p.cacheLocalCatalog(catalog)
+	return catalog, version, nil
+}
+
+// CacheRouteCatalog 缓存全部API权限的请求方法和路由模板,version为查询前获取的全局版本号,版本号已变化时放弃缓存
+func (p *PermissionCache) CacheRouteCatalog(ctx context.Context, version string, catalog []models.RoutePattern) error {
+	ok, err := p.cacheVersioned(ctx, string(constant.PermissionRouteCatalogKey), "", version, catalog)
+	if err != nil {
+		return err
+	}
+	if ok {
+		p.cacheLocalCatalog(catalog)
+	}
+	return nil
+}
+
+func (p *PermissionCache) cacheLocalCatalog(catalog []models.RoutePattern) {
+	if p.local == nil {
+		return
+	}
+	p.localCatalog.Store(&localRouteCatalog{
+		catalog:   catalog,
+		expiredAt: time.Now().Add(p.localExpiration),
+	})
+}
+
+func (p *PermissionCache) cacheLocal(uid uint, set *models.PermissionSet) {
+	if p.local == nil {
+		return
+	}
+	p.local.Add(uid, &localPermissionSet{
+		set:       set,
+		expiredAt: time.Now().Add(p.localExpiration),
+	})
+}
+
+// InvalidateUserPermissions 递增指定用户的版本号使其权限集合失效,失效前开始编译的集合不会被写回
+func (p *PermissionCache) InvalidateUserPermissions(ctx context.Context, uids ...uint) error {
+	if len(uids) == 0 {
+		return nil
+	}
+	keys := lo.FlatMap(uids, func(item uint, _ int) []string {
+		return []string{p.permissionVersionKey(item), p.permissionSetKey(item)}
+	})
+	err := invalidatePermissionSetScript.Run(ctx, p.redis.Client, keys, p.expiration.Milliseconds()).Err()
+	if err != nil {
+		return err
+	}
+	p.removeLocal(uids...)
+	return p.publish(ctx, strings.Join(lo.Map(uids, func(item uint, _ int) string {
+		return strconv.Itoa(int(item))
+	}), ","))
+}
+
+// InvalidateAllPermissions 递增全局版本号使全部用户的权限集合失效,旧版本的集合随过期时间清除
+func (p *PermissionCache) InvalidateAllPermissions(ctx context.Context) error {
+	if err := p.redis.Client.Incr(ctx, string(constant.PermissionSetVersionKey)).Err(); err != nil {
+		return err
+	}
+	p.purgeLocal()
+	return p.publish(ctx, invalidateAllPermissions)
+}
+
+func (p *PermissionCache) publish(ctx context.Context, message string) error {
+	if p.local == nil {
+		return nil
+	}
+	return p.redis.Client.Publish(ctx, string(constant.PermissionInvalidateChannel), message).Err()
+}
+
+func (p *PermissionCache) removeLocal(uids ...uint) {
+	if p.local == nil {
+		return
+	}
+	for _, uid := range uids {
+		p.local.Remove(uid)
+	}
+}
+
+func (p *PermissionCache) purgeLocal() {
+	if p.local != nil {
+		p.local.Purge()
+		p.localCatalog.Store(nil)
+	}
+}
+
+// Subscribe 订阅其他实例的失效通知并清除对应的进程内缓存,未启用进程内缓存时不订阅
+func (p *PermissionCache) Subscribe(ctx context.Context) error {
+	if p.local == nil {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.pubsub != nil {
+		return nil
+	}
+	pubsub := p.redis.Client.Subscribe(ctx, string(constant.PermissionInvalidateChannel))
+	// 等待订阅生效,避免订阅前的失效通知丢失
+	if _, err := pubsub.Receive(ctx); err != nil {
+		_ = pubsub.Close()
+		return err
+	}
+	p.pubsub = pubsub
+	go func(ch <-chan *goredislib.Message) {
+		for message := range ch {
+			p.handleInvalidation(message.Payload)
+		}
+	}(pubsub.Channel())
+	return nil
+}
+
+func (p *PermissionCache) handleInvalidation(payload string) {
+	if payload == invalidateAllPermissions {
+		p.purgeLocal()
+		return
+	}
+	for _, item := range strings.Split(payload, ",") {
+		uid, err := strconv.Atoi(item)
+		if err != nil {
+			continue
+		}
+		p.removeLocal(uint(uid))
+	}
+}
+
+// Unsubscribe 取消订阅失效通知并清空进程内缓存
+func (p *PermissionCache) Unsubscribe() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.purgeLocal()
+	if p.pubsub == nil {
+		return nil
+	}
+	err := p.pubsub.Close()
+	p.pubsub = nil
+	if err != nil && !errors.Is(err, goredislib.ErrClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	goredislib "github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/supuwoerc/weaver/conf"
+	"github.com/supuwoerc/weaver/models"
+	"github.com/supuwoerc/weaver/pkg/constant"
+	"github.com/supuwoerc/weaver/pkg/redis"
+)
+
+func newTestPermissionCache(t *testing.T, mr *miniredis.Miniredis, localSize int) *PermissionCache {
+	client := goredislib.NewClient(&goredislib.Options{Addr: mr.Addr()})
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+	return NewPermissionCache(redis.NewCommonRedisClient(client, nil), &conf.Config{
+		Permission: conf.PermissionConfig{LocalCacheSize: localSize},
+	})
+}
+
+func TestPermissionCache_PermissionSet(t *testing.T) {
+	ctx := context.Background()
+	set := models.NewPermissionSet([]*models.Permission{{Resource: "/api/v1/user/list", Type: constant.ApiRoute, Method: "GET"}})
+
+	t.Run("cache and invalidate user permission set", func(t *testing.T) {
+		mr := miniredis.RunT(t)
+		permissionCache := newTestPermissionCache(t, mr, 0)
+		got, version, err := permissionCache.GetPermissionSet(ctx, 1)
+		require.NoError(t, err)
+		assert.Nil(t, got)
+		require.NoError(t, permissionCache.CachePermissionSet(ctx, 1, version, set))
+		got, _, err = permissionCache.GetPermissionSet(ctx, 1)
+		require.NoError(t, err)
+		assert.True(t, got.HasRoute(models.RoutePattern{Method: "GET", Pattern: "/api/v1/user/list"}))
+		require.NoError(t, permissionCache.InvalidateUserPermissions(ctx, 1))
+		got, _, err = permissionCache.GetPermissionSet(ctx, 1)
+		require.NoError(t, err)
+		assert.Nil(t, got)
+	})
+
+	t.Run("invalidate all permission sets", func(t *testing.T) {
+		mr := miniredis.RunT(t)
+		permissionCache := newTestPermissionCache(t, mr, 0)
+		_, version, err := permissionCache.GetPermissionSet(ctx, 1)
+		require.NoError(t, err)
+		require.NoError(t, permissionCache.CachePermissionSet(ctx, 1, version, set))
+		require.NoError(t, permissionCache.InvalidateAllPermissions(ctx))
+		got, _, err := permissionCache.GetPermissionSet(ctx, 1)
+		require.NoError(t, err)
+		assert.Nil(t, got)
+	})
+
+	t.Run("set compiled before invalidation is discarded", func(t *testing.T) {
+		mr := miniredis.RunT(t)
+		permissionCache := newTestPermissionCache(t, mr, 0)
+		_, version, err := permissionCache.GetPermissionSet(ctx, 1)
+		require.NoError(t, err)
+		require.NoError(t, permissionCache.InvalidateAllPermissions(ctx))
+		require.NoError(t, permissionCache.CachePermissionSet(ctx, 1, version, set))
+		got, _, err := permissionCache.GetPermissionSet(ctx, 1)
+		require.NoError(t, err)
+		assert.Nil(t, got)
+	})
+
+	t.Run("set compiled before user invalidation is discarded", func(t *testing.T) {
+		mr := miniredis.RunT(t)
+		permissionCache := newTestPermissionCache(t, mr, 0)
+		_, version, err := permissionCache.GetPermissionSet(ctx, 1)
+		require.NoError(t, err)
+		_, otherVersion, err := permissionCache.GetPermissionSet(ctx, 2)
+		require.NoError(t, err)
+		require.NoError(t, permissionCache.InvalidateUserPermissions(ctx, 1))
+		require.NoError(t, permissionCache.CachePermissionSet(ctx, 1, version, set))
+		got, _, err := permissionCache.GetPermissionSet(ctx, 1)
+		require.NoError(t, err)
+		assert.Nil(t, got)
+		// 其他用户不受影响
+		require.NoError(t, permissionCache.CachePermissionSet(ctx, 2, otherVersion, set))
+		got, _, err = permissionCache.GetPermissionSet(ctx, 2)
+		require.NoError(t, err)
+		assert.NotNil(t, got)
+		// 版本号与集合同时过期
+		assert.Positive(t, mr.TTL(string(constant.PermissionVersionPrefix)+"1"))
+	})
+}
+
+func TestPermissionCache_RouteCatalog(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	permissionCache := newTestPermissionCache(t, mr, 0)
+	catalog, version, err := permissionCache.GetRouteCatalog(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, catalog)
+	require.NoError(t, permissionCache.CacheRouteCatalog(ctx, version, []models.RoutePattern{}))
+	catalog, _, err = permissionCache.GetRouteCatalog(ctx)
+	require.NoError(t, err)
+	assert.NotNil(t, catalog)
+	assert.Empty(t, catalog)
+
+	patterns := []models.RoutePattern{{Method: "*", Pattern: "/api/v1/role/*"}}
+	require.NoError(t, permissionCache.InvalidateAllPermissions(ctx))
+	catalog, version, err = permissionCache.GetRouteCatalog(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, catalog)
+	require.NoError(t, permissionCache.CacheRouteCatalog(ctx, version, patterns))
+	catalog, _, err = permissionCache.GetRouteCatalog(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, patterns, catalog)
+}
+
+func TestPermissionCache_LocalCache(t *testing.T) {
+	ctx := context.Background()
+	set := models.NewPermissionSet([]*models.Permission{{Resource: "/api/v1/user/list", Type: constant.ApiRoute, Method: "GET"}})
+	mr := miniredis.RunT(t)
+	local := newTestPermissionCache(t, mr, 10)
+	remote := newTestPermissionCache(t, mr, 10)
+	require.NoError(t, local.Subscribe(ctx))
+	t.Cleanup(func() {
+		_ = local.Unsubscribe()
+	})
+
+	_, version, err := local.GetPermissionSet(ctx, 1)
+	require.NoError(t, err)
+	require.NoError(t, local.CachePermissionSet(ctx, 1, version, set))
+	_, ok := local.local.Get(uint(1))
+	assert.True(t, ok)
+
+	// 其他实例的失效通知清除进程内缓存
+	require.NoError(t, remote.InvalidateUserPermissions(ctx, 1))
+	assert.Eventually(t, func() bool {
+		return !local.local.Contains(uint(1))
+	}, time.Second, 10*time.Millisecond)
+
+	_, version, err = local.GetPermissionSet(ctx, 2)
+	require.NoError(t, err)
+	require.NoError(t, local.CachePermissionSet(ctx, 2, version, set))
+	require.NoError(t, remote.InvalidateAllPermissions(ctx))
+	assert.Eventually(t, func() bool {
+		return local.local.Len() == 0
+	}, time.Second, 10*time.Millisecond)
+}
@@ -2,13 +2,76 @@ package cache
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/supuwoerc/weaver/models"
 	"github.com/supuwoerc/weaver/pkg/constant"
+	"github.com/supuwoerc/weaver/pkg/database"
 	"github.com/supuwoerc/weaver/pkg/redis"
+	"github.com/supuwoerc/weaver/pkg/response"
+
+	goredislib "github.com/redis/go-redis/v9"
+)
+
+const (
+	sessionUIDField       = "uid"
+	sessionDeviceField    = "device"
+	sessionIPField        = "ip"
+	sessionUserAgentField = "user_agent"
+	sessionCreatedAtField = "created_at"
+	sessionLastSeenField  = "last_seen"
+	sessionTokenField     = "token"
 )
 
+// rotateRefreshTokenScript 比较并替换会话中当前有效的 refresh token,同时刷新会话的客户端信息
+// 返回 1:轮换成功 0:会话不存在 -1:旧 token 被重复使用(已注销整个会话)
+var rotateRefreshTokenScript = goredislib.NewScript(`
+local current = redis.call('HGET', KEYS[1], 'token')
+if not current then
+	return 0
+end
+if current ~= ARGV[1] then
+	redis.call('DEL', KEYS[1])
+	redis.call('SREM', KEYS[2], ARGV[7])
+	return -1
+end
+redis.call('HSET', KEYS[1], 'token', ARGV[2], 'ip', ARGV[3], 'user_agent', ARGV[4], 'last_seen', ARGV[5])
+redis.call('PEXPIRE', KEYS[1], ARGV[6])
+redis.call('PEXPIRE', KEYS[2], ARGV[6])
+return 1
+`)
+
+// touchSessionScript 会话存在时更新最近活跃时间,返回 1:会话有效 0:会话不存在
+var touchSessionScript = goredislib.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return 0
+end
+redis.call('HSET', KEYS[1], 'last_seen', ARGV[1])
+return 1
+`)
+
+// verifySMSCodeScript 校验短信验证码,校验成功后删除验证码,错误次数达到上限时同样删除,返回 1:校验成功 0:校验失败
+var verifySMSCodeScript = goredislib.NewScript(`
+local code = redis.call('HGET', KEYS[1], 'code')
+if not code then
+	return 0
+end
+if code == ARGV[1] then
+	redis.call('DEL', KEYS[1])
+	return 1
+end
+if redis.call('HINCRBY', KEYS[1], 'attempts', 1) >= tonumber(ARGV[2]) then
+	redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
 type UserCache struct {
 	redis *redis.CommonRedisClient
 }
@@ -17,27 +80,214 @@ func NewUserCache(r *redis.CommonRedisClient) *UserCache {
 	return &UserCache{redis: r}
 }
 
-func (u *UserCache) refreshTokenCacheKey(email string) string {
-	return fmt.Sprintf("%s:%s", constant.UserRefreshTokenKey, email)
+func (u *UserCache) sessionKey(sid string) string {
+	return fmt.Sprintf("%s:%s", constant.UserSessionKey, sid)
 }
 
-// CacheRefreshToken 存储用户的refreshToken
-func (u *UserCache) CacheRefreshToken(ctx context.Context, email, refreshToken string, expiration time.Duration) error {
-	return u.redis.Client.Set(ctx, u.refreshTokenCacheKey(email), refreshToken, expiration).Err()
+func (u *UserCache) sessionsKey(uid uint) string {
+	return fmt.Sprintf("%s:%d", constant.UserSessionsKey, uid)
 }
 
-// DeleteRefreshToken 删除用户的refreshToken
-func (u *UserCache) DeleteRefreshToken(ctx context.Context, email string) error {
-	return u.redis.Client.Del(ctx, u.refreshTokenCacheKey(email)).Err()
+// CreateSession 创建登录会话并记录当前有效的 refreshToken,同一用户的其他会话不受影响
+func (u *UserCache) CreateSession(ctx context.Context, session *models.UserSession, tokenID string, expiration time.Duration) error {
+	sessionKey := u.sessionKey(session.ID)
+	sessionsKey := u.sessionsKey(session.UID)
+	_, err := u.redis.Client.TxPipelined(ctx, func(pipe goredislib.Pipeliner) error {
+		pipe.HSet(ctx, sessionKey,
+			sessionUIDField, session.UID,
+			sessionDeviceField, session.Device,
+			sessionIPField, session.IP,
+			sessionUserAgentField, session.UserAgent,
+			sessionCreatedAtField, session.CreatedAt.UnixMilli(),
+			sessionLastSeenField, session.LastSeen.UnixMilli(),
+			sessionTokenField, tokenID,
+		)
+		pipe.Expire(ctx, sessionKey, expiration)
+		pipe.SAdd(ctx, sessionsKey, session.ID)
+		pipe.Expire(ctx, sessionsKey, expiration)
+		return nil
+	})
+	return err
 }
 
-// GetRefreshToken 获取用户的refreshToken
-func (u *UserCache) GetRefreshToken(ctx context.Context, email string) (string, error) {
-	refreshToken, err := u.redis.Client.Get(ctx, u.refreshTokenCacheKey(email)).Result()
+// RotateRefreshToken 轮换会话中的 refreshToken,旧 token 被重复使用时注销整个会话
+func (u *UserCache) RotateRefreshToken(
+	ctx context.Context, uid uint, sid, oldTokenID, newTokenID string, client *models.LoginClient, expiration time.Duration,
+) error {
+	result, err := rotateRefreshTokenScript.Run(ctx, u.redis.Client, []string{u.sessionKey(sid), u.sessionsKey(uid)},
+		oldTokenID, newTokenID, client.IP, client.UserAgent, time.Now().UnixMilli(), expiration.Milliseconds(), sid).Int()
 	if err != nil {
-		return "", err
+		return err
+	}
+	switch result {
+	case 1:
+		return nil
+	case -1:
+		return response.RefreshTokenReused
+	default:
+		return response.InvalidRefreshToken
+	}
+}
+
+// TouchSession 检查会话是否有效并更新最近活跃时间
+func (u *UserCache) TouchSession(ctx context.Context, sid string) (bool, error) {
+	result, err := touchSessionScript.Run(ctx, u.redis.Client, []string{u.sessionKey(sid)}, time.Now().UnixMilli()).Int()
+	if err != nil {
+		return false, err
+	}
+	return result == 1, nil
+}
+
+// GetUserSessions 获取用户全部有效的登录会话(按最近活跃时间倒序),已过期的会话会从集合中移除
+func (u *UserCache) GetUserSessions(ctx context.Context, uid uint) ([]*models.UserSession, error) {
+	sessionsKey := u.sessionsKey(uid)
+	ids, err := u.redis.Client.SMembers(ctx, sessionsKey).Result()
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+	commands := make([]*goredislib.MapStringStringCmd, 0, len(ids))
+	_, err = u.redis.Client.Pipelined(ctx, func(pipe goredislib.Pipeliner) error {
+		for _, id := range ids {
+			commands = append(commands, pipe.HGetAll(ctx, u.sessionKey(id)))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sessions := make([]*models.UserSession, 0, len(ids))
+	expired := make([]any, 0)
+	for i, command := range commands {
+		values := command.Val()
+		if len(values) == 0 {
+			expired = append(expired, ids[i])
+			continue
+		}
+		sessions = append(sessions, u.parseSession(ids[i], values))
+	}
+	if len(expired) > 0 {
+		if err = u.redis.Client.SRem(ctx, sessionsKey, expired...).Err(); err != nil {
+			return nil, err
+		}
+	}
+	slices.SortFunc(sessions, func(a, b *models.UserSession) int {
+		return b.LastSeen.Compare(a.LastSeen)
+	})
+	return sessions, nil
+}
+
+func (u *UserCache) parseSession(sid string, values map[string]string) *models.UserSession {
+	uid, _ := strconv.ParseUint(values[sessionUIDField], 10, 64)
+	createdAt, _ := strconv.ParseInt(values[sessionCreatedAtField], 10, 64)
+	lastSeen, _ := strconv.ParseInt(values[sessionLastSeenField], 10, 64)
+	return &models.UserSession{
+		LoginClient: models.LoginClient{
+			Device:    values[sessionDeviceField],
+			IP:        values[sessionIPField],
+			UserAgent: values[sessionUserAgentField],
+		},
+		ID:        sid,
+		UID:       uint(uid),
+		CreatedAt: time.UnixMilli(createdAt),
+		LastSeen:  time.UnixMilli(lastSeen),
+	}
+}
+
+// DeleteSessions 注销用户指定的登录会话
+func (u *UserCache) DeleteSessions(ctx context.Context, uid uint, sids ...string) error {
+	if len(sids) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(sids))
+	members := make([]any, 0, len(sids))
+	for _, sid := range sids {
+		keys = append(keys, u.sessionKey(sid))
+		members = append(members, sid)
+	}
+	_, err := u.redis.Client.TxPipelined(ctx, func(pipe goredislib.Pipeliner) error {
+		pipe.Del(ctx, keys...)
+		pipe.SRem(ctx, u.sessionsKey(uid), members...)
+		return nil
+	})
+	return err
+}
+
+// DeleteUserSessions 注销用户全部的登录会话
+func (u *UserCache) DeleteUserSessions(ctx context.Context, uid uint) error {
+	sessionsKey := u.sessionsKey(uid)
+	ids, err := u.redis.Client.SMembers(ctx, sessionsKey).Result()
+	if err != nil {
+		return err
+	}
+	keys := []string{sessionsKey}
+	for _, id := range ids {
+		keys = append(keys, u.sessionKey(id))
+	}
+	return u.redis.Client.Del(ctx, keys...).Err()
+}
+
+func (u *UserCache) accessTokensKey(uid uint) string {
+	return fmt.Sprintf("%s:%d", constant.UserAccessTokensKey, uid)
+}
+
+func (u *UserCache) denylistKey(jti string) string {
+	return fmt.Sprintf("%s:%s", constant.TokenDenylistKey, jti)
+}
+
+// RecordAccessToken 记录用户签发的短token(会话ID:jti),吊销用户全部短token时使用,已过期的记录会被清理
+func (u *UserCache) RecordAccessToken(ctx context.Context, uid uint, sid, jti string, expiresAt time.Time) error {
+	key := u.accessTokensKey(uid)
+	_, err := u.redis.Client.TxPipelined(ctx, func(pipe goredislib.Pipeliner) error {
+		pipe.ZAdd(ctx, key, goredislib.Z{Score: float64(expiresAt.UnixMilli()), Member: sid + ":" + jti})
+		pipe.ZRemRangeByScore(ctx, key, "-inf", strconv.FormatInt(time.Now().UnixMilli(), 10))
+		pipe.PExpireAt(ctx, key, expiresAt)
+		return nil
+	})
+	return err
+}
+
+// DenyAccessToken 将短token加入黑名单,有效期为token的剩余有效期
+func (u *UserCache) DenyAccessToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return nil
 	}
-	return refreshToken, nil
+	return u.redis.Client.Set(ctx, u.denylistKey(jti), 1, ttl).Err()
+}
+
+// DenyUserAccessTokens 将用户全部未过期的短token加入黑名单,excludeSessions 中的会话签发的token除外
+func (u *UserCache) DenyUserAccessTokens(ctx context.Context, uid uint, excludeSessions ...string) error {
+	key := u.accessTokensKey(uid)
+	now := time.Now()
+	tokens, err := u.redis.Client.ZRangeByScoreWithScores(ctx, key, &goredislib.ZRangeBy{
+		Min: "(" + strconv.FormatInt(now.UnixMilli(), 10),
+		Max: "+inf",
+	}).Result()
+	if err != nil || len(tokens) == 0 {
+		return err
+	}
+	_, err = u.redis.Client.TxPipelined(ctx, func(pipe goredislib.Pipeliner) error {
+		for _, token := range tokens {
+			member, _ := token.Member.(string)
+			sid, jti, found := strings.Cut(member, ":")
+			if !found || slices.Contains(excludeSessions, sid) {
+				continue
+			}
+			pipe.Set(ctx, u.denylistKey(jti), 1, time.UnixMilli(int64(token.Score)).Sub(now))
+			pipe.ZRem(ctx, key, member)
+		}
+		return nil
+	})
+	return err
+}
+
+// IsAccessTokenDenied 短token是否已被吊销
+func (u *UserCache) IsAccessTokenDenied(ctx context.Context, jti string) (bool, error) {
+	count, err := u.redis.Client.Exists(ctx, u.denylistKey(jti)).Result()
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
 }
 
 func (u *UserCache) activeAccountKey(id uint) string {
@@ -59,3 +309,321 @@ func (u *UserCache) GetActiveAccountCode(ctx context.Context, id uint) (string,
 func (u *UserCache) RemoveActiveAccountCode(ctx context.Context, id uint) error {
 	return u.redis.Client.Del(ctx, u.activeAccountKey(id)).Err()
 }
+
+func (u *UserCache) resetPasswordKey(id uint) string {
+	return fmt.Sprintf("%s%d", constant.ResetPasswordPrefix, id)
+}
+
+func (u *UserCache) CacheResetPasswordCode(ctx context.Context, id uint, code string, duration time.Duration) error {
+	return u.redis.Client.Set(ctx, u.resetPasswordKey(id), code, duration).Err()
+}
+
+func (u *UserCache) GetResetPasswordCode(ctx context.Context, id uint) (string, error) {
+	result, err := u.redis.Client.Get(ctx, u.resetPasswordKey(id)).Result()
+	if err != nil {
+		return "", err
+	}
+	return result, nil
+}
+
+func (u *UserCache) RemoveResetPasswordCode(ctx context.Context, id uint) error {
+	return u.redis.Client.Del(ctx, u.resetPasswordKey(id)).Err()
+}
+
+// IncrRequestCount 累加固定窗口内的请求次数,窗口从第一次请求开始计算
+func (u *UserCache) IncrRequestCount(ctx context.Context, prefix constant.Prefix, key string, window time.Duration) (int64, error) {
+	cacheKey := fmt.Sprintf("%s%s", prefix, key)
+	var incr *goredislib.IntCmd
+	_, err := u.redis.Client.TxPipelined(ctx, func(pipe goredislib.Pipeliner) error {
+		incr = pipe.Incr(ctx, cacheKey)
+		pipe.ExpireNX(ctx, cacheKey, window)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return incr.Val(), nil
+}
+
+// GetRequestCount 获取固定窗口内的请求次数
+func (u *UserCache) GetRequestCount(ctx context.Context, prefix constant.Prefix, key string) (int64, error) {
+	count, err := u.redis.Client.Get(ctx, fmt.Sprintf("%s%s", prefix, key)).Int64()
+	if errors.Is(err, goredislib.Nil) {
+		return 0, nil
+	}
+	return count, err
+}
+
+// CacheLoginBlock 设置登录限制(等待或锁定),过期前禁止登录
+func (u *UserCache) CacheLoginBlock(ctx context.Context, prefix constant.Prefix, key string, expiration time.Duration) error {
+	return u.redis.Client.Set(ctx, fmt.Sprintf("%s%s", prefix, key), 1, expiration).Err()
+}
+
+// GetLoginBlock 获取登录限制的剩余时长,没有限制时返回0
+func (u *UserCache) GetLoginBlock(ctx context.Context, prefix constant.Prefix, key string) (time.Duration, error) {
+	ttl, err := u.redis.Client.PTTL(ctx, fmt.Sprintf("%s%s", prefix, key)).Result()
+	if err != nil {
+		return 0, err
+	}
+	// key不存在时返回-2,未设置过期时间时返回-1
+	if ttl < 0 {
+		return 0, nil
+	}
+	return ttl, nil
+}
+
+// ClearLoginFailures 清除邮箱的登录失败次数、等待及锁定,ip不为空时同时清除ip的失败次数及锁定
+func (u *UserCache) ClearLoginFailures(ctx context.Context, email string, ip string) error {
+	keys := []string{
+		fmt.Sprintf("%s%s", constant.LoginFailEmailPrefix, email),
+		fmt.Sprintf("%s%s", constant.LoginDelayPrefix, email),
+		fmt.Sprintf("%s%s", constant.LoginLockEmailPrefix, email),
+	}
+	if ip != "" {
+		keys = append(keys,
+			fmt.Sprintf("%s%s", constant.LoginFailIPPrefix, ip),
+			fmt.Sprintf("%s%s", constant.LoginLockIPPrefix, ip),
+		)
+	}
+	return u.redis.Client.Del(ctx, keys...).Err()
+}
+
+func (u *UserCache) mfaTicketKey(ticket string) string {
+	return fmt.Sprintf("%s%s", constant.MFATicketPrefix, ticket)
+}
+
+// CacheMFATicket 缓存两步验证登录凭证及发起登录的客户端信息
+func (u *UserCache) CacheMFATicket(
+	ctx context.Context, ticket string, uid uint, client *models.LoginClient, expiration time.Duration,
+) error {
+	return u.cacheLoginTicket(ctx, u.mfaTicketKey(ticket), uid, client, expiration)
+}
+
+// GetMFATicket 获取两步验证登录凭证对应的用户和客户端信息,凭证不存在时返回 redis.Nil
+func (u *UserCache) GetMFATicket(ctx context.Context, ticket string) (uint, *models.LoginClient, error) {
+	return u.getLoginTicket(ctx, u.mfaTicketKey(ticket))
+}
+
+// cacheLoginTicket 缓存登录凭证对应的用户及发起登录的客户端信息
+func (u *UserCache) cacheLoginTicket(
+	ctx context.Context, key string, uid uint, client *models.LoginClient, expiration time.Duration,
+) error {
+	_, err := u.redis.Client.TxPipelined(ctx, func(pipe goredislib.Pipeliner) error {
+		pipe.HSet(ctx, key,
+			sessionUIDField, uid,
+			sessionDeviceField, client.Device,
+			sessionIPField, client.IP,
+			sessionUserAgentField, client.UserAgent,
+		)
+		pipe.Expire(ctx, key, expiration)
+		return nil
+	})
+	return err
+}
+
+// getLoginTicket 获取登录凭证对应的用户和客户端信息,凭证不存在时返回 redis.Nil
+func (u *UserCache) getLoginTicket(ctx context.Context, key string) (uint, *models.LoginClient, error) {
+	values, err := u.redis.Client.HGetAll(ctx, key).Result()
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(values) == 0 {
+		return 0, nil, goredislib.Nil
+	}
+	uid, err := strconv.ParseUint(values[sessionUIDField], 10, 64)
+	if err != nil {
+		return 0, nil, err
+	}
+	return uint(uid), &models.LoginClient{
+		Device:    values[sessionDeviceField],
+		IP:        values[sessionIPField],
+		UserAgent: values[sessionUserAgentField],
+	}, nil
+}
+
+// ConsumeMFATicket 删除两步验证登录凭证,返回凭证是否由本次调用删除,保证凭证只能使用一次
+func (u *UserCache) ConsumeMFATicket(ctx context.Context, ticket string) (bool, error) {
+	count, err := u.redis.Client.Del(ctx, u.mfaTicketKey(ticket)).Result()
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// loginHistoryEntry 队列中的登录记录,UpsertTime的JSON格式不含时区,登录时间单独保存
+type loginHistoryEntry struct {
+	*models.UserLoginHistory
+	LoginAt time.Time `json:"login_at"`
+}
+
+// PushLoginHistory 登录记录加入待写入队列,由定时任务写入
+func (u *UserCache) PushLoginHistory(ctx context.Context, record *models.UserLoginHistory) error {
+	value, err := json.Marshal(&loginHistoryEntry{
+		UserLoginHistory: record,
+		LoginAt:          time.Time(record.CreatedAt),
+	})
+	if err != nil {
+		return err
+	}
+	return u.redis.Client.RPush(ctx, string(constant.LoginHistoryQueueKey), value).Err()
+}
+
+// PopLoginHistories 按加入顺序取出最多count条待写入的登录记录,无法解析的记录会被丢弃
+func (u *UserCache) PopLoginHistories(ctx context.Context, count int) ([]*models.UserLoginHistory, error) {
+	values, err := u.redis.Client.LPopCount(ctx, string(constant.LoginHistoryQueueKey), count).Result()
+	if err != nil {
+		if errors.Is(err, goredislib.Nil) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	records := make([]*models.UserLoginHistory, 0, len(values))
+	for _, item := range values {
+		var entry loginHistoryEntry
+		if e := json.Unmarshal([]byte(item), &entry); e != nil || entry.UserLoginHistory == nil {
+			continue
+		}
+		entry.CreatedAt = database.UpsertTime(entry.LoginAt)
+		records = append(records, entry.UserLoginHistory)
+	}
+	return records, nil
+}
+
+func (u *UserCache) passwordTicketKey(ticket string) string {
+	return fmt.Sprintf("%s%s", constant.PasswordTicketPrefix, ticket)
+}
+
+// CachePasswordTicket 缓存密码过期时的登录凭证及发起登录的客户端信息,修改密码后凭此完成登录
+func (u *UserCache) CachePasswordTicket(
+	ctx context.Context, ticket string, uid uint, client *models.LoginClient, expiration time.Duration,
+) error {
+	return u.cacheLoginTicket(ctx, u.passwordTicketKey(ticket), uid, client, expiration)
+}
+
+// GetPasswordTicket 获取密码过期登录凭证对应的用户和客户端信息,凭证不存在时返回 redis.Nil
+func (u *UserCache) GetPasswordTicket(ctx context.Context, ticket string) (uint, *models.LoginClient, error) {
+	return u.getLoginTicket(ctx, u.passwordTicketKey(ticket))
+}
+
+// ConsumePasswordTicket 删除密码过期登录凭证,返回凭证是否由本次调用删除,保证凭证只能使用一次
+func (u *UserCache) ConsumePasswordTicket(ctx context.Context, ticket string) (bool, error) {
+	count, err := u.redis.Client.Del(ctx, u.passwordTicketKey(ticket)).Result()
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// MarkTOTPStepUsed 标记用户已使用的TOTP周期,同一周期的验证码只能使用一次,已使用过时返回false
+func (u *UserCache) MarkTOTPStepUsed(ctx context.Context, uid uint, step int64, expiration time.Duration) (bool, error) {
+	key := fmt.Sprintf("%s%d:%d", constant.MFAUsedStepPrefix, uid, step)
+	return u.redis.Client.SetNX(ctx, key, 1, expiration).Result()
+}
+
+func (u *UserCache) ssoStateKey(state string) string {
+	return fmt.Sprintf("%s%s", constant.SSOStatePrefix, state)
+}
+
+// CacheSSOState 缓存单点登录授权请求的上下文
+func (u *UserCache) CacheSSOState(ctx context.Context, state string, value *models.SSOState, expiration time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return u.redis.Client.Set(ctx, u.ssoStateKey(state), data, expiration).Err()
+}
+
+// ConsumeSSOState 获取并删除单点登录授权请求的上下文,保证state只能使用一次,不存在时返回 redis.Nil
+func (u *UserCache) ConsumeSSOState(ctx context.Context, state string) (*models.SSOState, error) {
+	data, err := u.redis.Client.GetDel(ctx, u.ssoStateKey(state)).Bytes()
+	if err != nil {
+		return nil, err
+	}
+	var value models.SSOState
+	if err = json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	return &value, nil
+}
+
+func (u *UserCache) emailChangeKey(prefix constant.Prefix, code string) string {
+	return fmt.Sprintf("%s%s", prefix, code)
+}
+
+// CacheEmailChange 缓存修改邮箱的上下文,prefix 区分确认修改和撤销修改
+func (u *UserCache) CacheEmailChange(
+	ctx context.Context, prefix constant.Prefix, code string, value *models.EmailChange, expiration time.Duration,
+) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return u.redis.Client.Set(ctx, u.emailChangeKey(prefix, code), data, expiration).Err()
+}
+
+// ConsumeEmailChange 获取并删除修改邮箱的上下文,保证链接只能使用一次,不存在时返回 redis.Nil
+func (u *UserCache) ConsumeEmailChange(ctx context.Context, prefix constant.Prefix, code string) (*models.EmailChange, error) {
+	data, err := u.redis.Client.GetDel(ctx, u.emailChangeKey(prefix, code)).Bytes()
+	if err != nil {
+		return nil, err
+	}
+	var value models.EmailChange
+	if err = json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	return &value, nil
+}
+
+func (u *UserCache) smsCodeKey(scene constant.SMSScene, phone string) string {
+	return fmt.Sprintf("%s%s:%s", constant.SMSCodePrefix, scene, phone)
+}
+
+// AcquireSMSCooldown 占用手机号的重新发送间隔,间隔内已发送过验证码时返回false
+func (u *UserCache) AcquireSMSCooldown(ctx context.Context, phone string, interval time.Duration) (bool, error) {
+	return u.redis.Client.SetNX(ctx, fmt.Sprintf("%s%s", constant.SMSCooldownPrefix, phone), 1, interval).Result()
+}
+
+// CacheSMSCode 缓存短信验证码,重新发送时覆盖之前的验证码并清空错误次数
+func (u *UserCache) CacheSMSCode(
+	ctx context.Context, scene constant.SMSScene, phone string, code string, expiration time.Duration,
+) error {
+	key := u.smsCodeKey(scene, phone)
+	_, err := u.redis.Client.TxPipelined(ctx, func(pipe goredislib.Pipeliner) error {
+		pipe.Del(ctx, key)
+		pipe.HSet(ctx, key, "code", code, "attempts", 0)
+		pipe.Expire(ctx, key, expiration)
+		return nil
+	})
+	return err
+}
+
+// VerifySMSCode 校验短信验证码,验证码只能使用一次,错误次数达到maxAttempts后验证码失效
+func (u *UserCache) VerifySMSCode(
+	ctx context.Context, scene constant.SMSScene, phone string, code string, maxAttempts int64,
+) (bool, error) {
+	result, err := verifySMSCodeScript.Run(ctx, u.redis.Client, []string{u.smsCodeKey(scene, phone)}, code, maxAttempts).Int()
+	if err != nil {
+		return false, err
+	}
+	return result == 1, nil
+}
+
+// AcquireDataExportLimit 占用用户导出个人数据的间隔,间隔内已导出过时返回false
+func (u *UserCache) AcquireDataExportLimit(ctx context.Context, uid uint, interval time.Duration) (bool, error) {
+	return u.redis.Client.SetNX(ctx, fmt.Sprintf("%s%d", constant.DataExportLimitPrefix, uid), 1, interval).Result()
+}
+
+// ReleaseDataExportLimit 释放用户导出个人数据的间隔,用于导出失败后允许用户重新申请
+func (u *UserCache) ReleaseDataExportLimit(ctx context.Context, uid uint) error {
+	return u.redis.Client.Del(ctx, fmt.Sprintf("%s%d", constant.DataExportLimitPrefix, uid)).Err()
+}
+
+// CacheDataExport 缓存打包好的个人数据,过期后自动删除
+func (u *UserCache) CacheDataExport(ctx context.Context, code string, data []byte, expiration time.Duration) error {
+	return u.redis.Client.Set(ctx, fmt.Sprintf("%s%s", constant.DataExportPrefix, code), data, expiration).Err()
+}
+
+// GetDataExport 获取打包好的个人数据,有效期内可以重复下载,不存在时返回 redis.Nil
+func (u *UserCache) GetDataExport(ctx context.Context, code string) ([]byte, error) {
+	return u.redis.Client.Get(ctx, fmt.Sprintf("%s%s", constant.DataExportPrefix, code)).Bytes()
+}
@@ -0,0 +1,490 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	goredislib "github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/supuwoerc/weaver/models"
+	"github.com/supuwoerc/weaver/pkg/constant"
+	"github.com/supuwoerc/weaver/pkg/database"
+	"github.com/supuwoerc/weaver/pkg/redis"
+	"github.com/supuwoerc/weaver/pkg/response"
+)
+
+func newTestUserCache(t *testing.T) (*UserCache, *miniredis.Miniredis) {
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+	t.Cleanup(mr.Close)
+	client := goredislib.NewClient(&goredislib.Options{Addr: mr.Addr()})
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+	return NewUserCache(redis.NewCommonRedisClient(client, nil)), mr
+}
+
+func newTestSession(id string, uid uint, device string, lastSeen time.Time) *models.UserSession {
+	return &models.UserSession{
+		LoginClient: models.LoginClient{Device: device, IP: "127.0.0.1", UserAgent: "test"},
+		ID:          id,
+		UID:         uid,
+		CreatedAt:   lastSeen,
+		LastSeen:    lastSeen,
+	}
+}
+
+func TestUserCache_RotateRefreshToken(t *testing.T) {
+	ctx := context.Background()
+	expiration := time.Hour
+	client := &models.LoginClient{IP: "10.0.0.1", UserAgent: "agent"}
+
+	t.Run("rotate refresh token", func(t *testing.T) {
+		userCache, _ := newTestUserCache(t)
+		require.NoError(t, userCache.CreateSession(ctx, newTestSession("session", 1, "pc", time.Now()), "token-1", expiration))
+		assert.NoError(t, userCache.RotateRefreshToken(ctx, 1, "session", "token-1", "token-2", client, expiration))
+		assert.NoError(t, userCache.RotateRefreshToken(ctx, 1, "session", "token-2", "token-3", client, expiration))
+		sessions, err := userCache.GetUserSessions(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, sessions, 1)
+		assert.Equal(t, "pc", sessions[0].Device)
+		assert.Equal(t, client.IP, sessions[0].IP)
+		assert.Equal(t, client.UserAgent, sessions[0].UserAgent)
+	})
+
+	t.Run("reuse rotated refresh token revokes session", func(t *testing.T) {
+		userCache, mr := newTestUserCache(t)
+		require.NoError(t, userCache.CreateSession(ctx, newTestSession("session", 1, "pc", time.Now()), "token-1", expiration))
+		require.NoError(t, userCache.RotateRefreshToken(ctx, 1, "session", "token-1", "token-2", client, expiration))
+		err := userCache.RotateRefreshToken(ctx, 1, "session", "token-1", "token-3", client, expiration)
+		assert.ErrorIs(t, err, response.RefreshTokenReused)
+		assert.False(t, mr.Exists(userCache.sessionKey("session")))
+		// 会话被注销后最新的 token 同样失效
+		err = userCache.RotateRefreshToken(ctx, 1, "session", "token-2", "token-3", client, expiration)
+		assert.ErrorIs(t, err, response.InvalidRefreshToken)
+		valid, err := userCache.TouchSession(ctx, "session")
+		require.NoError(t, err)
+		assert.False(t, valid)
+	})
+
+	t.Run("login again keeps other sessions", func(t *testing.T) {
+		userCache, _ := newTestUserCache(t)
+		require.NoError(t, userCache.CreateSession(ctx, newTestSession("session-1", 1, "pc", time.Now()), "token-1", expiration))
+		require.NoError(t, userCache.CreateSession(ctx, newTestSession("session-2", 1, "phone", time.Now()), "token-2", expiration))
+		assert.NoError(t, userCache.RotateRefreshToken(ctx, 1, "session-1", "token-1", "token-3", client, expiration))
+		assert.NoError(t, userCache.RotateRefreshToken(ctx, 1, "session-2", "token-2", "token-4", client, expiration))
+	})
+}
+
+func TestUserCache_Sessions(t *testing.T) {
+	ctx := context.Background()
+	expiration := time.Hour
+	now := time.UnixMilli(time.Now().UnixMilli())
+
+	t.Run("list sessions", func(t *testing.T) {
+		userCache, mr := newTestUserCache(t)
+		require.NoError(t, userCache.CreateSession(ctx, newTestSession("session-1", 1, "pc", now.Add(-time.Minute)), "token-1", expiration))
+		require.NoError(t, userCache.CreateSession(ctx, newTestSession("session-2", 1, "phone", now), "token-2", expiration))
+		require.NoError(t, userCache.CreateSession(ctx, newTestSession("session-3", 2, "pad", now), "token-3", expiration))
+		sessions, err := userCache.GetUserSessions(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, sessions, 2)
+		assert.Equal(t, "session-2", sessions[0].ID)
+		assert.Equal(t, "phone", sessions[0].Device)
+		assert.Equal(t, uint(1), sessions[0].UID)
+		assert.True(t, now.Equal(sessions[0].CreatedAt))
+		assert.Equal(t, "session-1", sessions[1].ID)
+		// 过期的会话从集合中移除
+		mr.Del(userCache.sessionKey("session-1"))
+		sessions, err = userCache.GetUserSessions(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, sessions, 1)
+		members, err := mr.SMembers(userCache.sessionsKey(1))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"session-2"}, members)
+	})
+
+	t.Run("touch session", func(t *testing.T) {
+		userCache, mr := newTestUserCache(t)
+		require.NoError(t, userCache.CreateSession(ctx, newTestSession("session", 1, "pc", now.Add(-time.Hour)), "token", expiration))
+		valid, err := userCache.TouchSession(ctx, "session")
+		require.NoError(t, err)
+		assert.True(t, valid)
+		sessions, err := userCache.GetUserSessions(ctx, 1)
+		require.NoError(t, err)
+		assert.True(t, sessions[0].LastSeen.After(now.Add(-time.Minute)))
+		valid, err = userCache.TouchSession(ctx, "missing")
+		require.NoError(t, err)
+		assert.False(t, valid)
+		assert.False(t, mr.Exists(userCache.sessionKey("missing")))
+	})
+
+	t.Run("delete sessions", func(t *testing.T) {
+		userCache, mr := newTestUserCache(t)
+		require.NoError(t, userCache.CreateSession(ctx, newTestSession("session-1", 1, "pc", now), "token-1", expiration))
+		require.NoError(t, userCache.CreateSession(ctx, newTestSession("session-2", 1, "phone", now), "token-2", expiration))
+		require.NoError(t, userCache.DeleteSessions(ctx, 1, "session-1"))
+		assert.False(t, mr.Exists(userCache.sessionKey("session-1")))
+		assert.True(t, mr.Exists(userCache.sessionKey("session-2")))
+		require.NoError(t, userCache.DeleteUserSessions(ctx, 1))
+		assert.False(t, mr.Exists(userCache.sessionKey("session-2")))
+		assert.False(t, mr.Exists(userCache.sessionsKey(1)))
+		sessions, err := userCache.GetUserSessions(ctx, 1)
+		require.NoError(t, err)
+		assert.Empty(t, sessions)
+	})
+}
+
+func TestUserCache_IncrRequestCount(t *testing.T) {
+	ctx := context.Background()
+	userCache, mr := newTestUserCache(t)
+	for i := int64(1); i <= 3; i++ {
+		count, err := userCache.IncrRequestCount(ctx, constant.ForgotPasswordLimitPrefix, "test@example.com", time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, i, count)
+	}
+	// 窗口不会因为后续请求而延长
+	mr.FastForward(30 * time.Second)
+	_, err := userCache.IncrRequestCount(ctx, constant.ForgotPasswordLimitPrefix, "test@example.com", time.Minute)
+	require.NoError(t, err)
+	mr.FastForward(31 * time.Second)
+	count, err := userCache.IncrRequestCount(ctx, constant.ForgotPasswordLimitPrefix, "test@example.com", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+	// 不同邮箱分别计数
+	count, err = userCache.IncrRequestCount(ctx, constant.ForgotPasswordLimitPrefix, "other@example.com", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestUserCache_MFATicket(t *testing.T) {
+	ctx := context.Background()
+	client := &models.LoginClient{Device: "pc", IP: "127.0.0.1", UserAgent: "test"}
+
+	t.Run("ticket can only be consumed once", func(t *testing.T) {
+		userCache, _ := newTestUserCache(t)
+		require.NoError(t, userCache.CacheMFATicket(ctx, "ticket", 1, client, time.Minute))
+		uid, cachedClient, err := userCache.GetMFATicket(ctx, "ticket")
+		require.NoError(t, err)
+		assert.Equal(t, uint(1), uid)
+		assert.Equal(t, client, cachedClient)
+		consumed, err := userCache.ConsumeMFATicket(ctx, "ticket")
+		require.NoError(t, err)
+		assert.True(t, consumed)
+		consumed, err = userCache.ConsumeMFATicket(ctx, "ticket")
+		require.NoError(t, err)
+		assert.False(t, consumed)
+		_, _, err = userCache.GetMFATicket(ctx, "ticket")
+		assert.ErrorIs(t, err, goredislib.Nil)
+	})
+
+	t.Run("ticket expired", func(t *testing.T) {
+		userCache, mr := newTestUserCache(t)
+		require.NoError(t, userCache.CacheMFATicket(ctx, "ticket", 1, client, time.Minute))
+		mr.FastForward(2 * time.Minute)
+		_, _, err := userCache.GetMFATicket(ctx, "ticket")
+		assert.ErrorIs(t, err, goredislib.Nil)
+	})
+
+	t.Run("totp step can only be used once", func(t *testing.T) {
+		userCache, _ := newTestUserCache(t)
+		ok, err := userCache.MarkTOTPStepUsed(ctx, 1, 100, time.Minute)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		ok, err = userCache.MarkTOTPStepUsed(ctx, 1, 100, time.Minute)
+		require.NoError(t, err)
+		assert.False(t, ok)
+		ok, err = userCache.MarkTOTPStepUsed(ctx, 2, 100, time.Minute)
+		require.NoError(t, err)
+		assert.True(t, ok)
+	})
+}
+
+func TestUserCache_PasswordTicket(t *testing.T) {
+	ctx := context.Background()
+	client := &models.LoginClient{Device: "pc", IP: "127.0.0.1", UserAgent: "test"}
+	userCache, _ := newTestUserCache(t)
+	require.NoError(t, userCache.CachePasswordTicket(ctx, "ticket", 1, client, time.Minute))
+	// 与两步验证登录凭证互不影响
+	_, _, err := userCache.GetMFATicket(ctx, "ticket")
+	assert.ErrorIs(t, err, goredislib.Nil)
+	uid, cachedClient, err := userCache.GetPasswordTicket(ctx, "ticket")
+	require.NoError(t, err)
+	assert.Equal(t, uint(1), uid)
+	assert.Equal(t, client, cachedClient)
+	consumed, err := userCache.ConsumePasswordTicket(ctx, "ticket")
+	require.NoError(t, err)
+	assert.True(t, consumed)
+	consumed, err = userCache.ConsumePasswordTicket(ctx, "ticket")
+	require.NoError(t, err)
+	assert.False(t, consumed)
+}
+
+func TestUserCache_SSOState(t *testing.T) {
+	ctx := context.Background()
+	state := &models.SSOState{Provider: "corp", Nonce: "nonce", Verifier: "verifier"}
+
+	t.Run("state can only be consumed once", func(t *testing.T) {
+		userCache, _ := newTestUserCache(t)
+		require.NoError(t, userCache.CacheSSOState(ctx, "state", state, time.Minute))
+		cached, err := userCache.ConsumeSSOState(ctx, "state")
+		require.NoError(t, err)
+		assert.Equal(t, state, cached)
+		_, err = userCache.ConsumeSSOState(ctx, "state")
+		assert.ErrorIs(t, err, goredislib.Nil)
+	})
+
+	t.Run("state expired", func(t *testing.T) {
+		userCache, mr := newTestUserCache(t)
+		require.NoError(t, userCache.CacheSSOState(ctx, "state", state, time.Minute))
+		mr.FastForward(2 * time.Minute)
+		_, err := userCache.ConsumeSSOState(ctx, "state")
+		assert.ErrorIs(t, err, goredislib.Nil)
+	})
+}
+
+func TestUserCache_EmailChange(t *testing.T) {
+	ctx := context.Background()
+	change := &models.EmailChange{UID: 1, OldEmail: "old@example.com", NewEmail: "new@example.com"}
+
+	t.Run("code can only be consumed once", func(t *testing.T) {
+		userCache, _ := newTestUserCache(t)
+		require.NoError(t, userCache.CacheEmailChange(ctx, constant.ChangeEmailPrefix, "code", change, time.Minute))
+		cached, err := userCache.ConsumeEmailChange(ctx, constant.ChangeEmailPrefix, "code")
+		require.NoError(t, err)
+		assert.Equal(t, change, cached)
+		_, err = userCache.ConsumeEmailChange(ctx, constant.ChangeEmailPrefix, "code")
+		assert.ErrorIs(t, err, goredislib.Nil)
+	})
+
+	t.Run("confirm and revert codes are isolated", func(t *testing.T) {
+		userCache, _ := newTestUserCache(t)
+		require.NoError(t, userCache.CacheEmailChange(ctx, constant.ChangeEmailPrefix, "code", change, time.Minute))
+		_, err := userCache.ConsumeEmailChange(ctx, constant.RevertEmailPrefix, "code")
+		assert.ErrorIs(t, err, goredislib.Nil)
+	})
+}
+
+func TestUserCache_SMSCode(t *testing.T) {
+	ctx := context.Background()
+	phone := "13800000000"
+
+	t.Run("code can only be used once", func(t *testing.T) {
+		userCache, _ := newTestUserCache(t)
+		require.NoError(t, userCache.CacheSMSCode(ctx, constant.SMSLogin, phone, "123456", time.Minute))
+		valid, err := userCache.VerifySMSCode(ctx, constant.SMSBindPhone, phone, "123456", 5)
+		require.NoError(t, err)
+		assert.False(t, valid, "codes of different scenes are isolated")
+		valid, err = userCache.VerifySMSCode(ctx, constant.SMSLogin, phone, "123456", 5)
+		require.NoError(t, err)
+		assert.True(t, valid)
+		valid, err = userCache.VerifySMSCode(ctx, constant.SMSLogin, phone, "123456", 5)
+		require.NoError(t, err)
+		assert.False(t, valid)
+	})
+
+	t.Run("code expires after max attempts", func(t *testing.T) {
+		userCache, _ := newTestUserCache(t)
+		require.NoError(t, userCache.CacheSMSCode(ctx, constant.SMSLogin, phone, "123456", time.Minute))
+		for i := 0; i < 2; i++ {
+			valid, err := userCache.VerifySMSCode(ctx, constant.SMSLogin, phone, "000000", 2)
+			require.NoError(t, err)
+			assert.False(t, valid)
+		}
+		valid, err := userCache.VerifySMSCode(ctx, constant.SMSLogin, phone, "123456", 2)
+		require.NoError(t, err)
+		assert.False(t, valid)
+	})
+
+	t.Run("resend cooldown", func(t *testing.T) {
+		userCache, mr := newTestUserCache(t)
+		acquired, err := userCache.AcquireSMSCooldown(ctx, phone, time.Minute)
+		require.NoError(t, err)
+		assert.True(t, acquired)
+		acquired, err = userCache.AcquireSMSCooldown(ctx, phone, time.Minute)
+		require.NoError(t, err)
+		assert.False(t, acquired)
+		mr.FastForward(time.Minute)
+		acquired, err = userCache.AcquireSMSCooldown(ctx, phone, time.Minute)
+		require.NoError(t, err)
+		assert.True(t, acquired)
+	})
+}
+
+func TestUserCache_DataExport(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("export interval", func(t *testing.T) {
+		userCache, mr := newTestUserCache(t)
+		acquired, err := userCache.AcquireDataExportLimit(ctx, 1, time.Hour)
+		require.NoError(t, err)
+		assert.True(t, acquired)
+		acquired, err = userCache.AcquireDataExportLimit(ctx, 1, time.Hour)
+		require.NoError(t, err)
+		assert.False(t, acquired)
+		require.NoError(t, userCache.ReleaseDataExportLimit(ctx, 1))
+		acquired, err = userCache.AcquireDataExportLimit(ctx, 1, time.Hour)
+		require.NoError(t, err)
+		assert.True(t, acquired)
+		mr.FastForward(time.Hour)
+		acquired, err = userCache.AcquireDataExportLimit(ctx, 1, time.Hour)
+		require.NoError(t, err)
+		assert.True(t, acquired)
+	})
+
+	t.Run("bundle can be downloaded until expired", func(t *testing.T) {
+		userCache, mr := newTestUserCache(t)
+		require.NoError(t, userCache.CacheDataExport(ctx, "code", []byte("zip"), time.Hour))
+		for i := 0; i < 2; i++ {
+			data, err := userCache.GetDataExport(ctx, "code")
+			require.NoError(t, err)
+			assert.Equal(t, []byte("zip"), data)
+		}
+		mr.FastForward(time.Hour)
+		_, err := userCache.GetDataExport(ctx, "code")
+		assert.ErrorIs(t, err, goredislib.Nil)
+	})
+}
+
+func TestUserCache_LoginFailures(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("request count defaults to zero", func(t *testing.T) {
+		userCache, _ := newTestUserCache(t)
+		count, err := userCache.GetRequestCount(ctx, constant.LoginFailEmailPrefix, "a@b.com")
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), count)
+		_, err = userCache.IncrRequestCount(ctx, constant.LoginFailEmailPrefix, "a@b.com", time.Minute)
+		require.NoError(t, err)
+		count, err = userCache.GetRequestCount(ctx, constant.LoginFailEmailPrefix, "a@b.com")
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("login block expires", func(t *testing.T) {
+		userCache, mr := newTestUserCache(t)
+		remaining, err := userCache.GetLoginBlock(ctx, constant.LoginLockEmailPrefix, "a@b.com")
+		require.NoError(t, err)
+		assert.Zero(t, remaining)
+		require.NoError(t, userCache.CacheLoginBlock(ctx, constant.LoginLockEmailPrefix, "a@b.com", time.Minute))
+		remaining, err = userCache.GetLoginBlock(ctx, constant.LoginLockEmailPrefix, "a@b.com")
+		require.NoError(t, err)
+		assert.Equal(t, time.Minute, remaining)
+		mr.FastForward(2 * time.Minute)
+		remaining, err = userCache.GetLoginBlock(ctx, constant.LoginLockEmailPrefix, "a@b.com")
+		require.NoError(t, err)
+		assert.Zero(t, remaining)
+	})
+
+	t.Run("clear login failures", func(t *testing.T) {
+		userCache, mr := newTestUserCache(t)
+		_, err := userCache.IncrRequestCount(ctx, constant.LoginFailEmailPrefix, "a@b.com", time.Minute)
+		require.NoError(t, err)
+		_, err = userCache.IncrRequestCount(ctx, constant.LoginFailIPPrefix, "127.0.0.1", time.Minute)
+		require.NoError(t, err)
+		require.NoError(t, userCache.CacheLoginBlock(ctx, constant.LoginDelayPrefix, "a@b.com", time.Minute))
+		require.NoError(t, userCache.CacheLoginBlock(ctx, constant.LoginLockEmailPrefix, "a@b.com", time.Minute))
+		require.NoError(t, userCache.CacheLoginBlock(ctx, constant.LoginLockIPPrefix, "127.0.0.1", time.Minute))
+
+		require.NoError(t, userCache.ClearLoginFailures(ctx, "a@b.com", ""))
+		assert.False(t, mr.Exists(string(constant.LoginFailEmailPrefix)+"a@b.com"))
+		assert.False(t, mr.Exists(string(constant.LoginDelayPrefix)+"a@b.com"))
+		assert.False(t, mr.Exists(string(constant.LoginLockEmailPrefix)+"a@b.com"))
+		assert.True(t, mr.Exists(string(constant.LoginFailIPPrefix)+"127.0.0.1"))
+		assert.True(t, mr.Exists(string(constant.LoginLockIPPrefix)+"127.0.0.1"))
+
+		require.NoError(t, userCache.ClearLoginFailures(ctx, "a@b.com", "127.0.0.1"))
+		assert.False(t, mr.Exists(string(constant.LoginFailIPPrefix)+"127.0.0.1"))
+		assert.False(t, mr.Exists(string(constant.LoginLockIPPrefix)+"127.0.0.1"))
+	})
+}
+
+func TestUserCache_AccessTokenDenylist(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("deny single token", func(t *testing.T) {
+		userCache, mr := newTestUserCache(t)
+		require.NoError(t, userCache.DenyAccessToken(ctx, "jti", time.Now().Add(time.Minute)))
+		denied, err := userCache.IsAccessTokenDenied(ctx, "jti")
+		require.NoError(t, err)
+		assert.True(t, denied)
+		mr.FastForward(2 * time.Minute)
+		denied, err = userCache.IsAccessTokenDenied(ctx, "jti")
+		require.NoError(t, err)
+		assert.False(t, denied)
+	})
+
+	t.Run("expired token is not denied", func(t *testing.T) {
+		userCache, mr := newTestUserCache(t)
+		require.NoError(t, userCache.DenyAccessToken(ctx, "jti", time.Now().Add(-time.Minute)))
+		assert.False(t, mr.Exists(constant.TokenDenylistKey+":jti"))
+	})
+
+	t.Run("deny user tokens", func(t *testing.T) {
+		userCache, mr := newTestUserCache(t)
+		now := time.Now()
+		require.NoError(t, userCache.RecordAccessToken(ctx, 1, "s1", "jti-1", now.Add(time.Minute)))
+		require.NoError(t, userCache.RecordAccessToken(ctx, 1, "s2", "jti-2", now.Add(time.Minute)))
+		require.NoError(t, userCache.RecordAccessToken(ctx, 2, "s3", "jti-3", now.Add(time.Minute)))
+
+		require.NoError(t, userCache.DenyUserAccessTokens(ctx, 1, "s2"))
+		for jti, expected := range map[string]bool{"jti-1": true, "jti-2": false, "jti-3": false} {
+			denied, err := userCache.IsAccessTokenDenied(ctx, jti)
+			require.NoError(t, err)
+			assert.Equal(t, expected, denied, jti)
+		}
+		ttl := mr.TTL(constant.TokenDenylistKey + ":jti-1")
+		assert.Greater(t, ttl, time.Duration(0))
+		assert.LessOrEqual(t, ttl, time.Minute)
+		members, err := mr.ZMembers(constant.UserAccessTokensKey + ":1")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"s2:jti-2"}, members)
+	})
+
+	t.Run("expired records are pruned", func(t *testing.T) {
+		userCache, mr := newTestUserCache(t)
+		now := time.Now()
+		require.NoError(t, userCache.RecordAccessToken(ctx, 1, "s1", "old", now.Add(-time.Second)))
+		require.NoError(t, userCache.RecordAccessToken(ctx, 1, "s1", "new", now.Add(time.Minute)))
+		members, err := mr.ZMembers(constant.UserAccessTokensKey + ":1")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"s1:new"}, members)
+		require.NoError(t, userCache.DenyUserAccessTokens(ctx, 1))
+		assert.False(t, mr.Exists(constant.TokenDenylistKey+":old"))
+	})
+}
+
+func TestUserCache_LoginHistoryQueue(t *testing.T) {
+	ctx := context.Background()
+	userCache, _ := newTestUserCache(t)
+	records, err := userCache.PopLoginHistories(ctx, 2)
+	require.NoError(t, err)
+	assert.Empty(t, records)
+	uid := uint(1)
+	loginAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.FixedZone("CST", 8*3600))
+	for _, email := range []string{"a@b.com", "b@b.com", "c@b.com"} {
+		require.NoError(t, userCache.PushLoginHistory(ctx, &models.UserLoginHistory{
+			UserID:     &uid,
+			Email:      email,
+			Type:       constant.LoginPassword,
+			Success:    true,
+			IP:         "127.0.0.1",
+			BasicModel: database.BasicModel{CreatedAt: database.UpsertTime(loginAt)},
+		}))
+	}
+	records, err = userCache.PopLoginHistories(ctx, 2)
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, "a@b.com", records[0].Email)
+	assert.Equal(t, uid, *records[0].UserID)
+	assert.True(t, records[0].Success)
+	assert.True(t, loginAt.Equal(time.Time(records[0].CreatedAt)))
+	assert.Equal(t, "b@b.com", records[1].Email)
+	records, err = userCache.PopLoginHistories(ctx, 2)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, "c@b.com", records[0].Email)
+}
@@ -19,3 +19,13 @@ func NewAttachmentDAO(basicDAO *BasicDAO) *AttachmentDAO {
 func (a *AttachmentDAO) Create(ctx context.Context, records []*models.Attachment) error {
 	return a.Datasource(ctx).Create(records).Error
 }
+
+// GetByCreator 查询用户上传的附件
+func (a *AttachmentDAO) GetByCreator(ctx context.Context, uid uint) ([]*models.Attachment, error) {
+	var records []*models.Attachment
+	err := a.Datasource(ctx).Model(&models.Attachment{}).Where("creator_id = ?", uid).Order("id").Find(&records).Error
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
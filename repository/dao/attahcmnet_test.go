@@ -3,6 +3,7 @@ package dao
 import (
 	"context"
 	"database/sql"
+	"regexp"
 	"testing"
 	"time"
 
@@ -158,3 +159,22 @@ func (s *AttachmentDAOSuite) TestAttachmentDAO_Create() {
 		assert.ErrorContains(t, err, "empty slice found")
 	})
 }
+
+func (s *AttachmentDAOSuite) TestAttachmentDAO_GetByCreator() {
+	t := s.T()
+	s.Run("get attachments by creator", func() {
+		defer func() {
+			assert.NoError(t, s.mock.ExpectationsWereMet())
+		}()
+		s.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `attachments` WHERE creator_id = ? "+
+			"AND `attachments`.`deleted_at` = ? ORDER BY id")).
+			WithArgs(1, 0).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "creator_id", "size"}).
+				AddRow(1, "a.txt", 1, 10).
+				AddRow(2, "b.txt", 1, 20))
+		records, err := s.attachmentDAO.GetByCreator(context.Background(), 1)
+		require.NoError(t, err)
+		require.Len(t, records, 2)
+		assert.Equal(t, "b.txt", records[1].Name)
+	})
+}
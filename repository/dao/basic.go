@@ -3,6 +3,7 @@ package dao
 import (
 	"context"
 
+	"github.com/supuwoerc/weaver/pkg/constant"
 	"github.com/supuwoerc/weaver/pkg/database"
 
 	"gorm.io/gorm"
@@ -36,6 +37,24 @@ func (basic *BasicDAO) Preload(field string, args ...any) func(d *gorm.DB) *gorm
 	}
 }
 
+// userRoleIds 用户拥有的角色ID子查询,按继承方向包含直接持有的角色的后代角色或祖先角色
+func (basic *BasicDAO) userRoleIds(ctx context.Context, uid uint, inheritance constant.RoleInheritance) *gorm.DB {
+	query := basic.Datasource(ctx).Table("sys_user_role as user_role").Where("user_role.user_id = ?", uid)
+	switch inheritance {
+	case constant.InheritDescendants:
+		return query.Select("role.id").
+			Joins("inner join sys_role as role on role.id = user_role.role_id or find_in_set(user_role.role_id, role.ancestors)").
+			Where("role.deleted_at = ?", 0)
+	case constant.InheritAncestors:
+		return query.Select("role.id").
+			Joins("inner join sys_role as own on own.id = user_role.role_id").
+			Joins("inner join sys_role as role on role.id = own.id or find_in_set(role.id, own.ancestors)").
+			Where("role.deleted_at = ?", 0)
+	default:
+		return query.Select("user_role.role_id")
+	}
+}
+
 func queryAll[T any](db *gorm.DB, limit int) ([]T, error) {
 	var result []T
 	offset := 0
@@ -0,0 +1,41 @@
+package dao
+
+import (
+	"context"
+
+	"github.com/supuwoerc/weaver/pkg/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DataScope 按调用者的数据权限范围过滤列表查询,调用者的数据权限范围由登录中间件注入上下文,
+// 上下文中没有登录用户或调用者不限制范围时不过滤;department构造数据属于指定部门的条件,self构造数据属于指定用户的条件,
+// 可见部门与本人的数据取并集,都不可见时查询不到任何数据
+func (basic *BasicDAO) DataScope(
+	ctx context.Context,
+	department func(db *gorm.DB, departmentIds []uint) clause.Expression,
+	self func(db *gorm.DB, uid uint) clause.Expression,
+) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		scope, err := utils.GetContextDataScope(ctx)
+		if err != nil {
+			_ = db.AddError(err)
+			return db
+		}
+		if !scope.Limited() {
+			return db
+		}
+		conditions := make([]clause.Expression, 0, 2)
+		if len(scope.DepartmentIDs) > 0 && department != nil {
+			conditions = append(conditions, department(basic.Datasource(ctx), scope.DepartmentIDs))
+		}
+		if scope.Self && self != nil {
+			conditions = append(conditions, self(basic.Datasource(ctx), scope.UserID))
+		}
+		if len(conditions) == 0 {
+			return db.Where("1 = 0")
+		}
+		return db.Where(clause.Or(conditions...))
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/go-sql-driver/mysql"
 	"github.com/pkg/errors"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type DepartmentDAO struct {
@@ -54,6 +55,15 @@ func (r *DepartmentDAO) GetByID(ctx context.Context, id uint) (*models.Departmen
 	return &dept, nil
 }
 
+func (r *DepartmentDAO) GetByIds(ctx context.Context, ids []uint) ([]*models.Department, error) {
+	var depts []*models.Department
+	err := r.Datasource(ctx).Model(&models.Department{}).Where("id in (?)", ids).Find(&depts).Error
+	if err != nil {
+		return nil, err
+	}
+	return depts, nil
+}
+
 // GetAll 查询全部部门数据
 func (r *DepartmentDAO) GetAll(ctx context.Context) ([]*models.Department, error) {
 	depts, err := queryAll[*models.Department](r.Datasource(ctx).Model(&models.Department{}), r.QueryLimit)
@@ -80,3 +90,39 @@ func (r *DepartmentDAO) GetAllDepartmentLeader(ctx context.Context) ([]*models.D
 	}
 	return res, nil
 }
+
+// GetDepartmentsWithPosterity 查询部门的全部后代部门,不包含部门自身
+func (r *DepartmentDAO) GetDepartmentsWithPosterity(ctx context.Context, deptIds ...uint) ([]*models.Department, error) {
+	if len(deptIds) == 0 {
+		return nil, nil
+	}
+	var depts []*models.Department
+	orConditions := make([]clause.Expression, 0, len(deptIds))
+	for _, id := range deptIds {
+		orConditions = append(orConditions, gorm.Expr("find_in_set(?,ancestors)", id))
+	}
+	err := r.Datasource(ctx).Model(&models.Department{}).Where(clause.Or(orConditions...)).Find(&depts).Error
+	if err != nil {
+		return nil, err
+	}
+	return depts, nil
+}
+
+// dataScope 部门列表的数据权限,本人的数据为本人创建的部门
+func (r *DepartmentDAO) dataScope(ctx context.Context) func(db *gorm.DB) *gorm.DB {
+	return r.DataScope(ctx, func(_ *gorm.DB, departmentIds []uint) clause.Expression {
+		return gorm.Expr("id in (?)", departmentIds)
+	}, func(_ *gorm.DB, uid uint) clause.Expression {
+		return gorm.Expr("creator_id = ?", uid)
+	})
+}
+
+// GetIdsInDataScope 查询调用者的数据权限范围内的部门ID
+func (r *DepartmentDAO) GetIdsInDataScope(ctx context.Context) ([]uint, error) {
+	var ids []uint
+	err := r.Datasource(ctx).Model(&models.Department{}).Scopes(r.dataScope(ctx)).Pluck("id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
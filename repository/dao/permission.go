@@ -114,40 +114,46 @@ func (r *PermissionDAO) AssociateRoles(ctx context.Context, id uint, roles []*mo
 		Association("Roles").Replace(roles)
 }
 
-func (r *PermissionDAO) GetByNameOrResource(ctx context.Context, name, resource string) ([]*models.Permission, error) {
+// GetByNameOrResource 查询名称相同或资源和请求方法都相同的权限
+func (r *PermissionDAO) GetByNameOrResource(ctx context.Context, name, resource, method string) ([]*models.Permission, error) {
 	var permissions []*models.Permission
-	err := r.Datasource(ctx).Model(&models.Permission{}).Where("name = ? or resource = ?", name, resource).Find(&permissions).Error
+	err := r.Datasource(ctx).Model(&models.Permission{}).
+		Where("name = ? or (resource = ? and method = ?)", name, resource, method).
+		Find(&permissions).Error
 	if err != nil {
 		return nil, err
 	}
 	return permissions, nil
 }
 
-// CheckUserPermission 根据用户角色信息查询权限信息 TODO:修改为依赖角色
-func (r *PermissionDAO) CheckUserPermission(ctx context.Context, uid uint, resource string, permissionType constant.PermissionType) (bool, error) {
-	var count int64
-	err := r.Datasource(ctx).Model(&models.Permission{}).
-		Table("sys_permission as permission").
-		Joins("inner join sys_role_permission as role_permission on role_permission.permission_id = permission.id").
-		Joins("inner join sys_user_role as user_role on user_role.role_id = role_permission.role_id").
-		Where("user_role.user_id = ?", uid).
-		Where("permission.resource = ?", resource).
-		Where("permission.type = ?", permissionType).
-		Count(&count).Error
+func (r *PermissionDAO) GetByNames(ctx context.Context, names []string) ([]*models.Permission, error) {
+	var permissions []*models.Permission
+	err := r.Datasource(ctx).Model(&models.Permission{}).Where("name in (?)", names).Find(&permissions).Error
+	if err != nil {
+		return nil, err
+	}
+	return permissions, nil
+}
+
+// GetByType 查询指定类型的全部权限
+func (r *PermissionDAO) GetByType(ctx context.Context, permissionType constant.PermissionType) ([]*models.Permission, error) {
+	var permissions []*models.Permission
+	err := r.Datasource(ctx).Model(&models.Permission{}).Where("type = ?", permissionType).Find(&permissions).Error
 	if err != nil {
-		return false, err
+		return nil, err
 	}
-	return count > 0, nil
+	return permissions, nil
 }
 
-// GetUserPermissions 获取用户所有权限 TODO:修改为依赖角色
-func (r *PermissionDAO) GetUserPermissions(ctx context.Context, uid uint) ([]*models.Permission, error) {
+// GetUserPermissions 获取用户所有权限,按继承方向包含继承的角色的权限
+func (r *PermissionDAO) GetUserPermissions(
+	ctx context.Context, uid uint, inheritance constant.RoleInheritance,
+) ([]*models.Permission, error) {
 	var permissions []*models.Permission
 	err := r.Datasource(ctx).Model(&models.Permission{}).
 		Table("sys_permission as permission").
 		Joins("inner join sys_role_permission role_permission on permission.id = role_permission.permission_id").
-		Joins("inner join sys_user_role user_role on role_permission.role_id = user_role.role_id").
-		Where("user_role.user_id = ?", uid).
+		Where("role_permission.role_id in (?)", r.userRoleIds(ctx, uid, inheritance)).
 		Group("permission.id").
 		Find(&permissions).Error
 	if err != nil {
@@ -156,17 +162,16 @@ func (r *PermissionDAO) GetUserPermissions(ctx context.Context, uid uint) ([]*mo
 	return permissions, nil
 }
 
-// GetUserPermissionsByType 根据类型获取用户权限 TODO:修改为依赖角色
-func (r *PermissionDAO) GetUserPermissionsByType(ctx context.Context, uid uint, limit int, offset int,
-	permissionType ...constant.PermissionType) ([]*models.Permission, error) {
+// GetUserPermissionsByType 根据类型获取用户权限,按继承方向包含继承的角色的权限
+func (r *PermissionDAO) GetUserPermissionsByType(ctx context.Context, uid uint, inheritance constant.RoleInheritance,
+	limit int, offset int, permissionType ...constant.PermissionType) ([]*models.Permission, error) {
 	var permissions []*models.Permission
 
 	query := r.Datasource(ctx).
 		Model(&models.Permission{}).
 		Distinct("sys_permission.*").
 		Joins("INNER JOIN sys_role_permission ON sys_permission.id = sys_role_permission.permission_id").
-		Joins("INNER JOIN sys_user_role ON sys_role_permission.role_id = sys_user_role.role_id").
-		Where("sys_user_role.user_id = ?", uid).
+		Where("sys_role_permission.role_id IN (?)", r.userRoleIds(ctx, uid, inheritance)).
 		Where("sys_permission.type IN (?)", permissionType).
 		Order("sys_permission.id DESC").
 		Limit(limit).
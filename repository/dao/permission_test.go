@@ -0,0 +1,82 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"github.com/supuwoerc/weaver/pkg/constant"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+type PermissionDAOSuite struct {
+	permissionDAO *PermissionDAO
+	mock          sqlmock.Sqlmock
+	db            *sql.DB
+	suite.Suite
+}
+
+func TestPermissionDAOSuite(t *testing.T) {
+	suite.Run(t, new(PermissionDAOSuite))
+}
+
+func (s *PermissionDAOSuite) SetupSuite() {
+	t := s.T()
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	gormDB, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      db,
+		SkipInitializeWithVersion: true,
+	}))
+	require.NoError(t, err)
+	s.permissionDAO = NewPermissionDAO(NewBasicDao(gormDB))
+	s.mock = mock
+	s.db = db
+}
+
+func (s *PermissionDAOSuite) TearDownSuite() {
+	_ = s.db.Close()
+}
+
+func (s *PermissionDAOSuite) TestPermissionDAO_GetUserPermissions() {
+	t := s.T()
+	cases := []struct {
+		name        string
+		inheritance constant.RoleInheritance
+		query       string
+	}{
+		{
+			name:        "inherit descendants",
+			inheritance: constant.InheritDescendants,
+			query:       `role_permission.role_id in \(SELECT role.id FROM sys_user_role as user_role inner join sys_role as role on role.id = user_role.role_id or find_in_set\(user_role.role_id, role.ancestors\)`,
+		},
+		{
+			name:        "inherit ancestors",
+			inheritance: constant.InheritAncestors,
+			query:       `inner join sys_role as role on role.id = own.id or find_in_set\(role.id, own.ancestors\)`,
+		},
+		{
+			name:        "no inheritance",
+			inheritance: constant.InheritNone,
+			query:       `role_permission.role_id in \(SELECT user_role.role_id FROM sys_user_role as user_role WHERE user_role.user_id = \?\)`,
+		},
+	}
+	for _, c := range cases {
+		s.Run(c.name, func() {
+			defer func() {
+				assert.NoError(t, s.mock.ExpectationsWereMet())
+			}()
+			s.mock.ExpectQuery(c.query).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "resource", "type", "method"}).
+					AddRow(1, "/api/v1/user/list", constant.ApiRoute, "GET"))
+			permissions, err := s.permissionDAO.GetUserPermissions(context.Background(), 1, c.inheritance)
+			assert.NoError(t, err)
+			assert.Len(t, permissions, 1)
+		})
+	}
+}
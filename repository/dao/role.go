@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/supuwoerc/weaver/models"
+	"github.com/supuwoerc/weaver/pkg/constant"
 	"github.com/supuwoerc/weaver/pkg/database"
 	"github.com/supuwoerc/weaver/pkg/response"
 	"gorm.io/gorm/clause"
@@ -53,6 +54,7 @@ func (r *RoleDAO) GetList(ctx context.Context, keyword string, limit, offset int
 	var roles []*models.Role
 	var total int64
 	query := r.Datasource(ctx).Model(&models.Role{}).
+		Scopes(r.dataScope(ctx)).
 		Preload("Children").
 		Order("updated_at desc,id desc")
 	if keyword != "" {
@@ -68,6 +70,27 @@ func (r *RoleDAO) GetList(ctx context.Context, keyword string, limit, offset int
 	return roles, total, nil
 }
 
+// dataScope 角色列表的数据权限,角色属于持有该角色的用户所在的部门,本人的数据为本人创建或持有的角色
+func (r *RoleDAO) dataScope(ctx context.Context) func(db *gorm.DB) *gorm.DB {
+	return r.DataScope(ctx, func(db *gorm.DB, departmentIds []uint) clause.Expression {
+		return gorm.Expr("id in (?)", db.Model(&models.UserRole{}).Select("role_id").
+			Where("user_id in (?)", db.Model(&models.UserDepartment{}).
+				Select("user_id").Where("department_id in (?)", departmentIds)))
+	}, func(db *gorm.DB, uid uint) clause.Expression {
+		return gorm.Expr("(creator_id = ? or id in (?))", uid,
+			db.Model(&models.UserRole{}).Select("role_id").Where("user_id = ?", uid))
+	})
+}
+
+func (r *RoleDAO) GetByNames(ctx context.Context, names []string) ([]*models.Role, error) {
+	var roles []*models.Role
+	err := r.Datasource(ctx).Model(&models.Role{}).Where("name in (?)", names).Find(&roles).Error
+	if err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
 func (r *RoleDAO) GetByName(ctx context.Context, name string) (*models.Role, error) {
 	var role *models.Role
 	err := r.Datasource(ctx).Model(&models.Role{}).Where("name = ?", name).First(&role).Error
@@ -116,6 +139,11 @@ func (r *RoleDAO) AssociatePermissions(ctx context.Context, id uint, permissions
 	return r.Datasource(ctx).Model(&models.Role{BasicModel: database.BasicModel{ID: id}}).Association("Permissions").Replace(permissions)
 }
 
+func (r *RoleDAO) AssociateDataDepartments(ctx context.Context, id uint, departments []*models.Department) error {
+	return r.Datasource(ctx).Model(&models.Role{BasicModel: database.BasicModel{ID: id}}).
+		Association("DataDepartments").Replace(departments)
+}
+
 func (r *RoleDAO) DeleteByID(ctx context.Context, id, updater uint) error {
 	return r.Datasource(ctx).Model(&models.Role{}).Where("id = ?", id).
 		Select("updater_id", "deleted_at").
@@ -137,11 +165,12 @@ func (r *RoleDAO) GetPermissionsCount(ctx context.Context, id uint) int64 {
 	}).Association("Permissions").Count()
 }
 
+// GetUserRolesWithoutPosterity 查询用户直接持有的角色
 func (r *RoleDAO) GetUserRolesWithoutPosterity(ctx context.Context, uid uint) ([]*models.Role, error) {
 	var roles []*models.Role
 	err := r.Datasource(ctx).Model(&models.Role{}).
 		Table("sys_role as role").
-		Joins("inner join sys_user_role as user_role on user_role.role_id = role_permission.role_id").
+		Joins("inner join sys_user_role as user_role on user_role.role_id = role.id").
 		Where("user_role.user_id = ?", uid).
 		Find(&roles).Error
 	if err != nil {
@@ -150,6 +179,7 @@ func (r *RoleDAO) GetUserRolesWithoutPosterity(ctx context.Context, uid uint) ([
 	return roles, nil
 }
 
+// GetRolesWithPosterity 查询角色的全部后代角色,不包含角色自身
 func (r *RoleDAO) GetRolesWithPosterity(ctx context.Context, roleIds ...uint) ([]*models.Role, error) {
 	if len(roleIds) == 0 {
 		return nil, nil
@@ -166,3 +196,21 @@ func (r *RoleDAO) GetRolesWithPosterity(ctx context.Context, roleIds ...uint) ([
 	}
 	return roles, nil
 }
+
+// GetUserRoles 查询用户拥有的角色,按继承方向包含直接持有的角色的后代角色或祖先角色
+func (r *RoleDAO) GetUserRoles(
+	ctx context.Context, uid uint, inheritance constant.RoleInheritance, preload ...string,
+) ([]*models.Role, error) {
+	var roles []*models.Role
+	query := r.Datasource(ctx).Model(&models.Role{})
+	if len(preload) > 0 {
+		query = query.Scopes(lo.Map(preload, func(item string, index int) func(d *gorm.DB) *gorm.DB {
+			return r.Preload(item)
+		})...)
+	}
+	err := query.Where("id in (?)", r.userRoleIds(ctx, uid, inheritance)).Find(&roles).Error
+	if err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
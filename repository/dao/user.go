@@ -3,6 +3,7 @@ package dao
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/supuwoerc/weaver/models"
 	"github.com/supuwoerc/weaver/pkg/constant"
@@ -12,6 +13,7 @@ import (
 	"github.com/go-sql-driver/mysql"
 	"github.com/samber/lo"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type UserDAO struct {
@@ -51,6 +53,25 @@ func (u *UserDAO) GetByEmail(ctx context.Context, email string, preload ...strin
 	return &user, nil
 }
 
+// GetByPhone 按绑定的手机号查询用户
+func (u *UserDAO) GetByPhone(ctx context.Context, phone string, preload ...string) (*models.User, error) {
+	var user models.User
+	query := u.Datasource(ctx).Model(&models.User{})
+	if len(preload) > 0 {
+		query = query.Scopes(lo.Map(preload, func(item string, index int) func(d *gorm.DB) *gorm.DB {
+			return u.Preload(item)
+		})...)
+	}
+	err := query.Where("phone = ?", phone).First(&user).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.UserNotExist
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
 func (u *UserDAO) GetByID(ctx context.Context, uid uint, preload ...string) (*models.User, error) {
 	var user models.User
 	query := u.Datasource(ctx).Model(&models.User{})
@@ -84,14 +105,51 @@ func (u *UserDAO) GetByIds(ctx context.Context, ids []uint, preload ...string) (
 	return users, nil
 }
 
-func (u *UserDAO) GetList(ctx context.Context, keyword string, limit, offset int) ([]*models.User, int64, error) {
+// GetByIdsWithDeleted 查询用户,包含已删除和已注销的用户,用于关联数据的创建人和更新人
+func (u *UserDAO) GetByIdsWithDeleted(ctx context.Context, ids []uint) ([]*models.User, error) {
 	var users []*models.User
-	var total int64
-	query := u.Datasource(ctx).Model(&models.User{}).Order("updated_at desc,id desc")
-	if keyword != "" {
-		keyword = database.FuzzKeyword(keyword)
+	err := u.Datasource(ctx).Unscoped().Model(&models.User{}).Where("id in (?)", ids).Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// filterQuery 按条件构造用户查询
+func (u *UserDAO) filterQuery(ctx context.Context, filter *models.UserFilter) *gorm.DB {
+	query := u.Datasource(ctx).Model(&models.User{})
+	if filter.Keyword != "" {
+		keyword := database.FuzzKeyword(filter.Keyword)
 		query = query.Where("nickname like ? or email like ?", keyword, keyword)
 	}
+	if filter.Status != 0 {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.RoleID != 0 {
+		query = query.Where("id in (?)", u.Datasource(ctx).Model(&models.UserRole{}).
+			Select("user_id").Where("role_id = ?", filter.RoleID))
+	}
+	if filter.DepartmentID != 0 {
+		query = query.Where("id in (?)", u.Datasource(ctx).Model(&models.UserDepartment{}).
+			Select("user_id").Where("department_id = ?", filter.DepartmentID))
+	}
+	return query.Scopes(u.dataScope(ctx)).Order("updated_at desc,id desc")
+}
+
+// dataScope 用户列表的数据权限,用户属于其所在的部门,本人的数据为用户自身
+func (u *UserDAO) dataScope(ctx context.Context) func(db *gorm.DB) *gorm.DB {
+	return u.DataScope(ctx, func(db *gorm.DB, departmentIds []uint) clause.Expression {
+		return gorm.Expr("id in (?)", db.Model(&models.UserDepartment{}).
+			Select("user_id").Where("department_id in (?)", departmentIds))
+	}, func(_ *gorm.DB, uid uint) clause.Expression {
+		return gorm.Expr("id = ?", uid)
+	})
+}
+
+func (u *UserDAO) GetList(ctx context.Context, filter *models.UserFilter, limit, offset int) ([]*models.User, int64, error) {
+	var users []*models.User
+	var total int64
+	query := u.filterQuery(ctx, filter)
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
@@ -102,6 +160,22 @@ func (u *UserDAO) GetList(ctx context.Context, keyword string, limit, offset int
 	return users, total, nil
 }
 
+// GetAllByFilter 按条件查询全部用户,用于导出
+func (u *UserDAO) GetAllByFilter(ctx context.Context, filter *models.UserFilter) ([]*models.User, error) {
+	query := u.filterQuery(ctx, filter).Preload("Roles").Preload("Departments")
+	return queryAll[*models.User](query, u.QueryLimit)
+}
+
+// GetByEmails 查询邮箱已被使用的用户,包含已删除的用户
+func (u *UserDAO) GetByEmails(ctx context.Context, emails []string) ([]*models.User, error) {
+	var users []*models.User
+	err := u.Datasource(ctx).Unscoped().Model(&models.User{}).Where("email in (?)", emails).Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
 func (u *UserDAO) GetAll(ctx context.Context) ([]*models.User, error) {
 	res, err := queryAll[*models.User](u.Datasource(ctx).Model(&models.User{}), u.QueryLimit)
 	if err != nil {
@@ -113,3 +187,79 @@ func (u *UserDAO) GetAll(ctx context.Context) ([]*models.User, error) {
 func (u *UserDAO) UpdateAccountStatus(ctx context.Context, id uint, status constant.UserStatus) error {
 	return u.Datasource(ctx).Model(&models.User{}).Where("id = ?", id).Update("status", status).Error
 }
+
+// UpdatePassword 修改密码并记录修改时间
+func (u *UserDAO) UpdatePassword(ctx context.Context, id uint, password string) error {
+	return u.Datasource(ctx).Model(&models.User{}).Where("id = ?", id).
+		Updates(map[string]any{"password": password, "password_changed_at": time.Now()}).Error
+}
+
+// UpdateEmail 修改用户邮箱,用户当前邮箱不是 oldEmail 时返回 EmailChangeCodeInvalid,避免覆盖并发的修改
+func (u *UserDAO) UpdateEmail(ctx context.Context, id uint, oldEmail, newEmail string) error {
+	result := u.Datasource(ctx).Model(&models.User{}).Where("id = ? and email = ?", id, oldEmail).Update("email", newEmail)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return response.EmailChangeCodeInvalid
+	}
+	return nil
+}
+
+// UpdatePhone 修改用户绑定的手机号
+func (u *UserDAO) UpdatePhone(ctx context.Context, id uint, phone string) error {
+	return u.Datasource(ctx).Model(&models.User{}).Where("id = ?", id).Update("phone", phone).Error
+}
+
+func (u *UserDAO) UpdateProfile(ctx context.Context, user *models.User) error {
+	return u.Datasource(ctx).Model(user).Select("nickname", "avatar", "gender", "about", "birthday").Updates(user).Error
+}
+
+// DeleteByID 软删除用户,保留角色和部门关联以便恢复
+func (u *UserDAO) DeleteByID(ctx context.Context, id uint) error {
+	return u.Datasource(ctx).Where("id = ?", id).Delete(&models.User{}).Error
+}
+
+// GetDeletedByID 查询已删除的用户
+func (u *UserDAO) GetDeletedByID(ctx context.Context, id uint) (*models.User, error) {
+	var user models.User
+	err := u.Datasource(ctx).Unscoped().Model(&models.User{}).
+		Where("id = ? and deleted_at <> 0", id).First(&user).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.UserNotExist
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+// Restore 恢复已删除的用户
+func (u *UserDAO) Restore(ctx context.Context, id uint) error {
+	return u.Datasource(ctx).Unscoped().Model(&models.User{}).Where("id = ?", id).Update("deleted_at", 0).Error
+}
+
+// Anonymize 清除用户的个人信息并标记为已注销,保留用户记录,角色、部门等数据的创建人和更新人仍然有效
+func (u *UserDAO) Anonymize(ctx context.Context, id uint, email string) error {
+	return u.Datasource(ctx).Unscoped().Model(&models.User{}).Where("id = ?", id).Updates(map[string]any{
+		"email":               email,
+		"phone":               nil,
+		"password":            "",
+		"password_changed_at": nil,
+		"nickname":            nil,
+		"avatar":              nil,
+		"gender":              nil,
+		"about":               nil,
+		"birthday":            nil,
+		"status":              constant.Erased,
+	}).Error
+}
+
+func (u *UserDAO) AssociateRoles(ctx context.Context, id uint, roles []*models.Role) error {
+	return u.Datasource(ctx).Model(&models.User{BasicModel: database.BasicModel{ID: id}}).Association("Roles").Replace(roles)
+}
+
+func (u *UserDAO) AssociateDepartments(ctx context.Context, id uint, departments []*models.Department) error {
+	return u.Datasource(ctx).Model(&models.User{BasicModel: database.BasicModel{ID: id}}).
+		Association("Departments").Replace(departments)
+}
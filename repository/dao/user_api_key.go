@@ -0,0 +1,79 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/supuwoerc/weaver/models"
+	"github.com/supuwoerc/weaver/pkg/database"
+	"github.com/supuwoerc/weaver/pkg/response"
+
+	"gorm.io/gorm"
+)
+
+type UserAPIKeyDAO struct {
+	*BasicDAO
+}
+
+func NewUserAPIKeyDAO(basicDAO *BasicDAO) *UserAPIKeyDAO {
+	return &UserAPIKeyDAO{
+		BasicDAO: basicDAO,
+	}
+}
+
+func (u *UserAPIKeyDAO) Create(ctx context.Context, key *models.UserAPIKey) error {
+	return u.Datasource(ctx).Create(key).Error
+}
+
+func (u *UserAPIKeyDAO) GetByUserID(ctx context.Context, uid uint) ([]*models.UserAPIKey, error) {
+	var keys []*models.UserAPIKey
+	err := u.Datasource(ctx).Model(&models.UserAPIKey{}).Preload("Permissions").
+		Where("user_id = ?", uid).Order("id desc").Find(&keys).Error
+	return keys, err
+}
+
+func (u *UserAPIKeyDAO) CountByUserID(ctx context.Context, uid uint) (int64, error) {
+	var count int64
+	err := u.Datasource(ctx).Model(&models.UserAPIKey{}).Where("user_id = ?", uid).Count(&count).Error
+	return count, err
+}
+
+// GetByKey 根据密钥摘要查询密钥,同时加载所属用户及权限范围
+func (u *UserAPIKeyDAO) GetByKey(ctx context.Context, key string) (*models.UserAPIKey, error) {
+	var apiKey models.UserAPIKey
+	err := u.Datasource(ctx).Model(&models.UserAPIKey{}).Preload("User").Preload("Permissions").
+		Where("`key` = ?", key).First(&apiKey).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.APIKeyNotExist
+		}
+		return nil, err
+	}
+	return &apiKey, nil
+}
+
+func (u *UserAPIKeyDAO) DeleteByID(ctx context.Context, uid, id uint) error {
+	result := u.Datasource(ctx).Where("user_id = ?", uid).Delete(&models.UserAPIKey{
+		BasicModel: database.BasicModel{ID: id},
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return response.APIKeyNotExist
+	}
+	return nil
+}
+
+// DeleteByUserID 物理删除用户全部的API密钥
+func (u *UserAPIKeyDAO) DeleteByUserID(ctx context.Context, uid uint) error {
+	return u.Datasource(ctx).Unscoped().Where("user_id = ?", uid).Delete(&models.UserAPIKey{}).Error
+}
+
+// Touch 更新密钥的最后使用时间,距离上次更新不足 interval 时跳过,避免每个请求都写库
+func (u *UserAPIKeyDAO) Touch(ctx context.Context, id uint, now time.Time, interval time.Duration) error {
+	return u.Datasource(ctx).Model(&models.UserAPIKey{}).
+		Where("id = ? and (last_used_at is null or last_used_at < ?)", id, now.Add(-interval)).
+		UpdateColumn("last_used_at", now).Error
+}
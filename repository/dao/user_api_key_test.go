@@ -0,0 +1,107 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"github.com/supuwoerc/weaver/pkg/response"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+type UserAPIKeyDAOSuite struct {
+	apiKeyDAO *UserAPIKeyDAO
+	mock      sqlmock.Sqlmock
+	db        *sql.DB
+	suite.Suite
+}
+
+func TestUserAPIKeyDAOSuite(t *testing.T) {
+	suite.Run(t, new(UserAPIKeyDAOSuite))
+}
+
+func (s *UserAPIKeyDAOSuite) SetupSuite() {
+	t := s.T()
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	gormDB, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      db,
+		SkipInitializeWithVersion: true,
+	}))
+	require.NoError(t, err)
+	s.apiKeyDAO = NewUserAPIKeyDAO(NewBasicDao(gormDB))
+	s.mock = mock
+	s.db = db
+}
+
+func (s *UserAPIKeyDAOSuite) TearDownSuite() {
+	_ = s.db.Close()
+}
+
+func (s *UserAPIKeyDAOSuite) TestUserAPIKeyDAO_GetByKey() {
+	t := s.T()
+	s.Run("api key not found", func() {
+		defer func() {
+			assert.NoError(t, s.mock.ExpectationsWereMet())
+		}()
+		s.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `user_api_keys` WHERE `key` = ?")).
+			WithArgs("hash", 0, 1).
+			WillReturnError(gorm.ErrRecordNotFound)
+		key, err := s.apiKeyDAO.GetByKey(context.Background(), "hash")
+		assert.Nil(t, key)
+		assert.Equal(t, response.APIKeyNotExist, err)
+	})
+}
+
+func (s *UserAPIKeyDAOSuite) TestUserAPIKeyDAO_DeleteByID() {
+	t := s.T()
+	s.Run("successful delete", func() {
+		defer func() {
+			assert.NoError(t, s.mock.ExpectationsWereMet())
+		}()
+		s.mock.ExpectBegin()
+		s.mock.ExpectExec(regexp.QuoteMeta("UPDATE `user_api_keys` SET `deleted_at`=?")).
+			WithArgs(sqlmock.AnyArg(), 1, 2, 0).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		s.mock.ExpectCommit()
+		err := s.apiKeyDAO.DeleteByID(context.Background(), 1, 2)
+		assert.NoError(t, err)
+	})
+
+	s.Run("api key belongs to another user", func() {
+		defer func() {
+			assert.NoError(t, s.mock.ExpectationsWereMet())
+		}()
+		s.mock.ExpectBegin()
+		s.mock.ExpectExec(regexp.QuoteMeta("UPDATE `user_api_keys` SET `deleted_at`=?")).
+			WithArgs(sqlmock.AnyArg(), 1, 2, 0).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		s.mock.ExpectCommit()
+		err := s.apiKeyDAO.DeleteByID(context.Background(), 1, 2)
+		assert.Equal(t, response.APIKeyNotExist, err)
+	})
+}
+
+func (s *UserAPIKeyDAOSuite) TestUserAPIKeyDAO_Touch() {
+	t := s.T()
+	s.Run("update last used at", func() {
+		defer func() {
+			assert.NoError(t, s.mock.ExpectationsWereMet())
+		}()
+		now := time.Now()
+		s.mock.ExpectBegin()
+		s.mock.ExpectExec(regexp.QuoteMeta("UPDATE `user_api_keys` SET `last_used_at`=? WHERE (id = ? and (last_used_at is null or last_used_at < ?))")).
+			WithArgs(now, 1, now.Add(-time.Minute), 0).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		s.mock.ExpectCommit()
+		err := s.apiKeyDAO.Touch(context.Background(), 1, now, time.Minute)
+		assert.NoError(t, err)
+	})
+}
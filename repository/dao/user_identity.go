@@ -0,0 +1,43 @@
+package dao
+
+import (
+	"context"
+	"errors"
+
+	"github.com/supuwoerc/weaver/models"
+	"github.com/supuwoerc/weaver/pkg/response"
+
+	"gorm.io/gorm"
+)
+
+type UserIdentityDAO struct {
+	*BasicDAO
+}
+
+func NewUserIdentityDAO(basicDAO *BasicDAO) *UserIdentityDAO {
+	return &UserIdentityDAO{
+		BasicDAO: basicDAO,
+	}
+}
+
+func (u *UserIdentityDAO) Create(ctx context.Context, identity *models.UserIdentity) error {
+	return u.Datasource(ctx).Create(identity).Error
+}
+
+func (u *UserIdentityDAO) GetByProviderSubject(ctx context.Context, provider, subject string) (*models.UserIdentity, error) {
+	var identity models.UserIdentity
+	err := u.Datasource(ctx).Model(&models.UserIdentity{}).
+		Where("provider = ? and subject = ?", provider, subject).First(&identity).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.UserIdentityNotExist
+		}
+		return nil, err
+	}
+	return &identity, nil
+}
+
+// DeleteByUserID 物理删除用户绑定的全部外部账户
+func (u *UserIdentityDAO) DeleteByUserID(ctx context.Context, uid uint) error {
+	return u.Datasource(ctx).Unscoped().Where("user_id = ?", uid).Delete(&models.UserIdentity{}).Error
+}
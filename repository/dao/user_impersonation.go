@@ -0,0 +1,52 @@
+package dao
+
+import (
+	"context"
+	"time"
+
+	"github.com/supuwoerc/weaver/models"
+)
+
+type UserImpersonationDAO struct {
+	*BasicDAO
+}
+
+func NewUserImpersonationDAO(basicDAO *BasicDAO) *UserImpersonationDAO {
+	return &UserImpersonationDAO{
+		BasicDAO: basicDAO,
+	}
+}
+
+func (u *UserImpersonationDAO) Create(ctx context.Context, record *models.UserImpersonation) error {
+	return u.Datasource(ctx).Create(record).Error
+}
+
+// Stop 记录模拟登录的结束时间,已结束的记录不做修改
+func (u *UserImpersonationDAO) Stop(ctx context.Context, tokenID string, stoppedAt time.Time) error {
+	return u.Datasource(ctx).Model(&models.UserImpersonation{}).
+		Where("token_id = ? and stopped_at is null", tokenID).
+		Update("stopped_at", stoppedAt).Error
+}
+
+// GetList 查询模拟登录记录,actorID和userID为0时不过滤
+func (u *UserImpersonationDAO) GetList(
+	ctx context.Context, actorID, userID uint, limit, offset int,
+) ([]*models.UserImpersonation, int64, error) {
+	var records []*models.UserImpersonation
+	var total int64
+	query := u.Datasource(ctx).Model(&models.UserImpersonation{})
+	if actorID != 0 {
+		query = query.Where("actor_id = ?", actorID)
+	}
+	if userID != 0 {
+		query = query.Where("user_id = ?", userID)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := query.Preload("Actor").Preload("User").Order("id desc").Limit(limit).Offset(offset).Find(&records).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return records, total, nil
+}
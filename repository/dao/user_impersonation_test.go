@@ -0,0 +1,63 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+type UserImpersonationDAOSuite struct {
+	impersonationDAO *UserImpersonationDAO
+	mock             sqlmock.Sqlmock
+	db               *sql.DB
+	suite.Suite
+}
+
+func TestUserImpersonationDAOSuite(t *testing.T) {
+	suite.Run(t, new(UserImpersonationDAOSuite))
+}
+
+func (s *UserImpersonationDAOSuite) SetupSuite() {
+	t := s.T()
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	gormDB, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      db,
+		SkipInitializeWithVersion: true,
+	}))
+	require.NoError(t, err)
+	s.impersonationDAO = NewUserImpersonationDAO(NewBasicDao(gormDB))
+	s.mock = mock
+	s.db = db
+}
+
+func (s *UserImpersonationDAOSuite) TearDownSuite() {
+	_ = s.db.Close()
+}
+
+func (s *UserImpersonationDAOSuite) TestUserImpersonationDAO_Stop() {
+	t := s.T()
+	s.Run("only update running impersonation", func() {
+		defer func() {
+			assert.NoError(t, s.mock.ExpectationsWereMet())
+		}()
+		stoppedAt := time.Now()
+		s.mock.ExpectBegin()
+		s.mock.ExpectExec(regexp.QuoteMeta("UPDATE `user_impersonations` SET `stopped_at`=?,`updated_at`=? "+
+			"WHERE (token_id = ? and stopped_at is null) AND `user_impersonations`.`deleted_at` = ?")).
+			WithArgs(stoppedAt, sqlmock.AnyArg(), "jti", 0).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		s.mock.ExpectCommit()
+		err := s.impersonationDAO.Stop(context.Background(), "jti", stoppedAt)
+		assert.NoError(t, err)
+	})
+}
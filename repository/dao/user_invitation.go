@@ -0,0 +1,87 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/supuwoerc/weaver/models"
+	"github.com/supuwoerc/weaver/pkg/database"
+	"github.com/supuwoerc/weaver/pkg/response"
+
+	"gorm.io/gorm"
+)
+
+type UserInvitationDAO struct {
+	*BasicDAO
+}
+
+func NewUserInvitationDAO(basicDAO *BasicDAO) *UserInvitationDAO {
+	return &UserInvitationDAO{
+		BasicDAO: basicDAO,
+	}
+}
+
+func (u *UserInvitationDAO) Create(ctx context.Context, invitation *models.UserInvitation) error {
+	return u.Datasource(ctx).Create(invitation).Error
+}
+
+// GetByCode 根据邀请码摘要查询邀请,同时加载预设的角色和部门
+func (u *UserInvitationDAO) GetByCode(ctx context.Context, code string) (*models.UserInvitation, error) {
+	var invitation models.UserInvitation
+	err := u.Datasource(ctx).Model(&models.UserInvitation{}).Preload("Roles").Preload("Departments").
+		Where("code = ?", code).First(&invitation).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.InvitationNotExist
+		}
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+func (u *UserInvitationDAO) GetList(ctx context.Context, keyword string, limit, offset int) ([]*models.UserInvitation, int64, error) {
+	var invitations []*models.UserInvitation
+	var total int64
+	query := u.Datasource(ctx).Model(&models.UserInvitation{})
+	if keyword != "" {
+		query = query.Where("email like ?", database.FuzzKeyword(keyword))
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := query.Preload("Inviter").Preload("Roles").Preload("Departments").
+		Order("id desc").Limit(limit).Offset(offset).Find(&invitations).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return invitations, total, nil
+}
+
+// Accept 标记邀请已被接受,邀请已被接受时返回 InvitationNotExist,避免并发重复接受
+func (u *UserInvitationDAO) Accept(ctx context.Context, id uint, uid uint, now time.Time) error {
+	result := u.Datasource(ctx).Model(&models.UserInvitation{}).
+		Where("id = ? and accepted_at is null", id).
+		Updates(map[string]any{"accepted_at": now, "user_id": uid})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return response.InvitationNotExist
+	}
+	return nil
+}
+
+// DeleteByID 删除未被接受的邀请,已接受的邀请保留用于追溯
+func (u *UserInvitationDAO) DeleteByID(ctx context.Context, id uint) error {
+	result := u.Datasource(ctx).Where("accepted_at is null").Delete(&models.UserInvitation{
+		BasicModel: database.BasicModel{ID: id},
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return response.InvitationNotExist
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/supuwoerc/weaver/pkg/response"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+type UserInvitationDAOSuite struct {
+	invitationDAO *UserInvitationDAO
+	mock          sqlmock.Sqlmock
+	db            *sql.DB
+	suite.Suite
+}
+
+func TestUserInvitationDAOSuite(t *testing.T) {
+	suite.Run(t, new(UserInvitationDAOSuite))
+}
+
+func (s *UserInvitationDAOSuite) SetupSuite() {
+	t := s.T()
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	gormDB, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      db,
+		SkipInitializeWithVersion: true,
+	}))
+	require.NoError(t, err)
+	s.invitationDAO = NewUserInvitationDAO(NewBasicDao(gormDB))
+	s.mock = mock
+	s.db = db
+}
+
+func (s *UserInvitationDAOSuite) TearDownSuite() {
+	_ = s.db.Close()
+}
+
+func (s *UserInvitationDAOSuite) TestUserInvitationDAO_Accept() {
+	t := s.T()
+	query := regexp.QuoteMeta("UPDATE `user_invitations` SET `accepted_at`=?,`user_id`=?,`updated_at`=? " +
+		"WHERE (id = ? and accepted_at is null) AND `user_invitations`.`deleted_at` = ?")
+	s.Run("accept pending invitation", func() {
+		defer func() {
+			assert.NoError(t, s.mock.ExpectationsWereMet())
+		}()
+		now := time.Now()
+		s.mock.ExpectBegin()
+		s.mock.ExpectExec(query).
+			WithArgs(now, 2, sqlmock.AnyArg(), 1, 0).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		s.mock.ExpectCommit()
+		err := s.invitationDAO.Accept(context.Background(), 1, 2, now)
+		assert.NoError(t, err)
+	})
+
+	s.Run("invitation already accepted", func() {
+		defer func() {
+			assert.NoError(t, s.mock.ExpectationsWereMet())
+		}()
+		now := time.Now()
+		s.mock.ExpectBegin()
+		s.mock.ExpectExec(query).
+			WithArgs(now, 2, sqlmock.AnyArg(), 1, 0).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		s.mock.ExpectCommit()
+		err := s.invitationDAO.Accept(context.Background(), 1, 2, now)
+		assert.ErrorIs(t, err, response.InvitationNotExist)
+	})
+}
+
+func (s *UserInvitationDAOSuite) TestUserInvitationDAO_GetByCode() {
+	t := s.T()
+	s.Run("invitation not exist", func() {
+		defer func() {
+			assert.NoError(t, s.mock.ExpectationsWereMet())
+		}()
+		s.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `user_invitations` WHERE code = ? "+
+			"AND `user_invitations`.`deleted_at` = ? ORDER BY `user_invitations`.`id` LIMIT ?")).
+			WithArgs("hash", 0, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		invitation, err := s.invitationDAO.GetByCode(context.Background(), "hash")
+		assert.Nil(t, invitation)
+		assert.ErrorIs(t, err, response.InvitationNotExist)
+	})
+}
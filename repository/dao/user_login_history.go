@@ -0,0 +1,81 @@
+package dao
+
+import (
+	"context"
+
+	"github.com/supuwoerc/weaver/models"
+	"github.com/supuwoerc/weaver/pkg/database"
+)
+
+type UserLoginHistoryDAO struct {
+	*BasicDAO
+}
+
+func NewUserLoginHistoryDAO(basicDAO *BasicDAO) *UserLoginHistoryDAO {
+	return &UserLoginHistoryDAO{
+		BasicDAO: basicDAO,
+	}
+}
+
+func (u *UserLoginHistoryDAO) Create(ctx context.Context, record *models.UserLoginHistory) error {
+	return u.Datasource(ctx).Create(record).Error
+}
+
+// GetList 按条件查询登录记录,条件为零值时不过滤,按时间倒序
+func (u *UserLoginHistoryDAO) GetList(
+	ctx context.Context, filter *models.LoginHistoryFilter, limit, offset int,
+) ([]*models.UserLoginHistory, int64, error) {
+	var records []*models.UserLoginHistory
+	var total int64
+	query := u.Datasource(ctx).Model(&models.UserLoginHistory{})
+	if filter.UserID != 0 {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if filter.Email != "" {
+		query = query.Where("email like ?", database.FuzzKeyword(filter.Email))
+	}
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+	if filter.Success != nil {
+		query = query.Where("success = ?", *filter.Success)
+	}
+	if filter.IP != "" {
+		query = query.Where("ip = ?", filter.IP)
+	}
+	if filter.Start != nil {
+		query = query.Where("created_at >= ?", *filter.Start)
+	}
+	if filter.End != nil {
+		query = query.Where("created_at < ?", *filter.End)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := query.Order("id desc").Limit(limit).Offset(offset).Find(&records).Error; err != nil {
+		return nil, 0, err
+	}
+	return records, total, nil
+}
+
+// CountSuccess 统计用户在指定记录之前成功登录的次数,ip和userAgent为空时不过滤,用于判断是否来自新的ip或设备
+func (u *UserLoginHistoryDAO) CountSuccess(ctx context.Context, uid, beforeID uint, ip, userAgent string) (int64, error) {
+	var count int64
+	query := u.Datasource(ctx).Model(&models.UserLoginHistory{}).
+		Where("user_id = ? and success = ? and id < ?", uid, true, beforeID)
+	if ip != "" {
+		query = query.Where("ip = ?", ip)
+	}
+	if userAgent != "" {
+		query = query.Where("user_agent = ?", userAgent)
+	}
+	if err := query.Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// DeleteByUserID 物理删除用户全部的登录记录
+func (u *UserLoginHistoryDAO) DeleteByUserID(ctx context.Context, uid uint) error {
+	return u.Datasource(ctx).Unscoped().Where("user_id = ?", uid).Delete(&models.UserLoginHistory{}).Error
+}
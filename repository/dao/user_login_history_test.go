@@ -0,0 +1,92 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+
+	"github.com/supuwoerc/weaver/models"
+	"github.com/supuwoerc/weaver/pkg/constant"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+type UserLoginHistoryDAOSuite struct {
+	loginHistoryDAO *UserLoginHistoryDAO
+	mock            sqlmock.Sqlmock
+	db              *sql.DB
+	suite.Suite
+}
+
+func TestUserLoginHistoryDAOSuite(t *testing.T) {
+	suite.Run(t, new(UserLoginHistoryDAOSuite))
+}
+
+func (s *UserLoginHistoryDAOSuite) SetupSuite() {
+	t := s.T()
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	gormDB, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      db,
+		SkipInitializeWithVersion: true,
+	}))
+	require.NoError(t, err)
+	s.loginHistoryDAO = NewUserLoginHistoryDAO(NewBasicDao(gormDB))
+	s.mock = mock
+	s.db = db
+}
+
+func (s *UserLoginHistoryDAOSuite) TearDownSuite() {
+	_ = s.db.Close()
+}
+
+func (s *UserLoginHistoryDAOSuite) TestUserLoginHistoryDAO_GetList() {
+	t := s.T()
+	s.Run("filter by user, type and result", func() {
+		defer func() {
+			assert.NoError(t, s.mock.ExpectationsWereMet())
+		}()
+		s.mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `user_login_histories` "+
+			"WHERE user_id = ? AND type = ? AND success = ? AND `user_login_histories`.`deleted_at` = ?")).
+			WithArgs(1, constant.LoginPassword, false, 0).
+			WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(1))
+		s.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `user_login_histories` "+
+			"WHERE user_id = ? AND type = ? AND success = ? AND `user_login_histories`.`deleted_at` = ? "+
+			"ORDER BY id desc LIMIT ?")).
+			WithArgs(1, constant.LoginPassword, false, 0, 10).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "type", "success", "reason"}).
+				AddRow(2, 1, constant.LoginPassword, false, "userLoginFail"))
+		records, total, err := s.loginHistoryDAO.GetList(context.Background(), &models.LoginHistoryFilter{
+			UserID:  1,
+			Type:    constant.LoginPassword,
+			Success: lo.ToPtr(false),
+		}, 10, 0)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		require.Len(t, records, 1)
+		assert.Equal(t, "userLoginFail", records[0].Reason)
+	})
+}
+
+func (s *UserLoginHistoryDAOSuite) TestUserLoginHistoryDAO_CountSuccess() {
+	t := s.T()
+	s.Run("filter by ip", func() {
+		defer func() {
+			assert.NoError(t, s.mock.ExpectationsWereMet())
+		}()
+		s.mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `user_login_histories` "+
+			"WHERE (user_id = ? and success = ? and id < ?) AND ip = ? AND `user_login_histories`.`deleted_at` = ?")).
+			WithArgs(1, true, 10, "127.0.0.1", 0).
+			WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(3))
+		count, err := s.loginHistoryDAO.CountSuccess(context.Background(), 1, 10, "127.0.0.1", "")
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), count)
+	})
+}
@@ -0,0 +1,75 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/supuwoerc/weaver/models"
+	"github.com/supuwoerc/weaver/pkg/response"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type UserMFADAO struct {
+	*BasicDAO
+}
+
+func NewUserMFADAO(basicDAO *BasicDAO) *UserMFADAO {
+	return &UserMFADAO{
+		BasicDAO: basicDAO,
+	}
+}
+
+func (u *UserMFADAO) GetByUserID(ctx context.Context, uid uint) (*models.UserMFA, error) {
+	var mfa models.UserMFA
+	err := u.Datasource(ctx).Model(&models.UserMFA{}).Where("user_id = ?", uid).First(&mfa).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.MFANotEnabled
+		}
+		return nil, err
+	}
+	return &mfa, nil
+}
+
+// Upsert 保存用户的TOTP密钥,已存在时覆盖密钥并重置为未启用
+func (u *UserMFADAO) Upsert(ctx context.Context, mfa *models.UserMFA) error {
+	return u.Datasource(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"secret", "enabled", "enabled_at", "updated_at"}),
+	}).Create(mfa).Error
+}
+
+func (u *UserMFADAO) Enable(ctx context.Context, uid uint) error {
+	return u.Datasource(ctx).Model(&models.UserMFA{}).Where("user_id = ?", uid).
+		Updates(map[string]any{"enabled": true, "enabled_at": time.Now()}).Error
+}
+
+func (u *UserMFADAO) DeleteByUserID(ctx context.Context, uid uint) error {
+	return u.Datasource(ctx).Unscoped().Where("user_id = ?", uid).Delete(&models.UserMFA{}).Error
+}
+
+// ReplaceRecoveryCodes 使用新的恢复码替换用户全部的恢复码
+func (u *UserMFADAO) ReplaceRecoveryCodes(ctx context.Context, uid uint, codes []*models.UserRecoveryCode) error {
+	if err := u.DeleteRecoveryCodes(ctx, uid); err != nil {
+		return err
+	}
+	return u.Datasource(ctx).Create(codes).Error
+}
+
+// UseRecoveryCode 将未使用的恢复码标记为已使用,恢复码不存在或已被使用时返回false
+func (u *UserMFADAO) UseRecoveryCode(ctx context.Context, uid uint, code string) (bool, error) {
+	result := u.Datasource(ctx).Model(&models.UserRecoveryCode{}).
+		Where("user_id = ? and code = ? and used_at is null", uid, code).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (u *UserMFADAO) DeleteRecoveryCodes(ctx context.Context, uid uint) error {
+	return u.Datasource(ctx).Unscoped().Where("user_id = ?", uid).Delete(&models.UserRecoveryCode{}).Error
+}
@@ -0,0 +1,41 @@
+package dao
+
+import (
+	"context"
+
+	"github.com/supuwoerc/weaver/models"
+)
+
+type UserPasswordHistoryDAO struct {
+	*BasicDAO
+}
+
+func NewUserPasswordHistoryDAO(basicDAO *BasicDAO) *UserPasswordHistoryDAO {
+	return &UserPasswordHistoryDAO{
+		BasicDAO: basicDAO,
+	}
+}
+
+func (u *UserPasswordHistoryDAO) Create(ctx context.Context, history *models.UserPasswordHistory) error {
+	return u.Datasource(ctx).Create(history).Error
+}
+
+// GetRecent 查询用户最近使用过的 limit 个密码
+func (u *UserPasswordHistoryDAO) GetRecent(ctx context.Context, uid uint, limit int) ([]*models.UserPasswordHistory, error) {
+	var histories []*models.UserPasswordHistory
+	err := u.Datasource(ctx).Model(&models.UserPasswordHistory{}).
+		Where("user_id = ?", uid).Order("id desc").Limit(limit).Find(&histories).Error
+	return histories, err
+}
+
+// Prune 物理删除用户最近 keep 个以外的历史密码
+func (u *UserPasswordHistoryDAO) Prune(ctx context.Context, uid uint, keep int) error {
+	var ids []uint
+	err := u.Datasource(ctx).Model(&models.UserPasswordHistory{}).
+		Where("user_id = ?", uid).Order("id desc").Offset(keep).Limit(1).Pluck("id", &ids).Error
+	if err != nil || len(ids) == 0 {
+		return err
+	}
+	return u.Datasource(ctx).Unscoped().Where("user_id = ? and id <= ?", uid, ids[0]).
+		Delete(&models.UserPasswordHistory{}).Error
+}
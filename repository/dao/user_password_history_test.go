@@ -0,0 +1,76 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+type UserPasswordHistoryDAOSuite struct {
+	historyDAO *UserPasswordHistoryDAO
+	mock       sqlmock.Sqlmock
+	db         *sql.DB
+	suite.Suite
+}
+
+func TestUserPasswordHistoryDAOSuite(t *testing.T) {
+	suite.Run(t, new(UserPasswordHistoryDAOSuite))
+}
+
+func (s *UserPasswordHistoryDAOSuite) SetupSuite() {
+	t := s.T()
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	gormDB, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      db,
+		SkipInitializeWithVersion: true,
+	}))
+	require.NoError(t, err)
+	s.historyDAO = NewUserPasswordHistoryDAO(NewBasicDao(gormDB))
+	s.mock = mock
+	s.db = db
+}
+
+func (s *UserPasswordHistoryDAOSuite) TearDownSuite() {
+	_ = s.db.Close()
+}
+
+func (s *UserPasswordHistoryDAOSuite) TestUserPasswordHistoryDAO_Prune() {
+	t := s.T()
+	query := regexp.QuoteMeta("SELECT `id` FROM `user_password_histories` WHERE user_id = ? " +
+		"AND `user_password_histories`.`deleted_at` = ? ORDER BY id desc LIMIT ? OFFSET ?")
+	s.Run("delete histories beyond keep", func() {
+		defer func() {
+			assert.NoError(t, s.mock.ExpectationsWereMet())
+		}()
+		s.mock.ExpectQuery(query).
+			WithArgs(1, 0, 1, 5).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10))
+		s.mock.ExpectBegin()
+		s.mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `user_password_histories` WHERE user_id = ? and id <= ?")).
+			WithArgs(1, 10).
+			WillReturnResult(sqlmock.NewResult(0, 3))
+		s.mock.ExpectCommit()
+		err := s.historyDAO.Prune(context.Background(), 1, 5)
+		assert.NoError(t, err)
+	})
+
+	s.Run("nothing to prune", func() {
+		defer func() {
+			assert.NoError(t, s.mock.ExpectationsWereMet())
+		}()
+		s.mock.ExpectQuery(query).
+			WithArgs(1, 0, 1, 5).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		err := s.historyDAO.Prune(context.Background(), 1, 5)
+		assert.NoError(t, err)
+	})
+}
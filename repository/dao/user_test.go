@@ -0,0 +1,350 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"github.com/supuwoerc/weaver/models"
+	"github.com/supuwoerc/weaver/pkg/constant"
+	"github.com/supuwoerc/weaver/pkg/database"
+	"github.com/supuwoerc/weaver/pkg/response"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+type UserDAOSuite struct {
+	userDAO *UserDAO
+	mock    sqlmock.Sqlmock
+	db      *sql.DB
+	suite.Suite
+}
+
+func TestUserDAOSuite(t *testing.T) {
+	suite.Run(t, new(UserDAOSuite))
+}
+
+func (s *UserDAOSuite) SetupSuite() {
+	t := s.T()
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	gormDB, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      db,
+		SkipInitializeWithVersion: true,
+	}))
+	require.NoError(t, err)
+	s.userDAO = NewUserDAO(NewBasicDao(gormDB))
+	s.mock = mock
+	s.db = db
+}
+
+func (s *UserDAOSuite) TearDownSuite() {
+	_ = s.db.Close()
+}
+
+func (s *UserDAOSuite) TestUserDAO_UpdatePassword() {
+	t := s.T()
+	s.Run("successful update password", func() {
+		defer func() {
+			assert.NoError(t, s.mock.ExpectationsWereMet())
+		}()
+		s.mock.ExpectBegin()
+		s.mock.ExpectExec(regexp.QuoteMeta("UPDATE `users` SET `password`=?")).
+			WithArgs("hash", sqlmock.AnyArg(), sqlmock.AnyArg(), 1, 0).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		s.mock.ExpectCommit()
+		err := s.userDAO.UpdatePassword(context.Background(), 1, "hash")
+		assert.NoError(t, err)
+	})
+
+	s.Run("db error", func() {
+		defer func() {
+			assert.NoError(t, s.mock.ExpectationsWereMet())
+		}()
+		dbErr := errors.New("db error")
+		s.mock.ExpectBegin()
+		s.mock.ExpectExec(regexp.QuoteMeta("UPDATE `users` SET `password`=?")).
+			WithArgs(mockCountArgs(5)...).
+			WillReturnError(dbErr)
+		s.mock.ExpectRollback()
+		err := s.userDAO.UpdatePassword(context.Background(), 1, "hash")
+		assert.ErrorIs(t, err, dbErr)
+	})
+}
+
+func (s *UserDAOSuite) TestUserDAO_UpdateEmail() {
+	t := s.T()
+	query := regexp.QuoteMeta("UPDATE `users` SET `email`=?,`updated_at`=? " +
+		"WHERE (id = ? and email = ?) AND `users`.`deleted_at` = ?")
+	s.Run("successful update email", func() {
+		defer func() {
+			assert.NoError(t, s.mock.ExpectationsWereMet())
+		}()
+		s.mock.ExpectBegin()
+		s.mock.ExpectExec(query).
+			WithArgs("new@example.com", sqlmock.AnyArg(), 1, "old@example.com", 0).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		s.mock.ExpectCommit()
+		err := s.userDAO.UpdateEmail(context.Background(), 1, "old@example.com", "new@example.com")
+		assert.NoError(t, err)
+	})
+
+	s.Run("email already changed", func() {
+		defer func() {
+			assert.NoError(t, s.mock.ExpectationsWereMet())
+		}()
+		s.mock.ExpectBegin()
+		s.mock.ExpectExec(query).
+			WithArgs("new@example.com", sqlmock.AnyArg(), 1, "old@example.com", 0).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		s.mock.ExpectCommit()
+		err := s.userDAO.UpdateEmail(context.Background(), 1, "old@example.com", "new@example.com")
+		assert.ErrorIs(t, err, response.EmailChangeCodeInvalid)
+	})
+}
+
+func (s *UserDAOSuite) TestUserDAO_GetByPhone() {
+	t := s.T()
+	query := regexp.QuoteMeta("SELECT * FROM `users` WHERE phone = ? AND `users`.`deleted_at` = ? " +
+		"ORDER BY `users`.`id` LIMIT ?")
+	s.Run("phone bound", func() {
+		defer func() {
+			assert.NoError(t, s.mock.ExpectationsWereMet())
+		}()
+		s.mock.ExpectQuery(query).
+			WithArgs("13800000000", 0, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "email", "phone"}).AddRow(1, "a@example.com", "13800000000"))
+		user, err := s.userDAO.GetByPhone(context.Background(), "13800000000")
+		require.NoError(t, err)
+		assert.Equal(t, uint(1), user.ID)
+		assert.Equal(t, "13800000000", *user.Phone)
+	})
+
+	s.Run("phone not bound", func() {
+		defer func() {
+			assert.NoError(t, s.mock.ExpectationsWereMet())
+		}()
+		s.mock.ExpectQuery(query).
+			WithArgs("13800000000", 0, 1).
+			WillReturnError(gorm.ErrRecordNotFound)
+		_, err := s.userDAO.GetByPhone(context.Background(), "13800000000")
+		assert.ErrorIs(t, err, response.UserNotExist)
+	})
+}
+
+func (s *UserDAOSuite) TestUserDAO_UpdateProfile() {
+	t := s.T()
+	s.Run("update profile fields only", func() {
+		defer func() {
+			assert.NoError(t, s.mock.ExpectationsWereMet())
+		}()
+		nickname := "weaver"
+		gender := constant.Male
+		birthday := time.Date(2000, 1, 1, 0, 0, 0, 0, time.Local)
+		user := &models.User{
+			Email:      "ignored@example.com",
+			Nickname:   &nickname,
+			Gender:     &gender,
+			Birthday:   &birthday,
+			BasicModel: database.BasicModel{ID: 1},
+		}
+		s.mock.ExpectBegin()
+		s.mock.ExpectExec(regexp.QuoteMeta("UPDATE `users` SET `nickname`=?,`avatar`=?,`gender`=?,`about`=?,`birthday`=?,`updated_at`=? "+
+			"WHERE `users`.`deleted_at` = ? AND `id` = ?")).
+			WithArgs(nickname, nil, gender, nil, birthday, sqlmock.AnyArg(), 0, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		s.mock.ExpectCommit()
+		err := s.userDAO.UpdateProfile(context.Background(), user)
+		assert.NoError(t, err)
+	})
+}
+
+func (s *UserDAOSuite) TestUserDAO_DeleteByID() {
+	t := s.T()
+	s.Run("soft delete", func() {
+		defer func() {
+			assert.NoError(t, s.mock.ExpectationsWereMet())
+		}()
+		s.mock.ExpectBegin()
+		s.mock.ExpectExec(regexp.QuoteMeta("UPDATE `users` SET `deleted_at`=? WHERE id = ? AND `users`.`deleted_at` = ?")).
+			WithArgs(sqlmock.AnyArg(), 1, 0).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		s.mock.ExpectCommit()
+		err := s.userDAO.DeleteByID(context.Background(), 1)
+		assert.NoError(t, err)
+	})
+}
+
+func (s *UserDAOSuite) TestUserDAO_GetDeletedByID() {
+	t := s.T()
+	s.Run("deleted user", func() {
+		defer func() {
+			assert.NoError(t, s.mock.ExpectationsWereMet())
+		}()
+		s.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `users` WHERE id = ? and deleted_at <> 0 ORDER BY `users`.`id` LIMIT ?")).
+			WithArgs(1, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "email", "deleted_at"}).AddRow(1, "a@b.com", 1))
+		user, err := s.userDAO.GetDeletedByID(context.Background(), 1)
+		require.NoError(t, err)
+		assert.Equal(t, "a@b.com", user.Email)
+	})
+
+	s.Run("not deleted", func() {
+		defer func() {
+			assert.NoError(t, s.mock.ExpectationsWereMet())
+		}()
+		s.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `users` WHERE id = ? and deleted_at <> 0")).
+			WithArgs(1, 1).
+			WillReturnError(gorm.ErrRecordNotFound)
+		_, err := s.userDAO.GetDeletedByID(context.Background(), 1)
+		assert.ErrorIs(t, err, response.UserNotExist)
+	})
+}
+
+func (s *UserDAOSuite) TestUserDAO_GetByIdsWithDeleted() {
+	t := s.T()
+	s.Run("include deleted users", func() {
+		defer func() {
+			assert.NoError(t, s.mock.ExpectationsWereMet())
+		}()
+		s.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `users` WHERE id in (?,?)")).
+			WithArgs(1, 2).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "email", "deleted_at"}).
+				AddRow(1, "a@b.com", 0).AddRow(2, "erased-2@erased.invalid", 1))
+		users, err := s.userDAO.GetByIdsWithDeleted(context.Background(), []uint{1, 2})
+		require.NoError(t, err)
+		assert.Len(t, users, 2)
+	})
+}
+
+func (s *UserDAOSuite) TestUserDAO_Restore() {
+	t := s.T()
+	s.Run("restore", func() {
+		defer func() {
+			assert.NoError(t, s.mock.ExpectationsWereMet())
+		}()
+		s.mock.ExpectBegin()
+		s.mock.ExpectExec(regexp.QuoteMeta("UPDATE `users` SET `deleted_at`=?,`updated_at`=? WHERE id = ?")).
+			WithArgs(0, sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		s.mock.ExpectCommit()
+		err := s.userDAO.Restore(context.Background(), 1)
+		assert.NoError(t, err)
+	})
+}
+
+func (s *UserDAOSuite) TestUserDAO_Anonymize() {
+	t := s.T()
+	s.Run("anonymize deleted user", func() {
+		defer func() {
+			assert.NoError(t, s.mock.ExpectationsWereMet())
+		}()
+		s.mock.ExpectBegin()
+		s.mock.ExpectExec(regexp.QuoteMeta("UPDATE `users` SET `about`=?,`avatar`=?,`birthday`=?,`email`=?,`gender`=?,"+
+			"`nickname`=?,`password`=?,`password_changed_at`=?,`phone`=?,`status`=?,`updated_at`=? WHERE id = ?")).
+			WithArgs(nil, nil, nil, "erased-1@erased.invalid", nil, nil, "", nil, nil, constant.Erased, sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		s.mock.ExpectCommit()
+		err := s.userDAO.Anonymize(context.Background(), 1, "erased-1@erased.invalid")
+		assert.NoError(t, err)
+	})
+}
+
+func (s *UserDAOSuite) TestUserDAO_GetList() {
+	t := s.T()
+	s.Run("filter by status, role and department", func() {
+		defer func() {
+			assert.NoError(t, s.mock.ExpectationsWereMet())
+		}()
+		s.mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `users` WHERE (nickname like ? or email like ?) "+
+			"AND status = ? AND id in (SELECT `user_id` FROM `user_roles` WHERE role_id = ?) "+
+			"AND id in (SELECT `user_id` FROM `user_departments` WHERE department_id = ?) AND `users`.`deleted_at` = ?")).
+			WithArgs("%dev%", "%dev%", int(constant.Normal), 2, 3, 0).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		s.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `users`")).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		list, total, err := s.userDAO.GetList(context.Background(), &models.UserFilter{
+			Keyword:      "dev",
+			Status:       constant.Normal,
+			RoleID:       2,
+			DepartmentID: 3,
+		}, 10, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), total)
+		assert.Empty(t, list)
+	})
+}
+
+func (s *UserDAOSuite) TestUserDAO_GetByEmails() {
+	t := s.T()
+	s.Run("include deleted users", func() {
+		defer func() {
+			assert.NoError(t, s.mock.ExpectationsWereMet())
+		}()
+		s.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `users` WHERE email in (?,?)")).
+			WithArgs("a@example.com", "b@example.com").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "email", "deleted_at"}).AddRow(1, "a@example.com", 1))
+		users, err := s.userDAO.GetByEmails(context.Background(), []string{"a@example.com", "b@example.com"})
+		assert.NoError(t, err)
+		require.Len(t, users, 1)
+		assert.Equal(t, "a@example.com", users[0].Email)
+	})
+}
+
+func (s *UserDAOSuite) TestUserDAO_GetListInDataScope() {
+	t := s.T()
+	withDataScope := func(scope *models.DataScope) context.Context {
+		return context.WithValue(context.Background(), constant.DataScopeContextKey,
+			models.DataScopeLoader(func() (*models.DataScope, error) {
+				return scope, nil
+			}))
+	}
+	s.Run("departments and self", func() {
+		defer func() {
+			assert.NoError(t, s.mock.ExpectationsWereMet())
+		}()
+		condition := "WHERE status = ? AND (id in (SELECT `user_id` FROM `user_departments` WHERE department_id in (?,?)) " +
+			"OR id = ?) AND `users`.`deleted_at` = ?"
+		s.mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `users` "+condition)).
+			WithArgs(int(constant.Normal), 1, 2, 7, 0).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		s.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `users` "+condition)).
+			WithArgs(int(constant.Normal), 1, 2, 7, 0, 10).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		ctx := withDataScope(&models.DataScope{UserID: 7, Self: true, DepartmentIDs: []uint{1, 2}})
+		_, _, err := s.userDAO.GetList(ctx, &models.UserFilter{Status: constant.Normal}, 10, 0)
+		assert.NoError(t, err)
+	})
+	s.Run("nothing visible", func() {
+		defer func() {
+			assert.NoError(t, s.mock.ExpectationsWereMet())
+		}()
+		s.mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `users` WHERE 1 = 0 AND `users`.`deleted_at` = ?")).
+			WithArgs(0).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		s.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `users` WHERE 1 = 0")).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		_, total, err := s.userDAO.GetList(withDataScope(&models.DataScope{UserID: 7}), &models.UserFilter{}, 10, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), total)
+	})
+	s.Run("unlimited", func() {
+		defer func() {
+			assert.NoError(t, s.mock.ExpectationsWereMet())
+		}()
+		s.mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `users` WHERE `users`.`deleted_at` = ?")).
+			WithArgs(0).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		s.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `users` WHERE `users`.`deleted_at` = ?")).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		_, _, err := s.userDAO.GetList(withDataScope(&models.DataScope{All: true}), &models.UserFilter{}, 10, 0)
+		assert.NoError(t, err)
+	})
+}
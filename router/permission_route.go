@@ -0,0 +1,58 @@
+package router
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/supuwoerc/weaver/models"
+	"github.com/swaggo/swag"
+)
+
+// swagger路径参数{id}对应gin路由模板中的:id
+var swagPathParamRegexp = regexp.MustCompile(`\{([^}/]+)}`)
+
+// AttachSwaggerSummaries 使用swagger中的接口摘要填充路由的Summary
+func AttachSwaggerSummaries(routes []*models.RegisteredRoute) {
+	summaries := swaggerSummaries()
+	for _, item := range routes {
+		item.Summary = summaries[item.Method+" "+item.Path]
+	}
+}
+
+// swaggerSummaries 读取已注册的swagger文档中各接口的摘要,key为请求方法和gin的路由模板,未注册文档时返回空
+func swaggerSummaries() map[string]string {
+	doc, err := swag.ReadDoc()
+	if err != nil {
+		return map[string]string{}
+	}
+	summaries, err := parseSwaggerSummaries(doc)
+	if err != nil {
+		return map[string]string{}
+	}
+	return summaries
+}
+
+type swaggerOperation struct {
+	Summary string `json:"summary"`
+}
+
+func parseSwaggerSummaries(doc string) (map[string]string, error) {
+	var spec struct {
+		BasePath string                                 `json:"basePath"`
+		Paths    map[string]map[string]swaggerOperation `json:"paths"`
+	}
+	if err := json.Unmarshal([]byte(doc), &spec); err != nil {
+		return nil, fmt.Errorf("parse swagger doc: %w", err)
+	}
+	basePath := strings.TrimRight(spec.BasePath, "/")
+	summaries := make(map[string]string)
+	for path, operations := range spec.Paths {
+		route := basePath + swagPathParamRegexp.ReplaceAllString(path, ":$1")
+		for method, operation := range operations {
+			summaries[strings.ToUpper(method)+" "+route] = operation.Summary
+		}
+	}
+	return summaries, nil
+}
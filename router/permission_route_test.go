@@ -0,0 +1,27 @@
+package router
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSwaggerSummaries(t *testing.T) {
+	doc := `{
+		"basePath": "/api/v1",
+		"paths": {
+			"/role/detail/{id}": {"get": {"summary": "角色详情"}},
+			"/user/list": {"get": {"summary": "用户列表"}, "post": {}}
+		}
+	}`
+	summaries, err := parseSwaggerSummaries(doc)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"GET /api/v1/role/detail/:id": "角色详情",
+		"GET /api/v1/user/list":       "用户列表",
+		"POST /api/v1/user/list":      "",
+	}, summaries)
+	_, err = parseSwaggerSummaries("invalid")
+	assert.Error(t, err)
+}
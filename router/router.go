@@ -1,20 +1,27 @@
 package router
 
 import (
+	"context"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/supuwoerc/weaver/conf"
+	"github.com/supuwoerc/weaver/pkg/jwt"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
 const (
 	swagRoutePattern = "swagger/*any"
+	jwksRoutePattern = "/.well-known/jwks.json"
 )
 
+type JWKSProvider interface {
+	JWKS(ctx context.Context) (*jwt.JWKS, error)
+}
+
 func NewRouter(r *gin.Engine) *gin.RouterGroup {
 	return r.Group("api/v1")
 }
@@ -47,3 +54,17 @@ func InitHealthCheckRouter(r *gin.Engine) {
 		})
 	})
 }
+
+// InitJWKSRouter 公开token签名公钥,供其他服务校验token
+func InitJWKSRouter(r *gin.Engine, provider JWKSProvider) {
+	r.GET(jwksRoutePattern, func(ctx *gin.Context) {
+		jwks, err := provider.JWKS(ctx)
+		if err != nil {
+			_ = ctx.Error(err)
+			ctx.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+		ctx.Header("Cache-Control", "public, max-age=300")
+		ctx.JSON(http.StatusOK, jwks)
+	})
+}
@@ -1,6 +1,9 @@
 package captcha
 
 import (
+	"fmt"
+
+	"github.com/supuwoerc/weaver/conf"
 	"github.com/supuwoerc/weaver/pkg/captcha"
 	"github.com/supuwoerc/weaver/pkg/constant"
 	"github.com/supuwoerc/weaver/pkg/response"
@@ -10,31 +13,59 @@ import (
 
 type Service struct {
 	clients map[constant.CaptchaType]*captcha.Captcha // 不同业务使用不同参数的验证码生成器
+	audio   *captcha.Captcha                          // 语音验证码生成器,与图片验证码共用存储,可用于任意业务
 }
 
-func NewCaptchaService(store base64Captcha.Store) *Service {
+// NewCaptchaService 按配置创建各业务的验证码生成器,未配置的业务使用默认验证码的参数
+func NewCaptchaService(store base64Captcha.Store, config *conf.Config) *Service {
+	defaultConfig := &config.Captcha.Default
+	driverConfigs := map[constant.CaptchaType]*conf.CaptchaDriverConfig{
+		constant.Default:       defaultConfig,
+		constant.SignUp:        &config.Captcha.SignUp,
+		constant.ResetPassword: &config.Captcha.ResetPassword,
+		constant.Login:         &config.Captcha.Login,
+	}
+	clients := make(map[constant.CaptchaType]*captcha.Captcha, len(driverConfigs))
+	for t, driverConfig := range driverConfigs {
+		if driverConfig.Driver == "" {
+			driverConfig = defaultConfig
+		}
+		clients[t] = mustNewCaptcha(t, driverConfig, store)
+	}
+	audioConfig := config.Captcha.Audio
+	audioConfig.Driver = constant.AudioCaptcha
 	return &Service{
-		clients: map[constant.CaptchaType]*captcha.Captcha{
-			constant.Default: captcha.NewCaptcha(100, 200, 6, 0.3, 80, store), // 默认验证码
-			constant.SignUp:  captcha.NewCaptcha(100, 348, 6, 0.3, 80, store), // 注册验证码
-		},
+		clients: clients,
+		audio:   mustNewCaptcha("audioCaptcha", &audioConfig, store),
 	}
 }
 
-func (c *Service) Generate(t constant.CaptchaType) (*response.GetCaptchaResponse, error) {
-	var info *captcha.CommonCaptchaInfo
-	var err error
-	if target, ok := c.clients[t]; ok {
-		info, err = target.Generate()
-	} else {
-		info, err = c.clients[constant.Default].Generate()
+// mustNewCaptcha 创建验证码生成器,配置错误时无法启动
+func mustNewCaptcha(t constant.CaptchaType, config *conf.CaptchaDriverConfig, store base64Captcha.Store) *captcha.Captcha {
+	client, err := captcha.NewCaptcha(config, store)
+	if err != nil {
+		panic(fmt.Sprintf("%s: %s %s", t, err.Error(), config.Driver))
+	}
+	return client
+}
+
+// Generate 生成指定业务的验证码,audio为true时生成语音验证码代替图片验证码
+func (c *Service) Generate(t constant.CaptchaType, audio bool) (*response.GetCaptchaResponse, error) {
+	target, ok := c.clients[t]
+	if !ok {
+		target = c.clients[constant.Default]
+	}
+	if audio {
+		target = c.audio
 	}
+	info, err := target.Generate()
 	if err != nil {
 		return nil, err
 	}
 	return &response.GetCaptchaResponse{
 		ID:     info.ID,
 		Base64: info.Base64,
+		Type:   target.Driver(),
 	}, nil
 }
 
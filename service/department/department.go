@@ -27,6 +27,7 @@ type DAO interface {
 	GetAll(ctx context.Context) ([]*models.Department, error)
 	GetAllUserDepartment(ctx context.Context) ([]*models.UserDepartment, error)
 	GetAllDepartmentLeader(ctx context.Context) ([]*models.DepartmentLeader, error)
+	GetIdsInDataScope(ctx context.Context) ([]uint, error)
 }
 
 type Cache interface {
@@ -158,6 +159,15 @@ func (p *Service) CreateDepartment(ctx context.Context, operator uint, params *r
 }
 
 func (p *Service) GetDepartmentTree(ctx context.Context, withCrew bool) ([]*response.DepartmentTreeResponse, error) {
+	tree, err := p.departmentTree(ctx, withCrew)
+	if err != nil {
+		return nil, err
+	}
+	return p.filterDataScope(ctx, tree)
+}
+
+// departmentTree 全量部门树,优先读取缓存
+func (p *Service) departmentTree(ctx context.Context, withCrew bool) ([]*response.DepartmentTreeResponse, error) {
 	key := constant.DepartmentTreeSfgKey
 	if withCrew {
 		key = constant.DepartmentTreeWithCrewSfgKey
@@ -218,6 +228,14 @@ func (p *Service) processDepartmentWithCrew(ctx context.Context, departments []*
 	if err != nil {
 		return err
 	}
+	// 创建人和更新人可能已被删除或注销,需要包含已删除的用户,否则部门树无法构建
+	operatorIds := lo.Uniq(lo.FlatMap(departments, func(item *models.Department, _ int) []uint {
+		return []uint{item.CreatorID, item.UpdaterID}
+	}))
+	operators, err := p.userDAO.GetByIdsWithDeleted(ctx, operatorIds)
+	if err != nil {
+		return err
+	}
 	for _, dept := range departments {
 		ud := lo.Filter(userDept, func(item *models.UserDepartment, _ int) bool {
 			return item.DepartmentID == dept.ID
@@ -235,7 +253,7 @@ func (p *Service) processDepartmentWithCrew(ctx context.Context, departments []*
 				return item.UserID
 			}), item.ID)
 		})
-		creator, ok := lo.Find(users, func(item *models.User) bool {
+		creator, ok := lo.Find(operators, func(item *models.User) bool {
 			return item.ID == dept.CreatorID
 		})
 		if ok {
@@ -243,7 +261,7 @@ func (p *Service) processDepartmentWithCrew(ctx context.Context, departments []*
 		} else {
 			return response.Error
 		}
-		updater, ok := lo.Find(users, func(item *models.User) bool {
+		updater, ok := lo.Find(operators, func(item *models.User) bool {
 			return item.ID == dept.UpdaterID
 		})
 		if ok {
@@ -291,34 +309,44 @@ func (p *Service) processTree(departments []*models.Department) ([]*response.Dep
 }
 
 func (p *Service) GetDepartmentsByParentID(ctx context.Context, parentID *uint, withCrew bool) ([]*response.DepartmentTreeResponse, error) {
-	key := constant.DepartmentTreeSfgKey
-	if withCrew {
-		key = constant.DepartmentTreeWithCrewSfgKey
-	}
-	departmentCache, getCacheErr := p.getDepartmentCache(ctx, key)
-	if getCacheErr != nil {
-		return nil, getCacheErr
+	tree, err := p.departmentTree(ctx, withCrew)
+	if err != nil {
+		return nil, err
 	}
-	if departmentCache != nil {
-		return p.processTree(departmentCache)
+	return p.filterDataScope(ctx, tree)
+}
+
+// filterDataScope 按调用者的数据权限范围裁剪部门树,移除不可见的部门,其可见的后代部门上移到最近的可见祖先部门下
+func (p *Service) filterDataScope(
+	ctx context.Context, tree []*response.DepartmentTreeResponse,
+) ([]*response.DepartmentTreeResponse, error) {
+	scope, err := utils.GetContextDataScope(ctx)
+	if err != nil || !scope.Limited() {
+		return tree, err
 	}
-	result, err, _ := p.deptTreeSfg.Do(string(key), func() (interface{}, error) {
-		departments, err := p.departmentDAO.GetAll(ctx)
-		if err != nil {
-			return nil, err
-		}
-		if withCrew {
-			if err = p.processDepartmentWithCrew(ctx, departments); err != nil {
-				return nil, err
-			}
-		}
-		if err = p.departmentCache.CacheDepartment(ctx, key, departments); err != nil {
-			return nil, err
-		}
-		return p.processTree(departments)
-	})
+	ids, err := p.departmentDAO.GetIdsInDataScope(ctx)
 	if err != nil {
 		return nil, err
 	}
-	return result.([]*response.DepartmentTreeResponse), nil
+	return pruneDepartmentTree(tree, lo.SliceToMap(ids, func(item uint) (uint, struct{}) {
+		return item, struct{}{}
+	})), nil
+}
+
+func pruneDepartmentTree(
+	nodes []*response.DepartmentTreeResponse, visible map[uint]struct{},
+) []*response.DepartmentTreeResponse {
+	res := make([]*response.DepartmentTreeResponse, 0, len(nodes))
+	for _, node := range nodes {
+		children := pruneDepartmentTree(node.Children, visible)
+		if _, ok := visible[node.ID]; !ok {
+			res = append(res, children...)
+			continue
+		}
+		// 部门树可能被并发的请求共享,复制节点而不修改原节点
+		copied := *node
+		copied.Children = children
+		res = append(res, &copied)
+	}
+	return res
 }
@@ -0,0 +1,64 @@
+package department
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/supuwoerc/weaver/models"
+	"github.com/supuwoerc/weaver/pkg/constant"
+	"github.com/supuwoerc/weaver/pkg/database"
+	"github.com/supuwoerc/weaver/repository/dao"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+func TestService_processDepartmentWithCrew(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer func() {
+		_ = db.Close()
+	}()
+	gormDB, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      db,
+		SkipInitializeWithVersion: true,
+	}))
+	require.NoError(t, err)
+	basic := dao.NewBasicDao(gormDB)
+	p := NewDepartmentService(nil, dao.NewDepartmentDAO(basic), nil, dao.NewUserDAO(basic))
+
+	t.Run("creator erased", func(t *testing.T) {
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+		// 部门的创建人已注销,注销的用户被软删除,不再出现在全部用户中
+		erasedEmail := fmt.Sprintf(constant.ErasedEmailFormat, 1)
+		departments := []*models.Department{
+			{Name: "研发部", CreatorID: 1, UpdaterID: 2, BasicModel: database.BasicModel{ID: 10}},
+		}
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `department_leaders`")).
+			WillReturnRows(sqlmock.NewRows([]string{"department_id", "user_id"}).AddRow(10, 2))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `user_departments`")).
+			WillReturnRows(sqlmock.NewRows([]string{"user_id", "department_id"}).AddRow(2, 10))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `users` WHERE `users`.`deleted_at` = ?")).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "email", "status"}).
+				AddRow(2, "b@b.com", constant.Normal))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `users` WHERE id in (?,?)")).
+			WithArgs(1, 2).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "email", "status", "deleted_at"}).
+				AddRow(1, erasedEmail, constant.Erased, 1).
+				AddRow(2, "b@b.com", constant.Normal, 0))
+		require.NoError(t, p.processDepartmentWithCrew(context.Background(), departments))
+		tree, err := p.processTree(departments)
+		require.NoError(t, err)
+		require.Len(t, tree, 1)
+		assert.Equal(t, erasedEmail, tree[0].Creator.Email)
+		assert.Equal(t, "b@b.com", tree[0].Updater.Email)
+		require.Len(t, tree[0].Users, 1)
+		assert.Equal(t, uint(2), tree[0].Users[0].ID)
+	})
+}
@@ -3,6 +3,7 @@ package permission
 import (
 	"context"
 	"strconv"
+	"strings"
 
 	"github.com/samber/lo"
 	"github.com/supuwoerc/weaver/models"
@@ -13,6 +14,8 @@ import (
 	"github.com/supuwoerc/weaver/pkg/response"
 	"github.com/supuwoerc/weaver/pkg/utils"
 	"github.com/supuwoerc/weaver/service"
+
+	"golang.org/x/sync/singleflight"
 )
 
 type DAO interface {
@@ -24,37 +27,65 @@ type DAO interface {
 	GetRolesCount(ctx context.Context, id uint) int64
 	Update(ctx context.Context, permission *models.Permission) error
 	AssociateRoles(ctx context.Context, id uint, roles []*models.Role) error
-	GetByNameOrResource(ctx context.Context, name, resource string) ([]*models.Permission, error)
-	CheckUserPermission(ctx context.Context, uid uint, resource string, permissionType constant.PermissionType) (bool, error)
-	GetUserPermissions(ctx context.Context, userId uint) ([]*models.Permission, error)
-	GetUserPermissionsByType(ctx context.Context, userId uint, limit int, offset int, permissionType ...constant.PermissionType) ([]*models.Permission, error)
+	GetByNameOrResource(ctx context.Context, name, resource, method string) ([]*models.Permission, error)
+	GetByNames(ctx context.Context, names []string) ([]*models.Permission, error)
+	GetByType(ctx context.Context, permissionType constant.PermissionType) ([]*models.Permission, error)
+	GetUserPermissions(ctx context.Context, userId uint, inheritance constant.RoleInheritance) ([]*models.Permission, error)
+	GetUserPermissionsByType(ctx context.Context, userId uint, inheritance constant.RoleInheritance,
+		limit int, offset int, permissionType ...constant.PermissionType) ([]*models.Permission, error)
 }
 
 type RoleDAO interface {
 	GetByIds(ctx context.Context, ids []uint, preload ...string) ([]*models.Role, error)
 }
 
+type UserDAO interface {
+	GetByEmail(ctx context.Context, email string, preload ...string) (*models.User, error)
+}
+
 type RolePermissionDAO interface {
 	GetRolesByPermissionID(ctx context.Context, permissionID uint, keyword string, limit int, offset int) ([]*models.Role, error)
 }
 
+type Cache interface {
+	GetPermissionSet(ctx context.Context, uid uint) (*models.PermissionSet, string, error)
+	CachePermissionSet(ctx context.Context, uid uint, version string, set *models.PermissionSet) error
+	GetRouteCatalog(ctx context.Context) ([]models.RoutePattern, string, error)
+	CacheRouteCatalog(ctx context.Context, version string, catalog []models.RoutePattern) error
+	InvalidateAllPermissions(ctx context.Context) error
+	Subscribe(ctx context.Context) error
+	Unsubscribe() error
+}
+
 type Service struct {
 	*service.BasicService
 	permissionDAO     DAO
 	rolePermissionDAO RolePermissionDAO
 	roleDAO           RoleDAO
+	userDAO           UserDAO
+	permissionCache   Cache
+	permissionSetSfg  singleflight.Group
 }
 
 var (
 	_ cache.SystemCache = &Service{}
 )
 
-func NewPermissionService(basic *service.BasicService, permissionDAO DAO, rolePermissionDAO RolePermissionDAO, roleDAO RoleDAO) *Service {
+func NewPermissionService(
+	basic *service.BasicService,
+	permissionDAO DAO,
+	rolePermissionDAO RolePermissionDAO,
+	roleDAO RoleDAO,
+	userDAO UserDAO,
+	permissionCache Cache,
+) *Service {
 	return &Service{
 		BasicService:      basic,
 		permissionDAO:     permissionDAO,
 		rolePermissionDAO: rolePermissionDAO,
 		roleDAO:           roleDAO,
+		userDAO:           userDAO,
+		permissionCache:   permissionCache,
 	}
 }
 
@@ -83,7 +114,16 @@ func (s *Service) lockPermissionField(ctx context.Context, name, resource string
 	return locks, nil
 }
 
+// permissionMethod API权限的请求方法,未指定时匹配任意方法,非API权限固定为任意方法
+func permissionMethod(params *request.CreatePermissionRequest) string {
+	if params.Type != constant.ApiRoute || params.Method == "" {
+		return constant.AnyMethod
+	}
+	return strings.ToUpper(params.Method)
+}
+
 func (s *Service) CreatePermission(ctx context.Context, operator uint, params *request.CreatePermissionRequest) error {
+	method := permissionMethod(params)
 	locks, err := s.lockPermissionField(ctx, params.Name, params.Resource, params.Roles)
 	defer func() {
 		for _, l := range locks {
@@ -95,9 +135,9 @@ func (s *Service) CreatePermission(ctx context.Context, operator uint, params *r
 	if err != nil {
 		return err
 	}
-	return s.Transaction(ctx, false, func(ctx context.Context) error {
+	err = s.Transaction(ctx, false, func(ctx context.Context) error {
 		// 查询是否重复
-		existPermissions, temp := s.permissionDAO.GetByNameOrResource(ctx, params.Name, params.Resource)
+		existPermissions, temp := s.permissionDAO.GetByNameOrResource(ctx, params.Name, params.Resource, method)
 		if temp != nil {
 			return temp
 		}
@@ -117,11 +157,17 @@ func (s *Service) CreatePermission(ctx context.Context, operator uint, params *r
 			Name:      params.Name,
 			Resource:  params.Resource,
 			Type:      params.Type,
+			Method:    method,
 			Roles:     roles,
 			CreatorID: operator,
 			UpdaterID: operator,
 		})
 	})
+	// 新的API权限参与匹配,对请求生效的权限可能随之变化
+	if err != nil || (len(params.Roles) == 0 && params.Type != constant.ApiRoute) {
+		return err
+	}
+	return s.permissionCache.InvalidateAllPermissions(ctx)
 }
 
 func (s *Service) GetPermissionList(ctx context.Context, keyword string, limit, offset int) ([]*response.PermissionListRowResponse, int64, error) {
@@ -155,6 +201,7 @@ func (s *Service) GetRolesByPermissionID(ctx context.Context, params *request.Ge
 }
 
 func (s *Service) UpdatePermission(ctx context.Context, operator uint, params *request.UpdatePermissionRequest) error {
+	method := permissionMethod(&params.CreatePermissionRequest)
 	// 对权限自身加锁
 	permissionLock := s.Locksmith.NewLock(constant.PermissionIdPrefix, strconv.Itoa(int(params.ID)))
 	if err := permissionLock.Lock(ctx, true); err != nil {
@@ -181,9 +228,9 @@ func (s *Service) UpdatePermission(ctx context.Context, operator uint, params *r
 	if err != nil {
 		return err
 	}
-	return s.Transaction(ctx, false, func(ctx context.Context) error {
+	err = s.Transaction(ctx, false, func(ctx context.Context) error {
 		// 查询是否重复
-		existPermissions, temp := s.permissionDAO.GetByNameOrResource(ctx, params.Name, params.Resource)
+		existPermissions, temp := s.permissionDAO.GetByNameOrResource(ctx, params.Name, params.Resource, method)
 		if temp != nil {
 			return temp
 		}
@@ -196,6 +243,7 @@ func (s *Service) UpdatePermission(ctx context.Context, operator uint, params *r
 			Name:      params.Name,
 			Resource:  params.Resource,
 			Type:      params.Type,
+			Method:    method,
 			UpdaterID: operator,
 			BasicModel: database.BasicModel{
 				ID: params.ID,
@@ -215,6 +263,10 @@ func (s *Service) UpdatePermission(ctx context.Context, operator uint, params *r
 		// 更新关联关系
 		return s.permissionDAO.AssociateRoles(ctx, params.ID, roles)
 	})
+	if err != nil {
+		return err
+	}
+	return s.permissionCache.InvalidateAllPermissions(ctx)
 }
 
 func (s *Service) DeletePermission(ctx context.Context, id, operator uint) error {
@@ -232,14 +284,19 @@ func (s *Service) DeletePermission(ctx context.Context, id, operator uint) error
 	if count > 0 {
 		return response.PermissionExistRoleRef
 	}
-	return s.permissionDAO.DeleteByID(ctx, id, operator)
+	if err := s.permissionDAO.DeleteByID(ctx, id, operator); err != nil {
+		return err
+	}
+	// 删除的API权限不再参与匹配,对请求生效的权限可能随之变化
+	return s.permissionCache.InvalidateAllPermissions(ctx)
 }
 
 func (s *Service) GetUserViewRouteAndMenuPermissions(ctx context.Context, uid uint) (response.FrontEndPermissions, error) {
 	limit, offset := 200, 0
 	var list []*models.Permission
 	for {
-		page, err := s.permissionDAO.GetUserPermissionsByType(ctx, uid, limit, offset, constant.ViewRoute, constant.ViewMenu)
+		page, err := s.permissionDAO.GetUserPermissionsByType(ctx, uid, s.Conf.Permission.Inheritance(), limit, offset,
+			constant.ViewRoute, constant.ViewMenu)
 		if err != nil {
 			return nil, err
 		}
@@ -1,18 +1,125 @@
 package permission
 
-import "context"
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/supuwoerc/weaver/models"
+	"github.com/supuwoerc/weaver/pkg/constant"
+
+	"github.com/samber/lo"
+)
+
+// routeCatalogSfgKey 查询全部API权限的合并请求key,用户ID不会与之重复
+const routeCatalogSfgKey = "routes"
 
 func (s *Service) CacheKey() string {
-	//TODO implement me
-	panic("implement me")
+	return constant.AutoManagePermissionCache
+}
+
+// RefreshCache 使启动前编译的用户权限集合全部失效并订阅其他实例的失效通知
+func (s *Service) RefreshCache(ctx context.Context) error {
+	start := time.Now()
+	s.Logger.WithContext(ctx).Infow("refresh permission", "begin", start.Format(time.DateTime))
+	defer func() {
+		s.Logger.WithContext(ctx).Infow("refresh permission",
+			"end", time.Now().Format(time.DateTime), "cost",
+			fmt.Sprintf("%dms", time.Since(start).Milliseconds()),
+		)
+	}()
+	if err := s.permissionCache.InvalidateAllPermissions(ctx); err != nil {
+		return err
+	}
+	return s.permissionCache.Subscribe(ctx)
+}
+
+// CleanCache 取消订阅失效通知并清空进程内缓存,redis中的权限集合为各实例共享,不做清除
+func (s *Service) CleanCache(ctx context.Context) error {
+	s.Logger.WithContext(ctx).Infow("clean permission", "time", time.Now().Format(time.DateTime))
+	return s.permissionCache.Unsubscribe()
+}
+
+// GetUserPermissionSet 获取用户编译后的权限集合,未缓存时按角色继承方向查询用户的全部权限并缓存
+func (s *Service) GetUserPermissionSet(ctx context.Context, uid uint) (*models.PermissionSet, error) {
+	set, version, err := s.permissionCache.GetPermissionSet(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	if set != nil {
+		return set, nil
+	}
+	result, err, _ := s.permissionSetSfg.Do(strconv.Itoa(int(uid)), func() (interface{}, error) {
+		permissions, e := s.permissionDAO.GetUserPermissions(ctx, uid, s.Conf.Permission.Inheritance())
+		if e != nil {
+			return nil, e
+		}
+		compiled := models.NewPermissionSet(permissions)
+		// 缓存失败不影响本次鉴权
+		if e = s.permissionCache.CachePermissionSet(ctx, uid, version, compiled); e != nil {
+			s.Logger.WithContext(ctx).Errorw("cache permission set fail", "uid", uid, "err", e.Error())
+		}
+		return compiled, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*models.PermissionSet), nil
+}
+
+// getRouteCatalog 获取全部API权限的请求方法和路由模板,未缓存时查询并缓存
+func (s *Service) getRouteCatalog(ctx context.Context) ([]models.RoutePattern, error) {
+	catalog, version, err := s.permissionCache.GetRouteCatalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if catalog != nil {
+		return catalog, nil
+	}
+	result, err, _ := s.permissionSetSfg.Do(routeCatalogSfgKey, func() (interface{}, error) {
+		permissions, e := s.permissionDAO.GetByType(ctx, constant.ApiRoute)
+		if e != nil {
+			return nil, e
+		}
+		patterns := lo.Map(permissions, func(item *models.Permission, _ int) models.RoutePattern {
+			return item.GetRoutePattern()
+		})
+		if e = s.permissionCache.CacheRouteCatalog(ctx, version, patterns); e != nil {
+			s.Logger.WithContext(ctx).Errorw("cache route catalog fail", "err", e.Error())
+		}
+		return patterns, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.([]models.RoutePattern), nil
 }
 
-func (s *Service) RefreshCache(context.Context) error {
-	//TODO implement me
-	panic("implement me")
+// GetGoverningRoutes 找出对请求生效的API权限,route为gin的路由模板
+func (s *Service) GetGoverningRoutes(ctx context.Context, method, route string) ([]models.RoutePattern, error) {
+	catalog, err := s.getRouteCatalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return models.GoverningRoutes(catalog, method, route), nil
 }
 
-func (s *Service) CleanCache(context.Context) error {
-	//TODO implement me
-	panic("implement me")
+// CheckUserRoutePermission 检查用户是否拥有请求的API权限,route为gin的路由模板;
+// 匹配请求的全部API权限中具体程度最高的权限生效,用户拥有其中之一即可访问,没有匹配的权限时拒绝访问
+func (s *Service) CheckUserRoutePermission(ctx context.Context, uid uint, method, route string) (bool, error) {
+	governing, err := s.GetGoverningRoutes(ctx, method, route)
+	if err != nil {
+		return false, err
+	}
+	if len(governing) == 0 {
+		return false, nil
+	}
+	set, err := s.GetUserPermissionSet(ctx, uid)
+	if err != nil {
+		return false, err
+	}
+	return lo.SomeBy(governing, func(item models.RoutePattern) bool {
+		return set.HasRoute(item)
+	}), nil
 }
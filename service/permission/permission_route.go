@@ -0,0 +1,137 @@
+package permission
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/samber/lo"
+	"github.com/supuwoerc/weaver/models"
+	"github.com/supuwoerc/weaver/pkg/constant"
+	"github.com/supuwoerc/weaver/pkg/response"
+	"github.com/supuwoerc/weaver/pkg/utils"
+)
+
+const permissionNameMaxLength = 20 // 权限名的最大长度,与sys_permission.name的长度保持一致
+
+// SyncRoutePermissions 将gin中注册的需要权限控制的路由同步为API权限:没有任何API权限匹配的路由新建同请求方法同路由模板的权限,
+// 名称取swagger中的接口摘要,新建的权限不关联角色;不再匹配任何路由的API权限只报告不删除,避免误删已分配给角色的权限;
+// 创建人/更新人为配置的系统管理员;dryRun为true时只对比差异
+func (s *Service) SyncRoutePermissions(
+	ctx context.Context, routes []*models.RegisteredRoute, dryRun bool,
+) (*response.SyncRoutePermissionsResponse, error) {
+	if !dryRun {
+		lock := s.Locksmith.NewLock(constant.PermissionRouteSyncPrefix)
+		if err := lock.Lock(ctx, true); err != nil {
+			return nil, err
+		}
+		defer func(lock *utils.RedisLock) {
+			if e := lock.Unlock(); e != nil {
+				s.Logger.WithContext(ctx).Errorf("unlock fail %s", e.Error())
+			}
+		}(lock)
+	}
+	existPermissions, err := s.permissionDAO.GetByType(ctx, constant.ApiRoute)
+	if err != nil {
+		return nil, err
+	}
+	// 已被通配的API权限覆盖的路由不再新建权限,否则新建的权限具体程度更高,会使原有的授权失效
+	missing := lo.Filter(routes, func(route *models.RegisteredRoute, _ int) bool {
+		return lo.NoneBy(existPermissions, func(item *models.Permission) bool {
+			return item.GetRoutePattern().Match(route.Method, route.Path)
+		})
+	})
+	result := &response.SyncRoutePermissionsResponse{
+		Total:  len(routes),
+		DryRun: dryRun,
+		Orphans: lo.Filter(existPermissions, func(item *models.Permission, _ int) bool {
+			return lo.NoneBy(routes, func(route *models.RegisteredRoute) bool {
+				return item.GetRoutePattern().Match(route.Method, route.Path)
+			})
+		}),
+	}
+	names, err := s.routePermissionNames(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+	result.Created = lo.Map(missing, func(route *models.RegisteredRoute, index int) *models.Permission {
+		return &models.Permission{
+			Name:     names[index],
+			Resource: route.Path,
+			Type:     constant.ApiRoute,
+			Method:   route.Method,
+		}
+	})
+	if dryRun || len(result.Created) == 0 {
+		return result, nil
+	}
+	// 权限列表等按创建人/更新人预加载用户,同步的权限归属系统管理员
+	admin, err := s.userDAO.GetByEmail(ctx, s.Conf.System.Admin.Email)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range result.Created {
+		item.CreatorID, item.UpdaterID = admin.ID, admin.ID
+	}
+	err = s.Transaction(ctx, false, func(ctx context.Context) error {
+		for _, item := range result.Created {
+			if e := s.permissionDAO.Create(ctx, item); e != nil {
+				return e
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	// 新的API权限参与匹配,对请求生效的权限可能随之变化
+	if err = s.permissionCache.InvalidateAllPermissions(ctx); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// routePermissionNames 为待新建的路由权限生成不重复的名称,与已有权限或其他路由重名时追加序号
+func (s *Service) routePermissionNames(ctx context.Context, routes []*models.RegisteredRoute) ([]string, error) {
+	names := make([]string, len(routes))
+	used := make(map[string]struct{})
+	pending := lo.Range(len(routes))
+	for attempt := 1; len(pending) > 0; attempt++ {
+		candidates := lo.SliceToMap(pending, func(index int) (int, string) {
+			return index, routePermissionName(routes[index], attempt)
+		})
+		exists, err := s.permissionDAO.GetByNames(ctx, lo.Uniq(lo.Values(candidates)))
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range exists {
+			used[item.Name] = struct{}{}
+		}
+		next := make([]int, 0)
+		for _, index := range pending {
+			name := candidates[index]
+			if _, ok := used[name]; ok {
+				next = append(next, index)
+				continue
+			}
+			used[name] = struct{}{}
+			names[index] = name
+		}
+		pending = next
+	}
+	return names, nil
+}
+
+// routePermissionName 路由权限的名称,优先使用swagger中的接口摘要,没有摘要时使用请求方法和路由模板的末尾部分
+func routePermissionName(route *models.RegisteredRoute, attempt int) string {
+	suffix := ""
+	if attempt > 1 {
+		suffix = fmt.Sprintf("-%d", attempt)
+	}
+	limit := permissionNameMaxLength - len(suffix)
+	if summary := []rune(strings.TrimSpace(route.Summary)); len(summary) > 0 {
+		return string(summary[:min(len(summary), limit)]) + suffix
+	}
+	fallback := []rune(fmt.Sprintf("%s %s", route.Method, route.Path))
+	return string(fallback[max(len(fallback)-limit, 0):]) + suffix
+}
@@ -27,28 +27,59 @@ type DAO interface {
 	Update(ctx context.Context, role *models.Role) error
 	AssociateUsers(ctx context.Context, id uint, users []*models.User) error
 	AssociatePermissions(ctx context.Context, id uint, permissions []*models.Permission) error
+	AssociateDataDepartments(ctx context.Context, id uint, departments []*models.Department) error
 	DeleteByID(ctx context.Context, id, updater uint) error
 	GetUsersCount(ctx context.Context, id uint) int64
 	GetPermissionsCount(ctx context.Context, id uint) int64
+	GetUserRolesWithoutPosterity(ctx context.Context, uid uint) ([]*models.Role, error)
+	GetRolesWithPosterity(ctx context.Context, roleIds ...uint) ([]*models.Role, error)
+	GetUserRoles(ctx context.Context, uid uint, inheritance constant.RoleInheritance, preload ...string) ([]*models.Role, error)
 }
 
 type PermissionDAO interface {
 	GetByIds(ctx context.Context, ids []uint, preload ...string) ([]*models.Permission, error)
 }
 
+type DepartmentDAO interface {
+	GetByIds(ctx context.Context, ids []uint) ([]*models.Department, error)
+	GetDepartmentsWithPosterity(ctx context.Context, deptIds ...uint) ([]*models.Department, error)
+}
+
+type TokenCache interface {
+	DenyUserAccessTokens(ctx context.Context, uid uint, excludeSessions ...string) error
+}
+
+type PermissionCache interface {
+	InvalidateAllPermissions(ctx context.Context) error
+}
+
 type Service struct {
 	*service.BasicService
-	roleDAO       DAO
-	userDAO       user.DAO
-	permissionDAO PermissionDAO
+	roleDAO         DAO
+	userDAO         user.DAO
+	permissionDAO   PermissionDAO
+	departmentDAO   DepartmentDAO
+	tokenCache      TokenCache
+	permissionCache PermissionCache
 }
 
-func NewRoleService(basic *service.BasicService, roleDAO DAO, userDAO user.DAO, permissionDAO PermissionDAO) *Service {
+func NewRoleService(
+	basic *service.BasicService,
+	roleDAO DAO,
+	userDAO user.DAO,
+	permissionDAO PermissionDAO,
+	departmentDAO DepartmentDAO,
+	tokenCache TokenCache,
+	permissionCache PermissionCache,
+) *Service {
 	return &Service{
-		BasicService:  basic,
-		roleDAO:       roleDAO,
-		userDAO:       userDAO,
-		permissionDAO: permissionDAO,
+		BasicService:    basic,
+		roleDAO:         roleDAO,
+		userDAO:         userDAO,
+		permissionDAO:   permissionDAO,
+		departmentDAO:   departmentDAO,
+		tokenCache:      tokenCache,
+		permissionCache: permissionCache,
 	}
 }
 
@@ -71,6 +102,19 @@ func (r *Service) lockRoleField(ctx context.Context, name string, permissionIds
 	return locks, nil
 }
 
+// roleDataScope 角色的数据权限范围,未指定时为全部数据
+func roleDataScope(params *request.CreateRoleRequest) constant.DataScope {
+	return lo.CoalesceOrEmpty(params.DataScope, constant.DataScopeAll)
+}
+
+// getDataDepartments 查询自定义数据权限的有效部门,非自定义范围时不关联部门
+func (r *Service) getDataDepartments(ctx context.Context, params *request.CreateRoleRequest) ([]*models.Department, error) {
+	if roleDataScope(params) != constant.DataScopeCustom || len(params.DataDepartments) == 0 {
+		return nil, nil
+	}
+	return r.departmentDAO.GetByIds(ctx, params.DataDepartments)
+}
+
 func (r *Service) CreateRole(ctx context.Context, operator uint, params *request.CreateRoleRequest) error {
 	locks, err := r.lockRoleField(ctx, params.Name, params.Permissions)
 	defer func() {
@@ -83,7 +127,7 @@ func (r *Service) CreateRole(ctx context.Context, operator uint, params *request
 	if err != nil {
 		return err
 	}
-	return r.Transaction(ctx, false, func(ctx context.Context) error {
+	err = r.Transaction(ctx, false, func(ctx context.Context) error {
 		// 查询是否重复
 		existRole, temp := r.roleDAO.GetByName(ctx, params.Name)
 		if temp != nil && !errors.Is(temp, response.RoleNotExist) {
@@ -116,12 +160,19 @@ func (r *Service) CreateRole(ctx context.Context, operator uint, params *request
 				return err
 			}
 		}
+		// 查询自定义数据权限的部门
+		departments, temp := r.getDataDepartments(ctx, params)
+		if temp != nil {
+			return temp
+		}
 		role := &models.Role{
-			Name:        params.Name,
-			Users:       users,
-			Permissions: permissions,
-			CreatorID:   operator,
-			UpdaterID:   operator,
+			Name:            params.Name,
+			Users:           users,
+			Permissions:     permissions,
+			DataScope:       roleDataScope(params),
+			DataDepartments: departments,
+			CreatorID:       operator,
+			UpdaterID:       operator,
 		}
 		// 完善 Parent & Ancestors
 		if parentRole != nil {
@@ -139,6 +190,11 @@ func (r *Service) CreateRole(ctx context.Context, operator uint, params *request
 		// 创建角色 & 建立关联关系
 		return r.roleDAO.Create(ctx, role)
 	})
+	// 未关联用户和权限的角色不影响任何用户的权限
+	if err != nil || (len(params.Users) == 0 && len(params.Permissions) == 0) {
+		return err
+	}
+	return r.permissionCache.InvalidateAllPermissions(ctx)
 }
 
 func (r *Service) GetRoleList(ctx context.Context, keyword string, limit, offset int) ([]*response.RoleListRowResponse, int64, error) {
@@ -152,11 +208,51 @@ func (r *Service) GetRoleList(ctx context.Context, keyword string, limit, offset
 }
 
 func (r *Service) GetRoleDetail(ctx context.Context, id uint) (*response.RoleDetailResponse, error) {
-	role, err := r.roleDAO.GetByID(ctx, id, "Users", "Permissions", "Children")
+	role, err := r.roleDAO.GetByID(ctx, id, "Users", "Permissions", "Children", "DataDepartments")
+	if err != nil {
+		return nil, err
+	}
+	inherited, err := r.inheritedPermissions(ctx, role)
+	if err != nil {
+		return nil, err
+	}
+	return response.ToRoleDetailResponse(role, inherited), nil
+}
+
+// inheritedPermissions 按继承方向查询角色从后代角色或祖先角色继承的权限,不包含角色直接关联的权限
+func (r *Service) inheritedPermissions(ctx context.Context, role *models.Role) ([]*models.Permission, error) {
+	var roleIds []uint
+	switch r.Conf.Permission.Inheritance() {
+	case constant.InheritDescendants:
+		posterity, err := r.roleDAO.GetRolesWithPosterity(ctx, role.ID)
+		if err != nil {
+			return nil, err
+		}
+		roleIds = lo.Map(posterity, func(item *models.Role, _ int) uint {
+			return item.ID
+		})
+	case constant.InheritAncestors:
+		roleIds = role.GetAncestorIds()
+	}
+	if len(roleIds) == 0 {
+		return nil, nil
+	}
+	roles, err := r.roleDAO.GetByIds(ctx, roleIds, "Permissions")
 	if err != nil {
 		return nil, err
 	}
-	return response.ToRoleDetailResponse(role), nil
+	owned := lo.SliceToMap(role.Permissions, func(item *models.Permission) (uint, struct{}) {
+		return item.ID, struct{}{}
+	})
+	permissions := lo.FlatMap(roles, func(item *models.Role, _ int) []*models.Permission {
+		return item.Permissions
+	})
+	return lo.UniqBy(lo.Filter(permissions, func(item *models.Permission, _ int) bool {
+		_, ok := owned[item.ID]
+		return !ok
+	}), func(item *models.Permission) uint {
+		return item.ID
+	}), nil
 }
 
 func (r *Service) UpdateRole(ctx context.Context, operator uint, params *request.UpdateRoleRequest) error {
@@ -170,7 +266,7 @@ func (r *Service) UpdateRole(ctx context.Context, operator uint, params *request
 			r.Logger.WithContext(ctx).Errorf("unlock fail %s", e.Error())
 		}
 	}(roleLock)
-	_, err := r.roleDAO.GetByID(ctx, params.ID)
+	role, err := r.roleDAO.GetByID(ctx, params.ID, "Users")
 	if err != nil {
 		return err
 	}
@@ -186,7 +282,7 @@ func (r *Service) UpdateRole(ctx context.Context, operator uint, params *request
 	if err != nil {
 		return err
 	}
-	return r.Transaction(ctx, false, func(ctx context.Context) error {
+	err = r.Transaction(ctx, false, func(ctx context.Context) error {
 		// 查询是否重复
 		existRole, temp := r.roleDAO.GetByName(ctx, params.Name)
 		if temp != nil && !errors.Is(temp, response.RoleNotExist) {
@@ -198,6 +294,7 @@ func (r *Service) UpdateRole(ctx context.Context, operator uint, params *request
 		// 更新角色
 		err = r.roleDAO.Update(ctx, &models.Role{
 			Name:      params.Name,
+			DataScope: roleDataScope(&params.CreateRoleRequest),
 			UpdaterID: operator,
 			BasicModel: database.BasicModel{
 				ID: params.ID,
@@ -227,8 +324,35 @@ func (r *Service) UpdateRole(ctx context.Context, operator uint, params *request
 				return err
 			}
 		}
-		return r.roleDAO.AssociatePermissions(ctx, params.ID, permissions)
+		err = r.roleDAO.AssociatePermissions(ctx, params.ID, permissions)
+		if err != nil {
+			return err
+		}
+		// 查询自定义数据权限的部门
+		var departments []*models.Department
+		departments, err = r.getDataDepartments(ctx, &params.CreateRoleRequest)
+		if err != nil {
+			return err
+		}
+		return r.roleDAO.AssociateDataDepartments(ctx, params.ID, departments)
 	})
+	if err != nil {
+		return err
+	}
+	// 角色的权限通过继承影响其他角色的用户,全部用户的权限集合失效
+	if err = r.permissionCache.InvalidateAllPermissions(ctx); err != nil {
+		return err
+	}
+	// 被移出角色的用户吊销已签发的短token
+	removed, _ := lo.Difference(lo.Map(role.Users, func(item *models.User, _ int) uint {
+		return item.ID
+	}), params.Users)
+	for _, uid := range removed {
+		if err = r.tokenCache.DenyUserAccessTokens(ctx, uid); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (r *Service) DeleteRole(ctx context.Context, id, operator uint) error {
@@ -250,11 +374,79 @@ func (r *Service) DeleteRole(ctx context.Context, id, operator uint) error {
 	if usersCount > 0 {
 		return response.RoleExistUserRef
 	}
-	return r.roleDAO.DeleteByID(ctx, id, operator)
+	if err := r.roleDAO.DeleteByID(ctx, id, operator); err != nil {
+		return err
+	}
+	// 角色通过继承影响其他角色的用户,全部用户的权限集合失效
+	return r.permissionCache.InvalidateAllPermissions(ctx)
 }
 
 // GetUserRolesWithPosterity 查询用户的角色,包含后代角色
 func (r *Service) GetUserRolesWithPosterity(ctx context.Context, uid uint) ([]*models.Role, error) {
-	// 查询用户全部角色
-	return nil, nil
+	roles, err := r.roleDAO.GetUserRolesWithoutPosterity(ctx, uid)
+	if err != nil || len(roles) == 0 {
+		return roles, err
+	}
+	posterity, err := r.roleDAO.GetRolesWithPosterity(ctx, lo.Map(roles, func(item *models.Role, _ int) uint {
+		return item.ID
+	})...)
+	if err != nil {
+		return nil, err
+	}
+	return lo.UniqBy(append(roles, posterity...), func(item *models.Role) uint {
+		return item.ID
+	}), nil
+}
+
+// GetUserDataScope 解析用户的数据权限范围,按继承方向包含继承的角色,多个角色的范围取并集;未持有角色时仅可见本人的数据
+func (r *Service) GetUserDataScope(ctx context.Context, uid uint) (*models.DataScope, error) {
+	roles, err := r.roleDAO.GetUserRoles(ctx, uid, r.Conf.Permission.Inheritance(), "DataDepartments")
+	if err != nil {
+		return nil, err
+	}
+	scope := &models.DataScope{
+		UserID: uid,
+		Self:   len(roles) == 0,
+	}
+	var departmentIds []uint
+	var own, tree bool
+	for _, role := range roles {
+		switch role.DataScope {
+		case constant.DataScopeAll:
+			scope.All = true
+			return scope, nil
+		case constant.DataScopeDepartment:
+			own = true
+		case constant.DataScopeDepartmentTree:
+			tree = true
+		case constant.DataScopeCustom:
+			departmentIds = append(departmentIds, lo.Map(role.DataDepartments, func(item *models.Department, _ int) uint {
+				return item.ID
+			})...)
+		case constant.DataScopeSelf:
+			scope.Self = true
+		}
+	}
+	if own || tree {
+		user, e := r.userDAO.GetByID(ctx, uid, "Departments")
+		if e != nil {
+			return nil, e
+		}
+		ownIds := lo.Map(user.Departments, func(item *models.Department, _ int) uint {
+			return item.ID
+		})
+		departmentIds = append(departmentIds, ownIds...)
+		// 本部门及以下按祖先部门路径查询全部后代部门
+		if tree {
+			posterity, e := r.departmentDAO.GetDepartmentsWithPosterity(ctx, ownIds...)
+			if e != nil {
+				return nil, e
+			}
+			departmentIds = append(departmentIds, lo.Map(posterity, func(item *models.Department, _ int) uint {
+				return item.ID
+			})...)
+		}
+	}
+	scope.DepartmentIDs = lo.Uniq(departmentIds)
+	return scope, nil
 }
@@ -0,0 +1,106 @@
+package user
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/supuwoerc/weaver/models"
+	"github.com/supuwoerc/weaver/pkg/constant"
+	"github.com/supuwoerc/weaver/pkg/request"
+	"github.com/supuwoerc/weaver/pkg/response"
+	"github.com/supuwoerc/weaver/pkg/utils"
+
+	"github.com/samber/lo"
+)
+
+// GetAPIKeys 获取用户的API密钥
+func (u *Service) GetAPIKeys(ctx context.Context, uid uint) ([]*response.APIKeyResponse, error) {
+	keys, err := u.apiKeyDAO.GetByUserID(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	return lo.Map(keys, func(item *models.UserAPIKey, _ int) *response.APIKeyResponse {
+		return response.ToAPIKeyResponse(item)
+	}), nil
+}
+
+// CreateAPIKey 创建API密钥,权限范围只能是用户已拥有的接口权限,密钥明文仅返回一次
+func (u *Service) CreateAPIKey(
+	ctx context.Context, uid uint, email string, params *request.CreateAPIKeyRequest,
+) (*response.CreateAPIKeyResponse, error) {
+	if params.ExpiredAt != nil && !params.ExpiredAt.After(time.Now()) {
+		return nil, response.InvalidParams
+	}
+	userLock := u.Locksmith.NewLock(constant.UserIdPrefix, strconv.Itoa(int(uid)))
+	if err := userLock.Lock(ctx, true); err != nil {
+		return nil, err
+	}
+	defer func(lock *utils.RedisLock) {
+		if e := lock.Unlock(); e != nil {
+			u.Logger.WithContext(ctx).Errorf("unlock fail %s", e.Error())
+		}
+	}(userLock)
+	count, err := u.apiKeyDAO.CountByUserID(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	if count >= u.Conf.APIKey.MaxCount {
+		return nil, response.APIKeyLimitExceeded
+	}
+	permissions, err := u.apiKeyScope(ctx, uid, email, lo.Uniq(params.Permissions))
+	if err != nil {
+		return nil, err
+	}
+	key := u.Conf.APIKey.Prefix + lo.RandomString(constant.APIKeyLength, lo.AlphanumericCharset)
+	apiKey := &models.UserAPIKey{
+		UserID:      uid,
+		Name:        params.Name,
+		Prefix:      key[:len(u.Conf.APIKey.Prefix)+constant.APIKeyDisplayLength],
+		Key:         utils.Sha256(key),
+		ExpiredAt:   params.ExpiredAt,
+		Permissions: permissions,
+	}
+	if err = u.apiKeyDAO.Create(ctx, apiKey); err != nil {
+		return nil, err
+	}
+	return &response.CreateAPIKeyResponse{
+		APIKeyResponse: response.ToAPIKeyResponse(apiKey),
+		Key:            key,
+	}, nil
+}
+
+// apiKeyScope 校验API密钥的权限范围,超级管理员可以选择任意接口权限
+func (u *Service) apiKeyScope(ctx context.Context, uid uint, email string, ids []uint) ([]*models.Permission, error) {
+	permissions, err := u.permissionDAO.GetByIds(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	if len(permissions) != len(ids) {
+		return nil, response.PermissionNotExist
+	}
+	if lo.SomeBy(permissions, func(item *models.Permission) bool {
+		return !item.IsApiPermission()
+	}) {
+		return nil, response.APIKeyScopeExceeded
+	}
+	if email == u.Conf.System.Admin.Email {
+		return permissions, nil
+	}
+	owned, err := u.permissionDAO.GetUserPermissions(ctx, uid, u.Conf.Permission.Inheritance())
+	if err != nil {
+		return nil, err
+	}
+	ownedIds := lo.Map(owned, func(item *models.Permission, _ int) uint {
+		return item.ID
+	})
+	if !lo.Every(ownedIds, ids) {
+		return nil, response.APIKeyScopeExceeded
+	}
+	return permissions, nil
+}
+
+// DeleteAPIKey 删除用户的API密钥,删除后立即失效
+func (u *Service) DeleteAPIKey(ctx context.Context, uid uint, id uint) error {
+	return u.apiKeyDAO.DeleteByID(ctx, uid, id)
+}
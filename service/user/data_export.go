@@ -0,0 +1,149 @@
+package user
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/supuwoerc/weaver/models"
+	"github.com/supuwoerc/weaver/pkg/constant"
+	"github.com/supuwoerc/weaver/pkg/response"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/samber/lo"
+)
+
+// RequestDataExport 申请导出个人数据,异步打包个人资料、角色、部门、附件信息和登录记录,
+// 完成后向用户邮箱发送下载链接,两次申请之间需要间隔 account.data_export_interval
+func (u *Service) RequestDataExport(ctx context.Context, uid uint) error {
+	user, err := u.userDAO.GetByID(ctx, uid, "Roles", "Departments")
+	if err != nil {
+		return err
+	}
+	acquired, err := u.userCache.AcquireDataExportLimit(ctx, uid, u.Conf.Account.DataExportInterval*time.Second)
+	if err != nil {
+		return err
+	}
+	if !acquired {
+		return response.DataExportTooFrequent
+	}
+	logger := u.Logger.WithContext(ctx)
+	// 请求上下文在响应后会被回收,异步任务使用独立的上下文
+	go func(ctx context.Context) {
+		defer func() {
+			if r := recover(); r != nil {
+				logger.Errorw("data export panic", "uid", uid, "panic", r)
+			}
+		}()
+		if e := u.exportUserData(ctx, user); e != nil {
+			logger.Errorw("data export fail", "uid", uid, "err", e.Error())
+			// 导出失败时允许用户立即重新申请
+			if e = u.userCache.ReleaseDataExportLimit(ctx, uid); e != nil {
+				logger.Errorw("release data export limit fail", "uid", uid, "err", e.Error())
+			}
+		}
+	}(context.Background())
+	return nil
+}
+
+// exportUserData 打包个人数据并缓存,向用户邮箱发送下载链接
+func (u *Service) exportUserData(ctx context.Context, user *models.User) error {
+	data, err := u.buildDataExport(ctx, user)
+	if err != nil {
+		return err
+	}
+	code := lo.RandomString(constant.DataExportCodeLength, lo.AlphanumericCharset)
+	expiration := u.Conf.Account.DataExportExpiration * time.Second
+	if err = u.userCache.CacheDataExport(ctx, code, data, expiration); err != nil {
+		return err
+	}
+	variable := models.DataExportVariable{
+		DownloadURL: fmt.Sprintf("%s?code=%s", u.Conf.Account.DataExportURL, url.QueryEscape(code)),
+		Expiration:  int(expiration.Hours()),
+	}
+	return u.EmailClient.SendHTML(ctx, user.Email, constant.DataExport, constant.DataExportTemplate, variable)
+}
+
+// buildDataExport 将个人数据打包为zip,每类数据为一个json文件
+func (u *Service) buildDataExport(ctx context.Context, user *models.User) ([]byte, error) {
+	attachments, err := u.attachmentDAO.GetByCreator(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	// limit和offset为-1时不分页
+	histories, _, err := u.loginHistoryDAO.GetList(ctx, &models.LoginHistoryFilter{UserID: user.ID}, -1, -1)
+	if err != nil {
+		return nil, err
+	}
+	toRelation := func(id uint, name string) *models.DataExportRelation {
+		return &models.DataExportRelation{ID: id, Name: name}
+	}
+	files := []struct {
+		name  string
+		value any
+	}{
+		{name: "profile.json", value: &models.DataExportProfile{
+			ID:        user.ID,
+			Email:     user.Email,
+			Phone:     user.Phone,
+			Nickname:  user.Nickname,
+			Avatar:    user.Avatar,
+			Gender:    user.Gender,
+			About:     user.About,
+			Birthday:  user.Birthday,
+			Status:    user.Status,
+			CreatedAt: user.CreatedAt,
+		}},
+		{name: "roles.json", value: lo.Map(user.Roles, func(item *models.Role, _ int) *models.DataExportRelation {
+			return toRelation(item.ID, item.Name)
+		})},
+		{name: "departments.json", value: lo.Map(user.Departments, func(item *models.Department, _ int) *models.DataExportRelation {
+			return toRelation(item.ID, item.Name)
+		})},
+		{name: "attachments.json", value: lo.Map(attachments, func(item *models.Attachment, _ int) *models.DataExportAttachment {
+			return &models.DataExportAttachment{
+				ID:        item.ID,
+				Name:      item.Name,
+				Type:      item.Type,
+				Size:      item.Size,
+				Hash:      item.Hash,
+				CreatedAt: item.CreatedAt,
+			}
+		})},
+		{name: "login_history.json", value: histories},
+	}
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	for _, file := range files {
+		w, e := writer.Create(file.name)
+		if e != nil {
+			return nil, e
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if e = encoder.Encode(file.value); e != nil {
+			return nil, e
+		}
+	}
+	if err = writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// DownloadDataExport 通过下载链接中的code获取打包好的个人数据
+func (u *Service) DownloadDataExport(ctx context.Context, code string) ([]byte, error) {
+	data, err := u.userCache.GetDataExport(ctx, code)
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, response.DataExportCodeInvalid
+		}
+		return nil, err
+	}
+	return data, nil
+}
@@ -0,0 +1,150 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/supuwoerc/weaver/models"
+	"github.com/supuwoerc/weaver/pkg/constant"
+	"github.com/supuwoerc/weaver/pkg/response"
+	"github.com/supuwoerc/weaver/pkg/utils"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/samber/lo"
+)
+
+// ChangeEmail 发起修改邮箱,校验密码后向新邮箱发送确认链接,确认前邮箱不会变更
+func (u *Service) ChangeEmail(ctx context.Context, uid uint, password string, email string) error {
+	user, err := u.userDAO.GetByID(ctx, uid)
+	if err != nil {
+		return err
+	}
+	if !matchPassword(user.Password, password) {
+		return response.OldPasswordIncorrect
+	}
+	if strings.EqualFold(user.Email, email) {
+		return response.UserCreateDuplicateEmail
+	}
+	existUser, err := u.userDAO.GetByEmail(ctx, email)
+	if err != nil && !errors.Is(err, response.UserNotExist) {
+		return err
+	}
+	if existUser != nil {
+		return response.UserCreateDuplicateEmail
+	}
+	code := lo.RandomString(constant.EmailChangeCodeLength, lo.AlphanumericCharset)
+	expiration := u.Conf.Account.ChangeEmailExpiration * time.Second
+	err = u.userCache.CacheEmailChange(ctx, constant.ChangeEmailPrefix, code, &models.EmailChange{
+		UID:      user.ID,
+		OldEmail: user.Email,
+		NewEmail: email,
+	}, expiration)
+	if err != nil {
+		return err
+	}
+	variable := models.ChangeEmailVariable{
+		Email:      email,
+		ConfirmURL: fmt.Sprintf("%s?code=%s", u.Conf.Account.ChangeEmailURL, url.QueryEscape(code)),
+		Expiration: int(expiration.Minutes()),
+	}
+	return u.EmailClient.SendHTML(ctx, email, constant.ChangeEmail, constant.ChangeEmailTemplate, variable)
+}
+
+// ConfirmEmailChange 确认修改邮箱,更新邮箱后向原邮箱发送附带撤销链接的通知,
+// 携带原邮箱的短token全部吊销,客户端刷新token后获取新的邮箱
+func (u *Service) ConfirmEmailChange(ctx context.Context, code string) error {
+	change, err := u.userCache.ConsumeEmailChange(ctx, constant.ChangeEmailPrefix, code)
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return response.EmailChangeCodeInvalid
+		}
+		return err
+	}
+	revertCode := lo.RandomString(constant.EmailChangeCodeLength, lo.AlphanumericCharset)
+	expiration := u.Conf.Account.RevertEmailExpiration * time.Second
+	err = u.updateEmail(ctx, change.UID, change.OldEmail, change.NewEmail, func(ctx context.Context) error {
+		if e := u.userCache.CacheEmailChange(ctx, constant.RevertEmailPrefix, revertCode, change, expiration); e != nil {
+			return e
+		}
+		variable := models.EmailChangedVariable{
+			Email:      change.NewEmail,
+			RevertURL:  fmt.Sprintf("%s?code=%s", u.Conf.Account.RevertEmailURL, url.QueryEscape(revertCode)),
+			Expiration: int(expiration.Hours()),
+		}
+		return u.EmailClient.SendHTML(ctx, change.OldEmail, constant.EmailChanged, constant.EmailChangedTemplate, variable)
+	})
+	if err != nil {
+		return err
+	}
+	u.Logger.WithContext(ctx).Infow("email changed", "uid", change.UID, "old", change.OldEmail, "new", change.NewEmail)
+	return u.userCache.DenyUserAccessTokens(ctx, change.UID)
+}
+
+// RevertEmailChange 撤销修改邮箱,恢复原邮箱并注销用户全部登录会话,用于账户被盗用后原邮箱的所有者找回账户
+func (u *Service) RevertEmailChange(ctx context.Context, code string) error {
+	change, err := u.userCache.ConsumeEmailChange(ctx, constant.RevertEmailPrefix, code)
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return response.EmailChangeCodeInvalid
+		}
+		return err
+	}
+	if err = u.updateEmail(ctx, change.UID, change.NewEmail, change.OldEmail, nil); err != nil {
+		return err
+	}
+	u.Logger.WithContext(ctx).Infow("email change reverted", "uid", change.UID, "old", change.OldEmail, "new", change.NewEmail)
+	if err = u.userCache.DeleteUserSessions(ctx, change.UID); err != nil {
+		return err
+	}
+	return u.userCache.DenyUserAccessTokens(ctx, change.UID)
+}
+
+// updateEmail 在用户和目标邮箱的锁内将邮箱从 from 修改为 to,after 与修改在同一事务中执行,
+// 同时清除原邮箱的登录失败记录
+func (u *Service) updateEmail(
+	ctx context.Context, uid uint, from, to string, after func(ctx context.Context) error,
+) error {
+	userLock := u.Locksmith.NewLock(constant.UserIdPrefix, strconv.Itoa(int(uid)))
+	if err := userLock.Lock(ctx, true); err != nil {
+		return err
+	}
+	defer func(lock *utils.RedisLock) {
+		if e := lock.Unlock(); e != nil {
+			u.Logger.WithContext(ctx).Errorf("unlock fail %s", e.Error())
+		}
+	}(userLock)
+	emailLock := u.Locksmith.NewLock(constant.SignUpEmailPrefix, to)
+	if err := emailLock.Lock(ctx, true); err != nil {
+		return err
+	}
+	defer func(lock *utils.RedisLock) {
+		if e := lock.Unlock(); e != nil {
+			u.Logger.WithContext(ctx).Errorf("unlock fail %s", e.Error())
+		}
+	}(emailLock)
+	existUser, err := u.userDAO.GetByEmail(ctx, to)
+	if err != nil && !errors.Is(err, response.UserNotExist) {
+		return err
+	}
+	if existUser != nil {
+		return response.UserCreateDuplicateEmail
+	}
+	err = u.Transaction(ctx, false, func(ctx context.Context) error {
+		if err = u.userDAO.UpdateEmail(ctx, uid, from, to); err != nil {
+			return err
+		}
+		if after != nil {
+			return after(ctx)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return u.userCache.ClearLoginFailures(ctx, from, "")
+}
@@ -0,0 +1,109 @@
+package user
+
+import (
+	"context"
+	"time"
+
+	"github.com/supuwoerc/weaver/models"
+	"github.com/supuwoerc/weaver/pkg/constant"
+	"github.com/supuwoerc/weaver/pkg/jwt"
+	"github.com/supuwoerc/weaver/pkg/request"
+	"github.com/supuwoerc/weaver/pkg/response"
+
+	"github.com/google/uuid"
+)
+
+// Impersonate 以指定用户的身份签发短token,token沿用操作人的登录会话,操作人登出或会话被注销后随之失效,
+// 开始和结束都会记录模拟登录记录;除系统管理员外,只能模拟权限不超过自己的用户,避免越权
+func (u *Service) Impersonate(
+	ctx context.Context, actor *jwt.TokenClaimsBasic, session string, ip string, params *request.ImpersonateRequest,
+) (*response.ImpersonateResponse, error) {
+	user, err := u.userDAO.GetByID(ctx, params.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err = u.checkManageable(actor.ID, user); err != nil {
+		return nil, err
+	}
+	if err = u.checkImpersonatable(ctx, actor, user.ID); err != nil {
+		return nil, err
+	}
+	switch user.Status {
+	case constant.Inactive:
+		return nil, response.UserInactive
+	case constant.Disabled:
+		return nil, response.UserDisabled
+	}
+	tokenID := uuid.NewString()
+	token, expiresAt, err := u.tokenBuilder.GenerateImpersonationToken(&jwt.TokenClaimsBasic{
+		ID:       user.ID,
+		Email:    user.Email,
+		Nickname: user.Nickname,
+	}, actor, tokenID, session, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	err = u.impersonationDAO.Create(ctx, &models.UserImpersonation{
+		ActorID:   actor.ID,
+		UserID:    user.ID,
+		TokenID:   tokenID,
+		Reason:    params.Reason,
+		IP:        ip,
+		ExpiredAt: expiresAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+	// 记录在被模拟用户名下,用户被禁用、删除或重置密码时一并吊销
+	if err = u.userCache.RecordAccessToken(ctx, user.ID, session, tokenID, expiresAt); err != nil {
+		return nil, err
+	}
+	u.Logger.WithContext(ctx).Infow("impersonation start",
+		"actor", actor.ID, "uid", user.ID, "jti", tokenID, "reason", params.Reason, "ip", ip)
+	return &response.ImpersonateResponse{
+		User:      toLoginUser(user),
+		Token:     token,
+		ExpiredAt: expiresAt,
+	}, nil
+}
+
+// checkImpersonatable 被模拟用户的权限集合需要是操作人权限集合的子集
+func (u *Service) checkImpersonatable(ctx context.Context, actor *jwt.TokenClaimsBasic, uid uint) error {
+	if actor.Email == u.Conf.System.Admin.Email {
+		return nil
+	}
+	actorSet, err := u.permissionService.GetUserPermissionSet(ctx, actor.ID)
+	if err != nil {
+		return err
+	}
+	userSet, err := u.permissionService.GetUserPermissionSet(ctx, uid)
+	if err != nil {
+		return err
+	}
+	if !actorSet.Contains(userSet) {
+		return response.UserManageForbidden
+	}
+	return nil
+}
+
+// StopImpersonation 结束模拟登录,吊销模拟登录的token,操作人的登录会话不受影响
+func (u *Service) StopImpersonation(ctx context.Context, claims *jwt.TokenClaims) error {
+	if claims.Actor == nil {
+		return response.InvalidToken
+	}
+	if err := u.userCache.DenyAccessToken(ctx, claims.ID, claims.ExpiresAt.Time); err != nil {
+		return err
+	}
+	if err := u.impersonationDAO.Stop(ctx, claims.ID, time.Now()); err != nil {
+		return err
+	}
+	u.Logger.WithContext(ctx).Infow("impersonation stop", "actor", claims.Actor.ID, "uid", claims.User.ID, "jti", claims.ID)
+	return nil
+}
+
+// GetImpersonations 查询模拟登录记录
+func (u *Service) GetImpersonations(
+	ctx context.Context, params *request.GetImpersonationsRequest,
+) ([]*models.UserImpersonation, int64, error) {
+	return u.impersonationDAO.GetList(ctx, params.ActorID, params.UserID, params.Limit, params.Offset)
+}
@@ -0,0 +1,139 @@
+package user
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alicebob/miniredis/v2"
+	jwt2 "github.com/golang-jwt/jwt/v5"
+	goredislib "github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/supuwoerc/weaver/conf"
+	"github.com/supuwoerc/weaver/models"
+	"github.com/supuwoerc/weaver/pkg/constant"
+	"github.com/supuwoerc/weaver/pkg/jwt"
+	"github.com/supuwoerc/weaver/pkg/logger"
+	"github.com/supuwoerc/weaver/pkg/redis"
+	"github.com/supuwoerc/weaver/pkg/request"
+	"github.com/supuwoerc/weaver/pkg/response"
+	"github.com/supuwoerc/weaver/repository/cache"
+	"github.com/supuwoerc/weaver/repository/dao"
+	"github.com/supuwoerc/weaver/service"
+	"go.uber.org/zap/zaptest"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+func TestService_LogoutImpersonation(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	client := goredislib.NewClient(&goredislib.Options{Addr: mr.Addr()})
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer func() {
+		_ = db.Close()
+	}()
+	gormDB, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      db,
+		SkipInitializeWithVersion: true,
+	}))
+	require.NoError(t, err)
+	userCache := cache.NewUserCache(redis.NewCommonRedisClient(client, nil))
+	u := &Service{
+		BasicService:     &service.BasicService{Logger: logger.NewLogger(zaptest.NewLogger(t).Sugar())},
+		userCache:        userCache,
+		impersonationDAO: dao.NewUserImpersonationDAO(dao.NewBasicDao(gormDB)),
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(time.Hour)
+	for _, item := range []*models.UserSession{
+		{ID: "actor-sid", UID: 1, CreatedAt: now, LastSeen: now},
+		{ID: "target-sid", UID: 2, CreatedAt: now, LastSeen: now},
+	} {
+		require.NoError(t, userCache.CreateSession(ctx, item, item.ID+"-refresh", time.Hour))
+	}
+	require.NoError(t, userCache.RecordAccessToken(ctx, 1, "actor-sid", "actor-jti", expiresAt))
+	require.NoError(t, userCache.RecordAccessToken(ctx, 2, "target-sid", "target-jti", expiresAt))
+	// 模拟登录的token记录在被模拟用户名下,沿用操作人的会话
+	require.NoError(t, userCache.RecordAccessToken(ctx, 2, "actor-sid", "impersonation-jti", expiresAt))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `user_impersonations` SET `stopped_at`=?")).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), "impersonation-jti", 0).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	err = u.Logout(ctx, &jwt.TokenClaims{
+		RegisteredClaims: jwt2.RegisteredClaims{ID: "impersonation-jti", ExpiresAt: jwt2.NewNumericDate(expiresAt)},
+		User:             &jwt.TokenClaimsBasic{ID: 2},
+		Session:          "actor-sid",
+		Actor:            &jwt.TokenClaimsBasic{ID: 1},
+	})
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	denied, err := userCache.IsAccessTokenDenied(ctx, "impersonation-jti")
+	require.NoError(t, err)
+	assert.True(t, denied)
+	for uid, sid := range map[uint]string{1: "actor-sid", 2: "target-sid"} {
+		sessions, err := userCache.GetUserSessions(ctx, uid)
+		require.NoError(t, err)
+		require.Len(t, sessions, 1)
+		assert.Equal(t, sid, sessions[0].ID)
+	}
+	for _, jti := range []string{"actor-jti", "target-jti"} {
+		denied, err = userCache.IsAccessTokenDenied(ctx, jti)
+		require.NoError(t, err)
+		assert.False(t, denied)
+	}
+}
+
+// stubPermissionService 按用户返回固定的权限集合
+type stubPermissionService map[uint]*models.PermissionSet
+
+func (s stubPermissionService) GetUserPermissionSet(_ context.Context, uid uint) (*models.PermissionSet, error) {
+	return s[uid], nil
+}
+
+func TestService_ImpersonateRequiresPermissionSubset(t *testing.T) {
+	ctx := context.Background()
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer func() {
+		_ = db.Close()
+	}()
+	gormDB, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      db,
+		SkipInitializeWithVersion: true,
+	}))
+	require.NoError(t, err)
+	config := &conf.Config{}
+	config.System.Admin.Email = "admin@weaver.com"
+	u := &Service{
+		BasicService: &service.BasicService{Logger: logger.NewLogger(zaptest.NewLogger(t).Sugar()), Conf: config},
+		userDAO:      dao.NewUserDAO(dao.NewBasicDao(gormDB)),
+		permissionService: stubPermissionService{
+			1: models.NewPermissionSet([]*models.Permission{
+				{Type: constant.ApiRoute, Method: "GET", Resource: "/api/v1/user/list"},
+			}),
+			2: models.NewPermissionSet([]*models.Permission{
+				{Type: constant.ApiRoute, Method: "GET", Resource: "/api/v1/user/list"},
+				{Type: constant.ApiRoute, Method: "POST", Resource: "/api/v1/role/delete"},
+			}),
+		},
+	}
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `users`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "status"}).AddRow(2, "b@b.com", constant.Normal))
+	// 被模拟用户拥有操作人没有的权限
+	_, err = u.Impersonate(ctx, &jwt.TokenClaimsBasic{ID: 1, Email: "a@a.com"}, "actor-sid", "127.0.0.1",
+		&request.ImpersonateRequest{ID: 2, Reason: "debug"})
+	assert.ErrorIs(t, err, response.UserManageForbidden)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,182 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/supuwoerc/weaver/models"
+	"github.com/supuwoerc/weaver/pkg/constant"
+	"github.com/supuwoerc/weaver/pkg/jwt"
+	"github.com/supuwoerc/weaver/pkg/request"
+	"github.com/supuwoerc/weaver/pkg/response"
+	"github.com/supuwoerc/weaver/pkg/utils"
+
+	"github.com/samber/lo"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// signupMode 注册方式,未配置时允许公开注册
+func (u *Service) signupMode() constant.SignupMode {
+	if u.Conf.Account.SignupMode == "" {
+		return constant.SignupOpen
+	}
+	return u.Conf.Account.SignupMode
+}
+
+// CreateInvitation 创建注册邀请并发送邀请邮件,邀请码明文仅出现在邮件中,数据库中只保存摘要
+func (u *Service) CreateInvitation(
+	ctx context.Context, inviter *jwt.TokenClaimsBasic, params *request.CreateInvitationRequest,
+) error {
+	if u.signupMode() == constant.SignupDisabled {
+		return response.SignupClosed
+	}
+	existUser, err := u.userDAO.GetByEmail(ctx, params.Email)
+	if err != nil && !errors.Is(err, response.UserNotExist) {
+		return err
+	}
+	if existUser != nil {
+		return response.UserCreateDuplicateEmail
+	}
+	invitation := &models.UserInvitation{
+		Email:     params.Email,
+		InviterID: inviter.ID,
+		ExpiredAt: time.Now().Add(time.Duration(params.Expires) * time.Hour),
+	}
+	if len(params.Roles) > 0 {
+		if invitation.Roles, err = u.roleDAO.GetByIds(ctx, lo.Uniq(params.Roles)); err != nil {
+			return err
+		}
+	}
+	if len(params.Departments) > 0 {
+		if invitation.Departments, err = u.departmentDAO.GetByIds(ctx, lo.Uniq(params.Departments)); err != nil {
+			return err
+		}
+	}
+	code := lo.RandomString(constant.InvitationCodeLength, lo.AlphanumericCharset)
+	invitation.Code = utils.Sha256(code)
+	inviterName := inviter.Email
+	if inviter.Nickname != nil && *inviter.Nickname != "" {
+		inviterName = *inviter.Nickname
+	}
+	return u.Transaction(ctx, false, func(ctx context.Context) error {
+		if err = u.invitationDAO.Create(ctx, invitation); err != nil {
+			return err
+		}
+		variable := models.InvitationVariable{
+			Inviter:   inviterName,
+			AcceptURL: fmt.Sprintf("%s?code=%s", u.Conf.Account.InvitationURL, url.QueryEscape(code)),
+			ExpiredAt: invitation.ExpiredAt.Format(time.DateTime),
+		}
+		return u.EmailClient.SendHTML(ctx, invitation.Email, constant.Invitation, constant.InvitationTemplate, variable)
+	})
+}
+
+// getValidInvitation 根据邀请码查询未接受且未过期的邀请
+func (u *Service) getValidInvitation(ctx context.Context, code string) (*models.UserInvitation, error) {
+	if u.signupMode() == constant.SignupDisabled {
+		return nil, response.SignupClosed
+	}
+	invitation, err := u.invitationDAO.GetByCode(ctx, utils.Sha256(code))
+	if err != nil {
+		return nil, err
+	}
+	if !invitation.IsValid(time.Now()) {
+		return nil, response.InvitationNotExist
+	}
+	return invitation, nil
+}
+
+// GetInvitation 查询邀请的公开信息,用于接受邀请页面的预填
+func (u *Service) GetInvitation(ctx context.Context, code string) (*response.InvitationResponse, error) {
+	invitation, err := u.getValidInvitation(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	inviter, err := u.userDAO.GetByID(ctx, invitation.InviterID)
+	if err != nil && !errors.Is(err, response.UserNotExist) {
+		return nil, err
+	}
+	res := &response.InvitationResponse{
+		Email:     invitation.Email,
+		ExpiredAt: invitation.ExpiredAt,
+	}
+	if inviter != nil {
+		res.Inviter = lo.FromPtrOr(inviter.Nickname, inviter.Email)
+	}
+	return res, nil
+}
+
+// AcceptInvitation 接受邀请,创建已激活的用户并关联邀请中预设的角色和部门
+func (u *Service) AcceptInvitation(ctx context.Context, params *request.AcceptInvitationRequest) error {
+	invitation, err := u.getValidInvitation(ctx, params.Code)
+	if err != nil {
+		return err
+	}
+	if err = u.validatePassword(ctx, nil, params.Password); err != nil {
+		return err
+	}
+	password, err := bcrypt.GenerateFromPassword([]byte(params.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	user := &models.User{
+		Email:    invitation.Email,
+		Password: string(password),
+		Nickname: params.Nickname,
+		Status:   constant.Normal,
+	}
+	emailLock := u.Locksmith.NewLock(constant.SignUpEmailPrefix, user.Email)
+	if err = emailLock.Lock(ctx, true); err != nil {
+		return err
+	}
+	defer func(lock *utils.RedisLock) {
+		if e := lock.Unlock(); e != nil {
+			u.Logger.WithContext(ctx).Errorf("unlock fail %s", e.Error())
+		}
+	}(emailLock)
+	roleIds := lo.Map(invitation.Roles, func(item *models.Role, _ int) uint { return item.ID })
+	departmentIds := lo.Map(invitation.Departments, func(item *models.Department, _ int) uint { return item.ID })
+	locks, err := u.lockUserRelations(ctx, roleIds, departmentIds)
+	defer u.unlockAll(ctx, locks)
+	if err != nil {
+		return err
+	}
+	existUser, err := u.userDAO.GetByEmail(ctx, user.Email)
+	if err != nil && !errors.Is(err, response.UserNotExist) {
+		return err
+	}
+	if existUser != nil {
+		return response.UserCreateDuplicateEmail
+	}
+	err = u.Transaction(ctx, false, func(ctx context.Context) error {
+		if err = u.userDAO.Create(ctx, user); err != nil {
+			return err
+		}
+		if err = u.recordPasswordHistory(ctx, user.ID, user.Password); err != nil {
+			return err
+		}
+		if err = u.associateRelations(ctx, user.ID, roleIds, departmentIds); err != nil {
+			return err
+		}
+		return u.invitationDAO.Accept(ctx, invitation.ID, user.ID, time.Now())
+	})
+	if err != nil {
+		return err
+	}
+	return u.cleanDepartmentCache(ctx)
+}
+
+// GetInvitations 查询注册邀请列表
+func (u *Service) GetInvitations(
+	ctx context.Context, params *request.GetInvitationsRequest,
+) ([]*models.UserInvitation, int64, error) {
+	return u.invitationDAO.GetList(ctx, params.Keyword, params.Limit, params.Offset)
+}
+
+// RevokeInvitation 撤销未被接受的注册邀请
+func (u *Service) RevokeInvitation(ctx context.Context, id uint) error {
+	return u.invitationDAO.DeleteByID(ctx, id)
+}
@@ -0,0 +1,99 @@
+package user
+
+import (
+	"context"
+	"time"
+
+	"github.com/supuwoerc/weaver/pkg/constant"
+	"github.com/supuwoerc/weaver/pkg/response"
+)
+
+// checkLoginAllowed 检查邮箱及ip是否被临时锁定或处于失败后的等待期,失败次数较多时要求验证码
+func (u *Service) checkLoginAllowed(ctx context.Context, email string, ip string, id string, code string) error {
+	blocks := map[constant.Prefix]string{constant.LoginLockEmailPrefix: email}
+	if ip != "" {
+		blocks[constant.LoginLockIPPrefix] = ip
+	}
+	for prefix, key := range blocks {
+		remaining, err := u.userCache.GetLoginBlock(ctx, prefix, key)
+		if err != nil {
+			return err
+		}
+		if remaining > 0 {
+			return response.UserLoginLocked
+		}
+	}
+	remaining, err := u.userCache.GetLoginBlock(ctx, constant.LoginDelayPrefix, email)
+	if err != nil {
+		return err
+	}
+	if remaining > 0 {
+		return response.Busy
+	}
+	threshold := u.Conf.Account.LoginCaptchaThreshold
+	if threshold <= 0 {
+		return nil
+	}
+	failures, err := u.userCache.GetRequestCount(ctx, constant.LoginFailEmailPrefix, email)
+	if err != nil {
+		return err
+	}
+	if failures < threshold {
+		return nil
+	}
+	if id == "" || code == "" {
+		return response.LoginCaptchaRequired
+	}
+	if !u.Service.Verify(constant.Login, id, code) {
+		return response.CaptchaVerifyFail
+	}
+	return nil
+}
+
+// recordLoginFailure 记录登录失败次数,每次失败后的等待时长递增,达到阈值后临时锁定邮箱或ip
+func (u *Service) recordLoginFailure(ctx context.Context, email string, ip string) error {
+	account := u.Conf.Account
+	window := account.LoginFailWindow * time.Second
+	failures, err := u.userCache.IncrRequestCount(ctx, constant.LoginFailEmailPrefix, email, window)
+	if err != nil {
+		return err
+	}
+	if account.LoginLockThreshold > 0 && failures >= account.LoginLockThreshold {
+		err = u.userCache.CacheLoginBlock(ctx, constant.LoginLockEmailPrefix, email, account.LoginLockDuration*time.Second)
+	} else if delay := u.loginDelay(failures); delay > 0 {
+		err = u.userCache.CacheLoginBlock(ctx, constant.LoginDelayPrefix, email, delay)
+	}
+	if err != nil {
+		return err
+	}
+	if ip == "" || account.LoginIPLockThreshold <= 0 {
+		return nil
+	}
+	ipFailures, err := u.userCache.IncrRequestCount(ctx, constant.LoginFailIPPrefix, ip, window)
+	if err != nil {
+		return err
+	}
+	if ipFailures >= account.LoginIPLockThreshold {
+		return u.userCache.CacheLoginBlock(ctx, constant.LoginLockIPPrefix, ip, account.LoginLockDuration*time.Second)
+	}
+	return nil
+}
+
+// loginDelay 计算第 failures 次登录失败后的等待时长,从 LoginDelayBase 开始每次翻倍,不超过 LoginDelayMax
+func (u *Service) loginDelay(failures int64) time.Duration {
+	base := u.Conf.Account.LoginDelayBase * time.Second
+	limit := u.Conf.Account.LoginDelayMax * time.Second
+	if base <= 0 || limit <= 0 || failures <= 0 {
+		return 0
+	}
+	delay := base
+	for i := int64(1); i < failures && delay < limit; i++ {
+		delay *= 2
+	}
+	return min(delay, limit)
+}
+
+// UnlockLogin 解除邮箱(及ip)的登录锁定并清空失败次数
+func (u *Service) UnlockLogin(ctx context.Context, email string, ip string) error {
+	return u.userCache.ClearLoginFailures(ctx, email, ip)
+}
@@ -0,0 +1,137 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/supuwoerc/weaver/models"
+	"github.com/supuwoerc/weaver/pkg/constant"
+	"github.com/supuwoerc/weaver/pkg/database"
+	"github.com/supuwoerc/weaver/pkg/request"
+	"github.com/supuwoerc/weaver/pkg/response"
+)
+
+// newLoginAttempt 创建一次登录尝试的记录,登录流程结束时由 finishLoginAttempt 写入
+func newLoginAttempt(loginType constant.LoginType, email string, client *models.LoginClient) *models.UserLoginHistory {
+	attempt := &models.UserLoginHistory{
+		Email: email,
+		Type:  loginType,
+		MFA:   loginType == constant.LoginMFA,
+	}
+	if client != nil {
+		attempt.IP = client.IP
+		attempt.UserAgent = client.UserAgent
+		attempt.Device = client.Device
+	}
+	return attempt
+}
+
+// setAttemptUser 记录登录尝试对应的用户
+func setAttemptUser(attempt *models.UserLoginHistory, user *models.User) {
+	attempt.UserID = &user.ID
+	attempt.Email = user.Email
+}
+
+// finishLoginAttempt 登录记录加入待写入队列,由定时任务异步写入,completed表示已签发token,需要两步验证或修改过期密码的中间步骤不记录;
+// 业务错误记录为失败原因,内部错误不记录;加入队列失败不影响登录结果
+func (u *Service) finishLoginAttempt(ctx context.Context, attempt *models.UserLoginHistory, completed bool, err error) {
+	if err != nil {
+		var code response.StatusCode
+		if !errors.As(err, &code) {
+			return
+		}
+		attempt.Reason = code.String()
+	} else if !completed {
+		return
+	}
+	attempt.Success = err == nil
+	attempt.CreatedAt = database.UpsertTime(time.Now())
+	if e := u.userCache.PushLoginHistory(ctx, attempt); e != nil {
+		u.Logger.WithContext(ctx).Errorw("push login history fail", "email", attempt.Email, "err", e.Error())
+	}
+}
+
+// FlushLoginHistories 按登录顺序写入队列中的登录记录,从新的ip或设备登录成功时按配置发送登录提醒;
+// 单条写入或发送失败只记录日志
+func (u *Service) FlushLoginHistories(ctx context.Context) error {
+	logger := u.Logger.WithContext(ctx)
+	for {
+		records, err := u.userCache.PopLoginHistories(ctx, constant.LoginHistoryBatchSize)
+		if err != nil {
+			return err
+		}
+		for _, record := range records {
+			if e := u.loginHistoryDAO.Create(ctx, record); e != nil {
+				logger.Errorw("record login history fail", "email", record.Email, "err", e.Error())
+				continue
+			}
+			if e := u.sendLoginAlert(ctx, record); e != nil {
+				logger.Errorw("send login alert fail", "history", record.ID, "err", e.Error())
+			}
+		}
+		if len(records) < constant.LoginHistoryBatchSize {
+			return nil
+		}
+	}
+}
+
+// sendLoginAlert 登录来自用户此前从未成功登录过的ip或设备时发送提醒邮件,首次登录不提醒;
+// 只统计本次之前的记录,同一新设备的多次登录只有第一次提醒
+func (u *Service) sendLoginAlert(ctx context.Context, attempt *models.UserLoginHistory) error {
+	if !attempt.Success || attempt.UserID == nil || attempt.Type == constant.LoginRefresh || !u.Conf.Account.LoginAlert {
+		return nil
+	}
+	uid := *attempt.UserID
+	total, err := u.loginHistoryDAO.CountSuccess(ctx, uid, attempt.ID, "", "")
+	if err != nil || total == 0 {
+		return err
+	}
+	sameIP, err := u.loginHistoryDAO.CountSuccess(ctx, uid, attempt.ID, attempt.IP, "")
+	if err != nil {
+		return err
+	}
+	sameDevice, err := u.loginHistoryDAO.CountSuccess(ctx, uid, attempt.ID, "", attempt.UserAgent)
+	if err != nil {
+		return err
+	}
+	if sameIP > 0 && sameDevice > 0 {
+		return nil
+	}
+	variable := models.LoginAlertVariable{
+		Time:      time.Time(attempt.CreatedAt).Format(time.DateTime),
+		IP:        attempt.IP,
+		Device:    attempt.Device,
+		UserAgent: attempt.UserAgent,
+	}
+	return u.EmailClient.SendHTML(ctx, attempt.Email, constant.LoginAlert, constant.LoginAlertTemplate, variable)
+}
+
+// GetLoginHistory 查询用户本人的登录记录
+func (u *Service) GetLoginHistory(
+	ctx context.Context, uid uint, params *request.GetLoginHistoryRequest,
+) ([]*models.UserLoginHistory, int64, error) {
+	return u.loginHistoryDAO.GetList(ctx, &models.LoginHistoryFilter{UserID: uid}, params.Limit, params.Offset)
+}
+
+// GetLoginHistories 按条件查询全部用户的登录记录
+func (u *Service) GetLoginHistories(
+	ctx context.Context, params *request.GetLoginHistoriesRequest,
+) ([]*models.UserLoginHistory, int64, error) {
+	filter := &models.LoginHistoryFilter{
+		UserID:  params.UserID,
+		Email:   params.Email,
+		Type:    constant.LoginType(params.Type),
+		Success: params.Success,
+		IP:      params.IP,
+	}
+	if params.Start != nil {
+		start := time.Unix(*params.Start, 0)
+		filter.Start = &start
+	}
+	if params.End != nil {
+		end := time.Unix(*params.End, 0)
+		filter.End = &end
+	}
+	return u.loginHistoryDAO.GetList(ctx, filter, params.Limit, params.Offset)
+}
@@ -0,0 +1,333 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/supuwoerc/weaver/models"
+	"github.com/supuwoerc/weaver/pkg/constant"
+	"github.com/supuwoerc/weaver/pkg/request"
+	"github.com/supuwoerc/weaver/pkg/response"
+	"github.com/supuwoerc/weaver/pkg/utils"
+
+	"github.com/samber/lo"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// lockUserRelations 对用户关联的角色和部门加锁
+func (u *Service) lockUserRelations(ctx context.Context, roleIds, departmentIds []uint) ([]*utils.RedisLock, error) {
+	locks := make([]*utils.RedisLock, 0, len(roleIds)+len(departmentIds))
+	for _, id := range lo.Uniq(roleIds) {
+		roleLock := u.Locksmith.NewLock(constant.RoleIdPrefix, strconv.Itoa(int(id)))
+		if err := roleLock.Lock(ctx, true); err != nil {
+			return locks, err
+		}
+		locks = append(locks, roleLock)
+	}
+	for _, id := range lo.Uniq(departmentIds) {
+		deptLock := u.Locksmith.NewLock(constant.DepartmentIdPrefix, strconv.Itoa(int(id)))
+		if err := deptLock.Lock(ctx, true); err != nil {
+			return locks, err
+		}
+		locks = append(locks, deptLock)
+	}
+	return locks, nil
+}
+
+func (u *Service) unlockAll(ctx context.Context, locks []*utils.RedisLock) {
+	for _, l := range locks {
+		if e := l.Unlock(); e != nil {
+			u.Logger.WithContext(ctx).Errorf("unlock fail %s", e.Error())
+		}
+	}
+}
+
+// checkManageable 管理员不能管理自己和系统管理员的账户,避免误操作导致无法登录或越权
+func (u *Service) checkManageable(operator uint, user *models.User) error {
+	if user.ID == operator || user.Email == u.Conf.System.Admin.Email {
+		return response.UserManageForbidden
+	}
+	return nil
+}
+
+// associateRelations 替换用户的角色和部门,无效的角色和部门会被忽略
+func (u *Service) associateRelations(ctx context.Context, uid uint, roleIds, departmentIds []uint) error {
+	var (
+		roles       []*models.Role
+		departments []*models.Department
+		err         error
+	)
+	if len(roleIds) > 0 {
+		if roles, err = u.roleDAO.GetByIds(ctx, roleIds); err != nil {
+			return err
+		}
+	}
+	if len(departmentIds) > 0 {
+		if departments, err = u.departmentDAO.GetByIds(ctx, departmentIds); err != nil {
+			return err
+		}
+	}
+	if err = u.userDAO.AssociateRoles(ctx, uid, roles); err != nil {
+		return err
+	}
+	return u.userDAO.AssociateDepartments(ctx, uid, departments)
+}
+
+// cleanDepartmentCache 部门成员变更后删除部门树缓存
+func (u *Service) cleanDepartmentCache(ctx context.Context) error {
+	return u.deptCache.RemoveDepartmentCache(ctx, constant.DepartmentTreeSfgKey, constant.DepartmentTreeWithCrewSfgKey)
+}
+
+// cleanRelationCache 用户的角色和部门变更后删除用户的权限集合缓存和部门树缓存
+func (u *Service) cleanRelationCache(ctx context.Context, uid uint) error {
+	if err := u.permissionCache.InvalidateUserPermissions(ctx, uid); err != nil {
+		return err
+	}
+	return u.cleanDepartmentCache(ctx)
+}
+
+// CreateUser 管理员创建用户,发送激活邮件时用户为待激活状态,否则直接启用
+func (u *Service) CreateUser(ctx context.Context, params *request.CreateUserRequest) error {
+	if err := u.validatePassword(ctx, nil, params.Password); err != nil {
+		return err
+	}
+	password, err := bcrypt.GenerateFromPassword([]byte(params.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	user := &models.User{
+		Email:    params.Email,
+		Password: string(password),
+		Nickname: params.Nickname,
+		Status:   constant.Normal,
+	}
+	if params.SendActiveEmail {
+		user.Status = constant.Inactive
+	}
+	emailLock := u.Locksmith.NewLock(constant.SignUpEmailPrefix, user.Email)
+	if err = emailLock.Lock(ctx, true); err != nil {
+		return err
+	}
+	defer func(lock *utils.RedisLock) {
+		if e := lock.Unlock(); e != nil {
+			u.Logger.WithContext(ctx).Errorf("unlock fail %s", e.Error())
+		}
+	}(emailLock)
+	locks, err := u.lockUserRelations(ctx, params.Roles, params.Departments)
+	defer u.unlockAll(ctx, locks)
+	if err != nil {
+		return err
+	}
+	existUser, err := u.userDAO.GetByEmail(ctx, user.Email)
+	if err != nil && !errors.Is(err, response.UserNotExist) {
+		return err
+	}
+	if existUser != nil {
+		return response.UserCreateDuplicateEmail
+	}
+	err = u.Transaction(ctx, false, func(ctx context.Context) error {
+		if err = u.userDAO.Create(ctx, user); err != nil {
+			return err
+		}
+		if err = u.recordPasswordHistory(ctx, user.ID, user.Password); err != nil {
+			return err
+		}
+		if err = u.associateRelations(ctx, user.ID, params.Roles, params.Departments); err != nil {
+			return err
+		}
+		if params.SendActiveEmail {
+			return u.sendActiveEmail(ctx, user.ID, user.Email)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return u.cleanDepartmentCache(ctx)
+}
+
+// UpdateUserStatus 启用或禁用用户,禁用后注销用户全部的登录会话并吊销短token
+func (u *Service) UpdateUserStatus(ctx context.Context, operator uint, id uint, status constant.UserStatus) error {
+	userLock := u.Locksmith.NewLock(constant.UserIdPrefix, strconv.Itoa(int(id)))
+	if err := userLock.Lock(ctx, true); err != nil {
+		return err
+	}
+	defer func(lock *utils.RedisLock) {
+		if e := lock.Unlock(); e != nil {
+			u.Logger.WithContext(ctx).Errorf("unlock fail %s", e.Error())
+		}
+	}(userLock)
+	user, err := u.userDAO.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err = u.checkManageable(operator, user); err != nil {
+		return err
+	}
+	if user.Status == status {
+		return nil
+	}
+	if err = u.userDAO.UpdateAccountStatus(ctx, id, status); err != nil {
+		return err
+	}
+	if status == constant.Disabled {
+		return u.revokeUserTokens(ctx, id)
+	}
+	return nil
+}
+
+// DeleteUser 软删除用户,删除后注销用户全部的登录会话并吊销短token
+func (u *Service) DeleteUser(ctx context.Context, operator uint, id uint) error {
+	userLock := u.Locksmith.NewLock(constant.UserIdPrefix, strconv.Itoa(int(id)))
+	if err := userLock.Lock(ctx, true); err != nil {
+		return err
+	}
+	defer func(lock *utils.RedisLock) {
+		if e := lock.Unlock(); e != nil {
+			u.Logger.WithContext(ctx).Errorf("unlock fail %s", e.Error())
+		}
+	}(userLock)
+	user, err := u.userDAO.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err = u.checkManageable(operator, user); err != nil {
+		return err
+	}
+	if err = u.userDAO.DeleteByID(ctx, id); err != nil {
+		return err
+	}
+	if err = u.revokeUserTokens(ctx, id); err != nil {
+		return err
+	}
+	return u.cleanRelationCache(ctx, id)
+}
+
+// RestoreUser 恢复已删除的用户,角色和部门关联随之恢复
+func (u *Service) RestoreUser(ctx context.Context, id uint) error {
+	userLock := u.Locksmith.NewLock(constant.UserIdPrefix, strconv.Itoa(int(id)))
+	if err := userLock.Lock(ctx, true); err != nil {
+		return err
+	}
+	defer func(lock *utils.RedisLock) {
+		if e := lock.Unlock(); e != nil {
+			u.Logger.WithContext(ctx).Errorf("unlock fail %s", e.Error())
+		}
+	}(userLock)
+	user, err := u.userDAO.GetDeletedByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if user.Status == constant.Erased {
+		return response.UserErased
+	}
+	if err = u.userDAO.Restore(ctx, id); err != nil {
+		return err
+	}
+	return u.cleanRelationCache(ctx, id)
+}
+
+// EraseUser 注销用户,清除用户的个人信息、角色和部门关联、两步验证、绑定的外部账户、API密钥、历史密码和登录记录,
+// 用户记录保留为已注销的匿名账户并软删除,其创建和更新的角色、权限、部门等数据的关联不受影响,注销后不能恢复
+func (u *Service) EraseUser(ctx context.Context, operator uint, id uint) error {
+	userLock := u.Locksmith.NewLock(constant.UserIdPrefix, strconv.Itoa(int(id)))
+	if err := userLock.Lock(ctx, true); err != nil {
+		return err
+	}
+	defer func(lock *utils.RedisLock) {
+		if e := lock.Unlock(); e != nil {
+			u.Logger.WithContext(ctx).Errorf("unlock fail %s", e.Error())
+		}
+	}(userLock)
+	// 已删除的用户同样可以注销
+	user, err := u.userDAO.GetByID(ctx, id)
+	if errors.Is(err, response.UserNotExist) {
+		user, err = u.userDAO.GetDeletedByID(ctx, id)
+	}
+	if err != nil {
+		return err
+	}
+	if user.Status == constant.Erased {
+		return response.UserErased
+	}
+	if err = u.checkManageable(operator, user); err != nil {
+		return err
+	}
+	err = u.Transaction(ctx, false, func(ctx context.Context) error {
+		if e := u.userDAO.Anonymize(ctx, id, fmt.Sprintf(constant.ErasedEmailFormat, id)); e != nil {
+			return e
+		}
+		if e := u.associateRelations(ctx, id, nil, nil); e != nil {
+			return e
+		}
+		if e := u.mfaDAO.DeleteRecoveryCodes(ctx, id); e != nil {
+			return e
+		}
+		if e := u.mfaDAO.DeleteByUserID(ctx, id); e != nil {
+			return e
+		}
+		if e := u.identityDAO.DeleteByUserID(ctx, id); e != nil {
+			return e
+		}
+		if e := u.apiKeyDAO.DeleteByUserID(ctx, id); e != nil {
+			return e
+		}
+		if e := u.passwordHistoryDAO.Prune(ctx, id, 0); e != nil {
+			return e
+		}
+		if e := u.loginHistoryDAO.DeleteByUserID(ctx, id); e != nil {
+			return e
+		}
+		return u.userDAO.DeleteByID(ctx, id)
+	})
+	if err != nil {
+		return err
+	}
+	u.Logger.WithContext(ctx).Infow("user erased", "operator", operator, "uid", id)
+	if err = u.revokeUserTokens(ctx, id); err != nil {
+		return err
+	}
+	return u.cleanRelationCache(ctx, id)
+}
+
+// AssignUser 替换用户的角色和部门,被移除角色时吊销用户已签发的短token
+func (u *Service) AssignUser(ctx context.Context, operator uint, params *request.AssignUserRequest) error {
+	userLock := u.Locksmith.NewLock(constant.UserIdPrefix, strconv.Itoa(int(params.ID)))
+	if err := userLock.Lock(ctx, true); err != nil {
+		return err
+	}
+	defer func(lock *utils.RedisLock) {
+		if e := lock.Unlock(); e != nil {
+			u.Logger.WithContext(ctx).Errorf("unlock fail %s", e.Error())
+		}
+	}(userLock)
+	user, err := u.userDAO.GetByID(ctx, params.ID, "Roles")
+	if err != nil {
+		return err
+	}
+	if err = u.checkManageable(operator, user); err != nil {
+		return err
+	}
+	locks, err := u.lockUserRelations(ctx, params.Roles, params.Departments)
+	defer u.unlockAll(ctx, locks)
+	if err != nil {
+		return err
+	}
+	err = u.Transaction(ctx, false, func(ctx context.Context) error {
+		return u.associateRelations(ctx, params.ID, params.Roles, params.Departments)
+	})
+	if err != nil {
+		return err
+	}
+	removed, _ := lo.Difference(lo.Map(user.Roles, func(item *models.Role, _ int) uint {
+		return item.ID
+	}), params.Roles)
+	if len(removed) > 0 {
+		if err = u.userCache.DenyUserAccessTokens(ctx, params.ID); err != nil {
+			return err
+		}
+	}
+	return u.cleanRelationCache(ctx, params.ID)
+}
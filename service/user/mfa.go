@@ -0,0 +1,274 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/supuwoerc/weaver/models"
+	"github.com/supuwoerc/weaver/pkg/constant"
+	"github.com/supuwoerc/weaver/pkg/response"
+	"github.com/supuwoerc/weaver/pkg/totp"
+	"github.com/supuwoerc/weaver/pkg/utils"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/samber/lo"
+)
+
+// recoveryCodeCharset 恢复码字符集,去除了容易混淆的字符
+var recoveryCodeCharset = []rune("abcdefghjkmnpqrstuvwxyz23456789")
+
+// EnrollMFA 生成新的TOTP密钥,完成 VerifyMFA 后两步验证才会生效
+func (u *Service) EnrollMFA(ctx context.Context, uid uint, email string) (*response.MFAEnrollResponse, error) {
+	userLock := u.Locksmith.NewLock(constant.UserIdPrefix, strconv.Itoa(int(uid)))
+	if err := userLock.Lock(ctx, true); err != nil {
+		return nil, err
+	}
+	defer func(lock *utils.RedisLock) {
+		if e := lock.Unlock(); e != nil {
+			u.Logger.WithContext(ctx).Errorf("unlock fail %s", e.Error())
+		}
+	}(userLock)
+	mfa, err := u.mfaDAO.GetByUserID(ctx, uid)
+	if err != nil && !errors.Is(err, response.MFANotEnabled) {
+		return nil, err
+	}
+	if mfa != nil && mfa.Enabled {
+		return nil, response.MFAAlreadyEnabled
+	}
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err = u.mfaDAO.Upsert(ctx, &models.UserMFA{UserID: uid, Secret: secret}); err != nil {
+		return nil, err
+	}
+	uri := totp.URI(u.Conf.AppName, email, secret)
+	qrCode, err := totp.QRCode(uri, constant.MFAQRCodeSize)
+	if err != nil {
+		return nil, err
+	}
+	return &response.MFAEnrollResponse{
+		Secret: secret,
+		URI:    uri,
+		QRCode: qrCode,
+	}, nil
+}
+
+// VerifyMFA 校验认证器App生成的验证码,校验通过后开启两步验证并返回恢复码
+func (u *Service) VerifyMFA(ctx context.Context, uid uint, code string) (*response.MFARecoveryCodesResponse, error) {
+	userLock := u.Locksmith.NewLock(constant.UserIdPrefix, strconv.Itoa(int(uid)))
+	if err := userLock.Lock(ctx, true); err != nil {
+		return nil, err
+	}
+	defer func(lock *utils.RedisLock) {
+		if e := lock.Unlock(); e != nil {
+			u.Logger.WithContext(ctx).Errorf("unlock fail %s", e.Error())
+		}
+	}(userLock)
+	mfa, err := u.mfaDAO.GetByUserID(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	if mfa.Enabled {
+		return nil, response.MFAAlreadyEnabled
+	}
+	valid, err := u.verifyTOTP(ctx, mfa, code)
+	if err != nil {
+		return nil, err
+	}
+	if !valid {
+		return nil, response.InvalidMFACode
+	}
+	var codes []string
+	err = u.Transaction(ctx, false, func(ctx context.Context) error {
+		if err = u.mfaDAO.Enable(ctx, uid); err != nil {
+			return err
+		}
+		codes, err = u.replaceRecoveryCodes(ctx, uid)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &response.MFARecoveryCodesResponse{Codes: codes}, nil
+}
+
+// RegenerateRecoveryCodes 校验TOTP验证码后重新生成恢复码,旧的恢复码全部失效
+func (u *Service) RegenerateRecoveryCodes(ctx context.Context, uid uint, code string) (*response.MFARecoveryCodesResponse, error) {
+	userLock := u.Locksmith.NewLock(constant.UserIdPrefix, strconv.Itoa(int(uid)))
+	if err := userLock.Lock(ctx, true); err != nil {
+		return nil, err
+	}
+	defer func(lock *utils.RedisLock) {
+		if e := lock.Unlock(); e != nil {
+			u.Logger.WithContext(ctx).Errorf("unlock fail %s", e.Error())
+		}
+	}(userLock)
+	mfa, err := u.getEnabledMFA(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	valid, err := u.verifyTOTP(ctx, mfa, code)
+	if err != nil {
+		return nil, err
+	}
+	if !valid {
+		return nil, response.InvalidMFACode
+	}
+	var codes []string
+	err = u.Transaction(ctx, false, func(ctx context.Context) error {
+		codes, err = u.replaceRecoveryCodes(ctx, uid)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &response.MFARecoveryCodesResponse{Codes: codes}, nil
+}
+
+// DisableMFA 校验TOTP验证码或恢复码后关闭两步验证
+func (u *Service) DisableMFA(ctx context.Context, uid uint, code string) error {
+	userLock := u.Locksmith.NewLock(constant.UserIdPrefix, strconv.Itoa(int(uid)))
+	if err := userLock.Lock(ctx, true); err != nil {
+		return err
+	}
+	defer func(lock *utils.RedisLock) {
+		if e := lock.Unlock(); e != nil {
+			u.Logger.WithContext(ctx).Errorf("unlock fail %s", e.Error())
+		}
+	}(userLock)
+	mfa, err := u.getEnabledMFA(ctx, uid)
+	if err != nil {
+		return err
+	}
+	valid, err := u.verifyMFACode(ctx, mfa, code)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return response.InvalidMFACode
+	}
+	return u.Transaction(ctx, false, func(ctx context.Context) error {
+		if err = u.mfaDAO.DeleteRecoveryCodes(ctx, uid); err != nil {
+			return err
+		}
+		return u.mfaDAO.DeleteByUserID(ctx, uid)
+	})
+}
+
+// LoginMFA 使用登录凭证和TOTP验证码(或恢复码)完成登录,凭证在校验验证码前消费,只能使用一次,验证码错误时需要重新登录;
+// 错误的验证码计入用户的登录失败次数,避免通过重新登录获取新凭证无限次尝试
+func (u *Service) LoginMFA(ctx context.Context, ticket string, code string) (res *response.LoginResponse, err error) {
+	uid, client, err := u.userCache.GetMFATicket(ctx, ticket)
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, response.InvalidMFATicket
+		}
+		return nil, err
+	}
+	attempt := newLoginAttempt(constant.LoginMFA, "", client)
+	attempt.UserID = &uid
+	defer func() {
+		u.finishLoginAttempt(ctx, attempt, res != nil, err)
+	}()
+	user, err := u.userDAO.GetByID(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	setAttemptUser(attempt, user)
+	if user.Status != constant.Normal {
+		return nil, response.UserDisabled
+	}
+	remaining, err := u.userCache.GetLoginBlock(ctx, constant.LoginLockEmailPrefix, user.Email)
+	if err != nil {
+		return nil, err
+	}
+	if remaining > 0 {
+		return nil, response.UserLoginLocked
+	}
+	mfa, err := u.getEnabledMFA(ctx, uid)
+	if err != nil {
+		if errors.Is(err, response.MFANotEnabled) {
+			return nil, response.InvalidMFATicket
+		}
+		return nil, err
+	}
+	// 先消费凭证再校验验证码,并发或重放的请求不会消耗恢复码
+	consumed, err := u.userCache.ConsumeMFATicket(ctx, ticket)
+	if err != nil {
+		return nil, err
+	}
+	if !consumed {
+		return nil, response.InvalidMFATicket
+	}
+	valid, err := u.verifyMFACode(ctx, mfa, code)
+	if err != nil {
+		return nil, err
+	}
+	if !valid {
+		if err = u.recordLoginFailure(ctx, user.Email, client.IP); err != nil {
+			return nil, err
+		}
+		return nil, response.InvalidMFACode
+	}
+	if err = u.userCache.ClearLoginFailures(ctx, user.Email, ""); err != nil {
+		return nil, err
+	}
+	return u.issueTokens(ctx, user, client)
+}
+
+func (u *Service) getEnabledMFA(ctx context.Context, uid uint) (*models.UserMFA, error) {
+	mfa, err := u.mfaDAO.GetByUserID(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	if !mfa.Enabled {
+		return nil, response.MFANotEnabled
+	}
+	return mfa, nil
+}
+
+// verifyTOTP 校验TOTP验证码,同一周期的验证码只能使用一次
+func (u *Service) verifyTOTP(ctx context.Context, mfa *models.UserMFA, code string) (bool, error) {
+	step, ok := totp.Validate(mfa.Secret, code, time.Now(), constant.MFASkew)
+	if !ok {
+		return false, nil
+	}
+	expiration := time.Duration((2*constant.MFASkew+1)*totp.Period) * time.Second
+	return u.userCache.MarkTOTPStepUsed(ctx, mfa.UserID, step, expiration)
+}
+
+// verifyMFACode 校验TOTP验证码,不是TOTP验证码时按恢复码校验
+func (u *Service) verifyMFACode(ctx context.Context, mfa *models.UserMFA, code string) (bool, error) {
+	if len(code) == totp.Digits {
+		return u.verifyTOTP(ctx, mfa, code)
+	}
+	return u.mfaDAO.UseRecoveryCode(ctx, mfa.UserID, hashRecoveryCode(code))
+}
+
+// replaceRecoveryCodes 生成新的恢复码替换旧的恢复码,返回恢复码明文
+func (u *Service) replaceRecoveryCodes(ctx context.Context, uid uint) ([]string, error) {
+	codes := make([]string, 0, constant.RecoveryCodeCount)
+	records := make([]*models.UserRecoveryCode, 0, constant.RecoveryCodeCount)
+	half := constant.RecoveryCodeLength / 2
+	for i := 0; i < constant.RecoveryCodeCount; i++ {
+		raw := lo.RandomString(constant.RecoveryCodeLength, recoveryCodeCharset)
+		code := fmt.Sprintf("%s-%s", raw[:half], raw[half:])
+		codes = append(codes, code)
+		records = append(records, &models.UserRecoveryCode{UserID: uid, Code: hashRecoveryCode(code)})
+	}
+	if err := u.mfaDAO.ReplaceRecoveryCodes(ctx, uid, records); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// hashRecoveryCode 计算恢复码摘要,忽略大小写和分隔符
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	return utils.Sha256(normalized)
+}
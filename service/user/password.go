@@ -0,0 +1,140 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/supuwoerc/weaver/models"
+	"github.com/supuwoerc/weaver/pkg/constant"
+	"github.com/supuwoerc/weaver/pkg/password"
+	"github.com/supuwoerc/weaver/pkg/response"
+	"github.com/supuwoerc/weaver/pkg/utils"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/samber/lo"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// matchPassword 校验密码与哈希是否匹配,兼容客户端提交MD5摘要时生成的旧哈希
+func matchPassword(hash, plain string) bool {
+	if bcrypt.CompareHashAndPassword([]byte(hash), []byte(plain)) == nil {
+		return true
+	}
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(utils.Md5(plain))) == nil
+}
+
+// validatePassword 按密码策略校验新密码,user 不为空时同时禁止重复使用当前密码及最近的历史密码
+func (u *Service) validatePassword(ctx context.Context, user *models.User, plain string) error {
+	policy := &u.Conf.Password
+	if err := password.Validate(policy, plain); err != nil {
+		return err
+	}
+	if user == nil || policy.HistorySize <= 0 {
+		return nil
+	}
+	histories, err := u.passwordHistoryDAO.GetRecent(ctx, user.ID, policy.HistorySize)
+	if err != nil {
+		return err
+	}
+	hashes := append([]string{user.Password}, lo.Map(histories, func(item *models.UserPasswordHistory, _ int) string {
+		return item.Password
+	})...)
+	for _, hash := range hashes {
+		if matchPassword(hash, plain) {
+			return response.WithTemplateData(response.PasswordReused, map[string]any{"Count": policy.HistorySize})
+		}
+	}
+	return nil
+}
+
+// updatePassword 修改密码并记录历史密码
+func (u *Service) updatePassword(ctx context.Context, uid uint, plain string) error {
+	hash, err := bcrypt.GenerateFromPassword([]byte(plain), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	return u.Transaction(ctx, false, func(ctx context.Context) error {
+		if err = u.userDAO.UpdatePassword(ctx, uid, string(hash)); err != nil {
+			return err
+		}
+		return u.recordPasswordHistory(ctx, uid, string(hash))
+	})
+}
+
+// recordPasswordHistory 记录用户使用的密码,只保留策略要求的数量
+func (u *Service) recordPasswordHistory(ctx context.Context, uid uint, hash string) error {
+	size := u.Conf.Password.HistorySize
+	if size <= 0 {
+		return nil
+	}
+	if err := u.passwordHistoryDAO.Create(ctx, &models.UserPasswordHistory{UserID: uid, Password: hash}); err != nil {
+		return err
+	}
+	return u.passwordHistoryDAO.Prune(ctx, uid, size)
+}
+
+// passwordExpired 判断密码是否超过最长使用天数,从未修改过密码时以创建时间计算
+func (u *Service) passwordExpired(user *models.User, now time.Time) bool {
+	maxAge := u.Conf.Password.MaxAge
+	if maxAge <= 0 {
+		return false
+	}
+	changedAt := time.Time(user.CreatedAt)
+	if user.PasswordChangedAt != nil {
+		changedAt = *user.PasswordChangedAt
+	}
+	return now.After(changedAt.AddDate(0, 0, maxAge))
+}
+
+// ChangeExpiredPassword 使用密码过期时签发的登录凭证修改密码,修改成功后注销用户的全部登录会话并继续完成登录
+func (u *Service) ChangeExpiredPassword(
+	ctx context.Context, ticket string, plain string,
+) (*response.LoginResponse, error) {
+	uid, client, err := u.userCache.GetPasswordTicket(ctx, ticket)
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, response.InvalidPasswordTicket
+		}
+		return nil, err
+	}
+	userLock := u.Locksmith.NewLock(constant.UserIdPrefix, strconv.Itoa(int(uid)))
+	if err = userLock.Lock(ctx, true); err != nil {
+		return nil, err
+	}
+	defer func(lock *utils.RedisLock) {
+		if e := lock.Unlock(); e != nil {
+			u.Logger.WithContext(ctx).Errorf("unlock fail %s", e.Error())
+		}
+	}(userLock)
+	user, err := u.userDAO.GetByID(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	if user.Status != constant.Normal {
+		return nil, response.UserDisabled
+	}
+	if err = u.validatePassword(ctx, user, plain); err != nil {
+		return nil, err
+	}
+	consumed, err := u.userCache.ConsumePasswordTicket(ctx, ticket)
+	if err != nil {
+		return nil, err
+	}
+	if !consumed {
+		return nil, response.InvalidPasswordTicket
+	}
+	if err = u.updatePassword(ctx, uid, plain); err != nil {
+		return nil, err
+	}
+	if err = u.revokeUserTokens(ctx, uid); err != nil {
+		return nil, err
+	}
+	// 修改密码前的失败属于密码校验,不计入登录记录
+	attempt := newLoginAttempt(constant.LoginPassword, user.Email, client)
+	setAttemptUser(attempt, user)
+	res, err := u.completeLogin(ctx, user, client)
+	u.finishLoginAttempt(ctx, attempt, res != nil && res.Token != "", err)
+	return res, err
+}
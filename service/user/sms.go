@@ -0,0 +1,156 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/supuwoerc/weaver/models"
+	"github.com/supuwoerc/weaver/pkg/constant"
+	"github.com/supuwoerc/weaver/pkg/response"
+	"github.com/supuwoerc/weaver/pkg/utils"
+
+	"github.com/samber/lo"
+)
+
+// acquireSMSQuota 检查手机号的重新发送间隔和每日发送次数
+func (u *Service) acquireSMSQuota(ctx context.Context, phone string) error {
+	acquired, err := u.userCache.AcquireSMSCooldown(ctx, phone, u.Conf.SMS.ResendInterval*time.Second)
+	if err != nil {
+		return err
+	}
+	if !acquired {
+		return response.SMSTooFrequent
+	}
+	count, err := u.userCache.IncrRequestCount(ctx, constant.SMSLimitPrefix, phone, constant.SMSDailyWindow*time.Hour)
+	if err != nil {
+		return err
+	}
+	if count > u.Conf.SMS.DailyLimit {
+		return response.SMSTooFrequent
+	}
+	return nil
+}
+
+// deliverSMSCode 生成并缓存验证码后发送短信,重新发送时之前的验证码失效
+func (u *Service) deliverSMSCode(ctx context.Context, scene constant.SMSScene, phone string) error {
+	code := lo.RandomString(lo.CoalesceOrEmpty(u.Conf.SMS.CodeLength, constant.SMSCodeLength), lo.NumbersCharset)
+	expiration := u.Conf.SMS.CodeExpiration * time.Second
+	if err := u.userCache.CacheSMSCode(ctx, scene, phone, code, expiration); err != nil {
+		return err
+	}
+	return u.smsClient.Send(ctx, phone, scene, map[string]string{
+		"code":       code,
+		"expiration": strconv.Itoa(int(expiration.Minutes())),
+	})
+}
+
+// verifySMSCode 校验短信验证码,验证码只能使用一次
+func (u *Service) verifySMSCode(ctx context.Context, scene constant.SMSScene, phone string, code string) error {
+	valid, err := u.userCache.VerifySMSCode(ctx, scene, phone, code, u.Conf.SMS.MaxAttempts)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return response.SMSCodeInvalid
+	}
+	return nil
+}
+
+// checkPhoneAvailable 检查手机号是否未被其他用户绑定
+func (u *Service) checkPhoneAvailable(ctx context.Context, uid uint, phone string) error {
+	exist, err := u.userDAO.GetByPhone(ctx, phone)
+	if err != nil {
+		if errors.Is(err, response.UserNotExist) {
+			return nil
+		}
+		return err
+	}
+	if exist.ID != uid {
+		return response.PhoneAlreadyBound
+	}
+	return nil
+}
+
+// SendBindPhoneCode 向待绑定的手机号发送验证码
+func (u *Service) SendBindPhoneCode(ctx context.Context, uid uint, phone string) error {
+	if err := u.checkPhoneAvailable(ctx, uid, phone); err != nil {
+		return err
+	}
+	if err := u.acquireSMSQuota(ctx, phone); err != nil {
+		return err
+	}
+	return u.deliverSMSCode(ctx, constant.SMSBindPhone, phone)
+}
+
+// BindPhone 校验短信验证码后绑定手机号,已绑定的手机号会被替换
+func (u *Service) BindPhone(ctx context.Context, uid uint, phone string, code string) error {
+	phoneLock := u.Locksmith.NewLock(constant.PhonePrefix, phone)
+	if err := phoneLock.Lock(ctx, true); err != nil {
+		return err
+	}
+	defer func(lock *utils.RedisLock) {
+		if e := lock.Unlock(); e != nil {
+			u.Logger.WithContext(ctx).Errorf("unlock fail %s", e.Error())
+		}
+	}(phoneLock)
+	if err := u.checkPhoneAvailable(ctx, uid, phone); err != nil {
+		return err
+	}
+	if err := u.verifySMSCode(ctx, constant.SMSBindPhone, phone, code); err != nil {
+		return err
+	}
+	return u.userDAO.UpdatePhone(ctx, uid, phone)
+}
+
+// SendLoginSMSCode 校验图形验证码后发送登录验证码,手机号未绑定或账户不可用时不发送短信且不返回错误,
+// 频率限制先于账户查询,避免通过返回结果判断手机号是否绑定
+func (u *Service) SendLoginSMSCode(ctx context.Context, id string, code string, phone string) error {
+	if !u.Service.Verify(constant.Login, id, code) {
+		return response.CaptchaVerifyFail
+	}
+	if err := u.acquireSMSQuota(ctx, phone); err != nil {
+		return err
+	}
+	user, err := u.userDAO.GetByPhone(ctx, phone)
+	if err != nil {
+		if errors.Is(err, response.UserNotExist) {
+			return nil
+		}
+		return err
+	}
+	if user.Status != constant.Normal {
+		return nil
+	}
+	return u.deliverSMSCode(ctx, constant.SMSLogin, phone)
+}
+
+// LoginSMS 使用手机号和短信验证码登录,开启两步验证的用户同样需要完成两步验证
+func (u *Service) LoginSMS(
+	ctx context.Context, phone string, code string, client *models.LoginClient,
+) (res *response.LoginResponse, err error) {
+	attempt := newLoginAttempt(constant.LoginSMS, "", client)
+	defer func() {
+		u.finishLoginAttempt(ctx, attempt, res != nil && res.Token != "", err)
+	}()
+	user, err := u.userDAO.GetByPhone(ctx, phone, "Roles")
+	if err != nil {
+		// 未绑定的手机号不会收到验证码
+		if errors.Is(err, response.UserNotExist) {
+			return nil, response.SMSCodeInvalid
+		}
+		return nil, err
+	}
+	setAttemptUser(attempt, user)
+	if err = u.verifySMSCode(ctx, constant.SMSLogin, phone, code); err != nil {
+		return nil, err
+	}
+	switch user.Status {
+	case constant.Inactive:
+		return nil, response.UserInactive
+	case constant.Disabled:
+		return nil, response.UserDisabled
+	}
+	return u.completeLogin(ctx, user, client)
+}
@@ -0,0 +1,173 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/supuwoerc/weaver/conf"
+	"github.com/supuwoerc/weaver/models"
+	"github.com/supuwoerc/weaver/pkg/constant"
+	"github.com/supuwoerc/weaver/pkg/idp"
+	"github.com/supuwoerc/weaver/pkg/response"
+	"github.com/supuwoerc/weaver/pkg/utils"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/samber/lo"
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/oauth2"
+)
+
+// GetSSOProviders 获取已配置的身份提供方
+func (u *Service) GetSSOProviders(_ context.Context) []string {
+	return u.idpManager.Names()
+}
+
+// SSOAuthorize 生成身份提供方的授权地址,state/nonce/code_verifier 缓存至回调时校验,返回的state需要绑定到发起授权的浏览器
+func (u *Service) SSOAuthorize(ctx context.Context, name string) (*response.SSOAuthorizeResponse, string, error) {
+	provider, err := u.idpManager.Get(ctx, name)
+	if err != nil {
+		return nil, "", err
+	}
+	req := &idp.AuthRequest{
+		State:    lo.RandomString(constant.SSOStateLength, lo.AlphanumericCharset),
+		Nonce:    lo.RandomString(constant.SSOStateLength, lo.AlphanumericCharset),
+		Verifier: oauth2.GenerateVerifier(),
+	}
+	state := &models.SSOState{
+		Provider: name,
+		Nonce:    req.Nonce,
+		Verifier: req.Verifier,
+	}
+	if err = u.userCache.CacheSSOState(ctx, req.State, state, u.Conf.SSO.StateExpiration*time.Second); err != nil {
+		return nil, "", err
+	}
+	return &response.SSOAuthorizeResponse{URL: provider.AuthCodeURL(req)}, req.State, nil
+}
+
+// LoginSSO 使用身份提供方回调的授权码登录,外部账户未关联时按已验证的邮箱关联用户,按配置自动创建用户
+func (u *Service) LoginSSO(
+	ctx context.Context, name string, code string, state string, client *models.LoginClient,
+) (res *response.LoginResponse, err error) {
+	attempt := newLoginAttempt(constant.LoginSSO, "", client)
+	defer func() {
+		u.finishLoginAttempt(ctx, attempt, res != nil && res.Token != "", err)
+	}()
+	cached, err := u.userCache.ConsumeSSOState(ctx, state)
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, response.InvalidSSOState
+		}
+		return nil, err
+	}
+	if cached.Provider != name {
+		return nil, response.InvalidSSOState
+	}
+	provider, err := u.idpManager.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	identity, err := provider.Exchange(ctx, code, &idp.AuthRequest{
+		State:    state,
+		Nonce:    cached.Nonce,
+		Verifier: cached.Verifier,
+	})
+	if err != nil {
+		u.Logger.WithContext(ctx).Errorw("sso exchange fail", "provider", name, "err", err.Error())
+		return nil, response.SSOLoginFail
+	}
+	user, err := u.resolveSSOUser(ctx, provider.Config(), identity)
+	if err != nil {
+		return nil, err
+	}
+	setAttemptUser(attempt, user)
+	switch user.Status {
+	case constant.Inactive:
+		return nil, response.UserInactive
+	case constant.Disabled:
+		return nil, response.UserDisabled
+	}
+	return u.completeLogin(ctx, user, client)
+}
+
+// resolveSSOUser 查找外部账户关联的用户,不存在关联时按邮箱关联或自动创建用户
+func (u *Service) resolveSSOUser(
+	ctx context.Context, config *conf.IdentityProviderConfig, identity *idp.Identity,
+) (*models.User, error) {
+	linked, err := u.identityDAO.GetByProviderSubject(ctx, config.Name, identity.Subject)
+	if err != nil && !errors.Is(err, response.UserIdentityNotExist) {
+		return nil, err
+	}
+	if linked != nil {
+		return u.userDAO.GetByID(ctx, linked.UserID, "Roles")
+	}
+	// 未验证的邮箱可能被冒用,不能据此关联或创建用户
+	if identity.Email == "" || !identity.EmailVerified {
+		return nil, response.SSOLoginFail
+	}
+	emailLock := u.Locksmith.NewLock(constant.SignUpEmailPrefix, identity.Email)
+	if err = emailLock.Lock(ctx, true); err != nil {
+		return nil, err
+	}
+	defer func(lock *utils.RedisLock) {
+		if e := lock.Unlock(); e != nil {
+			u.Logger.WithContext(ctx).Errorf("unlock fail %s", e.Error())
+		}
+	}(emailLock)
+	user, err := u.userDAO.GetByEmail(ctx, identity.Email, "Roles")
+	if err != nil && !errors.Is(err, response.UserNotExist) {
+		return nil, err
+	}
+	if user == nil && !config.AutoProvision {
+		return nil, response.UserNotExist
+	}
+	// 仅邀请注册或关闭注册时,不能通过单点登录自动创建用户
+	if user == nil && u.signupMode() != constant.SignupOpen {
+		return nil, response.SignupClosed
+	}
+	err = u.Transaction(ctx, false, func(ctx context.Context) error {
+		if user == nil {
+			if user, err = u.provisionSSOUser(ctx, config, identity); err != nil {
+				return err
+			}
+		}
+		return u.identityDAO.Create(ctx, &models.UserIdentity{
+			UserID:   user.ID,
+			Provider: config.Name,
+			Subject:  identity.Subject,
+			Email:    identity.Email,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// provisionSSOUser 为外部账户创建用户,密码随机生成,用户可通过找回密码设置
+func (u *Service) provisionSSOUser(
+	ctx context.Context, config *conf.IdentityProviderConfig, identity *idp.Identity,
+) (*models.User, error) {
+	password, err := bcrypt.GenerateFromPassword(
+		[]byte(lo.RandomString(constant.SSOStateLength, lo.AlphanumericCharset)), bcrypt.DefaultCost,
+	)
+	if err != nil {
+		return nil, err
+	}
+	user := &models.User{
+		Email:    identity.Email,
+		Password: string(password),
+		Status:   constant.Inactive,
+	}
+	if config.ProvisionActive {
+		user.Status = constant.Normal
+	}
+	if identity.Name != "" {
+		nickname := lo.Substring(identity.Name, 0, constant.NicknameMaxLength)
+		user.Nickname = &nickname
+	}
+	if err = u.userDAO.Create(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
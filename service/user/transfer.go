@@ -0,0 +1,361 @@
+package user
+
+import (
+	"context"
+	"io"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/supuwoerc/weaver/models"
+	"github.com/supuwoerc/weaver/pkg/constant"
+	"github.com/supuwoerc/weaver/pkg/response"
+	"github.com/supuwoerc/weaver/pkg/spreadsheet"
+	"github.com/supuwoerc/weaver/pkg/utils"
+
+	regexp "github.com/dlclark/regexp2"
+	"github.com/samber/lo"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// 导入导出的表头,导出的文件可以直接用于导入
+const (
+	transferColumnEmail       = "email"
+	transferColumnNickname    = "nickname"
+	transferColumnDepartments = "departments"
+	transferColumnRoles       = "roles"
+	transferColumnStatus      = "status"
+	transferColumnCreatedAt   = "created_at"
+)
+
+const (
+	transferValueSeparator  = "," // 单元格内多个部门或角色的分隔符
+	departmentPathSeparator = "/" // 部门路径中各级部门名称的分隔符
+	importEmailMaxLength    = 50  // 导入邮箱的最大长度
+)
+
+var importEmailRegexp = regexp.MustCompile(constant.EmailRegexPattern, regexp.None)
+
+// importUserRow 校验通过的导入行
+type importUserRow struct {
+	email       string
+	nickname    *string
+	roles       []uint
+	departments []uint
+}
+
+// ImportUsers 从表格批量导入用户,首行为表头,校验全部行后按批次在事务中创建用户,
+// 存在校验错误或仅校验时不会创建任何用户
+func (u *Service) ImportUsers(
+	ctx context.Context, reader io.Reader, format spreadsheet.Format, dryRun, sendActiveEmail bool,
+) (*response.ImportUsersResponse, error) {
+	records, err := spreadsheet.Read(reader, format)
+	if err != nil || len(records) == 0 {
+		return nil, response.InvalidImportFile
+	}
+	columns := make(map[string]int, len(records[0]))
+	for index, name := range records[0] {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, ok := columns[name]; !ok {
+			columns[name] = index
+		}
+	}
+	if _, ok := columns[transferColumnEmail]; !ok {
+		return nil, response.InvalidImportFile
+	}
+	cell := func(record []string, column string) string {
+		index, ok := columns[column]
+		if !ok || index >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[index])
+	}
+	// 跳过空行,行号与表格中的行号保持一致
+	lines := make(map[int][]string, len(records)-1)
+	numbers := make([]int, 0, len(records)-1)
+	for index, record := range records[1:] {
+		if lo.EveryBy(record, func(item string) bool { return strings.TrimSpace(item) == "" }) {
+			continue
+		}
+		lines[index+2] = record
+		numbers = append(numbers, index+2)
+	}
+	if len(numbers) > u.Conf.Account.ImportMaxRows {
+		return nil, response.ImportRowsExceeded
+	}
+	result := &response.ImportUsersResponse{
+		Total:  len(numbers),
+		DryRun: dryRun,
+		Errors: make([]*response.ImportUserRowError, 0),
+	}
+	if len(numbers) == 0 {
+		return result, nil
+	}
+	// 预先查询已存在的邮箱、角色和部门路径
+	emails := lo.Map(numbers, func(item int, _ int) string {
+		return cell(lines[item], transferColumnEmail)
+	})
+	// 与注册、创建用户使用相同的邮箱锁,避免校验通过后邮箱被并发注册导致整批创建失败
+	if !dryRun {
+		emailLocks, e := u.lockEmails(ctx, lo.Compact(emails))
+		defer u.unlockAll(ctx, emailLocks)
+		if e != nil {
+			return nil, e
+		}
+	}
+	existUsers, err := u.userDAO.GetByEmails(ctx, lo.Compact(emails))
+	if err != nil {
+		return nil, err
+	}
+	existEmails := lo.SliceToMap(existUsers, func(item *models.User) (string, struct{}) {
+		return strings.ToLower(item.Email), struct{}{}
+	})
+	roleNames := lo.Uniq(lo.FlatMap(numbers, func(item int, _ int) []string {
+		return splitTransferValue(cell(lines[item], transferColumnRoles))
+	}))
+	roleIds := make(map[string]uint, len(roleNames))
+	if len(roleNames) > 0 {
+		roles, e := u.roleDAO.GetByNames(ctx, roleNames)
+		if e != nil {
+			return nil, e
+		}
+		for _, role := range roles {
+			roleIds[role.Name] = role.ID
+		}
+	}
+	paths, err := u.departmentPaths(ctx)
+	if err != nil {
+		return nil, err
+	}
+	departmentIds := make(map[string]uint, len(paths))
+	for id, path := range paths {
+		// 同名路径的部门无法区分,只保留ID最小的部门
+		if exist, ok := departmentIds[path]; !ok || id < exist {
+			departmentIds[path] = id
+		}
+	}
+	// 校验全部行,记录每一行的错误
+	addError := func(line int, column, value string, code response.StatusCode) {
+		result.Errors = append(result.Errors, &response.ImportUserRowError{
+			Row:    line,
+			Column: column,
+			Value:  value,
+			Code:   code,
+		})
+	}
+	seen := make(map[string]int, len(numbers))
+	rows := make([]*importUserRow, 0, len(numbers))
+	for _, line := range numbers {
+		record := lines[line]
+		valid := true
+		row := &importUserRow{email: cell(record, transferColumnEmail)}
+		key := strings.ToLower(row.email)
+		matched, _ := importEmailRegexp.MatchString(row.email)
+		_, exist := existEmails[key]
+		_, duplicate := seen[key]
+		switch {
+		case !matched || len(row.email) > importEmailMaxLength:
+			addError(line, transferColumnEmail, row.email, response.EmailValidErr)
+			valid = false
+		case exist || duplicate:
+			addError(line, transferColumnEmail, row.email, response.UserCreateDuplicateEmail)
+			valid = false
+		default:
+			seen[key] = line
+		}
+		if nickname := cell(record, transferColumnNickname); nickname != "" {
+			if utf8.RuneCountInString(nickname) > constant.NicknameMaxLength {
+				addError(line, transferColumnNickname, nickname, response.InvalidParams)
+				valid = false
+			}
+			row.nickname = &nickname
+		}
+		for _, path := range splitTransferValue(cell(record, transferColumnDepartments)) {
+			id, ok := departmentIds[normalizeDepartmentPath(path)]
+			if !ok {
+				addError(line, transferColumnDepartments, path, response.DeptNotExist)
+				valid = false
+				continue
+			}
+			row.departments = append(row.departments, id)
+		}
+		for _, name := range splitTransferValue(cell(record, transferColumnRoles)) {
+			id, ok := roleIds[name]
+			if !ok {
+				addError(line, transferColumnRoles, name, response.RoleNotExist)
+				valid = false
+				continue
+			}
+			row.roles = append(row.roles, id)
+		}
+		if valid {
+			rows = append(rows, row)
+		}
+	}
+	if len(result.Errors) > 0 || dryRun {
+		return result, nil
+	}
+	locks, err := u.lockUserRelations(ctx,
+		lo.FlatMap(rows, func(item *importUserRow, _ int) []uint { return item.roles }),
+		lo.FlatMap(rows, func(item *importUserRow, _ int) []uint { return item.departments }),
+	)
+	defer u.unlockAll(ctx, locks)
+	if err != nil {
+		return nil, err
+	}
+	for _, batch := range lo.Chunk(rows, max(u.Conf.Account.ImportBatchSize, 1)) {
+		users := make([]*models.User, 0, len(batch))
+		err = u.Transaction(ctx, false, func(ctx context.Context) error {
+			for _, row := range batch {
+				user, e := u.createImportUser(ctx, row, sendActiveEmail)
+				if e != nil {
+					return e
+				}
+				users = append(users, user)
+			}
+			return nil
+		})
+		if err != nil {
+			// 之前的批次已经提交,返回已创建的数量
+			if result.Created > 0 {
+				if e := u.cleanDepartmentCache(ctx); e != nil {
+					u.Logger.WithContext(ctx).Errorw("clean department cache fail", "err", e.Error())
+				}
+			}
+			return result, err
+		}
+		result.Created += len(users)
+		if sendActiveEmail {
+			for _, user := range users {
+				if e := u.sendActiveEmail(ctx, user.ID, user.Email); e != nil {
+					u.Logger.WithContext(ctx).Errorw("send import active email fail", "email", user.Email, "err", e.Error())
+				}
+			}
+		}
+	}
+	return result, u.cleanDepartmentCache(ctx)
+}
+
+// lockEmails 按顺序获取邮箱的注册锁,避免多个导入同时进行时互相等待
+func (u *Service) lockEmails(ctx context.Context, emails []string) ([]*utils.RedisLock, error) {
+	emails = lo.Uniq(emails)
+	slices.Sort(emails)
+	locks := make([]*utils.RedisLock, 0, len(emails))
+	for _, email := range emails {
+		emailLock := u.Locksmith.NewLock(constant.SignUpEmailPrefix, email)
+		if err := emailLock.Lock(ctx, true); err != nil {
+			return locks, err
+		}
+		locks = append(locks, emailLock)
+	}
+	return locks, nil
+}
+
+// createImportUser 创建导入的用户,密码随机生成,用户可通过找回密码设置
+func (u *Service) createImportUser(ctx context.Context, row *importUserRow, sendActiveEmail bool) (*models.User, error) {
+	// 随机密码不会被猜测,使用最小成本避免大批量导入时哈希耗时过长
+	password, err := bcrypt.GenerateFromPassword(
+		[]byte(lo.RandomString(constant.SSOStateLength, lo.AlphanumericCharset)), bcrypt.MinCost,
+	)
+	if err != nil {
+		return nil, err
+	}
+	user := &models.User{
+		Email:    row.email,
+		Password: string(password),
+		Nickname: row.nickname,
+		Status:   constant.Normal,
+	}
+	if sendActiveEmail {
+		user.Status = constant.Inactive
+	}
+	if err = u.userDAO.Create(ctx, user); err != nil {
+		return nil, err
+	}
+	if err = u.associateRelations(ctx, user.ID, row.roles, row.departments); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// ExportUsers 按条件导出用户,首行为表头,部门和角色列的格式与导入一致
+func (u *Service) ExportUsers(ctx context.Context, filter *models.UserFilter) ([][]string, error) {
+	users, err := u.userDAO.GetAllByFilter(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	paths, err := u.departmentPaths(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows := make([][]string, 0, len(users)+1)
+	rows = append(rows, []string{
+		transferColumnEmail,
+		transferColumnNickname,
+		transferColumnDepartments,
+		transferColumnRoles,
+		transferColumnStatus,
+		transferColumnCreatedAt,
+	})
+	for _, user := range users {
+		rows = append(rows, []string{
+			user.Email,
+			lo.FromPtr(user.Nickname),
+			strings.Join(lo.Map(user.Departments, func(item *models.Department, _ int) string {
+				return paths[item.ID]
+			}), transferValueSeparator),
+			strings.Join(lo.Map(user.Roles, func(item *models.Role, _ int) string {
+				return item.Name
+			}), transferValueSeparator),
+			user.Status.String(),
+			time.Time(user.CreatedAt).Format(time.DateTime),
+		})
+	}
+	return rows, nil
+}
+
+// departmentPaths 计算全部部门的完整路径,路径由各级部门名称拼接而成
+func (u *Service) departmentPaths(ctx context.Context) (map[uint]string, error) {
+	departments, err := u.departmentDAO.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	departmentMap := lo.SliceToMap(departments, func(item *models.Department) (uint, *models.Department) {
+		return item.ID, item
+	})
+	paths := make(map[uint]string, len(departments))
+	var resolve func(dept *models.Department, depth int) string
+	resolve = func(dept *models.Department, depth int) string {
+		if path, ok := paths[dept.ID]; ok {
+			return path
+		}
+		path := dept.Name
+		// 层级超过部门总数说明存在环,不再向上查找
+		if dept.ParentID != nil && depth < len(departments) {
+			if parent, ok := departmentMap[*dept.ParentID]; ok {
+				path = resolve(parent, depth+1) + departmentPathSeparator + dept.Name
+			}
+		}
+		paths[dept.ID] = path
+		return path
+	}
+	for _, dept := range departments {
+		resolve(dept, 0)
+	}
+	return paths, nil
+}
+
+// splitTransferValue 拆分单元格内的多个值,忽略空值
+func splitTransferValue(value string) []string {
+	return lo.Compact(lo.Map(strings.Split(value, transferValueSeparator), func(item string, _ int) string {
+		return strings.TrimSpace(item)
+	}))
+}
+
+// normalizeDepartmentPath 去除部门路径中各级名称两侧的空白
+func normalizeDepartmentPath(path string) string {
+	return strings.Join(lo.Map(strings.Split(path, departmentPathSeparator), func(item string, _ int) string {
+		return strings.TrimSpace(item)
+	}), departmentPathSeparator)
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/supuwoerc/weaver/models"
 	"github.com/supuwoerc/weaver/pkg/constant"
+	"github.com/supuwoerc/weaver/pkg/idp"
 	"github.com/supuwoerc/weaver/pkg/jwt"
 	"github.com/supuwoerc/weaver/pkg/response"
 	"github.com/supuwoerc/weaver/pkg/utils"
@@ -23,54 +24,246 @@ import (
 type DAO interface {
 	Create(ctx context.Context, user *models.User) error
 	GetByEmail(ctx context.Context, email string, preload ...string) (*models.User, error)
+	GetByPhone(ctx context.Context, phone string, preload ...string) (*models.User, error)
 	GetByID(ctx context.Context, uid uint, preload ...string) (*models.User, error)
 	GetByIds(ctx context.Context, ids []uint, preload ...string) ([]*models.User, error)
-	GetList(ctx context.Context, keyword string, limit, offset int) ([]*models.User, int64, error)
+	GetByIdsWithDeleted(ctx context.Context, ids []uint) ([]*models.User, error)
+	GetList(ctx context.Context, filter *models.UserFilter, limit, offset int) ([]*models.User, int64, error)
+	GetAllByFilter(ctx context.Context, filter *models.UserFilter) ([]*models.User, error)
+	GetByEmails(ctx context.Context, emails []string) ([]*models.User, error)
 	GetAll(ctx context.Context) ([]*models.User, error)
 	UpdateAccountStatus(ctx context.Context, id uint, status constant.UserStatus) error
+	UpdatePassword(ctx context.Context, id uint, password string) error
+	UpdateEmail(ctx context.Context, id uint, oldEmail, newEmail string) error
+	UpdatePhone(ctx context.Context, id uint, phone string) error
+	UpdateProfile(ctx context.Context, user *models.User) error
+	DeleteByID(ctx context.Context, id uint) error
+	GetDeletedByID(ctx context.Context, id uint) (*models.User, error)
+	Restore(ctx context.Context, id uint) error
+	AssociateRoles(ctx context.Context, id uint, roles []*models.Role) error
+	AssociateDepartments(ctx context.Context, id uint, departments []*models.Department) error
+	Anonymize(ctx context.Context, id uint, email string) error
 }
 type Cache interface {
-	CacheRefreshToken(ctx context.Context, email, refreshToken string, expiration time.Duration) error
-	DeleteRefreshToken(ctx context.Context, email string) error
-	GetRefreshToken(ctx context.Context, email string) (string, error)
+	CreateSession(ctx context.Context, session *models.UserSession, tokenID string, expiration time.Duration) error
+	RotateRefreshToken(
+		ctx context.Context, uid uint, sid, oldTokenID, newTokenID string, client *models.LoginClient, expiration time.Duration,
+	) error
+	GetUserSessions(ctx context.Context, uid uint) ([]*models.UserSession, error)
+	DeleteSessions(ctx context.Context, uid uint, sids ...string) error
+	DeleteUserSessions(ctx context.Context, uid uint) error
+	RecordAccessToken(ctx context.Context, uid uint, sid, jti string, expiresAt time.Time) error
+	DenyAccessToken(ctx context.Context, jti string, expiresAt time.Time) error
+	DenyUserAccessTokens(ctx context.Context, uid uint, excludeSessions ...string) error
 	CacheActiveAccountCode(ctx context.Context, id uint, code string, duration time.Duration) error
 	GetActiveAccountCode(ctx context.Context, id uint) (string, error)
 	RemoveActiveAccountCode(ctx context.Context, id uint) error
+	CacheResetPasswordCode(ctx context.Context, id uint, code string, duration time.Duration) error
+	GetResetPasswordCode(ctx context.Context, id uint) (string, error)
+	RemoveResetPasswordCode(ctx context.Context, id uint) error
+	IncrRequestCount(ctx context.Context, prefix constant.Prefix, key string, window time.Duration) (int64, error)
+	GetRequestCount(ctx context.Context, prefix constant.Prefix, key string) (int64, error)
+	CacheLoginBlock(ctx context.Context, prefix constant.Prefix, key string, expiration time.Duration) error
+	GetLoginBlock(ctx context.Context, prefix constant.Prefix, key string) (time.Duration, error)
+	ClearLoginFailures(ctx context.Context, email string, ip string) error
+	CacheMFATicket(ctx context.Context, ticket string, uid uint, client *models.LoginClient, expiration time.Duration) error
+	GetMFATicket(ctx context.Context, ticket string) (uint, *models.LoginClient, error)
+	ConsumeMFATicket(ctx context.Context, ticket string) (bool, error)
+	PushLoginHistory(ctx context.Context, record *models.UserLoginHistory) error
+	PopLoginHistories(ctx context.Context, count int) ([]*models.UserLoginHistory, error)
+	CachePasswordTicket(ctx context.Context, ticket string, uid uint, client *models.LoginClient, expiration time.Duration) error
+	GetPasswordTicket(ctx context.Context, ticket string) (uint, *models.LoginClient, error)
+	ConsumePasswordTicket(ctx context.Context, ticket string) (bool, error)
+	MarkTOTPStepUsed(ctx context.Context, uid uint, step int64, expiration time.Duration) (bool, error)
+	CacheSSOState(ctx context.Context, state string, value *models.SSOState, expiration time.Duration) error
+	ConsumeSSOState(ctx context.Context, state string) (*models.SSOState, error)
+	CacheEmailChange(
+		ctx context.Context, prefix constant.Prefix, code string, value *models.EmailChange, expiration time.Duration,
+	) error
+	ConsumeEmailChange(ctx context.Context, prefix constant.Prefix, code string) (*models.EmailChange, error)
+	AcquireSMSCooldown(ctx context.Context, phone string, interval time.Duration) (bool, error)
+	CacheSMSCode(ctx context.Context, scene constant.SMSScene, phone string, code string, expiration time.Duration) error
+	VerifySMSCode(ctx context.Context, scene constant.SMSScene, phone string, code string, maxAttempts int64) (bool, error)
+	AcquireDataExportLimit(ctx context.Context, uid uint, interval time.Duration) (bool, error)
+	ReleaseDataExportLimit(ctx context.Context, uid uint) error
+	CacheDataExport(ctx context.Context, code string, data []byte, expiration time.Duration) error
+	GetDataExport(ctx context.Context, code string) ([]byte, error)
+}
+
+type MFADAO interface {
+	GetByUserID(ctx context.Context, uid uint) (*models.UserMFA, error)
+	Upsert(ctx context.Context, mfa *models.UserMFA) error
+	Enable(ctx context.Context, uid uint) error
+	DeleteByUserID(ctx context.Context, uid uint) error
+	ReplaceRecoveryCodes(ctx context.Context, uid uint, codes []*models.UserRecoveryCode) error
+	UseRecoveryCode(ctx context.Context, uid uint, code string) (bool, error)
+	DeleteRecoveryCodes(ctx context.Context, uid uint) error
+}
+
+type IdentityDAO interface {
+	Create(ctx context.Context, identity *models.UserIdentity) error
+	GetByProviderSubject(ctx context.Context, provider, subject string) (*models.UserIdentity, error)
+	DeleteByUserID(ctx context.Context, uid uint) error
+}
+
+type APIKeyDAO interface {
+	Create(ctx context.Context, key *models.UserAPIKey) error
+	GetByUserID(ctx context.Context, uid uint) ([]*models.UserAPIKey, error)
+	CountByUserID(ctx context.Context, uid uint) (int64, error)
+	DeleteByID(ctx context.Context, uid, id uint) error
+	DeleteByUserID(ctx context.Context, uid uint) error
+}
+
+type ImpersonationDAO interface {
+	Create(ctx context.Context, record *models.UserImpersonation) error
+	Stop(ctx context.Context, tokenID string, stoppedAt time.Time) error
+	GetList(ctx context.Context, actorID, userID uint, limit, offset int) ([]*models.UserImpersonation, int64, error)
+}
+
+type InvitationDAO interface {
+	Create(ctx context.Context, invitation *models.UserInvitation) error
+	GetByCode(ctx context.Context, code string) (*models.UserInvitation, error)
+	GetList(ctx context.Context, keyword string, limit, offset int) ([]*models.UserInvitation, int64, error)
+	Accept(ctx context.Context, id uint, uid uint, now time.Time) error
+	DeleteByID(ctx context.Context, id uint) error
+}
+
+type PasswordHistoryDAO interface {
+	Create(ctx context.Context, history *models.UserPasswordHistory) error
+	GetRecent(ctx context.Context, uid uint, limit int) ([]*models.UserPasswordHistory, error)
+	Prune(ctx context.Context, uid uint, keep int) error
+}
+
+type LoginHistoryDAO interface {
+	Create(ctx context.Context, record *models.UserLoginHistory) error
+	GetList(ctx context.Context, filter *models.LoginHistoryFilter, limit, offset int) ([]*models.UserLoginHistory, int64, error)
+	CountSuccess(ctx context.Context, uid, beforeID uint, ip, userAgent string) (int64, error)
+	DeleteByUserID(ctx context.Context, uid uint) error
+}
+
+type AttachmentDAO interface {
+	GetByCreator(ctx context.Context, uid uint) ([]*models.Attachment, error)
+}
+
+type PermissionDAO interface {
+	GetByIds(ctx context.Context, ids []uint, preload ...string) ([]*models.Permission, error)
+	GetUserPermissions(ctx context.Context, uid uint, inheritance constant.RoleInheritance) ([]*models.Permission, error)
+}
+
+type RoleDAO interface {
+	GetByIds(ctx context.Context, ids []uint, preload ...string) ([]*models.Role, error)
+	GetByNames(ctx context.Context, names []string) ([]*models.Role, error)
+}
+
+type DepartmentDAO interface {
+	GetByIds(ctx context.Context, ids []uint) ([]*models.Department, error)
+	GetAll(ctx context.Context) ([]*models.Department, error)
+}
+
+type DepartmentCache interface {
+	RemoveDepartmentCache(ctx context.Context, keys ...constant.CacheKey) error
+}
+
+type PermissionCache interface {
+	InvalidateUserPermissions(ctx context.Context, uids ...uint) error
+}
+
+type PermissionService interface {
+	GetUserPermissionSet(ctx context.Context, uid uint) (*models.PermissionSet, error)
 }
 
 type EmailClient interface {
 	SendHTML(ctx context.Context, to string, subject constant.Subject, templatePath constant.Template, data any) error
 }
 
+type SMSClient interface {
+	Send(ctx context.Context, to string, scene constant.SMSScene, params map[string]string) error
+}
+
 type Service struct {
 	*service.BasicService
 	*captcha.Service
-	userDAO      DAO
-	userCache    Cache
-	tokenBuilder *jwt.TokenBuilder
+	userDAO            DAO
+	mfaDAO             MFADAO
+	identityDAO        IdentityDAO
+	apiKeyDAO          APIKeyDAO
+	impersonationDAO   ImpersonationDAO
+	invitationDAO      InvitationDAO
+	passwordHistoryDAO PasswordHistoryDAO
+	loginHistoryDAO    LoginHistoryDAO
+	attachmentDAO      AttachmentDAO
+	permissionDAO      PermissionDAO
+	roleDAO            RoleDAO
+	departmentDAO      DepartmentDAO
+	userCache          Cache
+	deptCache          DepartmentCache
+	permissionCache    PermissionCache
+	permissionService  PermissionService
+	tokenBuilder       *jwt.TokenBuilder
+	idpManager         *idp.Manager
+	smsClient          SMSClient
 }
 
 func NewUserService(
 	basic *service.BasicService,
 	captchaService *captcha.Service,
 	userDAO DAO,
+	mfaDAO MFADAO,
+	identityDAO IdentityDAO,
+	apiKeyDAO APIKeyDAO,
+	impersonationDAO ImpersonationDAO,
+	invitationDAO InvitationDAO,
+	passwordHistoryDAO PasswordHistoryDAO,
+	loginHistoryDAO LoginHistoryDAO,
+	attachmentDAO AttachmentDAO,
+	permissionDAO PermissionDAO,
+	roleDAO RoleDAO,
+	departmentDAO DepartmentDAO,
 	userCache Cache,
+	deptCache DepartmentCache,
+	permissionCache PermissionCache,
+	permissionService PermissionService,
 	tb *jwt.TokenBuilder,
+	idpManager *idp.Manager,
+	smsClient SMSClient,
 ) *Service {
 	return &Service{
-		BasicService: basic,
-		Service:      captchaService,
-		userDAO:      userDAO,
-		userCache:    userCache,
-		tokenBuilder: tb,
+		BasicService:       basic,
+		Service:            captchaService,
+		userDAO:            userDAO,
+		mfaDAO:             mfaDAO,
+		identityDAO:        identityDAO,
+		apiKeyDAO:          apiKeyDAO,
+		impersonationDAO:   impersonationDAO,
+		invitationDAO:      invitationDAO,
+		passwordHistoryDAO: passwordHistoryDAO,
+		loginHistoryDAO:    loginHistoryDAO,
+		attachmentDAO:      attachmentDAO,
+		permissionDAO:      permissionDAO,
+		roleDAO:            roleDAO,
+		departmentDAO:      departmentDAO,
+		userCache:          userCache,
+		deptCache:          deptCache,
+		permissionCache:    permissionCache,
+		permissionService:  permissionService,
+		tokenBuilder:       tb,
+		idpManager:         idpManager,
+		smsClient:          smsClient,
 	}
 }
 
 func (u *Service) SignUp(ctx context.Context, id string, code string, user *models.User) error {
+	// 仅邀请注册或关闭注册时,公开注册不可用
+	if u.signupMode() != constant.SignupOpen {
+		return response.SignupClosed
+	}
 	verify := u.Service.Verify(constant.SignUp, id, code)
 	if !verify {
 		return response.CaptchaVerifyFail
 	}
+	if err := u.validatePassword(ctx, nil, user.Password); err != nil {
+		return err
+	}
 	password, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
 	if err != nil {
 		return err
@@ -98,6 +291,9 @@ func (u *Service) SignUp(ctx context.Context, id string, code string, user *mode
 		if err = u.userDAO.Create(ctx, user); err != nil {
 			return err
 		}
+		if err = u.recordPasswordHistory(ctx, user.ID, user.Password); err != nil {
+			return err
+		}
 		return u.sendActiveEmail(ctx, user.ID, user.Email)
 	})
 }
@@ -125,9 +321,27 @@ func (u *Service) generateActiveURL(ctx context.Context, uid uint) (string, erro
 	return fmt.Sprintf("%s/view/v1/public/user/active?active_code=%s&id=%d", baseURL, activeCode, uid), nil
 }
 
-func (u *Service) Login(ctx context.Context, email string, password string) (*response.LoginResponse, error) {
+func (u *Service) Login(
+	ctx context.Context, email string, password string, id string, code string, client *models.LoginClient,
+) (res *response.LoginResponse, err error) {
+	attempt := newLoginAttempt(constant.LoginPassword, email, client)
+	defer func() {
+		u.finishLoginAttempt(ctx, attempt, res != nil && res.Token != "", err)
+	}()
+	if err = u.checkLoginAllowed(ctx, email, client.IP, id, code); err != nil {
+		return nil, err
+	}
 	user, err := u.userDAO.GetByEmail(ctx, email, "Roles")
+	if user != nil {
+		setAttemptUser(attempt, user)
+	}
 	switch {
+	case errors.Is(err, response.UserNotExist):
+		// 未注册的邮箱同样计入失败次数,避免通过是否被限制判断邮箱是否注册
+		if e := u.recordLoginFailure(ctx, email, client.IP); e != nil {
+			return nil, e
+		}
+		return nil, err
 	case err != nil:
 		return nil, err
 	case user.Status == constant.Inactive:
@@ -135,35 +349,204 @@ func (u *Service) Login(ctx context.Context, email string, password string) (*re
 	case user.Status == constant.Disabled:
 		return nil, response.UserDisabled
 	}
-	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
-	if err != nil {
+	if !matchPassword(user.Password, password) {
+		if e := u.recordLoginFailure(ctx, email, client.IP); e != nil {
+			return nil, e
+		}
 		return nil, response.UserLoginFail
 	}
-	accessToken, refreshToken, err := u.tokenBuilder.GenerateAccessAndRefreshToken(&jwt.TokenClaimsBasic{
+	if u.passwordExpired(user, time.Now()) {
+		if err = u.userCache.ClearLoginFailures(ctx, email, ""); err != nil {
+			return nil, err
+		}
+		// 密码过期时先签发修改密码的凭证,修改密码后再继续登录
+		ticket := lo.RandomString(constant.MFATicketLength, lo.AlphanumericCharset)
+		expiration := u.Conf.Account.MFATicketExpiration * time.Second
+		if err = u.userCache.CachePasswordTicket(ctx, ticket, user.ID, client, expiration); err != nil {
+			return nil, err
+		}
+		return &response.LoginResponse{
+			User:            toLoginUser(user),
+			PasswordExpired: true,
+			PasswordTicket:  ticket,
+		}, nil
+	}
+	res, err = u.completeLogin(ctx, user, client)
+	if err != nil || res.MFARequired {
+		// 需要两步验证时保留失败次数,通过两步验证后再清空
+		return res, err
+	}
+	// token已签发,清空失败次数失败不影响本次登录
+	if e := u.userCache.ClearLoginFailures(ctx, email, ""); e != nil {
+		u.Logger.WithContext(ctx).Errorw("clear login failures fail", "email", email, "err", e.Error())
+	}
+	return res, nil
+}
+
+// completeLogin 完成身份校验后的登录流程,开启两步验证的用户返回登录凭证,否则直接签发token
+func (u *Service) completeLogin(
+	ctx context.Context, user *models.User, client *models.LoginClient,
+) (*response.LoginResponse, error) {
+	mfa, err := u.mfaDAO.GetByUserID(ctx, user.ID)
+	if err != nil && !errors.Is(err, response.MFANotEnabled) {
+		return nil, err
+	}
+	if mfa != nil && mfa.Enabled {
+		// 开启两步验证的用户先签发登录凭证,校验验证码后再签发token
+		ticket := lo.RandomString(constant.MFATicketLength, lo.AlphanumericCharset)
+		expiration := u.Conf.Account.MFATicketExpiration * time.Second
+		if err = u.userCache.CacheMFATicket(ctx, ticket, user.ID, client, expiration); err != nil {
+			return nil, err
+		}
+		return &response.LoginResponse{
+			User:        toLoginUser(user),
+			MFARequired: true,
+			MFATicket:   ticket,
+		}, nil
+	}
+	return u.issueTokens(ctx, user, client)
+}
+
+func toLoginUser(user *models.User) response.LoginUser {
+	return response.LoginUser{
 		ID:       user.ID,
 		Email:    user.Email,
 		Nickname: user.Nickname,
-	})
+	}
+}
+
+// issueTokens 为用户创建新的登录会话并签发长短token
+func (u *Service) issueTokens(ctx context.Context, user *models.User, client *models.LoginClient) (*response.LoginResponse, error) {
+	pair, err := u.tokenBuilder.GenerateAccessAndRefreshToken(&jwt.TokenClaimsBasic{
+		ID:       user.ID,
+		Email:    user.Email,
+		Nickname: user.Nickname,
+	}, "")
 	if err != nil {
 		return nil, err
 	}
-	err = u.userCache.CacheRefreshToken(ctx, user.Email, refreshToken, u.tokenBuilder.GetRefreshTokenExpiration())
+	now := time.Now()
+	session := &models.UserSession{
+		LoginClient: *client,
+		ID:          pair.Session,
+		UID:         user.ID,
+		CreatedAt:   now,
+		LastSeen:    now,
+	}
+	err = u.userCache.CreateSession(ctx, session, pair.RefreshID, u.tokenBuilder.GetRefreshTokenExpiration())
 	if err != nil {
 		return nil, err
 	}
+	if err = u.userCache.RecordAccessToken(ctx, user.ID, pair.Session, pair.AccessID, pair.AccessExpiresAt); err != nil {
+		return nil, err
+	}
 	return &response.LoginResponse{
-		User: response.LoginUser{
-			ID:       user.ID,
-			Email:    user.Email,
-			Nickname: user.Nickname,
-		},
-		Token:        accessToken,
-		RefreshToken: refreshToken,
+		User:         toLoginUser(user),
+		Token:        pair.AccessToken,
+		RefreshToken: pair.RefreshToken,
 	}, nil
 }
 
-func (u *Service) Logout(ctx context.Context, email string) error {
-	return u.userCache.DeleteRefreshToken(ctx, email)
+// RefreshToken 使用 refreshToken 换取新的长短token,旧的 refreshToken 随即失效,重复使用会注销整个会话
+func (u *Service) RefreshToken(
+	ctx context.Context, refreshToken string, client *models.LoginClient,
+) (res *response.RefreshTokenResponse, err error) {
+	claims, err := u.tokenBuilder.ParseRefreshToken(refreshToken)
+	if err != nil {
+		return nil, err
+	}
+	attempt := newLoginAttempt(constant.LoginRefresh, claims.User.Email, client)
+	attempt.UserID = &claims.User.ID
+	defer func() {
+		u.finishLoginAttempt(ctx, attempt, res != nil, err)
+	}()
+	user, err := u.userDAO.GetByID(ctx, claims.User.ID)
+	switch {
+	case errors.Is(err, response.UserNotExist):
+		return nil, response.InvalidRefreshToken
+	case err != nil:
+		return nil, err
+	case user.Status == constant.Disabled:
+		return nil, response.UserDisabled
+	}
+	pair, err := u.tokenBuilder.GenerateAccessAndRefreshToken(&jwt.TokenClaimsBasic{
+		ID:       user.ID,
+		Email:    user.Email,
+		Nickname: user.Nickname,
+	}, claims.Session)
+	if err != nil {
+		return nil, err
+	}
+	expiration := u.tokenBuilder.GetRefreshTokenExpiration()
+	err = u.userCache.RotateRefreshToken(ctx, user.ID, claims.Session, claims.ID, pair.RefreshID, client, expiration)
+	if err != nil {
+		if errors.Is(err, response.RefreshTokenReused) {
+			u.Logger.WithContext(ctx).Warnw("refresh token reused, session revoked",
+				"uid", user.ID, "session", claims.Session)
+		}
+		return nil, err
+	}
+	if err = u.userCache.RecordAccessToken(ctx, user.ID, pair.Session, pair.AccessID, pair.AccessExpiresAt); err != nil {
+		return nil, err
+	}
+	return &response.RefreshTokenResponse{
+		Token:        pair.AccessToken,
+		RefreshToken: pair.RefreshToken,
+	}, nil
+}
+
+// Logout 注销当前会话并吊销当前的短token,用户在其他设备上的登录不受影响;
+// 模拟登录的token沿用操作人的会话,登出时只结束模拟登录,操作人和被模拟用户的登录会话都不受影响
+func (u *Service) Logout(ctx context.Context, claims *jwt.TokenClaims) error {
+	if claims.Actor != nil {
+		return u.StopImpersonation(ctx, claims)
+	}
+	if err := u.userCache.DeleteSessions(ctx, claims.User.ID, claims.Session); err != nil {
+		return err
+	}
+	return u.userCache.DenyAccessToken(ctx, claims.ID, claims.ExpiresAt.Time)
+}
+
+// GetSessions 获取用户全部有效的登录会话
+func (u *Service) GetSessions(ctx context.Context, uid uint, current string) ([]*response.UserSessionResponse, error) {
+	sessions, err := u.userCache.GetUserSessions(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	return lo.Map(sessions, func(item *models.UserSession, _ int) *response.UserSessionResponse {
+		return &response.UserSessionResponse{
+			UserSession: item,
+			Current:     item.ID == current,
+		}
+	}), nil
+}
+
+// RevokeSessions 注销用户指定的登录会话,不属于该用户的会话会被忽略
+func (u *Service) RevokeSessions(ctx context.Context, uid uint, sids []string) error {
+	sessions, err := u.userCache.GetUserSessions(ctx, uid)
+	if err != nil {
+		return err
+	}
+	owned := lo.Intersect(lo.Map(sessions, func(item *models.UserSession, _ int) string {
+		return item.ID
+	}), sids)
+	return u.userCache.DeleteSessions(ctx, uid, owned...)
+}
+
+// RevokeUserSessions 注销用户全部的登录会话
+func (u *Service) RevokeUserSessions(ctx context.Context, uid uint) error {
+	if _, err := u.userDAO.GetByID(ctx, uid); err != nil {
+		return err
+	}
+	return u.revokeUserTokens(ctx, uid)
+}
+
+// revokeUserTokens 注销用户全部的登录会话并吊销全部未过期的短token
+func (u *Service) revokeUserTokens(ctx context.Context, uid uint) error {
+	if err := u.userCache.DeleteUserSessions(ctx, uid); err != nil {
+		return err
+	}
+	return u.userCache.DenyUserAccessTokens(ctx, uid)
 }
 
 func (u *Service) Profile(ctx context.Context, uid uint) (*response.ProfileResponse, error) {
@@ -189,9 +572,9 @@ func (u *Service) Profile(ctx context.Context, uid uint) (*response.ProfileRespo
 }
 
 func (u *Service) GetUserList(
-	ctx context.Context, keyword string, limit, offset int,
+	ctx context.Context, filter *models.UserFilter, limit, offset int,
 ) ([]*response.UserListRowResponse, int64, error) {
-	list, total, err := u.userDAO.GetList(ctx, keyword, limit, offset)
+	list, total, err := u.userDAO.GetList(ctx, filter, limit, offset)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -261,3 +644,132 @@ func (u *Service) ActiveAccount(ctx context.Context, uid uint, activeCode string
 		})
 	}
 }
+
+// checkResetPasswordLimit 按邮箱限制找回/重置密码的请求频率
+func (u *Service) checkResetPasswordLimit(ctx context.Context, prefix constant.Prefix, email string) error {
+	count, err := u.userCache.IncrRequestCount(ctx, prefix, email, u.Conf.Account.ResetPasswordWindow*time.Second)
+	if err != nil {
+		return err
+	}
+	if count > u.Conf.Account.ResetPasswordLimit {
+		return response.ResetPasswordTooFrequent
+	}
+	return nil
+}
+
+// ForgotPassword 向邮箱发送重置密码的验证码,邮箱未注册或账户不可用时不发送邮件且不返回错误,避免暴露账户是否存在
+func (u *Service) ForgotPassword(ctx context.Context, id string, code string, email string) error {
+	verify := u.Service.Verify(constant.ResetPassword, id, code)
+	if !verify {
+		return response.CaptchaVerifyFail
+	}
+	if err := u.checkResetPasswordLimit(ctx, constant.ForgotPasswordLimitPrefix, email); err != nil {
+		return err
+	}
+	user, err := u.userDAO.GetByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, response.UserNotExist) {
+			return nil
+		}
+		return err
+	}
+	if user.Status != constant.Normal {
+		return nil
+	}
+	resetCode := lo.RandomString(constant.ResetPasswordCodeLength, lo.NumbersCharset)
+	expiration := u.Conf.Account.ResetPasswordExpiration
+	if err = u.userCache.CacheResetPasswordCode(ctx, user.ID, resetCode, expiration*time.Second); err != nil {
+		return err
+	}
+	variable := models.ResetPasswordVariable{
+		Code:       resetCode,
+		Expiration: int((expiration * time.Second).Minutes()),
+	}
+	return u.EmailClient.SendHTML(ctx, user.Email, constant.PasswordReset, constant.ResetPasswordTemplate, variable)
+}
+
+// ResetPassword 校验邮件中的验证码并重置密码,重置成功后注销用户全部的登录会话并吊销短token
+func (u *Service) ResetPassword(ctx context.Context, email string, code string, password string) error {
+	if err := u.checkResetPasswordLimit(ctx, constant.ResetPasswordLimitPrefix, email); err != nil {
+		return err
+	}
+	user, err := u.userDAO.GetByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, response.UserNotExist) {
+			return response.InvalidResetPasswordCode
+		}
+		return err
+	}
+	userLock := u.Locksmith.NewLock(constant.UserIdPrefix, strconv.Itoa(int(user.ID)))
+	if err = userLock.Lock(ctx, true); err != nil {
+		return err
+	}
+	defer func(lock *utils.RedisLock) {
+		if e := lock.Unlock(); e != nil {
+			u.Logger.WithContext(ctx).Errorf("unlock fail %s", e.Error())
+		}
+	}(userLock)
+	cacheCode, err := u.userCache.GetResetPasswordCode(ctx, user.ID)
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return response.InvalidResetPasswordCode
+		}
+		return err
+	}
+	if cacheCode != code {
+		return response.InvalidResetPasswordCode
+	}
+	if err = u.validatePassword(ctx, user, password); err != nil {
+		return err
+	}
+	if err = u.updatePassword(ctx, user.ID, password); err != nil {
+		return err
+	}
+	if err = u.userCache.RemoveResetPasswordCode(ctx, user.ID); err != nil {
+		return err
+	}
+	return u.revokeUserTokens(ctx, user.ID)
+}
+
+// UpdateProfile 更新个人资料
+func (u *Service) UpdateProfile(ctx context.Context, uid uint, profile *models.User) error {
+	profile.ID = uid
+	return u.userDAO.UpdateProfile(ctx, profile)
+}
+
+// ChangePassword 校验原密码后修改密码,修改成功后注销除当前会话外的全部登录会话并吊销其短token
+func (u *Service) ChangePassword(ctx context.Context, uid uint, sid string, oldPassword, newPassword string) error {
+	userLock := u.Locksmith.NewLock(constant.UserIdPrefix, strconv.Itoa(int(uid)))
+	if err := userLock.Lock(ctx, true); err != nil {
+		return err
+	}
+	defer func(lock *utils.RedisLock) {
+		if e := lock.Unlock(); e != nil {
+			u.Logger.WithContext(ctx).Errorf("unlock fail %s", e.Error())
+		}
+	}(userLock)
+	user, err := u.userDAO.GetByID(ctx, uid)
+	if err != nil {
+		return err
+	}
+	if !matchPassword(user.Password, oldPassword) {
+		return response.OldPasswordIncorrect
+	}
+	if err = u.validatePassword(ctx, user, newPassword); err != nil {
+		return err
+	}
+	if err = u.updatePassword(ctx, uid, newPassword); err != nil {
+		return err
+	}
+	sessions, err := u.userCache.GetUserSessions(ctx, uid)
+	if err != nil {
+		return err
+	}
+	others := lo.FilterMap(sessions, func(item *models.UserSession, _ int) (string, bool) {
+		return item.ID, item.ID != sid
+	})
+	if err = u.userCache.DeleteSessions(ctx, uid, others...); err != nil {
+		return err
+	}
+	return u.userCache.DenyUserAccessTokens(ctx, uid, sid)
+}